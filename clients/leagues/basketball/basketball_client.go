@@ -0,0 +1,57 @@
+package basketball
+
+import (
+	"encoding/json"
+	"fmt"
+	"goalfeed/utils"
+)
+
+// ESPN's league slugs, used in its basketball URLs
+const (
+	LeagueNBA  = "nba"
+	LeagueWNBA = "wnba"
+)
+
+// BasketballApiClient reads ESPN's scoreboard and game summaries for one
+// basketball league, named by its slug in League.
+type BasketballApiClient struct {
+	League string
+}
+
+// fetchByte allows tests to stub the HTTP fetcher
+var fetchByte = utils.GetByte
+
+func (c BasketballApiClient) baseURL() string {
+	return fmt.Sprintf("https://site.api.espn.com/apis/site/v2/sports/basketball/%s", c.League)
+}
+
+func (c BasketballApiClient) GetSchedule() BasketballScheduleResponse {
+	var body chan []byte = make(chan []byte)
+	go fetchByte(c.baseURL()+"/scoreboard", body)
+
+	bodyByte := <-body
+	var response BasketballScheduleResponse
+	json.Unmarshal(bodyByte, &response)
+	return response
+}
+
+func (c BasketballApiClient) GetScheduleByDate(date string) BasketballScheduleResponse {
+	var body chan []byte = make(chan []byte)
+	// Format: YYYYMMDD
+	go fetchByte(fmt.Sprintf("%s/scoreboard?dates=%s", c.baseURL(), date), body)
+
+	bodyByte := <-body
+	var response BasketballScheduleResponse
+	json.Unmarshal(bodyByte, &response)
+	return response
+}
+
+func (c BasketballApiClient) GetSummary(gameId string) BasketballSummaryResponse {
+	var body chan []byte = make(chan []byte)
+	go fetchByte(fmt.Sprintf("%s/summary?event=%s", c.baseURL(), gameId), body)
+
+	bodyByte := <-body
+	var response BasketballSummaryResponse
+	json.Unmarshal(bodyByte, &response)
+	return response
+}
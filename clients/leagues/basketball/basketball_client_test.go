@@ -0,0 +1,51 @@
+package basketball
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func withStubFetch(t *testing.T, payload interface{}) *string {
+	t.Helper()
+	old := fetchByte
+	var requested string
+	b, _ := json.Marshal(payload)
+	fetchByte = func(url string, ret chan []byte) {
+		requested = url
+		ret <- b
+	}
+	t.Cleanup(func() { fetchByte = old })
+	return &requested
+}
+
+func TestBasketballClient_Schedule(t *testing.T) {
+	url := withStubFetch(t, BasketballScheduleResponse{Events: []BasketballEvent{{ID: "1"}}})
+	resp := BasketballApiClient{League: LeagueWNBA}.GetScheduleByDate("20250701")
+	if len(resp.Events) != 1 || resp.Events[0].ID != "1" {
+		t.Fatalf("unexpected schedule resp: %+v", resp)
+	}
+	if *url != "https://site.api.espn.com/apis/site/v2/sports/basketball/wnba/scoreboard?dates=20250701" {
+		t.Errorf("unexpected url %s", *url)
+	}
+}
+
+func TestBasketballClient_Summary(t *testing.T) {
+	body := `{"header": {"id": "401", "competitions": [{"competitors": [{"homeAway": "home", "score": "54",
+		"team": {"id": "13", "abbreviation": "LAL", "logos": [{"href": "lal.png"}]}}]}]},
+		"plays": [{"id": "7", "scoringPlay": true, "scoreValue": 3, "homeScore": 54, "team": {"id": "13"}}]}`
+	var payload BasketballSummaryResponse
+	if err := json.Unmarshal([]byte(body), &payload); err != nil {
+		t.Fatalf("bad fixture: %v", err)
+	}
+	url := withStubFetch(t, payload)
+	resp := BasketballApiClient{League: LeagueNBA}.GetSummary("401")
+	if len(resp.Header.Competitions) != 1 || resp.Header.Competitions[0].Competitors[0].Team.Logos[0].Href != "lal.png" {
+		t.Fatalf("unexpected summary resp: %+v", resp)
+	}
+	if len(resp.Plays) != 1 || resp.Plays[0].ScoreValue != 3 || resp.Plays[0].Team.ID != "13" {
+		t.Fatalf("unexpected plays: %+v", resp.Plays)
+	}
+	if *url != "https://site.api.espn.com/apis/site/v2/sports/basketball/nba/summary?event=401" {
+		t.Errorf("unexpected url %s", *url)
+	}
+}
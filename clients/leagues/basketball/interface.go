@@ -0,0 +1,7 @@
+package basketball
+
+type IBasketballApiClient interface {
+	GetSchedule() BasketballScheduleResponse
+	GetScheduleByDate(date string) BasketballScheduleResponse
+	GetSummary(gameId string) BasketballSummaryResponse
+}
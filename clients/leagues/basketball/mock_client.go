@@ -0,0 +1,18 @@
+package basketball
+
+type MockBasketballApiClient struct {
+	ScheduleResponse BasketballScheduleResponse
+	SummaryResponse  BasketballSummaryResponse
+}
+
+func (m MockBasketballApiClient) GetSchedule() BasketballScheduleResponse {
+	return m.ScheduleResponse
+}
+
+func (m MockBasketballApiClient) GetScheduleByDate(date string) BasketballScheduleResponse {
+	return m.ScheduleResponse
+}
+
+func (m MockBasketballApiClient) GetSummary(gameId string) BasketballSummaryResponse {
+	return m.SummaryResponse
+}
@@ -0,0 +1,115 @@
+package basketball
+
+// BasketballScheduleResponse is ESPN's scoreboard for a day of games
+type BasketballScheduleResponse struct {
+	Events []BasketballEvent `json:"events"`
+}
+
+type BasketballEvent struct {
+	ID           string                  `json:"id"`
+	Date         string                  `json:"date"`
+	Name         string                  `json:"name"`
+	ShortName    string                  `json:"shortName"`
+	Season       BasketballSeason        `json:"season"`
+	Competitions []BasketballCompetition `json:"competitions"`
+	Status       BasketballStatus        `json:"status"`
+}
+
+type BasketballSeason struct {
+	Year int `json:"year"`
+	Type int `json:"type"`
+}
+
+type BasketballCompetition struct {
+	ID          string                 `json:"id"`
+	Date        string                 `json:"date"`
+	Venue       BasketballVenue        `json:"venue"`
+	Competitors []BasketballCompetitor `json:"competitors"`
+	Status      BasketballStatus       `json:"status"`
+}
+
+type BasketballVenue struct {
+	ID       string `json:"id"`
+	FullName string `json:"fullName"`
+	Address  struct {
+		City  string `json:"city"`
+		State string `json:"state"`
+	} `json:"address"`
+	Indoor bool `json:"indoor"`
+}
+
+type BasketballCompetitor struct {
+	ID       string         `json:"id"`
+	HomeAway string         `json:"homeAway"`
+	Winner   bool           `json:"winner"`
+	Score    string         `json:"score"`
+	Team     BasketballTeam `json:"team"`
+}
+
+// BasketballTeam carries a single logo on the scoreboard and a list of them
+// in a summary.
+type BasketballTeam struct {
+	ID           string `json:"id"`
+	Location     string `json:"location"`
+	Name         string `json:"name"`
+	Abbreviation string `json:"abbreviation"`
+	DisplayName  string `json:"displayName"`
+	Color        string `json:"color"`
+	Logo         string `json:"logo"`
+	Logos        []struct {
+		Href string `json:"href"`
+	} `json:"logos"`
+}
+
+type BasketballStatus struct {
+	Clock        float64 `json:"clock"`
+	DisplayClock string  `json:"displayClock"`
+	Period       int     `json:"period"`
+	Type         struct {
+		ID          string `json:"id"`
+		Name        string `json:"name"`
+		State       string `json:"state"`
+		Completed   bool   `json:"completed"`
+		Description string `json:"description"`
+		Detail      string `json:"detail"`
+		ShortDetail string `json:"shortDetail"`
+	} `json:"type"`
+}
+
+// BasketballSummaryResponse is ESPN's summary of one game. The score and
+// status live at header.competitions[0]; plays is the play-by-play so far.
+type BasketballSummaryResponse struct {
+	Header struct {
+		ID           string                  `json:"id"`
+		Season       BasketballSeason        `json:"season"`
+		Competitions []BasketballCompetition `json:"competitions"`
+	} `json:"header"`
+	GameInfo struct {
+		Venue BasketballVenue `json:"venue"`
+	} `json:"gameInfo"`
+	Plays []BasketballPlay `json:"plays"`
+}
+
+type BasketballPlay struct {
+	ID             string `json:"id"`
+	SequenceNumber string `json:"sequenceNumber"`
+	Type           struct {
+		ID   string `json:"id"`
+		Text string `json:"text"`
+	} `json:"type"`
+	Text      string `json:"text"`
+	AwayScore int    `json:"awayScore"`
+	HomeScore int    `json:"homeScore"`
+	Period    struct {
+		Number       int    `json:"number"`
+		DisplayValue string `json:"displayValue"`
+	} `json:"period"`
+	Clock struct {
+		DisplayValue string `json:"displayValue"`
+	} `json:"clock"`
+	ScoringPlay bool `json:"scoringPlay"`
+	ScoreValue  int  `json:"scoreValue"`
+	Team        struct {
+		ID string `json:"id"`
+	} `json:"team"`
+}
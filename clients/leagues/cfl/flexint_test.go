@@ -11,10 +11,10 @@ import (
 //   - testdata/cfl_live_game_stringid.json is a REAL capture of the
 //     BetGenius live-game endpoint (multisportgametracker) for CFL fixture
 //     13419716 (MTL @ OTT), fetched post-game on 2026-08-21. It is trimmed
-//     to keep only the first play-by-play "matchActions" entry (the parser
-//     doesn't read that array at all); every field the parser DOES read
-//     (sportId/competitionId as strings, scoreboardInfo, matchInfo,
-//     availableTabs) is byte-for-byte as captured. This is the exact shape
+//     to keep only the first play-by-play "matchActions" entry; every field
+//     the parser reads (sportId/competitionId as strings, scoreboardInfo,
+//     matchInfo, availableTabs, that one match action) is byte-for-byte as
+//     captured. This is the exact shape
 //     that used to make GetCFLLiveGame discard the whole response: sportId
 //     and competitionId arrive as JSON strings ("17"/"1035"), not numbers.
 //   - testdata/cfl_live_game_numericid.json is that same real capture with
@@ -104,3 +104,22 @@ func TestGetCFLLiveGame_RealPayloadNotDiscarded(t *testing.T) {
 		t.Fatalf("expected real score 46-16, got %d-%d", resp.Data.ScoreboardInfo.HomeScore, resp.Data.ScoreboardInfo.AwayScore)
 	}
 }
+
+// TestCFLCourt_Actions reads the captured match action and skips one that
+// doesn't decode instead of losing the rest of the play-by-play.
+func TestCFLCourt_Actions(t *testing.T) {
+	resp := loadCFLFixture(t, "cfl_live_game_stringid.json")
+	resp.Data.Court.MatchActions = append(resp.Data.Court.MatchActions, json.RawMessage(`{"id": 12}`))
+
+	actions := resp.Data.Court.Actions()
+	if len(actions) != 1 {
+		t.Fatalf("expected 1 action, got %d", len(actions))
+	}
+	action := actions[0]
+	if action.ID != "31-3" || action.Type != "Run" || action.TeamID != "88019" {
+		t.Errorf("unexpected action %q %q %q", action.ID, action.Type, action.TeamID)
+	}
+	if action.PhaseQualifier != 4 || action.PlayDetails.IsScoring {
+		t.Errorf("expected a non-scoring fourth-quarter play, got quarter %d scoring %v", action.PhaseQualifier, action.PlayDetails.IsScoring)
+	}
+}
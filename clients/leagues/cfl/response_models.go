@@ -202,8 +202,73 @@ type CFLThemeLogo struct {
 	SVG string `json:"svg"`
 }
 
+// CFLCourt carries the play-by-play. MatchActions is kept raw and decoded
+// one action at a time by Actions, so an action whose shape drifts from
+// CFLMatchAction is dropped on its own instead of failing the whole live game
+// response (see FlexInt).
 type CFLCourt struct {
-	MatchActions []interface{} `json:"matchActions"`
+	MatchActions []json.RawMessage `json:"matchActions"`
+}
+
+// Actions decodes the match actions that parse, in feed order.
+func (c CFLCourt) Actions() []CFLMatchAction {
+	actions := make([]CFLMatchAction, 0, len(c.MatchActions))
+	for _, raw := range c.MatchActions {
+		var action CFLMatchAction
+		if err := json.Unmarshal(raw, &action); err == nil {
+			actions = append(actions, action)
+		}
+	}
+	return actions
+}
+
+// CFLMatchAction is one play from court.matchActions. ID is
+// "<drive>-<play>". TeamID is the competitor in possession at the snap,
+// matching CFLDetailedTeam.CompetitorID. Type is the play type ("Run",
+// "Pass", "FieldGoal", "Punt", ...); whether it scored, and how, is only in
+// PlayDetails.IsScoring and the description.
+type CFLMatchAction struct {
+	ID             string            `json:"id"`
+	Type           string            `json:"type"`
+	SubType        string            `json:"subType"`
+	TeamID         string            `json:"teamId"`
+	Clock          string            `json:"clock"`
+	PhaseQualifier FlexInt           `json:"phaseQualifier"`
+	IsBigPlay      bool              `json:"isBigPlay"`
+	Timestamp      string            `json:"timestamp"`
+	PlayDetails    CFLPlayDetails    `json:"playDetails"`
+	Drive          CFLActionDrive    `json:"drive"`
+	PlayByPlayInfo CFLPlayByPlayInfo `json:"playByPlayInfo"`
+	Description    string            `json:"playDescription"`
+	StartPosition  string            `json:"playStartPosition"`
+}
+
+type CFLPlayDetails struct {
+	YardsToGo            FlexInt              `json:"yardsToGo"`
+	DownNumber           FlexInt              `json:"downNumber"`
+	ScrimmageLocation    CFLScrimmageLocation `json:"scrimmageLocation"`
+	Yards                FlexInt              `json:"yards"`
+	IsScoring            bool                 `json:"isScoring"`
+	IsChangeOfPossession bool                 `json:"isChangeOfPossession"`
+}
+
+// CFLScrimmageLocation gives the line of scrimmage as a yard line on one
+// side of the field. SideOfPitch is "Home" or "Away".
+type CFLScrimmageLocation struct {
+	SideOfPitch       string  `json:"sideOfPitch"`
+	ScrimmageYard     FlexInt `json:"scrimmageYard"`
+	NextScrimmageYard FlexInt `json:"nextScrimmageYard"`
+	NextSideOfPitch   string  `json:"nextSideOfPitch"`
+}
+
+type CFLActionDrive struct {
+	ID                 string `json:"id"`
+	TeamInPossessionID string `json:"teamInPossessionId"`
+}
+
+type CFLPlayByPlayInfo struct {
+	Clock       string `json:"clock"`
+	Description string `json:"description"`
 }
 
 type CFLAvailableTabs struct {
@@ -0,0 +1,71 @@
+package hockeytech
+
+import (
+	"encoding/json"
+	"fmt"
+	"goalfeed/utils"
+
+	"github.com/spf13/viper"
+)
+
+// HockeyTech client codes, one per league it hosts
+const (
+	ClientCodePWHL = "pwhl"
+	ClientCodeAHL  = "ahl"
+)
+
+// defaultKeys are the public keys each league's own site uses to read its
+// feed. hockeytech.<client code>.key overrides them.
+var defaultKeys = map[string]string{
+	ClientCodePWHL: "694cfeed58c932ee",
+	ClientCodeAHL:  "50c2cd9b5e18e390",
+}
+
+// HockeyTechApiClient reads one league's HockeyTech "modulekit" feed, named
+// by its client code in ClientCode. Key is the feed key; when empty it comes
+// from config or the league's default.
+type HockeyTechApiClient struct {
+	ClientCode string
+	Key        string
+}
+
+// fetchByte allows tests to stub the HTTP fetcher
+var fetchByte = utils.GetByte
+
+const baseURL = "https://lscluster.hockeytech.com/feed/index.php"
+
+func (c HockeyTechApiClient) key() string {
+	if c.Key != "" {
+		return c.Key
+	}
+	if key := viper.GetString("hockeytech." + c.ClientCode + ".key"); key != "" {
+		return key
+	}
+	return defaultKeys[c.ClientCode]
+}
+
+func (c HockeyTechApiClient) moduleKitURL(view string) string {
+	return fmt.Sprintf("%s?feed=modulekit&view=%s&key=%s&client_code=%s&lang=en&fmt=json", baseURL, view, c.key(), c.ClientCode)
+}
+
+// GetScorebar returns every game from daysBack days ago to daysAhead days
+// from now, with live scores for those in progress.
+func (c HockeyTechApiClient) GetScorebar(daysBack int, daysAhead int) HockeyTechScorebarResponse {
+	var body chan []byte = make(chan []byte)
+	go fetchByte(fmt.Sprintf("%s&numberofdaysback=%d&numberofdaysahead=%d", c.moduleKitURL("scorebar"), daysBack, daysAhead), body)
+
+	bodyByte := <-body
+	var response HockeyTechScorebarResponse
+	json.Unmarshal(bodyByte, &response)
+	return response
+}
+
+func (c HockeyTechApiClient) GetGameSummary(gameId string) HockeyTechGameSummaryResponse {
+	var body chan []byte = make(chan []byte)
+	go fetchByte(fmt.Sprintf("%s&game_id=%s", c.moduleKitURL("gamesummary"), gameId), body)
+
+	bodyByte := <-body
+	var response HockeyTechGameSummaryResponse
+	json.Unmarshal(bodyByte, &response)
+	return response
+}
@@ -0,0 +1,59 @@
+package hockeytech
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/spf13/viper"
+)
+
+func withStubFetch(t *testing.T, payload interface{}) *string {
+	t.Helper()
+	old := fetchByte
+	var requested string
+	b, _ := json.Marshal(payload)
+	fetchByte = func(url string, ret chan []byte) {
+		requested = url
+		ret <- b
+	}
+	t.Cleanup(func() { fetchByte = old })
+	return &requested
+}
+
+func TestHockeyTechClient_Scorebar(t *testing.T) {
+	body := `{"SiteKit": {"Scorebar": [{"ID": "210", "HomeCode": "BOS", "HomeGoals": "2", "VisitorCode": "MIN", "GameStatus": "2"}]}}`
+	var payload HockeyTechScorebarResponse
+	if err := json.Unmarshal([]byte(body), &payload); err != nil {
+		t.Fatalf("bad fixture: %v", err)
+	}
+	url := withStubFetch(t, payload)
+	resp := HockeyTechApiClient{ClientCode: ClientCodePWHL}.GetScorebar(1, 7)
+	if len(resp.SiteKit.Scorebar) != 1 || resp.SiteKit.Scorebar[0].HomeGoals != "2" {
+		t.Fatalf("unexpected scorebar resp: %+v", resp)
+	}
+	want := "https://lscluster.hockeytech.com/feed/index.php?feed=modulekit&view=scorebar&key=694cfeed58c932ee&client_code=pwhl&lang=en&fmt=json&numberofdaysback=1&numberofdaysahead=7"
+	if *url != want {
+		t.Errorf("unexpected url %s", *url)
+	}
+}
+
+func TestHockeyTechClient_GameSummaryKey(t *testing.T) {
+	body := `{"SiteKit": {"Gamesummary": {"goals": [{"team_id": "4", "period_id": "2", "time": "5:01", "power_play": "1",
+		"goal_scorer": {"first_name": "Marie-Philip", "last_name": "Poulin", "jersey_number": "29"}}]}}}`
+	var payload HockeyTechGameSummaryResponse
+	if err := json.Unmarshal([]byte(body), &payload); err != nil {
+		t.Fatalf("bad fixture: %v", err)
+	}
+	url := withStubFetch(t, payload)
+	viper.Set("hockeytech.ahl.key", "configured")
+	defer viper.Set("hockeytech.ahl.key", "")
+
+	resp := HockeyTechApiClient{ClientCode: ClientCodeAHL}.GetGameSummary("1027")
+	if goals := resp.SiteKit.Gamesummary.Goals; len(goals) != 1 || goals[0].GoalScorer.LastName != "Poulin" || goals[0].PowerPlay != "1" {
+		t.Fatalf("unexpected summary resp: %+v", resp)
+	}
+	if !strings.Contains(*url, "view=gamesummary&key=configured&client_code=ahl") || !strings.HasSuffix(*url, "&game_id=1027") {
+		t.Errorf("unexpected url %s", *url)
+	}
+}
@@ -0,0 +1,6 @@
+package hockeytech
+
+type IHockeyTechApiClient interface {
+	GetScorebar(daysBack int, daysAhead int) HockeyTechScorebarResponse
+	GetGameSummary(gameId string) HockeyTechGameSummaryResponse
+}
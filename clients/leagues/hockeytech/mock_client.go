@@ -0,0 +1,14 @@
+package hockeytech
+
+type MockHockeyTechApiClient struct {
+	ScorebarResponse    HockeyTechScorebarResponse
+	GameSummaryResponse HockeyTechGameSummaryResponse
+}
+
+func (m MockHockeyTechApiClient) GetScorebar(daysBack int, daysAhead int) HockeyTechScorebarResponse {
+	return m.ScorebarResponse
+}
+
+func (m MockHockeyTechApiClient) GetGameSummary(gameId string) HockeyTechGameSummaryResponse {
+	return m.GameSummaryResponse
+}
@@ -0,0 +1,111 @@
+package hockeytech
+
+// HockeyTech sends numbers as strings throughout ("3", "1"), so the models
+// keep them as strings and the service converts what it needs.
+
+// HockeyTechScorebarResponse is the modulekit scorebar: every game in a
+// window of days, with its live score, period and clock.
+type HockeyTechScorebarResponse struct {
+	SiteKit struct {
+		Scorebar []HockeyTechScorebarGame `json:"Scorebar"`
+	} `json:"SiteKit"`
+}
+
+type HockeyTechScorebarGame struct {
+	ID                     string `json:"ID"`
+	Date                   string `json:"Date"`
+	GameDateISO8601        string `json:"GameDateISO8601"`
+	ScheduledFormattedTime string `json:"ScheduledFormattedTime"`
+	Timezone               string `json:"Timezone"`
+	SeasonID               string `json:"SeasonID"`
+	HomeID                 string `json:"HomeID"`
+	HomeCode               string `json:"HomeCode"`
+	HomeCity               string `json:"HomeCity"`
+	HomeNickname           string `json:"HomeNickname"`
+	HomeLongName           string `json:"HomeLongName"`
+	HomeGoals              string `json:"HomeGoals"`
+	HomeLogo               string `json:"HomeLogo"`
+	VisitorID              string `json:"VisitorID"`
+	VisitorCode            string `json:"VisitorCode"`
+	VisitorCity            string `json:"VisitorCity"`
+	VisitorNickname        string `json:"VisitorNickname"`
+	VisitorLongName        string `json:"VisitorLongName"`
+	VisitorGoals           string `json:"VisitorGoals"`
+	VisitorLogo            string `json:"VisitorLogo"`
+	Period                 string `json:"Period"`
+	PeriodNameShort        string `json:"PeriodNameShort"`
+	GameClock              string `json:"GameClock"`
+	Intermission           string `json:"Intermission"`
+	// GameStatus is "1" before the game, "2" or "3" while it's on and "4"
+	// once it's final; GameStatusString says the same in words
+	GameStatus       string `json:"GameStatus"`
+	GameStatusString string `json:"GameStatusString"`
+	VenueName        string `json:"venue_name"`
+	VenueLocation    string `json:"venue_location"`
+}
+
+// HockeyTechGameSummaryResponse is the modulekit game summary, read for its
+// goals and shootout.
+type HockeyTechGameSummaryResponse struct {
+	SiteKit struct {
+		Gamesummary HockeyTechGameSummary `json:"Gamesummary"`
+	} `json:"SiteKit"`
+}
+
+type HockeyTechGameSummary struct {
+	Meta struct {
+		ID                string `json:"id"`
+		HomeTeam          string `json:"home_team"`
+		VisitingTeam      string `json:"visiting_team"`
+		HomeGoalCount     string `json:"home_goal_count"`
+		VisitingGoalCount string `json:"visiting_goal_count"`
+		Shootout          string `json:"shootout"`
+		Final             string `json:"final"`
+	} `json:"meta"`
+	Home            HockeyTechTeam            `json:"home"`
+	Visitor         HockeyTechTeam            `json:"visitor"`
+	Goals           []HockeyTechGoal          `json:"goals"`
+	ShootoutDetails HockeyTechShootoutDetails `json:"shootoutDetails"`
+}
+
+type HockeyTechTeam struct {
+	ID       string `json:"id"`
+	Code     string `json:"code"`
+	Name     string `json:"name"`
+	Nickname string `json:"nickname"`
+	City     string `json:"city"`
+}
+
+type HockeyTechGoal struct {
+	ID          string           `json:"game_goal_id"`
+	TeamID      string           `json:"team_id"`
+	PeriodID    string           `json:"period_id"`
+	Time        string           `json:"time"`
+	PowerPlay   string           `json:"power_play"`
+	ShortHanded string           `json:"short_handed"`
+	EmptyNet    string           `json:"empty_net"`
+	PenaltyShot string           `json:"penalty_shot"`
+	GoalScorer  HockeyTechPlayer `json:"goal_scorer"`
+	Assist1     HockeyTechPlayer `json:"assist1_player"`
+	Assist2     HockeyTechPlayer `json:"assist2_player"`
+}
+
+type HockeyTechPlayer struct {
+	PlayerID     string `json:"player_id"`
+	JerseyNumber string `json:"jersey_number"`
+	FirstName    string `json:"first_name"`
+	LastName     string `json:"last_name"`
+}
+
+// HockeyTechShootoutDetails lists each team's shootout attempts in order.
+type HockeyTechShootoutDetails struct {
+	HomeShots     []HockeyTechShootoutShot `json:"homeShots"`
+	VisitingShots []HockeyTechShootoutShot `json:"visitingShots"`
+	WinningTeamID string                   `json:"winningTeamId"`
+}
+
+type HockeyTechShootoutShot struct {
+	Shooter HockeyTechPlayer `json:"shooter"`
+	// IsGoal is "1" for a goal and "0" otherwise
+	IsGoal string `json:"isGoal"`
+}
@@ -1,6 +1,7 @@
 package iihf
 
 import (
+	"encoding/json"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -53,3 +54,16 @@ func TestMockIIHFApiClient_SetScores(t *testing.T) {
 		client.SetAwayScore(2)
 	})
 }
+
+func TestFlexInt_ScoresAsStrings(t *testing.T) {
+	var score IIHFScore
+	assert.NoError(t, json.Unmarshal([]byte(`{"Home": "3", "Away": 1}`), &score))
+	assert.Equal(t, FlexInt(3), score.Home)
+	assert.Equal(t, FlexInt(1), score.Away)
+
+	var team IIHFScheduleTeam
+	assert.NoError(t, json.Unmarshal([]byte(`{"Points": "", "TeamCode": "CAN"}`), &team))
+	assert.Equal(t, FlexInt(0), team.Points)
+
+	assert.Error(t, json.Unmarshal([]byte(`{"Home": "two"}`), &score))
+}
@@ -25,7 +25,7 @@ func (c MockIIHFApiClient) GetIIHFSchedule(sEventId string) IIHFScheduleResponse
 func (c MockIIHFApiClient) GetIIHFScoreBoard(sGameId string) IIHFGameScoreResponse {
 	var response IIHFGameScoreResponse
 	json.Unmarshal([]byte(ActiveGameScoreboard), &response)
-	response.CurrentScore.Away = awayScore
-	response.CurrentScore.Home = homeScore
+	response.CurrentScore.Away = FlexInt(awayScore)
+	response.CurrentScore.Home = FlexInt(homeScore)
 	return response
 }
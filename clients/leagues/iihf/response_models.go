@@ -1,9 +1,48 @@
 package iihf
 
 import (
+	"encoding/json"
+	"fmt"
+	"strconv"
 	"time"
 )
 
+// FlexInt unmarshals a JSON int OR a JSON string containing digits into an
+// int. The IIHF feeds send scores and points as strings ("Home": "2"), which
+// a plain int field rejects with a type-mismatch error, leaving the score at
+// zero. FlexInt accepts both encodings.
+type FlexInt int
+
+func (f *FlexInt) UnmarshalJSON(data []byte) error {
+	var n int
+	if err := json.Unmarshal(data, &n); err == nil {
+		*f = FlexInt(n)
+		return nil
+	}
+	var s string
+	if err := json.Unmarshal(data, &s); err == nil {
+		if s == "" {
+			*f = 0
+			return nil
+		}
+		v, err := strconv.Atoi(s)
+		if err != nil {
+			return fmt.Errorf("iihf: FlexInt: cannot parse %q as int: %w", s, err)
+		}
+		*f = FlexInt(v)
+		return nil
+	}
+	if string(data) == "null" {
+		*f = 0
+		return nil
+	}
+	return fmt.Errorf("iihf: FlexInt: value is neither a number nor a numeric string: %s", string(data))
+}
+
+func (f FlexInt) MarshalJSON() ([]byte, error) {
+	return json.Marshal(int(f))
+}
+
 type IIHFScheduleResponseGame struct {
 	GuestTeam       IIHFScheduleTeam `json:"GuestTeam"`
 	HomeTeam        IIHFScheduleTeam `json:"HomeTeam"`
@@ -15,18 +54,22 @@ type IIHFScheduleResponseGame struct {
 }
 
 type IIHFScheduleTeam struct {
-	Points   int    `json:"Points"`
-	TeamCode string `json:"TeamCode"`
+	Points   FlexInt `json:"Points"`
+	TeamCode string  `json:"TeamCode"`
 }
 
 type IIHFScheduleResponse []IIHFScheduleResponseGame
 
+// IIHFGameScoreResponse is the live game state. Status is the feed's text
+// for where the game is ("Period 1", "Overtime", "Game Winning Shots",
+// "Final", ...), and GameTime holds the period clock.
 type IIHFGameScoreResponse struct {
-	GameID          string `json:"GameId"`
-	GameNumber      string `json:"GameNumber"`
-	EventID         string `json:"EventId"`
-	Status          string `json:"Status"`
-	IsGameCompleted bool   `json:"IsGameCompleted"`
+	GameID          string       `json:"GameId"`
+	GameNumber      string       `json:"GameNumber"`
+	EventID         string       `json:"EventId"`
+	Status          string       `json:"Status"`
+	GameTime        IIHFGameTime `json:"GameTime"`
+	IsGameCompleted bool         `json:"IsGameCompleted"`
 	HomeTeam        struct {
 		ShortTeamName string `json:"ShortTeamName"`
 		LongTeamName  string `json:"LongTeamName"`
@@ -37,8 +80,64 @@ type IIHFGameScoreResponse struct {
 		LongTeamName  string `json:"LongTeamName"`
 		Color         string `json:"Color"`
 	} `json:"AwayTeam"`
-	CurrentScore struct {
-		Home int `json:"Home"`
-		Away int `json:"Away"`
-	} `json:"CurrentScore"`
+	CurrentScore IIHFScore    `json:"CurrentScore"`
+	Periods      []IIHFPeriod `json:"Periods"`
+}
+
+type IIHFScore struct {
+	Home FlexInt `json:"Home"`
+	Away FlexInt `json:"Away"`
+}
+
+// IIHFGameTime is the period clock. PlayTime is how far into the period play
+// has got, either as whole minutes ("6") or minutes and seconds ("06:12"),
+// and TimeMaxvalue is the length of the period in minutes.
+type IIHFGameTime struct {
+	TimedGameStatus string `json:"TimedGameStatus"`
+	PlayTime        string `json:"PlayTime"`
+	Time            string `json:"Time"`
+	TimeMaxvalue    string `json:"TimeMaxvalue"`
+}
+
+// IIHFPeriod is one entry of the game state's Periods list. PeriodCode is
+// "1".."3" for regulation, "OT" for overtime, "GWS" for the game winning
+// shots (shootout) and "TOT" for the totals pseudo-period.
+type IIHFPeriod struct {
+	Type       int          `json:"Type"`
+	PeriodCode string       `json:"PeriodCode"`
+	Score      IIHFScore    `json:"Score"`
+	Actions    []IIHFAction `json:"Actions"`
+}
+
+// IIHFAction is a single play-by-play action within a period. TimeOfPlay is
+// the time elapsed in the period. Goals name their assists in Assistant1 and
+// Assistant2, and SituationType gives the strength they were scored at
+// ("EQ", "PP1", "SH1", "EN", ...).
+type IIHFAction struct {
+	ID                      string      `json:"Id"`
+	FullTypeName            string      `json:"FullTypeName"`
+	Code                    string      `json:"Code"`
+	TimeOfPlay              string      `json:"TimeOfPlay"`
+	IsExecutedByHomeTeam    bool        `json:"IsExecutedByHomeTeam"`
+	ExecutedByShortTeamName string      `json:"ExecutedByShortTeamName"`
+	Athlete                 IIHFAthlete `json:"Athlete"`
+	Assistant1              IIHFAthlete `json:"Assistant1"`
+	Assistant2              IIHFAthlete `json:"Assistant2"`
+	Displays                struct {
+		Default struct {
+			Title       string `json:"Title"`
+			Description string `json:"Description"`
+		} `json:"_default"`
+	} `json:"Displays"`
+	SituationType string `json:"SituationType"`
+}
+
+type IIHFAthlete struct {
+	AthleteID     string `json:"IH_Athlete_Id"`
+	NOCCode       string `json:"NOC_Code"`
+	Number        string `json:"Number"`
+	Position      string `json:"Position"`
+	ReportingName string `json:"ReportingName"`
+	FamilyName    string `json:"FamilyName"`
+	GivenName     string `json:"GivenName"`
 }
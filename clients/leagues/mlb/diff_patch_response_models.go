@@ -7,9 +7,12 @@ import (
 type MLBDiffSet struct {
 	Diff []MLBDiffItem `json:"diff"`
 }
+
+// MLBDiffItem is one RFC 6902 operation against the live feed document.
 type MLBDiffItem struct {
 	Path  string          `json:"path"`
 	Op    string          `json:"op"`
+	From  string          `json:"from,omitempty"`
 	Value json.RawMessage `json:"value"`
 }
 type MLBDiffPatch []MLBDiffSet
@@ -1,9 +1,12 @@
 package mlb
 
+import "encoding/json"
+
 type IMLBApiClient interface {
 	GetMLBSchedule() MLBScheduleResponse
 	GetMLBScheduleByDate(date string) MLBScheduleResponse
 	GetMLBScoreBoard(sGameId string) MLBScoreboardResponse
+	GetMLBLiveFeed(sGameId string) (json.RawMessage, error)
 	GetDiffPatch(gameId string, timestamp string) (MLBDiffPatch, error)
 	GetTeam(sLink string) MLBTeamResponse
 	GetAllTeams() MLBTeamResponse
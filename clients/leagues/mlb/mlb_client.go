@@ -24,6 +24,20 @@ func (c MLBApiClient) GetMLBScoreBoard(gameId string) MLBScoreboardResponse {
 	return response
 }
 
+// GetMLBLiveFeed returns the raw live feed document, for callers that keep
+// it current by applying diff patches
+func (c MLBApiClient) GetMLBLiveFeed(gameId string) (json.RawMessage, error) {
+	var body chan []byte = make(chan []byte)
+	url := fmt.Sprintf("https://statsapi.mlb.com/api/v1.1/game/%s/feed/live", gameId)
+	go fetchByte(url, body)
+
+	bodyByte := <-body
+	if !json.Valid(bodyByte) {
+		return nil, fmt.Errorf("invalid live feed for game %s", gameId)
+	}
+	return bodyByte, nil
+}
+
 func (c MLBApiClient) GetMLBSchedule() MLBScheduleResponse {
 	var body chan []byte = make(chan []byte)
 
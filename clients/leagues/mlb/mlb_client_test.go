@@ -133,3 +133,18 @@ func TestMockMLBApiClient_GetAllTeams(t *testing.T) {
 	// Verify the response is valid
 	assert.NotNil(t, response)
 }
+
+func TestMLBApiClient_GetMLBLiveFeed(t *testing.T) {
+	restore := withStubFetchMLB(t, json.RawMessage(`{"gamePk":745804,"metaData":{"timeStamp":"20240401_180000"}}`))
+	defer restore()
+	raw, err := MLBApiClient{}.GetMLBLiveFeed("745804")
+	assert.NoError(t, err)
+	assert.JSONEq(t, `{"gamePk":745804,"metaData":{"timeStamp":"20240401_180000"}}`, string(raw))
+}
+
+func TestMLBApiClient_GetMLBLiveFeed_EmptyResponse(t *testing.T) {
+	restore := withStubFetchMLBError(t)
+	defer restore()
+	_, err := MLBApiClient{}.GetMLBLiveFeed("745804")
+	assert.Error(t, err)
+}
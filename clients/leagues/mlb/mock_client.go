@@ -36,6 +36,10 @@ func (c MockMLBApiClient) GetMLBScoreBoard(sGameId string) MLBScoreboardResponse
 	return response
 }
 
+func (c MockMLBApiClient) GetMLBLiveFeed(sGameId string) (json.RawMessage, error) {
+	return json.Marshal(c.GetMLBScoreBoard(sGameId))
+}
+
 func (c MockMLBApiClient) GetTeam(sLink string) MLBTeamResponse {
 	var response MLBTeamResponse
 	json.Unmarshal([]byte(TeamResponseJson), &response)
@@ -114,10 +114,13 @@ type Details struct {
 	Hasreview     bool   `json:"hasReview"`
 }
 type Result struct {
-	Type      string `json:"type"`
-	Rbi       int    `json:"rbi"`
-	AwayScore int    `json:"awayScore"`
-	HomeScore int    `json:"homeScore"`
+	Type        string `json:"type"`
+	Event       string `json:"event"`
+	EventType   string `json:"eventType"`
+	Description string `json:"description"`
+	Rbi         int    `json:"rbi"`
+	AwayScore   int    `json:"awayScore"`
+	HomeScore   int    `json:"homeScore"`
 }
 type About struct {
 	Atbatindex       int       `json:"atBatIndex"`
@@ -167,10 +170,29 @@ type Boxscore struct {
 	Pitchingnotes []interface{} `json:"pitchingNotes"`
 }
 type LiveData struct {
+	Plays     Plays     `json:"plays"`
 	Linescore Linescore `json:"linescore"`
 	Boxscore  Boxscore  `json:"boxscore"`
 }
 
+// Plays holds the live feed's at-bats. AllPlays is indexed by
+// About.Atbatindex; the last entry is the at-bat in progress.
+type Plays struct {
+	AllPlays    []Play `json:"allPlays"`
+	CurrentPlay Play   `json:"currentPlay"`
+}
+
+type Play struct {
+	Result  Result  `json:"result"`
+	About   About   `json:"about"`
+	Matchup Matchup `json:"matchup"`
+}
+
+type Matchup struct {
+	Batter  Person `json:"batter"`
+	Pitcher Person `json:"pitcher"`
+}
+
 // Offense describes the current offense context including runners on base
 type Offense struct {
 	Batter  *OffenseRunner `json:"batter,omitempty"`
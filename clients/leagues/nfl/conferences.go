@@ -0,0 +1,28 @@
+package nfl
+
+// conferenceNames maps ESPN's FBS conference IDs ("conferenceId" on a
+// scoreboard team) to the names used in watch lists.
+var conferenceNames = map[string]string{
+	"1":   "ACC",
+	"4":   "Big 12",
+	"5":   "Big Ten",
+	"8":   "SEC",
+	"9":   "Pac-12",
+	"12":  "Conference USA",
+	"15":  "MAC",
+	"17":  "Mountain West",
+	"18":  "FBS Independents",
+	"37":  "Sun Belt",
+	"151": "American",
+}
+
+// ConferenceName returns the name of an ESPN FBS conference ID, or the ID
+// itself for conferences without a name here. A team with no conference
+// gets "". Other sports number their conferences differently, so this is
+// for football only.
+func ConferenceName(id string) string {
+	if name, ok := conferenceNames[id]; ok {
+		return name
+	}
+	return id
+}
@@ -75,6 +75,7 @@ func (c NFLMockClient) GetNFLSchedule() NFLScheduleResponse {
 									Color            string `json:"color"`
 									AlternateColor   string `json:"alternateColor"`
 									IsActive         bool   `json:"isActive"`
+									ConferenceID     string `json:"conferenceId"`
 									Venue            struct {
 										ID string `json:"id"`
 									} `json:"venue"`
@@ -117,6 +118,7 @@ func (c NFLMockClient) GetNFLSchedule() NFLScheduleResponse {
 									Color            string `json:"color"`
 									AlternateColor   string `json:"alternateColor"`
 									IsActive         bool   `json:"isActive"`
+									ConferenceID     string `json:"conferenceId"`
 									Venue            struct {
 										ID string `json:"id"`
 									} `json:"venue"`
@@ -242,6 +244,7 @@ func (c NFLMockClient) GetNFLSchedule() NFLScheduleResponse {
 									Color            string `json:"color"`
 									AlternateColor   string `json:"alternateColor"`
 									IsActive         bool   `json:"isActive"`
+									ConferenceID     string `json:"conferenceId"`
 									Venue            struct {
 										ID string `json:"id"`
 									} `json:"venue"`
@@ -284,6 +287,7 @@ func (c NFLMockClient) GetNFLSchedule() NFLScheduleResponse {
 									Color            string `json:"color"`
 									AlternateColor   string `json:"alternateColor"`
 									IsActive         bool   `json:"isActive"`
+									ConferenceID     string `json:"conferenceId"`
 									Venue            struct {
 										ID string `json:"id"`
 									} `json:"venue"`
@@ -425,6 +429,7 @@ func (c NFLMockClient) GetNFLScoreBoard(gameId string) NFLScoreboardResponse {
 										Color            string `json:"color"`
 										AlternateColor   string `json:"alternateColor"`
 										IsActive         bool   `json:"isActive"`
+										ConferenceID     string `json:"conferenceId"`
 										Venue            struct {
 											ID string `json:"id"`
 										} `json:"venue"`
@@ -461,6 +466,7 @@ func (c NFLMockClient) GetNFLScoreBoard(gameId string) NFLScoreboardResponse {
 										Color            string `json:"color"`
 										AlternateColor   string `json:"alternateColor"`
 										IsActive         bool   `json:"isActive"`
+										ConferenceID     string `json:"conferenceId"`
 										Venue            struct {
 											ID string `json:"id"`
 										} `json:"venue"`
@@ -569,6 +575,7 @@ func (c NFLMockClient) GetNFLScoreBoard(gameId string) NFLScoreboardResponse {
 										Color            string `json:"color"`
 										AlternateColor   string `json:"alternateColor"`
 										IsActive         bool   `json:"isActive"`
+										ConferenceID     string `json:"conferenceId"`
 										Venue            struct {
 											ID string `json:"id"`
 										} `json:"venue"`
@@ -605,6 +612,7 @@ func (c NFLMockClient) GetNFLScoreBoard(gameId string) NFLScoreboardResponse {
 										Color            string `json:"color"`
 										AlternateColor   string `json:"alternateColor"`
 										IsActive         bool   `json:"isActive"`
+										ConferenceID     string `json:"conferenceId"`
 										Venue            struct {
 											ID string `json:"id"`
 										} `json:"venue"`
@@ -713,6 +721,7 @@ func (c NFLMockClient) GetNFLScoreBoard(gameId string) NFLScoreboardResponse {
 										Color            string `json:"color"`
 										AlternateColor   string `json:"alternateColor"`
 										IsActive         bool   `json:"isActive"`
+										ConferenceID     string `json:"conferenceId"`
 										Venue            struct {
 											ID string `json:"id"`
 										} `json:"venue"`
@@ -749,6 +758,7 @@ func (c NFLMockClient) GetNFLScoreBoard(gameId string) NFLScoreboardResponse {
 										Color            string `json:"color"`
 										AlternateColor   string `json:"alternateColor"`
 										IsActive         bool   `json:"isActive"`
+										ConferenceID     string `json:"conferenceId"`
 										Venue            struct {
 											ID string `json:"id"`
 										} `json:"venue"`
@@ -828,6 +838,7 @@ func (c NFLMockClient) GetNFLScoreBoard(gameId string) NFLScoreboardResponse {
 									Color            string `json:"color"`
 									AlternateColor   string `json:"alternateColor"`
 									IsActive         bool   `json:"isActive"`
+									ConferenceID     string `json:"conferenceId"`
 									Venue            struct {
 										ID string `json:"id"`
 									} `json:"venue"`
@@ -864,6 +875,7 @@ func (c NFLMockClient) GetNFLScoreBoard(gameId string) NFLScoreboardResponse {
 									Color            string `json:"color"`
 									AlternateColor   string `json:"alternateColor"`
 									IsActive         bool   `json:"isActive"`
+									ConferenceID     string `json:"conferenceId"`
 									Venue            struct {
 										ID string `json:"id"`
 									} `json:"venue"`
@@ -926,9 +938,7 @@ func (c NFLMockClient) GetNFLScoreBoard(gameId string) NFLScoreboardResponse {
 				},
 			},
 		},
-		Drives: struct {
-			Current DriveCurrent `json:"current"`
-		}{
+		Drives: NFLDrives{
 			Current: DriveCurrent{
 				ID:          "D1",
 				Description: "Drive start",
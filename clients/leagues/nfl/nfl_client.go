@@ -6,15 +6,47 @@ import (
 	"goalfeed/utils"
 )
 
+// ESPN sport/league paths the client can read. The scoreboard and summary
+// documents share one shape across them, so college football and college
+// hockey are read with the same models as the NFL.
+const (
+	LeagueNFL         = "football/nfl"
+	LeagueNCAAF       = "football/college-football"
+	LeagueNCAAMHockey = "hockey/mens-college-hockey"
+	LeagueNCAAWHockey = "hockey/womens-college-hockey"
+)
+
+// fbsGroup is ESPN's group for FBS; without it the college football
+// scoreboard only lists games involving ranked teams.
+const fbsGroup = "80"
+
+// NFLAPIClient reads ESPN's site API for League, one of the League*
+// constants; the zero value reads the NFL.
 type NFLAPIClient struct {
+	League string
 }
 
 // fetchByte allows tests to stub the HTTP fetcher
 var fetchByte = utils.GetByte
 
+func (c NFLAPIClient) baseURL() string {
+	league := c.League
+	if league == "" {
+		league = LeagueNFL
+	}
+	return "https://site.api.espn.com/apis/site/v2/sports/" + league
+}
+
+func (c NFLAPIClient) scoreboardURL() string {
+	if c.League == LeagueNCAAF {
+		return c.baseURL() + "/scoreboard?groups=" + fbsGroup
+	}
+	return c.baseURL() + "/scoreboard"
+}
+
 func (c NFLAPIClient) GetNFLSchedule() NFLScheduleResponse {
 	var body chan []byte = make(chan []byte)
-	url := "https://site.api.espn.com/apis/site/v2/sports/football/nfl/scoreboard"
+	url := c.scoreboardURL()
 	go fetchByte(url, body)
 
 	bodyByte := <-body
@@ -26,7 +58,11 @@ func (c NFLAPIClient) GetNFLSchedule() NFLScheduleResponse {
 func (c NFLAPIClient) GetNFLScheduleByDate(date string) NFLScheduleResponse {
 	var body chan []byte = make(chan []byte)
 	// Format: YYYYMMDD
-	url := fmt.Sprintf("https://site.api.espn.com/apis/site/v2/sports/football/nfl/scoreboard?dates=%s", date)
+	separator := "?"
+	if c.League == LeagueNCAAF {
+		separator = "&"
+	}
+	url := fmt.Sprintf("%s%sdates=%s", c.scoreboardURL(), separator, date)
 	go fetchByte(url, body)
 
 	bodyByte := <-body
@@ -37,7 +73,7 @@ func (c NFLAPIClient) GetNFLScheduleByDate(date string) NFLScheduleResponse {
 
 func (c NFLAPIClient) GetNFLScoreBoard(gameId string) NFLScoreboardResponse {
 	var body chan []byte = make(chan []byte)
-	url := fmt.Sprintf("https://site.api.espn.com/apis/site/v2/sports/football/nfl/summary?event=%s", gameId)
+	url := fmt.Sprintf("%s/summary?event=%s", c.baseURL(), gameId)
 	go fetchByte(url, body)
 
 	bodyByte := <-body
@@ -48,7 +84,7 @@ func (c NFLAPIClient) GetNFLScoreBoard(gameId string) NFLScoreboardResponse {
 
 func (c NFLAPIClient) GetTeam(teamAbbr string) NFLTeamResponse {
 	var body chan []byte = make(chan []byte)
-	url := fmt.Sprintf("https://site.api.espn.com/apis/site/v2/sports/football/nfl/teams/%s", teamAbbr)
+	url := fmt.Sprintf("%s/teams/%s", c.baseURL(), teamAbbr)
 	go fetchByte(url, body)
 
 	bodyByte := <-body
@@ -59,7 +95,7 @@ func (c NFLAPIClient) GetTeam(teamAbbr string) NFLTeamResponse {
 
 func (c NFLAPIClient) GetAllTeams() NFLTeamResponse {
 	var body chan []byte = make(chan []byte)
-	url := "https://site.api.espn.com/apis/site/v2/sports/football/nfl/teams"
+	url := c.baseURL() + "/teams"
 	go fetchByte(url, body)
 
 	bodyByte := <-body
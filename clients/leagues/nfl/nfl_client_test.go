@@ -53,3 +53,44 @@ func TestNFLClient_AllTeams(t *testing.T) {
 		t.Fatalf("unexpected all teams resp: %+v", resp)
 	}
 }
+
+func TestNFLClient_LeagueURLs(t *testing.T) {
+	var urls []string
+	old := fetchByte
+	fetchByte = func(url string, ret chan []byte) {
+		urls = append(urls, url)
+		ret <- []byte(`{}`)
+	}
+	defer func() { fetchByte = old }()
+
+	NFLAPIClient{}.GetNFLSchedule()
+	NFLAPIClient{League: LeagueNCAAF}.GetNFLSchedule()
+	NFLAPIClient{League: LeagueNCAAF}.GetNFLScheduleByDate("20261017")
+	NFLAPIClient{League: LeagueNCAAMHockey}.GetNFLScheduleByDate("20261017")
+	NFLAPIClient{League: LeagueNCAAWHockey}.GetNFLScoreBoard("401")
+
+	want := []string{
+		"https://site.api.espn.com/apis/site/v2/sports/football/nfl/scoreboard",
+		"https://site.api.espn.com/apis/site/v2/sports/football/college-football/scoreboard?groups=80",
+		"https://site.api.espn.com/apis/site/v2/sports/football/college-football/scoreboard?groups=80&dates=20261017",
+		"https://site.api.espn.com/apis/site/v2/sports/hockey/mens-college-hockey/scoreboard?dates=20261017",
+		"https://site.api.espn.com/apis/site/v2/sports/hockey/womens-college-hockey/summary?event=401",
+	}
+	if len(urls) != len(want) {
+		t.Fatalf("unexpected urls: %v", urls)
+	}
+	for i := range want {
+		if urls[i] != want[i] {
+			t.Fatalf("url %d = %q, want %q", i, urls[i], want[i])
+		}
+	}
+}
+
+func TestConferenceName(t *testing.T) {
+	if ConferenceName("8") != "SEC" || ConferenceName("5") != "Big Ten" {
+		t.Fatal("expected FBS conference names")
+	}
+	if ConferenceName("999") != "999" {
+		t.Fatal("unknown conference IDs are returned as-is")
+	}
+}
@@ -73,6 +73,18 @@ type NFLCompetition struct {
 	Notes       []struct {
 		Headline string `json:"headline"`
 	} `json:"notes"`
+	// Groups names the conference of a college conference game
+	Groups NFLCompetitionGroup `json:"groups"`
+}
+
+// NFLCompetitionGroup models "competitions[].groups", the conference a
+// college game is played in. ESPN only sends it for conference games; its ID
+// is the teams' "conferenceId".
+type NFLCompetitionGroup struct {
+	ID           string `json:"id"`
+	Name         string `json:"name"`
+	ShortName    string `json:"shortName"`
+	IsConference bool   `json:"isConference"`
 }
 
 type NFLCompetitor struct {
@@ -93,6 +105,7 @@ type NFLCompetitor struct {
 		Color            string `json:"color"`
 		AlternateColor   string `json:"alternateColor"`
 		IsActive         bool   `json:"isActive"`
+		ConferenceID     string `json:"conferenceId"`
 		Venue            struct {
 			ID string `json:"id"`
 		} `json:"venue"`
@@ -120,4 +133,20 @@ type NFLCompetitor struct {
 		Type         string `json:"type"`
 		Summary      string `json:"summary"`
 	} `json:"records"`
+	// CuratedRank is the college poll ranking; ESPN reports 99 for an
+	// unranked team and leaves it out for pro leagues.
+	CuratedRank CuratedRank `json:"curatedRank"`
+}
+
+// CuratedRank models a competitor's "curatedRank" on college scoreboards.
+type CuratedRank struct {
+	Current int `json:"current"`
+}
+
+// Rank is the poll ranking, or 0 for an unranked team (ESPN reports 99).
+func (r CuratedRank) Rank() int {
+	if r.Current <= 0 || r.Current >= 99 {
+		return 0
+	}
+	return r.Current
 }
@@ -25,13 +25,42 @@ package nfl
 // and falls back to Events only for compatibility with existing
 // mocks/tests. Events will always be empty for real traffic.
 type NFLScoreboardResponse struct {
-	Leagues []NFLScoreboardLeague `json:"leagues"`
-	Events  []NFLScoreboardEvent  `json:"events"`
-	Drives  struct {
-		Current DriveCurrent `json:"current"`
-	} `json:"drives"`
-	Header   NFLSummaryHeader   `json:"header"`
-	GameInfo NFLSummaryGameInfo `json:"gameInfo"`
+	Leagues      []NFLScoreboardLeague `json:"leagues"`
+	Events       []NFLScoreboardEvent  `json:"events"`
+	Drives       NFLDrives             `json:"drives"`
+	Header       NFLSummaryHeader      `json:"header"`
+	GameInfo     NFLSummaryGameInfo    `json:"gameInfo"`
+	ScoringPlays []NFLScoringPlay      `json:"scoringPlays"`
+}
+
+// NFLScoringPlay models "scoringPlays[]" from the summary endpoint. Each
+// entry is one scoring play in game order, and the scores are the totals
+// once it (including any conversion after a touchdown) was complete. Type
+// names the play ("Passing Touchdown", "Interception Return Touchdown",
+// "Field Goal Good", "Safety", ...) while ScoringType is the coarse kind
+// ("touchdown", "field-goal", "safety").
+type NFLScoringPlay struct {
+	ID   string `json:"id"`
+	Type struct {
+		ID           string `json:"id"`
+		Text         string `json:"text"`
+		Abbreviation string `json:"abbreviation"`
+	} `json:"type"`
+	Text      string `json:"text"`
+	AwayScore int    `json:"awayScore"`
+	HomeScore int    `json:"homeScore"`
+	Period    struct {
+		Number int `json:"number"`
+	} `json:"period"`
+	Clock struct {
+		DisplayValue string `json:"displayValue"`
+	} `json:"clock"`
+	Team        DriveTeam `json:"team"`
+	ScoringType struct {
+		Name         string `json:"name"`
+		DisplayName  string `json:"displayName"`
+		Abbreviation string `json:"abbreviation"`
+	} `json:"scoringType"`
 }
 
 // NFLSummaryHeader models "header" from the real "/summary?event=" payload.
@@ -61,6 +90,7 @@ type NFLSummaryCompetition struct {
 	Date        string                    `json:"date"`
 	Competitors []NFLScoreboardCompetitor `json:"competitors"`
 	Status      NFLSummaryStatus          `json:"status"`
+	Groups      NFLCompetitionGroup       `json:"groups"`
 }
 
 // NFLSummaryStatus models "header.competitions[].status" from the summary
@@ -179,6 +209,7 @@ type NFLScoreboardCompetitor struct {
 		Color            string `json:"color"`
 		AlternateColor   string `json:"alternateColor"`
 		IsActive         bool   `json:"isActive"`
+		ConferenceID     string `json:"conferenceId"`
 		Venue            struct {
 			ID string `json:"id"`
 		} `json:"venue"`
@@ -213,15 +244,37 @@ type NFLScoreboardCompetitor struct {
 		Type         string `json:"type"`
 		Summary      string `json:"summary"`
 	} `json:"records"`
+	// CuratedRank is the college poll ranking; ESPN reports 99 for an
+	// unranked team and leaves it out for pro leagues.
+	CuratedRank CuratedRank `json:"curatedRank"`
 }
 
 // Drives and current drive start info
+//
+// The summary lists the game's finished drives under "drives.previous" and
+// the one in progress under "drives.current", each with its plays. Result
+// is ESPN's short code for how a drive ended ("TD", "FG", "PUNT", "INT",
+// "FUMBLE", "DOWNS", ...), empty while it's in progress. A Fastcast game
+// document has no drives; its "situation" is mapped onto Current.Start.
+
+type NFLDrives struct {
+	Current  DriveCurrent   `json:"current"`
+	Previous []DriveCurrent `json:"previous"`
+}
 
 type DriveCurrent struct {
-	ID          string     `json:"id"`
-	Description string     `json:"description"`
-	Team        DriveTeam  `json:"team"`
-	Start       DriveStart `json:"start"`
+	ID             string     `json:"id"`
+	Description    string     `json:"description"`
+	Team           DriveTeam  `json:"team"`
+	Start          DriveStart `json:"start"`
+	End            DriveStart `json:"end"`
+	TimeElapsed    DriveClock `json:"timeElapsed"`
+	Yards          int        `json:"yards"`
+	IsScore        bool       `json:"isScore"`
+	OffensivePlays int        `json:"offensivePlays"`
+	Result         string     `json:"result"`
+	DisplayResult  string     `json:"displayResult"`
+	Plays          []NFLPlay  `json:"plays"`
 }
 
 type DriveTeam struct {
@@ -230,15 +283,47 @@ type DriveTeam struct {
 	Abbreviation string `json:"abbreviation"`
 }
 
+type DriveClock struct {
+	DisplayValue string `json:"displayValue"`
+}
+
+// DriveStart is a spot on the field: where a drive or play started or
+// ended, or the live situation. YardsToEndzone is measured from the goal
+// line the team with the ball is attacking.
 type DriveStart struct {
 	Down                  int    `json:"down"`
 	Distance              int    `json:"distance"`
 	YardLine              int    `json:"yardLine"`
 	YardsToEndzone        int    `json:"yardsToEndzone"`
+	Text                  string `json:"text"`
 	DownDistanceText      string `json:"downDistanceText"`
 	ShortDownDistanceText string `json:"shortDownDistanceText"`
 	PossessionText        string `json:"possessionText"`
-	Team                  struct {
+	IsRedZone             bool   `json:"isRedZone"`
+	Period                struct {
+		Number int `json:"number"`
+	} `json:"period"`
+	Clock DriveClock `json:"clock"`
+	Team  struct {
 		ID string `json:"id"`
 	} `json:"team"`
 }
+
+// NFLPlay is one play in a drive.
+type NFLPlay struct {
+	ID   string `json:"id"`
+	Type struct {
+		ID           string `json:"id"`
+		Text         string `json:"text"`
+		Abbreviation string `json:"abbreviation"`
+	} `json:"type"`
+	Text   string `json:"text"`
+	Period struct {
+		Number int `json:"number"`
+	} `json:"period"`
+	Clock       DriveClock `json:"clock"`
+	ScoringPlay bool       `json:"scoringPlay"`
+	Start       DriveStart `json:"start"`
+	End         DriveStart `json:"end"`
+	StatYardage int        `json:"statYardage"`
+}
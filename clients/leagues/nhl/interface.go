@@ -4,6 +4,7 @@ type INHLApiClient interface {
 	GetNHLSchedule() NHLScheduleResponse
 	GetNHLScheduleByDate(date string) NHLScheduleResponse
 	GetNHLScoreBoard(sGameId string) NHLScoreboardResponse
+	GetNHLPlayByPlay(sGameId string) NHLPlayByPlayResponse
 	GetTeam(teamAbbr string) NHLTeamResponse
 	GetAllTeams() NHLTeamResponse
 }
@@ -12,6 +12,7 @@ type MockNHLApiClient struct {
 
 var homeScore = 0
 var awayScore = 0
+var playByPlay = NHLPlayByPlayResponse{}
 
 func (m *MockNHLApiClient) SetGameStatus(status string) {
 	m.mockedGameStatus = status
@@ -23,6 +24,14 @@ func (c MockNHLApiClient) SetAwayScore(score int) {
 	awayScore = score
 }
 
+func (c MockNHLApiClient) SetPlayByPlay(response NHLPlayByPlayResponse) {
+	playByPlay = response
+}
+
+func (c MockNHLApiClient) GetNHLPlayByPlay(sGameId string) NHLPlayByPlayResponse {
+	return playByPlay
+}
+
 func (c MockNHLApiClient) GetNHLSchedule() NHLScheduleResponse {
 	var response NHLScheduleResponse
 	c.GetNHLScheduleCalls++
@@ -23,6 +23,17 @@ func (c NHLApiClient) GetNHLScoreBoard(gameId string) NHLScoreboardResponse {
 	return response
 }
 
+func (c NHLApiClient) GetNHLPlayByPlay(gameId string) NHLPlayByPlayResponse {
+	var body chan []byte = make(chan []byte)
+	url := fmt.Sprintf("https://api-web.nhle.com/v1/gamecenter/%s/play-by-play", gameId)
+	go fetchByte(url, body)
+
+	bodyByte := <-body
+	var response NHLPlayByPlayResponse
+	json.Unmarshal(bodyByte, &response)
+	return response
+}
+
 func (c NHLApiClient) GetNHLSchedule() NHLScheduleResponse {
 	var body chan []byte = make(chan []byte)
 	url := "https://api-web.nhle.com/v1/schedule/now" // Updated URL
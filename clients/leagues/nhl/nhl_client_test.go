@@ -135,3 +135,23 @@ func TestMockNHLApiClient_GetAllTeams(t *testing.T) {
 	// Verify the response is valid
 	assert.NotNil(t, response)
 }
+
+func TestNHLApiClient_GetNHLPlayByPlay(t *testing.T) {
+	restore := withStubFetchNHL(t, json.RawMessage(`{
+		"id": 2023020001,
+		"homeTeam": {"id": 52, "abbrev": "WPG"},
+		"awayTeam": {"id": 10, "abbrev": "TOR"},
+		"plays": [
+			{"eventId": 12, "periodDescriptor": {"number": 3, "periodType": "REG"}, "timeRemaining": "01:12",
+			 "situationCode": "0651", "typeDescKey": "goal", "details": {"eventOwnerTeamId": 52, "scoringPlayerId": 8478398}}
+		],
+		"rosterSpots": [{"teamId": 52, "playerId": 8478398, "firstName": {"default": "Kyle"}, "lastName": {"default": "Connor"}, "sweaterNumber": 81}]
+	}`))
+	defer restore()
+	resp := NHLApiClient{}.GetNHLPlayByPlay("2023020001")
+	assert.Equal(t, "WPG", string(resp.HomeTeam.Abbrev))
+	assert.Len(t, resp.Plays, 1)
+	assert.Equal(t, "0651", resp.Plays[0].SituationCode)
+	assert.Equal(t, 52, resp.Plays[0].Details.EventOwnerTeamID)
+	assert.Equal(t, "Connor", string(resp.RosterSpots[0].LastName))
+}
@@ -0,0 +1,50 @@
+package nhl
+
+// NHLPlayByPlayResponse models the gamecenter "/play-by-play" endpoint.
+type NHLPlayByPlayResponse struct {
+	ID          int               `json:"id,omitempty"`
+	GameState   string            `json:"gameState,omitempty"`
+	AwayTeam    NHLPlayByPlayTeam `json:"awayTeam,omitempty"`
+	HomeTeam    NHLPlayByPlayTeam `json:"homeTeam,omitempty"`
+	Plays       []NHLPlay         `json:"plays,omitempty"`
+	RosterSpots []NHLRosterSpot   `json:"rosterSpots,omitempty"`
+}
+
+type NHLPlayByPlayTeam struct {
+	ID     int             `json:"id"`
+	Abbrev LocalizedString `json:"abbrev"`
+}
+
+// NHLPlay is a single play. SituationCode is four digits read as away
+// goalie, away skaters, home skaters, home goalie: "1551" is five-on-five
+// with both goalies in net, "0651" means the away team has pulled its goalie
+// for an extra attacker.
+type NHLPlay struct {
+	EventID          int              `json:"eventId"`
+	PeriodDescriptor PeriodDescriptor `json:"periodDescriptor"`
+	TimeInPeriod     string           `json:"timeInPeriod,omitempty"`
+	TimeRemaining    string           `json:"timeRemaining,omitempty"`
+	SituationCode    string           `json:"situationCode,omitempty"`
+	TypeDescKey      string           `json:"typeDescKey"`
+	SortOrder        int              `json:"sortOrder,omitempty"`
+	Details          NHLPlayDetails   `json:"details,omitempty"`
+}
+
+type NHLPlayDetails struct {
+	EventOwnerTeamID int `json:"eventOwnerTeamId,omitempty"`
+	ScoringPlayerID  int `json:"scoringPlayerId,omitempty"`
+	Assist1PlayerID  int `json:"assist1PlayerId,omitempty"`
+	Assist2PlayerID  int `json:"assist2PlayerId,omitempty"`
+	GoalieInNetID    int `json:"goalieInNetId,omitempty"`
+	AwayScore        int `json:"awayScore,omitempty"`
+	HomeScore        int `json:"homeScore,omitempty"`
+}
+
+type NHLRosterSpot struct {
+	TeamID        int             `json:"teamId"`
+	PlayerID      int             `json:"playerId"`
+	FirstName     LocalizedString `json:"firstName"`
+	LastName      LocalizedString `json:"lastName"`
+	SweaterNumber int             `json:"sweaterNumber,omitempty"`
+	PositionCode  string          `json:"positionCode,omitempty"`
+}
@@ -1,6 +1,7 @@
 package nhl
 
 import (
+	"encoding/json"
 	"time"
 )
 
@@ -33,4 +34,52 @@ type NHLScoreboardResponse struct {
 	TicketsLink       string           `json:"ticketsLink,omitempty"`
 	PeriodDescriptor  PeriodDescriptor `json:"periodDescriptor,omitempty"`
 	Clock             Clock            `json:"clock,omitempty"`
+	GameOutcome       GameOutcome      `json:"gameOutcome,omitempty"`
+	Summary           GameSummary      `json:"summary,omitempty"`
+}
+
+// GameOutcome is present once a game is final; LastPeriodType is "REG",
+// "OT" or "SO" and tells us how the game was decided.
+type GameOutcome struct {
+	LastPeriodType string `json:"lastPeriodType,omitempty"`
+}
+
+// GameSummary models the landing endpoint's "summary" block.
+type GameSummary struct {
+	Shootout []ShootoutAttempt `json:"shootout,omitempty"`
+}
+
+// ShootoutAttempt is one entry of summary.shootout, in shooting order.
+// Result is "goal", "save" or "miss".
+type ShootoutAttempt struct {
+	Sequence   int             `json:"sequence"`
+	PlayerID   int             `json:"playerId"`
+	TeamAbbrev LocalizedString `json:"teamAbbrev"`
+	FirstName  LocalizedString `json:"firstName"`
+	LastName   LocalizedString `json:"lastName"`
+	ShotType   string          `json:"shotType,omitempty"`
+	Result     string          `json:"result"`
+	GameWinner bool            `json:"gameWinner,omitempty"`
+}
+
+// LocalizedString accepts either a plain JSON string or the NHL API's
+// {"default": "..."} localized object. The API uses both forms for the same
+// fields depending on endpoint, and a mismatch would otherwise fail the whole
+// landing payload.
+type LocalizedString string
+
+func (l *LocalizedString) UnmarshalJSON(data []byte) error {
+	var s string
+	if err := json.Unmarshal(data, &s); err == nil {
+		*l = LocalizedString(s)
+		return nil
+	}
+	var localized struct {
+		Default string `json:"default"`
+	}
+	if err := json.Unmarshal(data, &localized); err != nil {
+		return err
+	}
+	*l = LocalizedString(localized.Default)
+	return nil
 }
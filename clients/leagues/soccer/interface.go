@@ -0,0 +1,7 @@
+package soccer
+
+type ISoccerApiClient interface {
+	GetSchedule() SoccerScheduleResponse
+	GetScheduleByDate(date string) SoccerScheduleResponse
+	GetSummary(gameId string) SoccerSummaryResponse
+}
@@ -0,0 +1,18 @@
+package soccer
+
+type MockSoccerApiClient struct {
+	ScheduleResponse SoccerScheduleResponse
+	SummaryResponse  SoccerSummaryResponse
+}
+
+func (m MockSoccerApiClient) GetSchedule() SoccerScheduleResponse {
+	return m.ScheduleResponse
+}
+
+func (m MockSoccerApiClient) GetScheduleByDate(date string) SoccerScheduleResponse {
+	return m.ScheduleResponse
+}
+
+func (m MockSoccerApiClient) GetSummary(gameId string) SoccerSummaryResponse {
+	return m.SummaryResponse
+}
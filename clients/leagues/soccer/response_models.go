@@ -0,0 +1,126 @@
+package soccer
+
+// SoccerScheduleResponse is ESPN's scoreboard for a day of matches
+type SoccerScheduleResponse struct {
+	Events []SoccerEvent `json:"events"`
+}
+
+type SoccerEvent struct {
+	ID           string              `json:"id"`
+	Date         string              `json:"date"`
+	Name         string              `json:"name"`
+	ShortName    string              `json:"shortName"`
+	Season       SoccerSeason        `json:"season"`
+	Competitions []SoccerCompetition `json:"competitions"`
+	Status       SoccerStatus        `json:"status"`
+}
+
+type SoccerSeason struct {
+	Year int    `json:"year"`
+	Slug string `json:"slug"`
+}
+
+type SoccerCompetition struct {
+	ID          string             `json:"id"`
+	Date        string             `json:"date"`
+	Venue       SoccerVenue        `json:"venue"`
+	Competitors []SoccerCompetitor `json:"competitors"`
+	Status      SoccerStatus       `json:"status"`
+}
+
+type SoccerVenue struct {
+	ID       string `json:"id"`
+	FullName string `json:"fullName"`
+	Address  struct {
+		City    string `json:"city"`
+		Country string `json:"country"`
+	} `json:"address"`
+}
+
+// SoccerCompetitor is one side of a match. ShootoutScore is only set once a
+// match goes to penalties; Score doesn't include it.
+type SoccerCompetitor struct {
+	ID            string     `json:"id"`
+	HomeAway      string     `json:"homeAway"`
+	Winner        bool       `json:"winner"`
+	Score         string     `json:"score"`
+	ShootoutScore int        `json:"shootoutScore"`
+	Team          SoccerTeam `json:"team"`
+}
+
+// SoccerTeam carries a single logo on the scoreboard and a list of them in a
+// summary.
+type SoccerTeam struct {
+	ID           string `json:"id"`
+	Location     string `json:"location"`
+	Name         string `json:"name"`
+	Abbreviation string `json:"abbreviation"`
+	DisplayName  string `json:"displayName"`
+	Color        string `json:"color"`
+	Logo         string `json:"logo"`
+	Logos        []struct {
+		Href string `json:"href"`
+	} `json:"logos"`
+}
+
+// SoccerStatus is a match's status. DisplayClock is the match minute, with
+// stoppage time after a plus ("45'+2'").
+type SoccerStatus struct {
+	Clock        float64 `json:"clock"`
+	DisplayClock string  `json:"displayClock"`
+	Period       int     `json:"period"`
+	Type         struct {
+		ID          string `json:"id"`
+		Name        string `json:"name"`
+		State       string `json:"state"`
+		Completed   bool   `json:"completed"`
+		Description string `json:"description"`
+		Detail      string `json:"detail"`
+		ShortDetail string `json:"shortDetail"`
+	} `json:"type"`
+}
+
+// SoccerSummaryResponse is ESPN's summary of one match. The score and status
+// live at header.competitions[0]; keyEvents lists the goals, cards,
+// substitutions and the like so far, in order.
+type SoccerSummaryResponse struct {
+	Header struct {
+		ID           string              `json:"id"`
+		Season       SoccerSeason        `json:"season"`
+		Competitions []SoccerCompetition `json:"competitions"`
+	} `json:"header"`
+	GameInfo struct {
+		Venue SoccerVenue `json:"venue"`
+	} `json:"gameInfo"`
+	KeyEvents []SoccerKeyEvent `json:"keyEvents"`
+}
+
+type SoccerKeyEvent struct {
+	ID   string `json:"id"`
+	Type struct {
+		ID   string `json:"id"`
+		Text string `json:"text"`
+		Type string `json:"type"`
+	} `json:"type"`
+	Text   string `json:"text"`
+	Period struct {
+		Number int `json:"number"`
+	} `json:"period"`
+	Clock struct {
+		Value        float64 `json:"value"`
+		DisplayValue string  `json:"displayValue"`
+	} `json:"clock"`
+	ScoringPlay bool `json:"scoringPlay"`
+	// Shootout is set on kicks taken in a penalty shootout
+	Shootout bool `json:"shootout"`
+	Team     struct {
+		ID          string `json:"id"`
+		DisplayName string `json:"displayName"`
+	} `json:"team"`
+	Participants []struct {
+		Athlete struct {
+			ID          string `json:"id"`
+			DisplayName string `json:"displayName"`
+		} `json:"athlete"`
+	} `json:"participants"`
+}
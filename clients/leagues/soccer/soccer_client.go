@@ -0,0 +1,58 @@
+package soccer
+
+import (
+	"encoding/json"
+	"fmt"
+	"goalfeed/utils"
+)
+
+// ESPN's league slugs, used in its soccer URLs
+const (
+	LeagueEPL = "eng.1"
+	LeagueMLS = "usa.1"
+	LeagueUCL = "uefa.champions"
+)
+
+// SoccerApiClient reads ESPN's scoreboard and match summaries for one soccer
+// competition, named by its slug in League.
+type SoccerApiClient struct {
+	League string
+}
+
+// fetchByte allows tests to stub the HTTP fetcher
+var fetchByte = utils.GetByte
+
+func (c SoccerApiClient) baseURL() string {
+	return fmt.Sprintf("https://site.api.espn.com/apis/site/v2/sports/soccer/%s", c.League)
+}
+
+func (c SoccerApiClient) GetSchedule() SoccerScheduleResponse {
+	var body chan []byte = make(chan []byte)
+	go fetchByte(c.baseURL()+"/scoreboard", body)
+
+	bodyByte := <-body
+	var response SoccerScheduleResponse
+	json.Unmarshal(bodyByte, &response)
+	return response
+}
+
+func (c SoccerApiClient) GetScheduleByDate(date string) SoccerScheduleResponse {
+	var body chan []byte = make(chan []byte)
+	// Format: YYYYMMDD
+	go fetchByte(fmt.Sprintf("%s/scoreboard?dates=%s", c.baseURL(), date), body)
+
+	bodyByte := <-body
+	var response SoccerScheduleResponse
+	json.Unmarshal(bodyByte, &response)
+	return response
+}
+
+func (c SoccerApiClient) GetSummary(gameId string) SoccerSummaryResponse {
+	var body chan []byte = make(chan []byte)
+	go fetchByte(fmt.Sprintf("%s/summary?event=%s", c.baseURL(), gameId), body)
+
+	bodyByte := <-body
+	var response SoccerSummaryResponse
+	json.Unmarshal(bodyByte, &response)
+	return response
+}
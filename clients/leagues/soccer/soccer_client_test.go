@@ -0,0 +1,54 @@
+package soccer
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func withStubFetch(t *testing.T, payload interface{}) *string {
+	t.Helper()
+	old := fetchByte
+	var requested string
+	b, _ := json.Marshal(payload)
+	fetchByte = func(url string, ret chan []byte) {
+		requested = url
+		ret <- b
+	}
+	t.Cleanup(func() { fetchByte = old })
+	return &requested
+}
+
+func TestSoccerClient_Schedule(t *testing.T) {
+	url := withStubFetch(t, SoccerScheduleResponse{Events: []SoccerEvent{{ID: "1"}}})
+	resp := SoccerApiClient{League: LeagueMLS}.GetScheduleByDate("20250712")
+	if len(resp.Events) != 1 || resp.Events[0].ID != "1" {
+		t.Fatalf("unexpected schedule resp: %+v", resp)
+	}
+	if *url != "https://site.api.espn.com/apis/site/v2/sports/soccer/usa.1/scoreboard?dates=20250712" {
+		t.Errorf("unexpected url %s", *url)
+	}
+}
+
+func TestSoccerClient_Summary(t *testing.T) {
+	body := `{"header": {"id": "704", "competitions": [{"competitors": [{"homeAway": "home", "score": "1",
+		"team": {"id": "359", "abbreviation": "ARS", "logos": [{"href": "ars.png"}]}}],
+		"status": {"period": 1, "displayClock": "45'+2'"}}]},
+		"keyEvents": [{"id": "9", "type": {"text": "Penalty - Scored"}, "scoringPlay": true,
+			"clock": {"displayValue": "45'+1'"}, "team": {"id": "359"},
+			"participants": [{"athlete": {"displayName": "Bukayo Saka"}}]}]}`
+	var payload SoccerSummaryResponse
+	if err := json.Unmarshal([]byte(body), &payload); err != nil {
+		t.Fatalf("bad fixture: %v", err)
+	}
+	url := withStubFetch(t, payload)
+	resp := SoccerApiClient{League: LeagueEPL}.GetSummary("704")
+	if len(resp.Header.Competitions) != 1 || resp.Header.Competitions[0].Status.DisplayClock != "45'+2'" {
+		t.Fatalf("unexpected summary resp: %+v", resp)
+	}
+	if len(resp.KeyEvents) != 1 || resp.KeyEvents[0].Type.Text != "Penalty - Scored" || resp.KeyEvents[0].Participants[0].Athlete.DisplayName != "Bukayo Saka" {
+		t.Fatalf("unexpected key events: %+v", resp.KeyEvents)
+	}
+	if *url != "https://site.api.espn.com/apis/site/v2/sports/soccer/eng.1/summary?event=704" {
+		t.Errorf("unexpected url %s", *url)
+	}
+}
@@ -29,10 +29,41 @@ func init() {
 	viper.SetDefault("nfl.fastcast.pong_wait_sec", 60)
 	viper.SetDefault("nfl.fastcast.reconnect_base_ms", 2000)
 	viper.SetDefault("nfl.fastcast.reconnect_max_ms", 30000)
+	viper.SetDefault("nfl.fastcast.stale_after_sec", 60)
+	// Basketball fires lead changes and scoring runs rather than every
+	// basket; run_points 0 turns run events off
+	viper.SetDefault("basketball.lead_change_events", true)
+	viper.SetDefault("basketball.run_points", 10)
 	// Security defaults: reject remote (non-private) Home Assistant URLs and
 	// don't let the runtime API persist config changes to disk unless the
 	// operator opts in.
 	viper.SetDefault("home_assistant.allow_remote_url", false)
+	// Events go over a persistent Home Assistant websocket, with REST as
+	// the fallback while it is down
+	viper.SetDefault("home_assistant.websocket.enabled", true)
+	// Entities Goalfeed published for teams taken off a watch list are only
+	// pruned on request, or when the list changes with on_watch_change;
+	// mode unavailable keeps them, marked unavailable
+	viper.SetDefault("home_assistant.prune.on_watch_change", false)
+	viper.SetDefault("home_assistant.prune.mode", "remove")
+	viper.SetDefault("home_assistant.prune.entities_path", "ha_entities.json")
+	// Entity IDs are goalfeed_<league>_<team>_team_<metric>, named "WPG
+	// team.current score", unless home_assistant.entities says otherwise
+	viper.SetDefault("home_assistant.entities.prefix", "goalfeed")
+	viper.SetDefault("home_assistant.entities.slug_style", "full")
+	viper.SetDefault("home_assistant.entities.friendly_name", "{team} {metric}")
+	// MQTT discovery replaces the REST sensors once a broker is set
+	viper.SetDefault("mqtt.enabled", false)
+	viper.SetDefault("mqtt.discovery_prefix", "homeassistant")
+	viper.SetDefault("mqtt.topic_prefix", "goalfeed")
+	viper.SetDefault("mqtt.client_id", "goalfeed")
+	// Failed deliveries are retried with backoff until they're older than
+	// max_age, then dropped; rejected ones wait on the dead-letter list
+	viper.SetDefault("deliveries.path", "deliveries.json")
+	viper.SetDefault("deliveries.max_age", "10m")
+	viper.SetDefault("deliveries.retry_base_ms", 2000)
+	viper.SetDefault("deliveries.retry_max_ms", 60000)
+	viper.SetDefault("deliveries.dead_letter_max", 100)
 	viper.SetDefault("web.allow_config_writes", false)
 	if err := viper.ReadInConfig(); err != nil {
 		var notFound viper.ConfigFileNotFoundError
@@ -53,11 +84,13 @@ var configMissing bool
 // config.yaml was found AND no teams were configured by any other means, or ""
 // when there is nothing to say. Callers print it once, before the log opens.
 func MissingConfigNotice() string {
-	if !configMissing {
+	if !configMissing || viper.IsSet("iihf.tournaments") {
 		return ""
 	}
 	for _, k := range []string{
-		"watch.nhl", "watch.mlb", "watch.cfl", "watch.nfl",
+		"watch.nhl", "watch.mlb", "watch.cfl", "watch.nfl", "watch.nba", "watch.wnba",
+		"watch.epl", "watch.mls", "watch.ucl", "watch.pwhl", "watch.ahl",
+		"watch.ncaaf", "watch.ncaamh", "watch.ncaawh",
 	} {
 		if len(viper.GetStringSlice(k)) > 0 {
 			return ""
@@ -0,0 +1,139 @@
+package config
+
+import (
+	"fmt"
+	"goalfeed/models"
+	"strings"
+	"sync"
+
+	"github.com/spf13/viper"
+)
+
+// Tournament is an IIHF event followed as its own league. Key names the
+// watch list (watch.<key>) of country codes, and EventID is the event's id
+// on realtime.iihf.com.
+type Tournament struct {
+	Key      string        `mapstructure:"key" json:"key"`
+	Name     string        `mapstructure:"name" json:"name"`
+	EventID  string        `mapstructure:"event_id" json:"eventId"`
+	LeagueID models.League `mapstructure:"league_id" json:"leagueId"`
+}
+
+// firstTournamentLeagueId is the lowest league_id a tournament may take;
+// those below it belong to the built-in leagues.
+const firstTournamentLeagueId = 100
+
+// tournamentLeagueIds keeps the league ids the Olympic tournaments had when
+// they were hard-wired, so their stored games and entities carry over.
+var tournamentLeagueIds = map[string]models.League{
+	"olympic_men":   models.LeagueIdOlympicMensHockey,
+	"olympic_women": models.LeagueIdOlympicWomensHockey,
+}
+
+var (
+	tournamentsMu       sync.Mutex
+	tournamentsLoaded   bool
+	loadedTournaments   []Tournament
+	tournamentsWarnings []string
+)
+
+// Tournaments returns the tournaments listed under iihf.tournaments.
+// Entries without a key, event id or league id, or reusing another's key or
+// league id, are left out; TournamentWarnings says why. They're read once, and again
+// after ReloadTournaments, since publishing looks them up for every entity.
+func Tournaments() []Tournament {
+	tournamentsMu.Lock()
+	defer tournamentsMu.Unlock()
+	loadTournamentsLocked()
+	return loadedTournaments
+}
+
+// TournamentWarnings explains each iihf.tournaments entry Tournaments leaves
+// out.
+func TournamentWarnings() []string {
+	tournamentsMu.Lock()
+	defer tournamentsMu.Unlock()
+	loadTournamentsLocked()
+	return tournamentsWarnings
+}
+
+// ReloadTournaments rereads iihf.tournaments after the configuration
+// changed.
+func ReloadTournaments() {
+	tournamentsMu.Lock()
+	defer tournamentsMu.Unlock()
+	tournamentsLoaded = false
+	loadTournamentsLocked()
+}
+
+func loadTournamentsLocked() {
+	if tournamentsLoaded {
+		return
+	}
+	loadedTournaments, tournamentsWarnings = tournamentsFromConfig()
+	tournamentsLoaded = true
+}
+
+func tournamentsFromConfig() ([]Tournament, []string) {
+	var configured []Tournament
+	if err := viper.UnmarshalKey("iihf.tournaments", &configured); err != nil {
+		return nil, []string{fmt.Sprintf("Ignoring iihf.tournaments: %v", err)}
+	}
+
+	var tournaments []Tournament
+	var warnings []string
+	keys := map[string]bool{}
+	ids := map[models.League]bool{}
+	for i, t := range configured {
+		t.Key = strings.ToLower(strings.TrimSpace(t.Key))
+		t.EventID = strings.TrimSpace(t.EventID)
+		if t.Key == "" || t.EventID == "" {
+			warnings = append(warnings, fmt.Sprintf("Skipping IIHF tournament %d: key and event_id are required", i+1))
+			continue
+		}
+		if t.LeagueID == 0 {
+			t.LeagueID = tournamentLeagueIds[t.Key]
+		}
+		// The league id ends up in game keys and entity names, so it's set
+		// rather than taken from the entry's place in the list
+		if t.LeagueID == 0 {
+			warnings = append(warnings, fmt.Sprintf("Skipping IIHF tournament %s: league_id (%d or more) is required", t.Key, firstTournamentLeagueId))
+			continue
+		}
+		if t.Name == "" {
+			t.Name = "IIHF " + strings.ToUpper(t.Key)
+		}
+		if t.LeagueID < firstTournamentLeagueId && t.LeagueID != tournamentLeagueIds[t.Key] {
+			warnings = append(warnings, fmt.Sprintf("Skipping IIHF tournament %s: league ids below %d belong to built-in leagues", t.Key, firstTournamentLeagueId))
+			continue
+		}
+		if keys[t.Key] || ids[t.LeagueID] {
+			warnings = append(warnings, fmt.Sprintf("Skipping IIHF tournament %s: key or league id %d already used", t.Key, t.LeagueID))
+			continue
+		}
+		keys[t.Key] = true
+		ids[t.LeagueID] = true
+		tournaments = append(tournaments, t)
+	}
+	return tournaments, warnings
+}
+
+// TournamentForLeague returns the configured tournament with the league id.
+func TournamentForLeague(leagueId models.League) (Tournament, bool) {
+	for _, t := range Tournaments() {
+		if t.LeagueID == leagueId {
+			return t, true
+		}
+	}
+	return Tournament{}, false
+}
+
+// TournamentForName returns the configured tournament with the display name.
+func TournamentForName(name string) (Tournament, bool) {
+	for _, t := range Tournaments() {
+		if strings.EqualFold(t.Name, name) {
+			return t, true
+		}
+	}
+	return Tournament{}, false
+}
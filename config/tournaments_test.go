@@ -0,0 +1,59 @@
+package config
+
+import (
+	"goalfeed/models"
+	"testing"
+
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+)
+
+func setTournaments(t *testing.T, tournaments []map[string]interface{}) {
+	t.Helper()
+	viper.Set("iihf.tournaments", tournaments)
+	ReloadTournaments()
+	t.Cleanup(func() {
+		viper.Set("iihf.tournaments", nil)
+		ReloadTournaments()
+	})
+}
+
+func TestTournaments(t *testing.T) {
+	setTournaments(t, []map[string]interface{}{
+		{"key": "worlds", "name": "IIHF World Championship", "event_id": "503", "league_id": 100},
+		{"key": "Olympic_Men", "name": "Olympic Men's Hockey", "event_id": "601"},
+		{"key": "wjc", "event_id": "520", "league_id": 120},
+		{"key": "missing_event", "league_id": 121},
+		{"key": "worlds", "event_id": "504", "league_id": 122},
+		{"key": "nhl_clash", "event_id": "505", "league_id": 1},
+		{"key": "u18", "event_id": "530"},
+	})
+
+	tournaments := Tournaments()
+	if assert.Len(t, tournaments, 3) {
+		assert.Equal(t, Tournament{Key: "worlds", Name: "IIHF World Championship", EventID: "503", LeagueID: 100}, tournaments[0])
+		assert.Equal(t, models.League(models.LeagueIdOlympicMensHockey), tournaments[1].LeagueID)
+		assert.Equal(t, "olympic_men", tournaments[1].Key)
+		assert.Equal(t, Tournament{Key: "wjc", Name: "IIHF WJC", EventID: "520", LeagueID: 120}, tournaments[2])
+	}
+	assert.Len(t, TournamentWarnings(), 4)
+	assert.Contains(t, TournamentWarnings(), "Skipping IIHF tournament u18: league_id (100 or more) is required")
+
+	viper.Set("iihf.tournaments", nil)
+	assert.Len(t, Tournaments(), 3, "read once, not on every lookup")
+	ReloadTournaments()
+	assert.Empty(t, Tournaments())
+	setTournaments(t, []map[string]interface{}{
+		{"key": "wjc", "event_id": "520", "league_id": 120},
+		{"key": "Olympic_Men", "name": "Olympic Men's Hockey", "event_id": "601"},
+	})
+
+	tournament, ok := TournamentForLeague(120)
+	assert.True(t, ok)
+	assert.Equal(t, "wjc", tournament.Key)
+	tournament, ok = TournamentForName("olympic men's hockey")
+	assert.True(t, ok)
+	assert.Equal(t, "olympic_men", tournament.Key)
+	_, ok = TournamentForLeague(models.LeagueIdNHL)
+	assert.False(t, ok)
+}
@@ -3,9 +3,10 @@ module goalfeed
 go 1.24.0
 
 require (
+	github.com/eclipse/paho.mqtt.golang v1.5.0
 	github.com/gin-contrib/cors v1.7.4
 	github.com/gin-gonic/gin v1.11.0
-	github.com/gorilla/websocket v1.5.1
+	github.com/gorilla/websocket v1.5.3
 	github.com/joho/godotenv v1.3.0
 	github.com/spf13/cobra v1.7.0
 	github.com/spf13/viper v1.17.0
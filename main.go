@@ -2,20 +2,32 @@ package main
 
 import (
 	"fmt"
+	basketballClients "goalfeed/clients/leagues/basketball"
 	cflClients "goalfeed/clients/leagues/cfl"
+	hockeytechClients "goalfeed/clients/leagues/hockeytech"
+	iihfClients "goalfeed/clients/leagues/iihf"
 	mlbClients "goalfeed/clients/leagues/mlb"
 	nflClients "goalfeed/clients/leagues/nfl"
 	nhlClients "goalfeed/clients/leagues/nhl"
+	soccerClients "goalfeed/clients/leagues/soccer"
 	"goalfeed/config"
 	"goalfeed/models"
 	"goalfeed/services/leagues"
+	"goalfeed/services/leagues/basketball"
 	"goalfeed/services/leagues/cfl"
+	"goalfeed/services/leagues/collegehockey"
+	"goalfeed/services/leagues/hockeytech"
+	"goalfeed/services/leagues/iihf"
 	"goalfeed/services/leagues/mlb"
 	"goalfeed/services/leagues/nfl"
 	"goalfeed/services/leagues/nhl"
+	"goalfeed/services/leagues/soccer"
+	"goalfeed/targets/actions"
 	"goalfeed/targets/applog"
+	"goalfeed/targets/deliveries"
 	"goalfeed/targets/homeassistant"
 	"goalfeed/targets/memoryStore"
+	"goalfeed/targets/mqtt"
 	"goalfeed/utils"
 	webApi "goalfeed/web/api"
 	"os"
@@ -49,13 +61,120 @@ var rootCmd = &cobra.Command{
 		}
 	},
 }
+
+// haCmd groups Home Assistant maintenance commands.
+var haCmd = &cobra.Command{
+	Use:   "ha",
+	Short: "Home Assistant maintenance",
+}
+
+var haPruneCmd = &cobra.Command{
+	Use:   "prune",
+	Short: "Remove the Home Assistant entities of teams no longer watched",
+	Long: `Removes the sensor, binary_sensor and event entities Goalfeed created for
+teams that are no longer on a watch list, or marks them unavailable with
+home_assistant.prune.mode: unavailable.`,
+	// main prints the error; a failed prune isn't a usage mistake
+	SilenceUsage:  true,
+	SilenceErrors: true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		dryRun, _ := cmd.Flags().GetBool("dry-run")
+		homeassistant.LoadPublishedEntities()
+		results, err := homeassistant.PruneStaleEntities(dryRun)
+		if err != nil {
+			return fmt.Errorf("couldn't list Home Assistant entities: %v", err)
+		}
+		failed := 0
+		for _, r := range results {
+			switch {
+			case r.Error != "":
+				failed++
+				fmt.Printf("failed     %s: %s\n", r.EntityID, r.Error)
+			case dryRun:
+				fmt.Printf("would be %s %s\n", r.Action, r.EntityID)
+			default:
+				fmt.Printf("%-11s%s\n", r.Action, r.EntityID)
+			}
+		}
+		if len(results) == 0 {
+			fmt.Println("No stale entities")
+		}
+		if failed > 0 {
+			return fmt.Errorf("%d of %d entities couldn't be pruned", failed, len(results))
+		}
+		return nil
+	},
+}
+
+var haMigrateNamesCmd = &cobra.Command{
+	Use:   "migrate-names",
+	Short: "Rename existing Home Assistant entities to the configured naming",
+	Long: `Renames the entities Goalfeed created under an earlier naming, by default
+goalfeed_<league>_<team>_team_<metric>, to the one in home_assistant.entities.
+Use --from-prefix and --from-slug-style when the old naming wasn't the default.`,
+	SilenceUsage:  true,
+	SilenceErrors: true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		dryRun, _ := cmd.Flags().GetBool("dry-run")
+		fromPrefix, _ := cmd.Flags().GetString("from-prefix")
+		fromSlugStyle, _ := cmd.Flags().GetString("from-slug-style")
+		from := homeassistant.EntityNaming{Prefix: fromPrefix, SlugStyle: fromSlugStyle}
+		homeassistant.LoadPublishedEntities()
+		results, err := homeassistant.MigrateEntityNames(from, dryRun)
+		if err != nil {
+			return fmt.Errorf("couldn't list Home Assistant entities: %v", err)
+		}
+		failed := 0
+		for _, r := range results {
+			switch {
+			case r.Error != "":
+				failed++
+				fmt.Printf("failed  %s: %s\n", r.From, r.Error)
+			case dryRun:
+				fmt.Printf("would rename %s -> %s\n", r.From, r.To)
+			default:
+				fmt.Printf("renamed %s -> %s\n", r.From, r.To)
+			}
+		}
+		if len(results) == 0 {
+			fmt.Println("No entities to rename")
+		}
+		if failed > 0 {
+			return fmt.Errorf("%d of %d entities couldn't be renamed", failed, len(results))
+		}
+		return nil
+	},
+}
+
 var (
 	leagueServices                    = map[int]leagues.ILeagueService{}
 	needRefresh                       = false
 	logger                            = utils.GetLogger()
 	eventSender    func(models.Event) = homeassistant.SendEvent // Allow this to be replaced in tests
+
+	// Home Assistant state publishing, replaced in tests like eventSender
+	teamSensorPublisher func(models.Game) = homeassistant.PublishTeamSensors
+	endOfGamePublisher  func(models.Game) = homeassistant.PublishEndOfGameReset
+	gameUpdateSender    func(models.Game) = homeassistant.SendGameUpdate
+	// The actions: engine, replaced in tests like eventSender
+	actionHandler func(models.Event) = actions.Handle
 )
 
+// gameLocks serialises updates to each game. Polls can overlap, and NFL games
+// are also updated from the Fastcast stream, so each read-update-store of a
+// game holds its lock to keep events from firing twice. A game's lock is
+// dropped once nothing holds or waits for it, so ended games don't pile up.
+var gameLocks = struct {
+	sync.Mutex
+	locks map[string]*gameLock
+}{locks: map[string]*gameLock{}}
+
+// gameLock is a game's lock and how many callers hold or wait for it.
+type gameLock struct {
+	sync.Mutex
+	users int
+}
+
 // TickerConfig holds configuration for a ticker
 type TickerConfig struct {
 	Duration time.Duration
@@ -132,6 +251,16 @@ func init() {
 	rootCmd.PersistentFlags().StringSlice("nhl", []string{}, "NHL teams to watch")
 	rootCmd.PersistentFlags().StringSlice("mlb", []string{}, "MLB teams to watch")
 	rootCmd.PersistentFlags().StringSlice("cfl", []string{}, "CFL teams to watch")
+	rootCmd.PersistentFlags().StringSlice("nba", []string{}, "NBA teams to watch")
+	rootCmd.PersistentFlags().StringSlice("wnba", []string{}, "WNBA teams to watch")
+	rootCmd.PersistentFlags().StringSlice("epl", []string{}, "Premier League teams to watch")
+	rootCmd.PersistentFlags().StringSlice("mls", []string{}, "MLS teams to watch")
+	rootCmd.PersistentFlags().StringSlice("ucl", []string{}, "Champions League teams to watch")
+	rootCmd.PersistentFlags().StringSlice("pwhl", []string{}, "PWHL teams to watch")
+	rootCmd.PersistentFlags().StringSlice("ahl", []string{}, "AHL teams to watch")
+	rootCmd.PersistentFlags().StringSlice("ncaaf", []string{}, "NCAA football teams or conferences (conf:SEC) to watch")
+	rootCmd.PersistentFlags().StringSlice("ncaamh", []string{}, "NCAA men's hockey teams or conferences to watch")
+	rootCmd.PersistentFlags().StringSlice("ncaawh", []string{}, "NCAA women's hockey teams or conferences to watch")
 	rootCmd.PersistentFlags().Bool("test-goals", false, "Enable or disable sending test goals every minute")
 	rootCmd.PersistentFlags().Bool("web", false, "Start web interface mode")
 	rootCmd.PersistentFlags().String("web-port", "8080", "Port for web interface")
@@ -140,10 +269,27 @@ func init() {
 	viper.BindPFlag("watch.nhl", rootCmd.PersistentFlags().Lookup("nhl"))
 	viper.BindPFlag("watch.mlb", rootCmd.PersistentFlags().Lookup("mlb"))
 	viper.BindPFlag("watch.cfl", rootCmd.PersistentFlags().Lookup("cfl"))
+	viper.BindPFlag("watch.nba", rootCmd.PersistentFlags().Lookup("nba"))
+	viper.BindPFlag("watch.wnba", rootCmd.PersistentFlags().Lookup("wnba"))
+	viper.BindPFlag("watch.epl", rootCmd.PersistentFlags().Lookup("epl"))
+	viper.BindPFlag("watch.mls", rootCmd.PersistentFlags().Lookup("mls"))
+	viper.BindPFlag("watch.ucl", rootCmd.PersistentFlags().Lookup("ucl"))
+	viper.BindPFlag("watch.pwhl", rootCmd.PersistentFlags().Lookup("pwhl"))
+	viper.BindPFlag("watch.ahl", rootCmd.PersistentFlags().Lookup("ahl"))
+	viper.BindPFlag("watch.ncaaf", rootCmd.PersistentFlags().Lookup("ncaaf"))
+	viper.BindPFlag("watch.ncaamh", rootCmd.PersistentFlags().Lookup("ncaamh"))
+	viper.BindPFlag("watch.ncaawh", rootCmd.PersistentFlags().Lookup("ncaawh"))
 	viper.BindPFlag("test-goals", rootCmd.PersistentFlags().Lookup("test-goals"))
 	viper.BindPFlag("web", rootCmd.PersistentFlags().Lookup("web"))
 	viper.BindPFlag("web-port", rootCmd.PersistentFlags().Lookup("web-port"))
 
+	haPruneCmd.Flags().Bool("dry-run", false, "List the entities that would be pruned without touching them")
+	haCmd.AddCommand(haPruneCmd)
+	haMigrateNamesCmd.Flags().Bool("dry-run", false, "List the renames without touching any entity")
+	haMigrateNamesCmd.Flags().String("from-prefix", "goalfeed", "Entity ID prefix of the old naming")
+	haMigrateNamesCmd.Flags().String("from-slug-style", "full", "Slug style of the old naming, full or short")
+	haCmd.AddCommand(haMigrateNamesCmd)
+	rootCmd.AddCommand(haCmd)
 }
 
 func main() {
@@ -177,9 +323,37 @@ func initialize() {
 	leagueServices[models.LeagueIdMLB] = mlb.MLBService{Client: mlbClients.MLBApiClient{}}
 	leagueServices[models.LeagueIdCFL] = cfl.CFLService{Client: cflClients.CFLApiClient{}}
 	leagueServices[models.LeagueIdNFL] = nfl.NFLService{Client: nflClients.NFLAPIClient{}}
-	// OLYMPICS-DISABLED (standards pass v1.0.37): Olympic hockey support is
-	// implemented but not yet committed; re-enable by restoring the import and the
-	// service registration below. See CHANGELOG [Unreleased] -> Notes.
+	leagueServices[models.LeagueIdNBA] = basketball.BasketballService{Client: basketballClients.BasketballApiClient{League: basketballClients.LeagueNBA}, League: models.LeagueIdNBA}
+	leagueServices[models.LeagueIdWNBA] = basketball.BasketballService{Client: basketballClients.BasketballApiClient{League: basketballClients.LeagueWNBA}, League: models.LeagueIdWNBA}
+	leagueServices[models.LeagueIdEPL] = soccer.SoccerService{Client: soccerClients.SoccerApiClient{League: soccerClients.LeagueEPL}, League: models.LeagueIdEPL}
+	leagueServices[models.LeagueIdMLS] = soccer.SoccerService{Client: soccerClients.SoccerApiClient{League: soccerClients.LeagueMLS}, League: models.LeagueIdMLS}
+	leagueServices[models.LeagueIdUCL] = soccer.SoccerService{Client: soccerClients.SoccerApiClient{League: soccerClients.LeagueUCL}, League: models.LeagueIdUCL}
+	leagueServices[models.LeagueIdPWHL] = hockeytech.HockeyTechService{Client: hockeytechClients.HockeyTechApiClient{ClientCode: hockeytechClients.ClientCodePWHL}, League: models.LeagueIdPWHL}
+	leagueServices[models.LeagueIdAHL] = hockeytech.HockeyTechService{Client: hockeytechClients.HockeyTechApiClient{ClientCode: hockeytechClients.ClientCodeAHL}, League: models.LeagueIdAHL}
+	leagueServices[models.LeagueIdNCAAF] = nfl.NFLService{Client: nflClients.NFLAPIClient{League: nflClients.LeagueNCAAF}, League: models.LeagueIdNCAAF}
+	leagueServices[models.LeagueIdNCAAMHockey] = collegehockey.CollegeHockeyService{Client: nflClients.NFLAPIClient{League: nflClients.LeagueNCAAMHockey}, League: models.LeagueIdNCAAMHockey}
+	leagueServices[models.LeagueIdNCAAWHockey] = collegehockey.CollegeHockeyService{Client: nflClients.NFLAPIClient{League: nflClients.LeagueNCAAWHockey}, League: models.LeagueIdNCAAWHockey}
+	// Each IIHF tournament in iihf.tournaments is a league of its own
+	for _, warning := range config.TournamentWarnings() {
+		logger.Warn(warning)
+	}
+	for _, tournament := range config.Tournaments() {
+		leagueServices[int(tournament.LeagueID)] = iihf.IIHFService{Client: iihfClients.IIHFApiClient{}, Tournament: tournament}
+	}
+	for _, warning := range actions.Warnings() {
+		logger.Warn(warning)
+	}
+	for _, warning := range homeassistant.EventWarnings() {
+		logger.Warn(warning)
+	}
+	// Entities published by earlier runs, the only ones pruning may touch
+	homeassistant.LoadPublishedEntities()
+	for _, warning := range homeassistant.NamingWarnings() {
+		logger.Warn(warning)
+	}
+	for _, warning := range homeassistant.InstanceWarnings() {
+		logger.Warn(warning)
+	}
 
 	logger.Info("Initializing Active Games")
 	checkLeaguesForActiveGames()
@@ -187,7 +361,15 @@ func initialize() {
 	// Publish baseline sensors for monitored teams at startup
 	homeassistant.PublishBaselineForMonitoredTeams()
 
+	// Keep a websocket open to Home Assistant; events fall back to REST
+	homeassistant.StartWebSocket()
+	// Sensors go over MQTT discovery instead of REST when it's configured
+	mqtt.Start()
+	// Retry events that failed to send, including those queued before a restart
+	deliveries.Start()
+
 	// Start Fastcast listener for NFL if enabled
+	nfl.SetFastcastUpdateHandler(handlePushedGameUpdate)
 	nfl.StartNFLFastcast()
 }
 
@@ -204,8 +386,8 @@ func checkForNewActiveGames(service leagues.ILeagueService) {
 	go service.GetActiveGames(gamesChan)
 	for _, game := range <-gamesChan {
 		// Check if the home and away teams are being monitored
-		if teamIsMonitoredByLeague(game.CurrentState.Home.Team.TeamCode, service.GetLeagueName()) ||
-			teamIsMonitoredByLeague(game.CurrentState.Away.Team.TeamCode, service.GetLeagueName()) {
+		if teamIsMonitored(game.CurrentState.Home.Team, service.GetLeagueName()) ||
+			teamIsMonitored(game.CurrentState.Away.Team, service.GetLeagueName()) {
 			if !gameIsMonitored(game) {
 				logger.Info(fmt.Sprintf("Adding %s game (%s @ %s) to active monitored games", service.GetLeagueName(), game.CurrentState.Away.Team.TeamCode, game.CurrentState.Home.Team.TeamCode))
 				memoryStore.SetGame(game)
@@ -234,7 +416,31 @@ func watchActiveGames() {
 	}
 }
 
+func lockGame(gameKey string) func() {
+	gameLocks.Lock()
+	lock, ok := gameLocks.locks[gameKey]
+	if !ok {
+		lock = &gameLock{}
+		gameLocks.locks[gameKey] = lock
+	}
+	lock.users++
+	gameLocks.Unlock()
+	lock.Lock()
+	return func() {
+		lock.Unlock()
+		gameLocks.Lock()
+		lock.users--
+		if lock.users == 0 {
+			delete(gameLocks.locks, gameKey)
+		}
+		gameLocks.Unlock()
+	}
+}
+
 func checkGame(gameKey string) {
+	unlock := lockGame(gameKey)
+	defer unlock()
+
 	game, err := memoryStore.GetGameByGameKey(gameKey)
 	if err != nil {
 		return
@@ -249,6 +455,32 @@ func checkGame(gameKey string) {
 	go service.GetGameUpdate(game, updateChan)
 	gameUpdate := <-updateChan
 
+	applyGameUpdate(service, game, gameUpdate)
+}
+
+// handlePushedGameUpdate applies an update that arrived over a push stream
+// rather than from a poll. The stored game may have moved on since the update
+// was built, so it is diffed against the current state instead.
+func handlePushedGameUpdate(game models.Game, gameUpdate models.GameUpdate) {
+	gameKey := game.GetGameKey()
+	unlock := lockGame(gameKey)
+	defer unlock()
+
+	current, err := memoryStore.GetGameByGameKey(gameKey)
+	if err != nil || current.CurrentState.Status == models.StatusEnded {
+		return
+	}
+	service := leagueServices[int(current.LeagueId)]
+	if service == nil {
+		return
+	}
+	gameUpdate.OldState = current.CurrentState
+	applyGameUpdate(service, current, gameUpdate)
+}
+
+// applyGameUpdate fires the events for an update, stores the new state and
+// stops watching the game once it has ended.
+func applyGameUpdate(service leagues.ILeagueService, game models.Game, gameUpdate models.GameUpdate) {
 	// Detect and fire goal events
 	eventChan := make(chan []models.Event)
 	go service.GetEvents(gameUpdate, eventChan)
@@ -264,18 +496,38 @@ func checkGame(gameKey string) {
 			LeagueId:    int(game.LeagueId),
 			LeagueName:  service.GetLeagueName(),
 			Type:        models.EventTypePeriodStart,
-			Description: fmt.Sprintf("Period %d started", gameUpdate.NewState.Period),
+			Description: periodStartDescription(gameUpdate.NewState),
+			Period:      gameUpdate.NewState.Period,
+			PeriodType:  gameUpdate.NewState.PeriodType,
+			GameCode:    game.GameCode,
+			// The teams in the game, for a Home Assistant watching only one
+			Score: models.ScoreUpdate{
+				HomeScore: gameUpdate.NewState.Home.Score,
+				AwayScore: gameUpdate.NewState.Away.Score,
+				HomeTeam:  gameUpdate.NewState.Home.Team.TeamCode,
+				AwayTeam:  gameUpdate.NewState.Away.Team.TeamCode,
+			},
+		}
+		go eventSender(event)
+		// Actions only run for the watched teams' games, as in fireGoalEvents
+		if teamIsMonitored(gameUpdate.NewState.Home.Team, service.GetLeagueName()) ||
+			teamIsMonitored(gameUpdate.NewState.Away.Team, service.GetLeagueName()) {
+			actionHandler(event)
 		}
-		eventSender(event)
 	}
 
 	// Update the game with new state
 	updatedGame := game
 	updatedGame.CurrentState = gameUpdate.NewState
+	if gameUpdate.Plays != nil {
+		updatedGame.Events = gameUpdate.Plays
+	}
 	memoryStore.SetGame(updatedGame)
+	publishGameState(service, updatedGame, gameUpdate.OldState)
 
 	// Remove game from active monitoring if it has ended
 	if gameUpdate.NewState.Status == models.StatusEnded {
+		gameKey := updatedGame.GetGameKey()
 		logger.Info(fmt.Sprintf("Game %s has ended, removing from active monitoring", gameKey))
 		memoryStore.DeleteActiveGame(updatedGame)
 		memoryStore.DeleteActiveGameKey(gameKey)
@@ -283,12 +535,127 @@ func checkGame(gameKey string) {
 	}
 }
 
+// publishGameState pushes a watched game's sensors to Home Assistant on
+// every update. Sensors go through the dedupe/debounce cache in
+// homeassistant.publishEntity, so only changed values reach Home Assistant;
+// the game_update event is sent when the score, period or status moves, and
+// an ended game gets its live sensors reset. It only queues the game, so the
+// calls to Home Assistant don't hold up the game's lock.
+func publishGameState(service leagues.ILeagueService, game models.Game, old models.GameState) {
+	leagueName := service.GetLeagueName()
+	if !teamIsMonitored(game.CurrentState.Home.Team, leagueName) && !teamIsMonitored(game.CurrentState.Away.Team, leagueName) {
+		return
+	}
+	gamePublishes.push(game, gameStateChanged(old, game.CurrentState))
+}
+
+// gamePublish is a watched game's state waiting to go to Home Assistant.
+// sendUpdate stays set when a newer state replaces one that changed, so
+// its game_update isn't lost.
+type gamePublish struct {
+	game       models.Game
+	sendUpdate bool
+}
+
+// gamePublishQueue sends games to Home Assistant in the background, one at
+// a time and in the order they changed. Only the latest state of each game
+// waits, so a slow Home Assistant can't build a backlog.
+type gamePublishQueue struct {
+	mu      sync.Mutex
+	pending map[string]gamePublish
+	order   []string
+	sending bool
+	wake    chan struct{}
+	start   sync.Once
+}
+
+var gamePublishes = &gamePublishQueue{pending: map[string]gamePublish{}, wake: make(chan struct{}, 1)}
+
+func (q *gamePublishQueue) push(game models.Game, sendUpdate bool) {
+	q.start.Do(func() { go q.run() })
+	key := game.GetGameKey()
+	q.mu.Lock()
+	if waiting, ok := q.pending[key]; ok {
+		sendUpdate = sendUpdate || waiting.sendUpdate
+	} else {
+		q.order = append(q.order, key)
+	}
+	q.pending[key] = gamePublish{game: game, sendUpdate: sendUpdate}
+	q.mu.Unlock()
+	select {
+	case q.wake <- struct{}{}:
+	default:
+	}
+}
+
+func (q *gamePublishQueue) run() {
+	for range q.wake {
+		for {
+			q.mu.Lock()
+			if len(q.order) == 0 {
+				q.sending = false
+				q.mu.Unlock()
+				break
+			}
+			key := q.order[0]
+			q.order = q.order[1:]
+			publish := q.pending[key]
+			delete(q.pending, key)
+			q.sending = true
+			q.mu.Unlock()
+
+			teamSensorPublisher(publish.game)
+			if publish.sendUpdate {
+				gameUpdateSender(publish.game)
+			}
+			if publish.game.CurrentState.Status == models.StatusEnded {
+				endOfGamePublisher(publish.game)
+			}
+		}
+	}
+}
+
+// idle reports whether nothing is waiting or being sent.
+func (q *gamePublishQueue) idle() bool {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	return len(q.order) == 0 && !q.sending
+}
+
+func gameStateChanged(old, new models.GameState) bool {
+	return old.Home.Score != new.Home.Score ||
+		old.Away.Score != new.Away.Score ||
+		old.Period != new.Period ||
+		old.PeriodType != new.PeriodType ||
+		old.Status != new.Status
+}
+
+// periodStartDescription names overtime and shootouts explicitly so a
+// period_start event for period 4 or 5 doesn't read like a regular period.
+func periodStartDescription(state models.GameState) string {
+	switch state.PeriodType {
+	case models.PeriodTypeOvertime:
+		return "Overtime started"
+	case models.PeriodTypeShootout:
+		return "Shootout started"
+	default:
+		return fmt.Sprintf("Period %d started", state.Period)
+	}
+}
+
 func fireGoalEvents(events chan []models.Event, game models.Game) {
 	for _, event := range <-events {
 		logger.Info(fmt.Sprintf("Event %s: %s", event.Type, event.Description))
-		if teamIsMonitoredByLeague(event.TeamCode, leagueServices[int(game.LeagueId)].GetLeagueName()) {
+		team := eventTeam(game, event.TeamCode)
+		if event.TeamConference == "" {
+			// For actions and instances filtering on conf:
+			event.TeamConference = team.Conference
+		}
+		if teamIsMonitored(team, leagueServices[int(game.LeagueId)].GetLeagueName()) {
 			// Send enhanced event to Home Assistant
 			go eventSender(event)
+			// Run the configured actions for it
+			actionHandler(event)
 			// Append to app log
 			go applog.AppendEvent(event)
 			// Broadcast event to web clients
@@ -297,31 +664,41 @@ func fireGoalEvents(events chan []models.Event, game models.Game) {
 	}
 }
 func teamIsMonitoredByLeague(teamCode, leagueName string) bool {
+	return teamIsMonitored(models.Team{TeamCode: teamCode}, leagueName)
+}
+
+// teamIsMonitored checks the team against the league's watch list, where
+// an entry can be "*", a team code or a college conference ("conf:SEC").
+func teamIsMonitored(team models.Team, leagueName string) bool {
 	// Convert leagueName to config key (lowercase, special mapping for Olympic hockey)
 	configKey := leagueNameToWatchConfigKey(leagueName)
 
 	// Get the teams to watch for the given league from the configuration
-	teamsToWatch := config.GetStringSlice("watch." + configKey)
-
-	// If "*" is in the watch list, monitor all teams for this league
-	for _, team := range teamsToWatch {
-		if team == "*" {
+	for _, entry := range config.GetStringSlice("watch." + configKey) {
+		if team.MatchesWatchEntry(entry) {
 			return true
 		}
 	}
+	return false
+}
 
-	// Check if the teamCode is in the list of teams to watch
-	for _, team := range teamsToWatch {
-		if strings.EqualFold(team, teamCode) {
-			return true
-		}
+// eventTeam finds the game's team an event is for, so conference entries
+// in the watch list apply to events too.
+func eventTeam(game models.Game, teamCode string) models.Team {
+	switch teamCode {
+	case game.CurrentState.Home.Team.TeamCode:
+		return game.CurrentState.Home.Team
+	case game.CurrentState.Away.Team.TeamCode:
+		return game.CurrentState.Away.Team
 	}
-
-	return false
+	return models.Team{TeamCode: teamCode}
 }
 
 // leagueNameToWatchConfigKey maps league display name to viper config key (watch.<key>)
 func leagueNameToWatchConfigKey(leagueName string) string {
+	if tournament, ok := config.TournamentForName(leagueName); ok {
+		return tournament.Key
+	}
 	lower := strings.ToLower(leagueName)
 	// Olympic Women's Hockey -> olympic_women (check "women" before "men" to avoid substring match)
 	if strings.Contains(lower, "olympic") && strings.Contains(lower, "women") {
@@ -353,16 +730,28 @@ func sendTestGoal() {
 
 func publishSchedules() {
 	logger.Info("Publishing schedule sensors")
-	leagueConfigs := []struct {
+	type leagueConfig struct {
 		id   models.League
 		name string
-	}{
+	}
+	leagueConfigs := []leagueConfig{
 		{models.LeagueIdNHL, "nhl"},
 		{models.LeagueIdMLB, "mlb"},
 		{models.LeagueIdCFL, "cfl"},
 		{models.LeagueIdNFL, "nfl"},
-		{models.LeagueIdOlympicMensHockey, "olympic_men"},
-		{models.LeagueIdOlympicWomensHockey, "olympic_women"},
+		{models.LeagueIdNBA, "nba"},
+		{models.LeagueIdWNBA, "wnba"},
+		{models.LeagueIdEPL, "epl"},
+		{models.LeagueIdMLS, "mls"},
+		{models.LeagueIdUCL, "ucl"},
+		{models.LeagueIdPWHL, "pwhl"},
+		{models.LeagueIdAHL, "ahl"},
+		{models.LeagueIdNCAAF, "ncaaf"},
+		{models.LeagueIdNCAAMHockey, "ncaamh"},
+		{models.LeagueIdNCAAWHockey, "ncaawh"},
+	}
+	for _, tournament := range config.Tournaments() {
+		leagueConfigs = append(leagueConfigs, leagueConfig{tournament.LeagueID, tournament.Key})
 	}
 
 	for _, lc := range leagueConfigs {
@@ -378,7 +767,7 @@ func publishSchedules() {
 		go svc.GetUpcomingGames(ch)
 		upcoming := <-ch
 		for _, g := range upcoming {
-			if teamIsMonitoredByLeague(g.CurrentState.Home.Team.TeamCode, lc.name) || teamIsMonitoredByLeague(g.CurrentState.Away.Team.TeamCode, lc.name) {
+			if teamIsMonitored(g.CurrentState.Home.Team, lc.name) || teamIsMonitored(g.CurrentState.Away.Team, lc.name) {
 				if g.CurrentState.Status == models.StatusUpcoming || (!g.GameDetails.GameDate.IsZero() && g.GameDetails.GameDate.After(time.Now().Add(-1*time.Hour))) {
 					homeassistant.PublishScheduleSensorsForGame(g)
 				}
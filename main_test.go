@@ -10,6 +10,7 @@ import (
 	"github.com/spf13/viper"
 	"github.com/stretchr/testify/assert"
 
+	"goalfeed/config"
 	"goalfeed/models"
 	"goalfeed/services/leagues"
 	"goalfeed/targets/memoryStore"
@@ -50,6 +51,8 @@ func (m *MockLeagueService) GetEvents(update models.GameUpdate, ch chan []models
 
 // Test Helpers
 func setupTest(t *testing.T) {
+	// The last test's games go out before its publishers are replaced
+	waitForGamePublishes(t)
 	viper.Reset()
 	memoryStore.SetActiveGameKeys([]string{})
 
@@ -60,6 +63,10 @@ func setupTest(t *testing.T) {
 		int(models.LeagueIdNFL): &MockLeagueService{},
 		int(models.LeagueIdCFL): &MockLeagueService{},
 	}
+	teamSensorPublisher = func(models.Game) {}
+	endOfGamePublisher = func(models.Game) {}
+	gameUpdateSender = func(models.Game) {}
+	actionHandler = func(models.Event) {}
 }
 
 func createTestGame(leagueId models.League, homeTeam, awayTeam string) models.Game {
@@ -643,6 +650,22 @@ func TestTeamIsMonitoredByLeague_OlympicHockey(t *testing.T) {
 	assert.False(t, teamIsMonitoredByLeague("USA", "Olympic Women's Hockey"))
 }
 
+func TestTeamIsMonitoredByLeague_IIHFTournament(t *testing.T) {
+	setupTest(t)
+	viper.Set("iihf.tournaments", []map[string]interface{}{
+		{"key": "worlds", "name": "IIHF World Championship", "event_id": "503", "league_id": 100},
+	})
+	config.ReloadTournaments()
+	defer func() {
+		viper.Set("iihf.tournaments", nil)
+		config.ReloadTournaments()
+	}()
+	viper.Set("watch.worlds", []string{"FIN"})
+	assert.Equal(t, "worlds", leagueNameToWatchConfigKey("IIHF World Championship"))
+	assert.True(t, teamIsMonitoredByLeague("FIN", "IIHF World Championship"))
+	assert.False(t, teamIsMonitoredByLeague("SWE", "IIHF World Championship"))
+}
+
 func TestCheckForNewActiveGames_TeamsMonitored(t *testing.T) {
 	setupTest(t)
 
@@ -913,3 +936,188 @@ func TestTickerManager_RefreshTicker(t *testing.T) {
 		refreshTicker.Task()
 	})
 }
+
+// recordingLeagueService passes on the updates GetEvents is asked about
+type recordingLeagueService struct {
+	MockLeagueService
+	updates chan models.GameUpdate
+}
+
+func (r *recordingLeagueService) GetEvents(update models.GameUpdate, ch chan []models.Event) {
+	r.updates <- update
+	ch <- []models.Event{}
+}
+
+func TestHandlePushedGameUpdate_DiffsAgainstStoredGame(t *testing.T) {
+	setupTest(t)
+
+	game := createTestGame(models.LeagueIdNFL, "KC", "BUF")
+	game.GameCode = "401547417"
+	stale := game
+	game.CurrentState.Home.Score = 7
+	memoryStore.AppendActiveGame(game)
+
+	service := &recordingLeagueService{updates: make(chan models.GameUpdate, 1)}
+	leagueServices[int(models.LeagueIdNFL)] = service
+
+	pushed := stale.CurrentState
+	pushed.Home.Score = 10
+	handlePushedGameUpdate(stale, models.GameUpdate{OldState: stale.CurrentState, NewState: pushed})
+
+	select {
+	case update := <-service.updates:
+		assert.Equal(t, 7, update.OldState.Home.Score)
+		assert.Equal(t, 10, update.NewState.Home.Score)
+	case <-time.After(time.Second):
+		t.Fatal("GetEvents was not called")
+	}
+	stored, err := memoryStore.GetGameByGameKey(game.GetGameKey())
+	assert.NoError(t, err)
+	assert.Equal(t, 10, stored.CurrentState.Home.Score)
+}
+
+func TestApplyGameUpdate_StoresPlayTimeline(t *testing.T) {
+	setupTest(t)
+
+	game := createTestGame(models.LeagueIdCFL, "OTT", "MTL")
+	game.Events = []models.GameEvent{{Id: "1-1", Type: models.EventTypePlay}}
+	memoryStore.AppendActiveGame(game)
+	service := &recordingLeagueService{updates: make(chan models.GameUpdate, 2)}
+
+	applyGameUpdate(service, game, models.GameUpdate{OldState: game.CurrentState, NewState: game.CurrentState})
+	stored, err := memoryStore.GetGameByGameKey(game.GetGameKey())
+	assert.NoError(t, err)
+	assert.Len(t, stored.Events, 1, "an update without plays keeps the stored timeline")
+
+	plays := []models.GameEvent{{Id: "1-1", Type: models.EventTypePlay}, {Id: "1-2", Type: models.EventTypeBigPlay}}
+	applyGameUpdate(service, stored, models.GameUpdate{OldState: game.CurrentState, NewState: game.CurrentState, Plays: plays})
+	stored, err = memoryStore.GetGameByGameKey(game.GetGameKey())
+	assert.NoError(t, err)
+	assert.Equal(t, plays, stored.Events)
+}
+
+func TestApplyGameUpdate_PublishesSensorsForWatchedTeams(t *testing.T) {
+	setupTest(t)
+	viper.Set("watch.nfl", []string{"KC"})
+
+	var published, updates, resets int
+	teamSensorPublisher = func(models.Game) { published++ }
+	gameUpdateSender = func(models.Game) { updates++ }
+	endOfGamePublisher = func(models.Game) { resets++ }
+
+	game := createTestGame(models.LeagueIdNFL, "KC", "BUF")
+	memoryStore.AppendActiveGame(game)
+	service := &recordingLeagueService{MockLeagueService: MockLeagueService{leagueName: "NFL"}, updates: make(chan models.GameUpdate, 3)}
+
+	applyGameUpdate(service, game, models.GameUpdate{OldState: game.CurrentState, NewState: game.CurrentState})
+	waitForGamePublishes(t)
+	assert.Equal(t, 1, published)
+	assert.Equal(t, 0, updates, "an unchanged state sends no game_update")
+	assert.Equal(t, 0, resets)
+
+	ended := game.CurrentState
+	ended.Home.Score = 21
+	ended.Status = models.StatusEnded
+	applyGameUpdate(service, game, models.GameUpdate{OldState: game.CurrentState, NewState: ended})
+	waitForGamePublishes(t)
+	assert.Equal(t, 2, published)
+	assert.Equal(t, 1, updates)
+	assert.Equal(t, 1, resets)
+
+	other := createTestGame(models.LeagueIdNFL, "DAL", "NYG")
+	memoryStore.AppendActiveGame(other)
+	applyGameUpdate(service, other, models.GameUpdate{OldState: other.CurrentState, NewState: other.CurrentState})
+	waitForGamePublishes(t)
+	assert.Equal(t, 2, published, "unwatched games publish nothing")
+}
+
+func TestApplyGameUpdate_RunsPeriodStartActionsOnlyForWatchedTeams(t *testing.T) {
+	setupTest(t)
+	viper.Set("watch.nfl", []string{"KC"})
+	var handled []string
+	actionHandler = func(event models.Event) { handled = append(handled, event.Score.HomeTeam) }
+	service := &recordingLeagueService{MockLeagueService: MockLeagueService{leagueName: "NFL"}, updates: make(chan models.GameUpdate, 3)}
+
+	for _, game := range []models.Game{createTestGame(models.LeagueIdNFL, "KC", "BUF"), createTestGame(models.LeagueIdNFL, "DAL", "NYG")} {
+		memoryStore.AppendActiveGame(game)
+		next := game.CurrentState
+		next.Period++
+		applyGameUpdate(service, game, models.GameUpdate{OldState: game.CurrentState, NewState: next})
+	}
+	waitForGamePublishes(t)
+	assert.Equal(t, []string{"KC"}, handled, "a period start in an unwatched game runs no actions")
+}
+
+func TestPublishGameState_DoesNotWaitForHomeAssistant(t *testing.T) {
+	setupTest(t)
+	viper.Set("watch.nfl", []string{"KC"})
+	release := make(chan struct{})
+	var mu sync.Mutex
+	var published []int
+	teamSensorPublisher = func(game models.Game) {
+		<-release
+		mu.Lock()
+		published = append(published, game.CurrentState.Home.Score)
+		mu.Unlock()
+	}
+	var updates int
+	gameUpdateSender = func(models.Game) { updates++ }
+	service := &MockLeagueService{leagueName: "NFL"}
+	game := createTestGame(models.LeagueIdNFL, "KC", "BUF")
+
+	done := make(chan struct{})
+	go func() {
+		for score := 1; score <= 3; score++ {
+			next := game
+			next.CurrentState.Home.Score = score
+			publishGameState(service, next, game.CurrentState)
+			game = next
+		}
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("publishing waited on Home Assistant")
+	}
+	close(release)
+	waitForGamePublishes(t)
+
+	mu.Lock()
+	defer mu.Unlock()
+	assert.Equal(t, 3, published[len(published)-1], "the latest state is sent")
+	assert.Less(t, len(published), 3, "states replaced while waiting aren't")
+	assert.Equal(t, len(published), updates, "each send still carries its game_update")
+}
+
+// waitForGamePublishes waits until publishGameState's queue has sent
+// everything.
+func waitForGamePublishes(t *testing.T) {
+	t.Helper()
+	assert.Eventually(t, gamePublishes.idle, time.Second, 5*time.Millisecond)
+}
+
+func TestLockGame_DroppedOnceUnused(t *testing.T) {
+	unlock := lockGame("nfl-1")
+	locked := make(chan struct{})
+	go func() {
+		second := lockGame("nfl-1")
+		close(locked)
+		second()
+	}()
+
+	select {
+	case <-locked:
+		t.Fatal("a second caller got the game's lock while it was held")
+	case <-time.After(20 * time.Millisecond):
+	}
+	unlock()
+	<-locked
+
+	assert.Eventually(t, func() bool {
+		gameLocks.Lock()
+		defer gameLocks.Unlock()
+		_, held := gameLocks.locks["nfl-1"]
+		return !held
+	}, time.Second, 5*time.Millisecond)
+}
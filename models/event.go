@@ -10,11 +10,12 @@ type Event struct {
 	Description string    `json:"description"`
 
 	// Team and player info
-	TeamCode     string `json:"teamCode"`
-	TeamName     string `json:"teamName"`
-	TeamHash     string `json:"teamHash"`
-	PlayerName   string `json:"playerName,omitempty"`
-	PlayerNumber int    `json:"playerNumber,omitempty"`
+	TeamCode       string `json:"teamCode"`
+	TeamName       string `json:"teamName"`
+	TeamHash       string `json:"teamHash"`
+	TeamConference string `json:"teamConference,omitempty"` // college teams, for conf: filters
+	PlayerName     string `json:"playerName,omitempty"`
+	PlayerNumber   int    `json:"playerNumber,omitempty"`
 
 	// Game context
 	LeagueId   int    `json:"leagueId"`
@@ -22,6 +23,7 @@ type Event struct {
 	GameCode   string `json:"gameCode"`
 	GameId     string `json:"gameId"`
 	Period     int    `json:"period"`
+	PeriodType string `json:"periodType,omitempty"`
 	Time       string `json:"time"`
 	Clock      string `json:"clock,omitempty"`
 
@@ -113,11 +115,11 @@ func (ep EventPriority) String() string {
 // GetEventPriority determines the priority based on event type
 func (e Event) GetEventPriority() EventPriority {
 	switch e.Type {
-	case EventTypeGoal, EventTypeTouchdown, EventTypeHomeRun:
+	case EventTypeGoal, EventTypeTouchdown, EventTypeFieldGoal, EventTypeSafety, EventTypeSingle, EventTypeConvert, EventTypeHomeRun, EventTypeShootoutResult:
 		return PriorityHigh
 	case EventTypeGameStart, EventTypeGameEnd, EventTypePeriodStart, EventTypePeriodEnd:
 		return PriorityNormal
-	case EventTypePenalty, EventTypeTurnover, EventTypeFumble:
+	case EventTypePenalty, EventTypeTurnover, EventTypeFumble, EventTypeInterception, EventTypeRedZoneEntry, EventTypeLeadChange, EventTypeRedCard, EventTypeVAROverturn:
 		return PriorityHigh
 	default:
 		return PriorityNormal
@@ -127,12 +129,28 @@ func (e Event) GetEventPriority() EventPriority {
 // GetEventIcon returns an appropriate icon for the event type
 func (e Event) GetEventIcon() string {
 	switch e.Type {
-	case EventTypeGoal:
+	case EventTypeGoal, EventTypeShootoutAttempt, EventTypeShootoutResult:
 		return "🏒"
-	case EventTypeTouchdown:
+	case EventTypeTouchdown, EventTypeFieldGoal, EventTypeSafety, EventTypeSingle, EventTypeConvert:
 		return "🏈"
 	case EventTypeHomeRun:
 		return "⚾"
+	case EventTypeGoaliePulled, EventTypeGoalieReturned:
+		return "🥅"
+	case EventTypeRedZoneEntry:
+		return "🚩"
+	case EventTypeBigPlay:
+		return "💥"
+	case EventTypeLeadChange:
+		return "🔀"
+	case EventTypeScoringRun:
+		return "🏀"
+	case EventTypeRedCard:
+		return "🟥"
+	case EventTypeVAROverturn:
+		return "📺"
+	case EventTypeTurnover, EventTypeFumble, EventTypeInterception:
+		return "🔄"
 	case EventTypePenalty:
 		return "⚠️"
 	case EventTypePowerPlay:
@@ -163,11 +181,11 @@ func (e Event) GetEventIcon() string {
 // GetEventColor returns a color for the event type
 func (e Event) GetEventColor() string {
 	switch e.Type {
-	case EventTypeGoal, EventTypeTouchdown, EventTypeHomeRun:
+	case EventTypeGoal, EventTypeTouchdown, EventTypeFieldGoal, EventTypeSafety, EventTypeSingle, EventTypeConvert, EventTypeHomeRun, EventTypeShootoutResult:
 		return "green"
-	case EventTypePenalty, EventTypeTurnover, EventTypeFumble, EventTypeError:
+	case EventTypePenalty, EventTypeTurnover, EventTypeFumble, EventTypeInterception, EventTypeError, EventTypeRedCard:
 		return "red"
-	case EventTypePowerPlay, EventTypeStrikeout:
+	case EventTypePowerPlay, EventTypeStrikeout, EventTypeGoaliePulled, EventTypeRedZoneEntry, EventTypeBigPlay, EventTypeLeadChange, EventTypeScoringRun, EventTypeVAROverturn:
 		return "yellow"
 	case EventTypeGameStart, EventTypeGameEnd:
 		return "blue"
@@ -18,6 +18,15 @@ func TestEventPriorityIconColor(t *testing.T) {
 		{EventTypeTouchdown, PriorityHigh, "🏈", "green"},
 		{EventTypeHomeRun, PriorityHigh, "⚾", "green"},
 		{EventTypePenalty, PriorityHigh, "⚠️", "red"},
+		{EventTypeTurnover, PriorityHigh, "🔄", "red"},
+		{EventTypeInterception, PriorityHigh, "🔄", "red"},
+		{EventTypeDriveResult, PriorityNormal, "📰", "gray"},
+		{EventTypeRedZoneEntry, PriorityHigh, "🚩", "yellow"},
+		{EventTypeBigPlay, PriorityNormal, "💥", "yellow"},
+		{EventTypeLeadChange, PriorityHigh, "🔀", "yellow"},
+		{EventTypeScoringRun, PriorityNormal, "🏀", "yellow"},
+		{EventTypeRedCard, PriorityHigh, "🟥", "red"},
+		{EventTypeVAROverturn, PriorityHigh, "📺", "yellow"},
 		{EventTypePowerPlay, PriorityNormal, "⚡", "yellow"},
 		{EventTypeShot, PriorityNormal, "🎯", "gray"},
 		{EventTypeSave, PriorityNormal, "🛡️", "gray"},
@@ -29,6 +29,17 @@ type TeamState struct {
 	// Enhanced team state
 	PeriodScores []int     `json:"periodScores,omitempty"`
 	Statistics   TeamStats `json:"statistics,omitempty"`
+	// Hockey: the team has pulled its goalie for an extra attacker
+	GoaliePulled bool `json:"goaliePulled,omitempty"`
+	// Hockey: how many polls in a row have seen the goalie out; it's only
+	// reported pulled once this reaches the league service's threshold
+	GoalieOutPolls int `json:"goalieOutPolls,omitempty"`
+	// College: the team's poll ranking going into the game, 0 if unranked
+	Rank int `json:"rank,omitempty"`
+	// Football: the ScoringType of the team's latest score, "convert" once
+	// its try is in, so a try reported a poll after its touchdown isn't
+	// taken for a safety
+	LastScoringType string `json:"lastScoringType,omitempty"`
 }
 
 // GameState is a reflection of a games state. It contains the score and status
@@ -45,11 +56,113 @@ type GameState struct {
 	Clock         string  `json:"clock,omitempty"`
 	Venue         Venue   `json:"venue,omitempty"`
 	Weather       Weather `json:"weather,omitempty"`
+	// Hockey shootout progress; only populated once a game reaches a shootout
+	Shootout *ShootoutState `json:"shootout,omitempty"`
+	// Number of completed plays already turned into events, for services that
+	// read a play-by-play feed and must report each play once
+	PlayCursor int `json:"playCursor,omitempty"`
+	// Football: the drive in progress, or the one that just ended (with its
+	// Result set) until the next one starts
+	Drive *DriveState `json:"drive,omitempty"`
+	// Basketball: the points one team has scored since the other last did,
+	// and the team that last held the lead (kept through ties, so a lead
+	// change across a tie is still seen)
+	Run    *RunState `json:"run,omitempty"`
+	Leader string    `json:"leader,omitempty"`
+	// Soccer: minutes of stoppage time played so far in the current half
+	AddedTime int `json:"addedTime,omitempty"`
 	// Baseball-specific details
 	Details    EventDetails `json:"details,omitempty"`
 	Statistics TeamStats    `json:"statistics,omitempty"`
 }
 
+// Period types reported in GameState.PeriodType. Football services use their
+// own "QUARTER"/"HALFTIME" labels alongside these.
+const (
+	PeriodTypeRegular  = "REGULAR"
+	PeriodTypeOvertime = "OVERTIME"
+	PeriodTypeShootout = "SHOOTOUT"
+)
+
+// Goal types reported in EventDetails.GoalType for hockey and soccer goals
+const (
+	GoalTypeEvenStrength = "even_strength"
+	GoalTypePowerPlay    = "power_play"
+	GoalTypeShortHanded  = "short_handed"
+	GoalTypeEmptyNet     = "empty_net"
+	GoalTypePenalty      = "penalty"  // soccer penalty kick
+	GoalTypeOwnGoal      = "own_goal" // soccer, credited to the team that benefits
+)
+
+// Scoring types and conversions reported in EventDetails for football scores
+const (
+	ScoringTypeTouchdown          = "touchdown"
+	ScoringTypeDefensiveTouchdown = "defensive_touchdown"
+	ScoringTypeFieldGoal          = "field_goal"
+	ScoringTypeSafety             = "safety"
+	ScoringTypeSingle             = "single"  // CFL rouge
+	ScoringTypeConvert            = "convert" // CFL convert scored as its own play
+
+	ConversionExtraPoint       = "extra_point"
+	ConversionExtraPointMissed = "extra_point_missed"
+	ConversionTwoPoint         = "two_point"
+	ConversionTwoPointFailed   = "two_point_failed"
+)
+
+// Drive results reported in DriveState.Result
+const (
+	DriveResultTouchdown       = "touchdown"
+	DriveResultFieldGoal       = "field_goal"
+	DriveResultMissedFieldGoal = "missed_field_goal"
+	DriveResultSingle          = "single" // CFL rouge
+	DriveResultSafety          = "safety"
+	DriveResultPunt            = "punt"
+	DriveResultTurnover        = "turnover"
+	DriveResultDowns           = "downs"
+	DriveResultEndOfHalf       = "end_of_half"
+)
+
+// DriveState summarises a football drive. StartYardLine is measured from the
+// offence's own goal line, so a drive starting at the opponent's 40 on a
+// 110-yard CFL field starts at 70.
+type DriveState struct {
+	Id            string `json:"id"`
+	TeamCode      string `json:"teamCode"`
+	Period        int    `json:"period"`
+	StartYardLine int    `json:"startYardLine"`
+	Plays         int    `json:"plays"`
+	Yards         int    `json:"yards"`
+	TimeConsumed  string `json:"timeConsumed,omitempty"` // "m:ss" of game clock
+	RedZone       bool   `json:"redZone,omitempty"`      // the drive has reached the opponent's 20
+	Result        string `json:"result,omitempty"`       // empty while the drive is in progress
+}
+
+// RunState is a basketball scoring run: the points TeamCode has scored
+// without answer since the other team last scored.
+type RunState struct {
+	TeamCode string `json:"teamCode"`
+	Points   int    `json:"points"`
+}
+
+// ShootoutState tracks a hockey or soccer shootout attempt by attempt. The
+// final score of a hockey game decided in a shootout includes one extra
+// "goal" for the winner that was never scored in play, so services use this
+// to keep that point out of goal detection. Soccer scores leave the shootout
+// out.
+type ShootoutState struct {
+	Attempts  []ShootoutAttempt `json:"attempts"`
+	HomeGoals int               `json:"homeGoals"`
+	AwayGoals int               `json:"awayGoals"`
+	Winner    string            `json:"winner,omitempty"` // Team code of the shootout winner, once decided
+}
+
+type ShootoutAttempt struct {
+	Sequence int    `json:"sequence"`
+	TeamCode string `json:"teamCode"`
+	Player   Player `json:"player"`
+	Result   string `json:"result"` // "goal", "save", "miss"
+}
+
 type GameDetails struct {
 	GameId       string        `json:"gameId"`
 	Season       string        `json:"season"`
@@ -160,6 +273,8 @@ const (
 	EventTypeTouchdown    EventType = "touchdown"
 	EventTypeFieldGoal    EventType = "field_goal"
 	EventTypeSafety       EventType = "safety"
+	EventTypeSingle       EventType = "single"
+	EventTypeConvert      EventType = "convert"
 	EventTypeHomeRun      EventType = "home_run"
 	EventTypeStrikeout    EventType = "strikeout"
 	EventTypeWalk         EventType = "walk"
@@ -168,6 +283,25 @@ const (
 	EventTypePeriodEnd    EventType = "period_end"
 	EventTypeGameStart    EventType = "game_start"
 	EventTypeGameEnd      EventType = "game_end"
+	// Hockey shootouts
+	EventTypeShootoutAttempt EventType = "shootout_attempt"
+	EventTypeShootoutResult  EventType = "shootout_result"
+	// Hockey goalie pulled for an extra attacker, and back in net
+	EventTypeGoaliePulled   EventType = "goalie_pulled"
+	EventTypeGoalieReturned EventType = "goalie_returned"
+	// Football drive milestones
+	EventTypeRedZoneEntry EventType = "red_zone_entry"
+	EventTypeBigPlay      EventType = "big_play"
+	EventTypeDriveResult  EventType = "drive_result"
+
+	EventTypeLeadChange EventType = "lead_change"
+	EventTypeScoringRun EventType = "scoring_run"
+	// Soccer sending-offs, and goals or calls reversed by the video referee
+	EventTypeRedCard     EventType = "red_card"
+	EventTypeVAROverturn EventType = "var_overturn"
+	// A play-by-play entry with nothing more specific to say about it, used
+	// for the timeline on Game.Events
+	EventTypePlay EventType = "play"
 )
 
 type Player struct {
@@ -186,10 +320,19 @@ type BaseRunners struct {
 
 type EventDetails struct {
 	// Goal details
-	GoalType string `json:"goalType,omitempty"` // "even_strength", "power_play", "short_handed"
+	GoalType string `json:"goalType,omitempty"` // "even_strength", "power_play", "short_handed", "empty_net", "penalty", "own_goal"
 	Assist1  Player `json:"assist1,omitempty"`
 	Assist2  Player `json:"assist2,omitempty"`
 
+	// Shootout details
+	ShootoutRound  int    `json:"shootoutRound,omitempty"`
+	ShootoutResult string `json:"shootoutResult,omitempty"` // "goal", "save", "miss"
+
+	// Football scoring details
+	ScoringType string `json:"scoringType,omitempty"` // "touchdown", "defensive_touchdown", "field_goal", "safety", "single", "convert"
+	Conversion  string `json:"conversion,omitempty"`  // "extra_point", "extra_point_missed", "two_point", "two_point_failed"
+	Points      int    `json:"points,omitempty"`
+
 	// Penalty details
 	PenaltyType    string `json:"penaltyType,omitempty"`
 	PenaltyMinutes int    `json:"penaltyMinutes,omitempty"`
@@ -200,6 +343,12 @@ type EventDetails struct {
 	Distance    int    `json:"distance,omitempty"`
 	YardsGained int    `json:"yardsGained,omitempty"`
 	Possession  string `json:"possession,omitempty"` // Team code with possession
+	// The drive a red zone entry, turnover, big play or drive result is about
+	Drive *DriveState `json:"drive,omitempty"`
+
+	// Basketball details
+	Lead int       `json:"lead,omitempty"` // the team's margin after a lead change
+	Run  *RunState `json:"run,omitempty"`  // the run a scoring_run event is about
 
 	// Baseball details
 	Inning      int    `json:"inning,omitempty"`
@@ -217,7 +366,13 @@ type EventDetails struct {
 type GameUpdate struct {
 	OldState GameState
 	NewState GameState
-	Events   []GameEvent
+	// Events carries play-level detail a service read from its feed, such as
+	// every scoring play so far, for GetEvents to attach to the events it fires
+	Events []GameEvent
+	// Plays is the game's recent play-by-play, stored on Game.Events as its
+	// timeline. Services that don't read one leave it nil and the stored
+	// timeline is kept.
+	Plays []GameEvent
 }
 
 const (
@@ -11,4 +11,13 @@ const (
 	LeagueIdNFL                 = 6
 	LeagueIdOlympicMensHockey   = 7
 	LeagueIdOlympicWomensHockey = 8
+	LeagueIdNBA                 = 9
+	LeagueIdWNBA                = 10
+	LeagueIdMLS                 = 11
+	LeagueIdUCL                 = 12
+	LeagueIdPWHL                = 13
+	LeagueIdAHL                 = 14
+	LeagueIdNCAAF               = 15
+	LeagueIdNCAAMHockey         = 16
+	LeagueIdNCAAWHockey         = 17
 )
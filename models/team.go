@@ -4,8 +4,13 @@ import (
 	"crypto/md5"
 	"encoding/hex"
 	"fmt"
+	"strings"
 )
 
+// WatchConferencePrefix marks a watch list entry that names a whole
+// conference ("conf:SEC") rather than a team.
+const WatchConferencePrefix = "conf:"
+
 type Team struct {
 	ID       int    `json:"teamId"`
 	TeamCode string `json:"teamCode"`
@@ -13,6 +18,9 @@ type Team struct {
 	LeagueID int    `json:"leagueId"`
 	ExtID    string `json:"extId"`
 	LogoURL  string `json:"logoUrl,omitempty"`
+	// Conference is the college conference the team plays in, for
+	// conference entries in the watch list
+	Conference string `json:"conference,omitempty"`
 }
 
 // GetTeamHash generates a unique has for the team based on the TeamCode and LeagueId
@@ -24,3 +32,31 @@ func (t Team) GetTeamHash() string {
 
 	return hex.EncodeToString(hash[:])
 }
+
+// MatchesWatchEntry reports whether a watch list entry selects the team: "*"
+// for every team, the team's code, or its conference after
+// WatchConferencePrefix. Codes and conferences ignore case.
+func (t Team) MatchesWatchEntry(entry string) bool {
+	entry = strings.TrimSpace(entry)
+	switch entry {
+	case "":
+		return false
+	case "*":
+		return true
+	}
+	if conference, ok := WatchConference(entry); ok {
+		return t.Conference != "" && strings.EqualFold(conference, t.Conference)
+	}
+	return strings.EqualFold(entry, t.TeamCode)
+}
+
+// WatchConference returns the conference a watch list entry names, and
+// whether it is a conference entry at all. The prefix ignores case, so
+// "Conf:SEC" names the SEC too.
+func WatchConference(entry string) (string, bool) {
+	entry = strings.TrimSpace(entry)
+	if len(entry) < len(WatchConferencePrefix) || !strings.EqualFold(entry[:len(WatchConferencePrefix)], WatchConferencePrefix) {
+		return "", false
+	}
+	return strings.TrimSpace(entry[len(WatchConferencePrefix):]), true
+}
@@ -138,3 +138,27 @@ func TestTeam_GetTeamHash_DifferentTeams(t *testing.T) {
 	// Same team should have same hash
 	assert.Equal(t, team1.GetTeamHash(), team1.GetTeamHash())
 }
+
+func TestMatchesWatchEntry(t *testing.T) {
+	team := Team{TeamCode: "OSU", Conference: "Big Ten"}
+	assert.True(t, team.MatchesWatchEntry("*"))
+	assert.True(t, team.MatchesWatchEntry("osu"))
+	assert.True(t, team.MatchesWatchEntry("conf:Big Ten"))
+	assert.True(t, team.MatchesWatchEntry("conf:big ten"))
+	assert.True(t, team.MatchesWatchEntry("Conf:Big Ten"))
+	assert.True(t, team.MatchesWatchEntry(" CONF: big ten "))
+	assert.False(t, team.MatchesWatchEntry("conf:SEC"))
+	assert.False(t, team.MatchesWatchEntry("MICH"))
+	assert.False(t, team.MatchesWatchEntry(""))
+	assert.False(t, Team{TeamCode: "WPG"}.MatchesWatchEntry("conf:"), "a team without a conference matches no conference entry")
+}
+
+func TestWatchConference(t *testing.T) {
+	conference, ok := WatchConference("Conf:SEC")
+	assert.True(t, ok)
+	assert.Equal(t, "SEC", conference)
+	_, ok = WatchConference("CON")
+	assert.False(t, ok)
+	_, ok = WatchConference("OSU")
+	assert.False(t, ok)
+}
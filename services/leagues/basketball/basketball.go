@@ -0,0 +1,228 @@
+package basketball
+
+import (
+	"goalfeed/clients/leagues/basketball"
+	"goalfeed/models"
+	"goalfeed/services/leagues/espn"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// regulationPeriods is the number of quarters before overtime
+const regulationPeriods = 4
+
+// BasketballService follows one ESPN basketball league; League is
+// models.LeagueIdNBA or models.LeagueIdWNBA.
+type BasketballService struct {
+	Client basketball.IBasketballApiClient
+	League models.League
+}
+
+func (s BasketballService) GetLeagueName() string {
+	if s.League == models.LeagueIdWNBA {
+		return "WNBA"
+	}
+	return "NBA"
+}
+
+func (s BasketballService) GetActiveGames(ret chan []models.Game) {
+	var activeGames []models.Game
+	for _, event := range s.Client.GetSchedule().Events {
+		if gameStatusFromStatus(event.Status) != models.StatusActive {
+			continue
+		}
+		game := s.gameFromEvent(event)
+		// Start from the summary's state so the first update doesn't report
+		// a run or lead change that happened before the game was picked up
+		if summary := s.Client.GetSummary(event.ID); len(summary.Header.Competitions) > 0 {
+			game.CurrentState = s.gameStateFromSummary(game.CurrentState, summary)
+		}
+		activeGames = append(activeGames, game)
+	}
+	ret <- activeGames
+}
+
+func (s BasketballService) GetUpcomingGames(ret chan []models.Game) {
+	var upcomingGames []models.Game
+	for _, event := range s.Client.GetSchedule().Events {
+		if gameStatusFromStatus(event.Status) == models.StatusUpcoming {
+			upcomingGames = append(upcomingGames, s.gameFromEvent(event))
+		}
+	}
+	ret <- upcomingGames
+}
+
+func (s BasketballService) GetGamesByDate(date string, ret chan []models.Game) {
+	// Convert YYYY-MM-DD to YYYYMMDD for ESPN
+	schedule := s.Client.GetScheduleByDate(strings.ReplaceAll(date, "-", ""))
+	var games []models.Game
+	for _, event := range schedule.Events {
+		games = append(games, s.gameFromEvent(event))
+	}
+	ret <- games
+}
+
+func (s BasketballService) GetGameUpdate(game models.Game, ret chan models.GameUpdate) {
+	summary := s.Client.GetSummary(game.GameCode)
+	ret <- models.GameUpdate{
+		OldState: game.CurrentState,
+		NewState: s.gameStateFromSummary(game.CurrentState, summary),
+	}
+}
+
+func (s BasketballService) GetEvents(update models.GameUpdate, ret chan []models.Event) {
+	events := []models.Event{}
+	if event, ok := s.leadChangeEvent(update); ok {
+		events = append(events, event)
+	}
+	if event, ok := s.scoringRunEvent(update); ok {
+		events = append(events, event)
+	}
+	ret <- events
+}
+
+// gameStatusFromStatus reads an event's status the ESPN way.
+func gameStatusFromStatus(status basketball.BasketballStatus) models.GameStatus {
+	return espn.GameStatus(status.Type.State, status.Type.Completed, status.Type.Name)
+}
+
+// homeAway is the side a competitor plays on, for espn.CompetitorsBySide.
+func homeAway(competitor basketball.BasketballCompetitor) string {
+	return competitor.HomeAway
+}
+
+// periodType labels overtime periods; regulation quarters are "QUARTER" as
+// in football.
+func periodType(period int) string {
+	if period > regulationPeriods {
+		return models.PeriodTypeOvertime
+	}
+	return "QUARTER"
+}
+
+func (s BasketballService) teamFromCompetitor(competitor basketball.BasketballCompetitor) models.Team {
+	logo := competitor.Team.Logo
+	if logo == "" && len(competitor.Team.Logos) > 0 {
+		logo = competitor.Team.Logos[0].Href
+	}
+	return models.Team{
+		TeamName: competitor.Team.DisplayName,
+		TeamCode: competitor.Team.Abbreviation,
+		ExtID:    competitor.Team.ID,
+		LeagueID: int(s.League),
+		LogoURL:  logo,
+	}
+}
+
+// setClock fills in the period and clock from an ESPN status, labelling
+// halftime the way the football services do.
+func setClock(state *models.GameState, status basketball.BasketballStatus) {
+	state.Period = status.Period
+	state.PeriodType = periodType(status.Period)
+	state.Clock = status.DisplayClock
+	if strings.Contains(strings.ToLower(status.Type.ShortDetail), "halftime") {
+		state.PeriodType = "HALFTIME"
+		state.Clock = "HALFTIME"
+	}
+	state.TimeRemaining = state.Clock
+}
+
+func (s BasketballService) gameFromEvent(event basketball.BasketballEvent) models.Game {
+	var home, away basketball.BasketballCompetitor
+	var venue models.Venue
+	if len(event.Competitions) > 0 {
+		competition := event.Competitions[0]
+		home, away = espn.CompetitorsBySide(competition.Competitors, homeAway)
+		venue = models.Venue{
+			Id:     competition.Venue.ID,
+			Name:   competition.Venue.FullName,
+			City:   competition.Venue.Address.City,
+			State:  competition.Venue.Address.State,
+			Indoor: true,
+		}
+	}
+	homeScore, _ := strconv.Atoi(home.Score)
+	awayScore, _ := strconv.Atoi(away.Score)
+	gameDate := espn.ParseDate(event.Date)
+
+	state := models.GameState{
+		ExtTimestamp: event.Date,
+		Home:         models.TeamState{Team: s.teamFromCompetitor(home), Score: homeScore},
+		Away:         models.TeamState{Team: s.teamFromCompetitor(away), Score: awayScore},
+		Status:       gameStatusFromStatus(event.Status),
+		FetchedAt:    time.Now(),
+		Venue:        venue,
+	}
+	setClock(&state, event.Status)
+	state.Leader = leaderOf(state)
+
+	gameTime := "TBD"
+	if !gameDate.IsZero() {
+		gameTime = gameDate.Format("3:04 PM")
+	}
+	if state.Status == models.StatusUpcoming {
+		state.Clock = gameTime
+		state.TimeRemaining = ""
+	}
+
+	seasonType := "REGULAR"
+	if event.Season.Type == 3 {
+		seasonType = "POSTSEASON"
+	}
+	return models.Game{
+		CurrentState: state,
+		GameCode:     event.ID,
+		ExtTimestamp: event.Date,
+		LeagueId:     s.League,
+		GameDetails: models.GameDetails{
+			GameId:     event.ID,
+			Season:     strconv.Itoa(event.Season.Year),
+			SeasonType: seasonType,
+			GameDate:   gameDate,
+			GameTime:   gameTime,
+			Timezone:   "UTC",
+		},
+	}
+}
+
+// gameStateFromSummary builds the game's new state from a summary. The old
+// state is returned unchanged when the summary carries no competitors.
+func (s BasketballService) gameStateFromSummary(old models.GameState, summary basketball.BasketballSummaryResponse) models.GameState {
+	if len(summary.Header.Competitions) == 0 {
+		return old
+	}
+	competition := summary.Header.Competitions[0]
+	home, away := espn.CompetitorsBySide(competition.Competitors, homeAway)
+	if home.Team.Abbreviation == "" || away.Team.Abbreviation == "" {
+		return old
+	}
+	homeScore, _ := strconv.Atoi(home.Score)
+	awayScore, _ := strconv.Atoi(away.Score)
+
+	state := old
+	state.Home = models.TeamState{Team: s.teamFromCompetitor(home), Score: homeScore}
+	state.Away = models.TeamState{Team: s.teamFromCompetitor(away), Score: awayScore}
+	state.Status = gameStatusFromStatus(competition.Status)
+	state.FetchedAt = time.Now()
+	setClock(&state, competition.Status)
+	if venue := summary.GameInfo.Venue; venue.FullName != "" {
+		state.Venue = models.Venue{
+			Id:     venue.ID,
+			Name:   venue.FullName,
+			City:   venue.Address.City,
+			State:  venue.Address.State,
+			Indoor: true,
+		}
+	}
+
+	if len(summary.Plays) > 0 {
+		state.Run, state.Leader = runFromPlays(summary.Plays, state)
+	} else {
+		state.Run = extendRun(old.Run, state, homeScore-old.Home.Score, awayScore-old.Away.Score)
+		if leader := leaderOf(state); leader != "" {
+			state.Leader = leader
+		}
+	}
+	return state
+}
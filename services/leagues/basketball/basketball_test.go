@@ -0,0 +1,208 @@
+package basketball
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"testing"
+
+	basketballClients "goalfeed/clients/leagues/basketball"
+	"goalfeed/models"
+
+	"github.com/spf13/viper"
+)
+
+// summaryJSON is a BOS @ LAL summary in the third quarter with the given
+// plays.
+func summaryJSON(t *testing.T, homeScore int, awayScore int, plays string) basketballClients.BasketballSummaryResponse {
+	t.Helper()
+	body := fmt.Sprintf(`{
+		"header": {"id": "401", "season": {"year": 2025}, "competitions": [{
+			"competitors": [
+				{"homeAway": "home", "score": "%d", "team": {"id": "13", "abbreviation": "LAL", "displayName": "Los Angeles Lakers", "logos": [{"href": "lal.png"}]}},
+				{"homeAway": "away", "score": "%d", "team": {"id": "2", "abbreviation": "BOS", "displayName": "Boston Celtics", "logos": [{"href": "bos.png"}]}}
+			],
+			"status": {"period": 3, "displayClock": "5:32", "type": {"state": "in", "shortDetail": "5:32 - 3rd"}}
+		}]},
+		"gameInfo": {"venue": {"id": "1", "fullName": "Crypto.com Arena", "address": {"city": "Los Angeles", "state": "CA"}}},
+		"plays": %s
+	}`, homeScore, awayScore, plays)
+	var summary basketballClients.BasketballSummaryResponse
+	if err := json.Unmarshal([]byte(body), &summary); err != nil {
+		t.Fatalf("bad fixture: %v", err)
+	}
+	return summary
+}
+
+func getBasketballUpdate(t *testing.T, summary basketballClients.BasketballSummaryResponse, old models.GameState) (models.GameUpdate, []models.Event) {
+	t.Helper()
+	service := BasketballService{Client: basketballClients.MockBasketballApiClient{SummaryResponse: summary}, League: models.LeagueIdNBA}
+	updates := make(chan models.GameUpdate)
+	go service.GetGameUpdate(models.Game{GameCode: "401", LeagueId: models.LeagueIdNBA, CurrentState: old}, updates)
+	update := <-updates
+	events := make(chan []models.Event)
+	go service.GetEvents(update, events)
+	return update, <-events
+}
+
+func basketballState(homeScore int, awayScore int, leader string, run *models.RunState) models.GameState {
+	return models.GameState{
+		Home:   models.TeamState{Team: models.Team{TeamCode: "LAL"}, Score: homeScore},
+		Away:   models.TeamState{Team: models.Team{TeamCode: "BOS"}, Score: awayScore},
+		Status: models.StatusActive,
+		Leader: leader,
+		Run:    run,
+	}
+}
+
+func eventTypes(events []models.Event) []string {
+	var types []string
+	for _, event := range events {
+		types = append(types, event.TeamCode+" "+string(event.Type))
+	}
+	return types
+}
+
+// BOS led 60-58 until LAL scored the last 10 points: a tying basket, a three
+// for the lead, then five more.
+const lakersRun = `[
+	{"id": "1", "homeScore": 58, "awayScore": 60, "scoringPlay": true},
+	{"id": "2", "homeScore": 60, "awayScore": 60, "scoringPlay": true},
+	{"id": "3", "homeScore": 60, "awayScore": 60, "text": "Tatum misses 3"},
+	{"id": "4", "homeScore": 63, "awayScore": 60, "scoringPlay": true},
+	{"id": "5", "homeScore": 65, "awayScore": 60, "scoringPlay": true},
+	{"id": "6", "homeScore": 68, "awayScore": 60, "scoringPlay": true}
+]`
+
+func TestGetGameUpdate_StateFromSummary(t *testing.T) {
+	update, _ := getBasketballUpdate(t, summaryJSON(t, 68, 60, lakersRun), models.GameState{})
+	state := update.NewState
+
+	if state.Home.Team.TeamCode != "LAL" || state.Home.Score != 68 || state.Away.Score != 60 || state.Home.Team.LogoURL != "lal.png" {
+		t.Errorf("unexpected teams %+v / %+v", state.Home, state.Away)
+	}
+	if state.Status != models.StatusActive || state.Period != 3 || state.PeriodType != "QUARTER" || state.Clock != "5:32" {
+		t.Errorf("unexpected status %v, period %d %s, clock %s", state.Status, state.Period, state.PeriodType, state.Clock)
+	}
+	if state.Run == nil || *state.Run != (models.RunState{TeamCode: "LAL", Points: 10}) || state.Leader != "LAL" {
+		t.Errorf("expected LAL leading on a 10-point run, got %+v led by %s", state.Run, state.Leader)
+	}
+	if state.Venue.Name != "Crypto.com Arena" {
+		t.Errorf("unexpected venue %+v", state.Venue)
+	}
+}
+
+func TestGetEvents_LeadChangeAndRun(t *testing.T) {
+	old := basketballState(60, 60, "BOS", &models.RunState{TeamCode: "LAL", Points: 2})
+	_, events := getBasketballUpdate(t, summaryJSON(t, 68, 60, lakersRun), old)
+
+	got := eventTypes(events)
+	if len(got) != 2 || got[0] != "LAL lead_change" || got[1] != "LAL scoring_run" {
+		t.Fatalf("expected a LAL lead change and run, got %v", got)
+	}
+	if events[0].Description != "LAL take the lead, 68-60" || events[0].Details.Lead != 8 || events[0].OpponentCode != "BOS" {
+		t.Errorf("unexpected lead change %+v", events[0])
+	}
+	if events[1].Description != "LAL on a 10-0 run" || events[1].Details.Run == nil || events[1].Details.Run.Points != 10 {
+		t.Errorf("unexpected run %+v", events[1])
+	}
+	if events[1].LeagueId != models.LeagueIdNBA || events[1].LeagueName != "NBA" {
+		t.Errorf("unexpected league %d %s", events[1].LeagueId, events[1].LeagueName)
+	}
+}
+
+func TestGetEvents_ReportedOnce(t *testing.T) {
+	old := basketballState(68, 60, "LAL", &models.RunState{TeamCode: "LAL", Points: 10})
+	_, events := getBasketballUpdate(t, summaryJSON(t, 68, 60, lakersRun), old)
+	if len(events) != 0 {
+		t.Errorf("expected nothing new, got %v", eventTypes(events))
+	}
+
+	_, events = getBasketballUpdate(t, summaryJSON(t, 68, 60, lakersRun), models.GameState{})
+	if len(events) != 0 {
+		t.Errorf("expected nothing on the first read, got %v", eventTypes(events))
+	}
+}
+
+func TestGetEvents_Configurable(t *testing.T) {
+	viper.Set("basketball.lead_change_events", false)
+	viper.Set("basketball.run_points", 12)
+	defer viper.Set("basketball.lead_change_events", defaultLeadChangeEvents)
+	defer viper.Set("basketball.run_points", defaultRunPoints)
+
+	old := basketballState(60, 60, "BOS", &models.RunState{TeamCode: "LAL", Points: 2})
+	_, events := getBasketballUpdate(t, summaryJSON(t, 68, 60, lakersRun), old)
+	if len(events) != 0 {
+		t.Errorf("expected no lead change and a run short of 12, got %v", eventTypes(events))
+	}
+
+	viper.Set("basketball.run_points", 8)
+	_, events = getBasketballUpdate(t, summaryJSON(t, 68, 60, lakersRun), old)
+	if got := eventTypes(events); len(got) != 1 || got[0] != "LAL scoring_run" {
+		t.Errorf("expected only the run at 8 points, got %v", got)
+	}
+}
+
+// Without a play-by-play, runs and the lead come from how the score moved
+// between polls.
+func TestGameStateFromSummary_NoPlays(t *testing.T) {
+	service := BasketballService{League: models.LeagueIdWNBA}
+	old := basketballState(60, 62, "BOS", &models.RunState{TeamCode: "LAL", Points: 5})
+
+	state := service.gameStateFromSummary(old, summaryJSON(t, 64, 62, `[]`))
+	if state.Run == nil || *state.Run != (models.RunState{TeamCode: "LAL", Points: 9}) || state.Leader != "LAL" {
+		t.Errorf("expected LAL's run to reach 9 and take the lead, got %+v led by %s", state.Run, state.Leader)
+	}
+	if state.Home.Team.LeagueID != models.LeagueIdWNBA {
+		t.Errorf("expected WNBA teams, got league %d", state.Home.Team.LeagueID)
+	}
+
+	state = service.gameStateFromSummary(state, summaryJSON(t, 64, 64, `[]`))
+	if state.Run == nil || *state.Run != (models.RunState{TeamCode: "BOS", Points: 2}) || state.Leader != "LAL" {
+		t.Errorf("expected a new BOS run with LAL keeping the lead through the tie, got %+v led by %s", state.Run, state.Leader)
+	}
+
+	state = service.gameStateFromSummary(state, summaryJSON(t, 66, 67, `[]`))
+	if state.Run != nil {
+		t.Errorf("expected an unknown run when both teams scored, got %+v", state.Run)
+	}
+}
+
+func TestGameFromEvent(t *testing.T) {
+	body := `{"events": [{"id": "401", "date": "2025-10-22T23:30Z", "season": {"year": 2026, "type": 2},
+		"competitions": [{"venue": {"fullName": "TD Garden"}, "competitors": [
+			{"homeAway": "home", "score": "0", "team": {"id": "2", "abbreviation": "BOS", "displayName": "Boston Celtics", "logo": "bos.png"}},
+			{"homeAway": "away", "score": "0", "team": {"id": "18", "abbreviation": "NY", "displayName": "New York Knicks", "logo": "ny.png"}}
+		]}],
+		"status": {"period": 0, "displayClock": "0.0", "type": {"state": "pre", "shortDetail": "10/22 - 7:30 PM EDT"}}}]}`
+	var schedule basketballClients.BasketballScheduleResponse
+	if err := json.Unmarshal([]byte(body), &schedule); err != nil {
+		t.Fatalf("bad fixture: %v", err)
+	}
+	service := BasketballService{Client: basketballClients.MockBasketballApiClient{ScheduleResponse: schedule}, League: models.LeagueIdNBA}
+
+	games := make(chan []models.Game)
+	go service.GetUpcomingGames(games)
+	upcoming := <-games
+	if len(upcoming) != 1 {
+		t.Fatalf("expected one upcoming game, got %d", len(upcoming))
+	}
+	game := upcoming[0]
+	if game.GameCode != "401" || game.LeagueId != models.LeagueIdNBA || game.CurrentState.Away.Team.TeamCode != "NY" || game.CurrentState.Home.Team.LogoURL != "bos.png" {
+		t.Errorf("unexpected game %+v", game)
+	}
+	if game.CurrentState.Status != models.StatusUpcoming || game.GameDetails.GameDate.IsZero() || !strings.HasSuffix(game.CurrentState.Clock, "PM") {
+		t.Errorf("expected an upcoming game with a start time, got %+v", game.CurrentState)
+	}
+
+	go service.GetActiveGames(games)
+	if active := <-games; len(active) != 0 {
+		t.Errorf("expected no active games, got %d", len(active))
+	}
+}
+
+func TestPeriodType(t *testing.T) {
+	if periodType(4) != "QUARTER" || periodType(5) != models.PeriodTypeOvertime {
+		t.Errorf("expected the fifth period to be overtime")
+	}
+}
@@ -0,0 +1,163 @@
+package basketball
+
+import (
+	"fmt"
+
+	"goalfeed/clients/leagues/basketball"
+	"goalfeed/models"
+
+	"github.com/spf13/viper"
+)
+
+// leaderOf is the team ahead in the state, or "" when the game is tied.
+func leaderOf(state models.GameState) string {
+	switch {
+	case state.Home.Score > state.Away.Score:
+		return state.Home.Team.TeamCode
+	case state.Away.Score > state.Home.Score:
+		return state.Away.Team.TeamCode
+	}
+	return ""
+}
+
+// extendRun carries a scoring run on through points scored by one side.
+// Points for the other side start a new run; when both sides scored, the
+// order is unknown and so is the run.
+func extendRun(run *models.RunState, state models.GameState, homePoints int, awayPoints int) *models.RunState {
+	var team string
+	var points int
+	switch {
+	case homePoints > 0 && awayPoints > 0:
+		return nil
+	case homePoints > 0:
+		team, points = state.Home.Team.TeamCode, homePoints
+	case awayPoints > 0:
+		team, points = state.Away.Team.TeamCode, awayPoints
+	default:
+		return run
+	}
+	if run != nil && run.TeamCode == team {
+		return &models.RunState{TeamCode: team, Points: run.Points + points}
+	}
+	return &models.RunState{TeamCode: team, Points: points}
+}
+
+// runFromPlays replays the play-by-play's running score to find the current
+// run and the team that last held the lead.
+func runFromPlays(plays []basketball.BasketballPlay, state models.GameState) (*models.RunState, string) {
+	var run *models.RunState
+	var leader string
+	home, away := 0, 0
+	for _, play := range plays {
+		if play.HomeScore == home && play.AwayScore == away {
+			continue
+		}
+		run = extendRun(run, state, play.HomeScore-home, play.AwayScore-away)
+		home, away = play.HomeScore, play.AwayScore
+		switch {
+		case home > away:
+			leader = state.Home.Team.TeamCode
+		case away > home:
+			leader = state.Away.Team.TeamCode
+		}
+	}
+	return run, leader
+}
+
+// Defaults for when config.go's haven't been loaded
+const (
+	defaultRunPoints        = 10
+	defaultLeadChangeEvents = true
+)
+
+// runPoints is how many unanswered points make a run worth an event, or 0
+// when run events are off.
+func runPoints() int {
+	if !viper.IsSet("basketball.run_points") {
+		return defaultRunPoints
+	}
+	return viper.GetInt("basketball.run_points")
+}
+
+func leadChangeEvents() bool {
+	if !viper.IsSet("basketball.lead_change_events") {
+		return defaultLeadChangeEvents
+	}
+	return viper.GetBool("basketball.lead_change_events")
+}
+
+// leadChangeEvent fires when the lead passes from one team to the other,
+// through a tie or not.
+func (s BasketballService) leadChangeEvent(update models.GameUpdate) (models.Event, bool) {
+	old, current := update.OldState.Leader, update.NewState.Leader
+	if !leadChangeEvents() || old == "" || current == "" || old == current {
+		return models.Event{}, false
+	}
+	event := s.teamEvent(update, models.EventTypeLeadChange, current)
+	event.Details.Lead = margin(update.NewState)
+	event.Description = fmt.Sprintf("%s take the lead, %d-%d", current, leadingScore(update.NewState), leadingScore(update.NewState)-event.Details.Lead)
+	event.Id = fmt.Sprintf("lead-%s-%d-%d", current, update.NewState.Home.Score, update.NewState.Away.Score)
+	return event, true
+}
+
+// scoringRunEvent fires once when a run reaches basketball.run_points. No
+// old run means it wasn't known, as on a game's first read, so nothing is
+// reported.
+func (s BasketballService) scoringRunEvent(update models.GameUpdate) (models.Event, bool) {
+	threshold := runPoints()
+	run, old := update.NewState.Run, update.OldState.Run
+	if threshold <= 0 || run == nil || old == nil || run.Points < threshold {
+		return models.Event{}, false
+	}
+	if old.TeamCode == run.TeamCode && old.Points >= threshold && old.Points <= run.Points {
+		return models.Event{}, false
+	}
+	event := s.teamEvent(update, models.EventTypeScoringRun, run.TeamCode)
+	event.Details.Run = &models.RunState{TeamCode: run.TeamCode, Points: run.Points}
+	event.Details.Points = run.Points
+	event.Description = fmt.Sprintf("%s on a %d-0 run", run.TeamCode, run.Points)
+	event.Id = fmt.Sprintf("run-%s-%d-%d", run.TeamCode, update.NewState.Home.Score, update.NewState.Away.Score)
+	return event, true
+}
+
+func margin(state models.GameState) int {
+	if state.Home.Score > state.Away.Score {
+		return state.Home.Score - state.Away.Score
+	}
+	return state.Away.Score - state.Home.Score
+}
+
+func leadingScore(state models.GameState) int {
+	if state.Home.Score > state.Away.Score {
+		return state.Home.Score
+	}
+	return state.Away.Score
+}
+
+func (s BasketballService) teamEvent(update models.GameUpdate, eventType models.EventType, teamCode string) models.Event {
+	state := update.NewState
+	team, opponent := state.Home.Team, state.Away.Team
+	if teamCode == state.Away.Team.TeamCode {
+		team, opponent = opponent, team
+	}
+	return models.Event{
+		Type:         eventType,
+		TeamCode:     team.TeamCode,
+		TeamName:     team.TeamName,
+		TeamHash:     team.GetTeamHash(),
+		LeagueId:     int(s.League),
+		LeagueName:   s.GetLeagueName(),
+		Period:       state.Period,
+		PeriodType:   state.PeriodType,
+		Clock:        state.Clock,
+		OpponentCode: opponent.TeamCode,
+		OpponentName: opponent.TeamName,
+		OpponentHash: opponent.GetTeamHash(),
+		Score: models.ScoreUpdate{
+			HomeScore: state.Home.Score,
+			AwayScore: state.Away.Score,
+			HomeTeam:  state.Home.Team.TeamCode,
+			AwayTeam:  state.Away.Team.TeamCode,
+		},
+	}
+}
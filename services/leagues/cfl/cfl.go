@@ -1,6 +1,7 @@
 package cfl
 
 import (
+	"fmt"
 	"goalfeed/clients/leagues/cfl"
 	"goalfeed/models"
 	"goalfeed/utils"
@@ -184,10 +185,24 @@ func (s CFLService) GetGameUpdate(game models.Game, ret chan models.GameUpdate)
 
 	logger.Infof("CFL GetGameUpdate: New state - Status='%s', Period=%d, Clock='%s'", newState.Status, newState.Period, newState.Clock)
 
-	ret <- models.GameUpdate{
+	plays := playsFromLiveGame(liveGame)
+	sides := sidesFromLiveGame(liveGame, newState)
+	drives := drivesFromPlays(plays, sides, newState)
+	newState.PlayCursor = nextPlayCursor(plays, game.CurrentState.PlayCursor)
+	newState.Drive = currentDrive(drives)
+
+	update := models.GameUpdate{
 		OldState: game.CurrentState,
 		NewState: newState,
+		Events: append(
+			scoringPlaysSince(plays, game.CurrentState.PlayCursor, sides, game.CurrentState, newState),
+			playEventsSince(drives, game.CurrentState.PlayCursor, newState)...,
+		),
+	}
+	if len(plays) > 0 {
+		update.Plays = timelineFromDrives(drives, newState)
 	}
+	ret <- update
 }
 
 func (s CFLService) teamFromCFLTeam(cflTeam cfl.CFLTeam) models.Team {
@@ -385,28 +400,63 @@ func gameStatusFromLiveGame(matchStatus string) models.GameStatus {
 
 func (s CFLService) GetEvents(update models.GameUpdate, ret chan []models.Event) {
 	events := append(
-		s.getTouchdownEvents(update.OldState.Home, update.NewState.Home, update.OldState.Away.Team),
-		s.getTouchdownEvents(update.OldState.Away, update.NewState.Away, update.OldState.Home.Team)...,
+		s.getScoringEvents(update, update.OldState.Home, update.NewState.Home, update.OldState.Away.Team),
+		s.getScoringEvents(update, update.OldState.Away, update.NewState.Away, update.OldState.Home.Team)...,
 	)
+	events = append(events, s.getPlayEvents(update)...)
 	ret <- events
 }
 
-func (s CFLService) getTouchdownEvents(oldState models.TeamState, newState models.TeamState, opponent models.Team) []models.Event {
+// getScoringEvents fires one event per score by the team, using the scoring
+// plays the update carries and classifying any points they don't explain.
+func (s CFLService) getScoringEvents(update models.GameUpdate, oldState models.TeamState, newState models.TeamState, opponent models.Team) []models.Event {
 	events := []models.Event{}
 	diff := newState.Score - oldState.Score
+	if diff <= 0 {
+		return events
+	}
 	team := newState.Team
 
-	// Emit a single scoring event per update if the score increased
-	if diff > 0 {
+	var plays []models.GameEvent
+	explained := 0
+	for _, play := range update.Events {
+		if play.Team.TeamCode == team.TeamCode && play.Details.Points > 0 {
+			plays = append(plays, play)
+			explained += play.Details.Points
+		}
+	}
+	if explained < diff {
+		plays = append(plays, scoringFromPoints(diff-explained, "", team, update.NewState)...)
+	}
+
+	for _, play := range plays {
+		description := play.Description
+		if description == "" {
+			description = fmt.Sprintf("%s for %s", scoringDescription(play.Details.ScoringType, play.Details.Conversion), team.TeamCode)
+		}
 		events = append(events, models.Event{
+			Id:           play.Id,
+			Type:         play.Type,
+			Description:  description,
 			TeamCode:     team.TeamCode,
 			TeamName:     team.TeamName,
 			TeamHash:     team.GetTeamHash(),
+			PlayerName:   play.Player.Name,
 			LeagueId:     models.LeagueIdCFL,
 			LeagueName:   s.GetLeagueName(),
+			Period:       play.Period,
+			PeriodType:   update.NewState.PeriodType,
+			Clock:        play.Clock,
 			OpponentCode: opponent.TeamCode,
 			OpponentName: opponent.TeamName,
 			OpponentHash: opponent.GetTeamHash(),
+			Details:      play.Details,
+			Score: models.ScoreUpdate{
+				HomeScore: update.NewState.Home.Score,
+				AwayScore: update.NewState.Away.Score,
+				HomeTeam:  update.NewState.Home.Team.TeamCode,
+				AwayTeam:  update.NewState.Away.Team.TeamCode,
+			},
 		})
 	}
 	return events
@@ -0,0 +1,393 @@
+package cfl
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"goalfeed/models"
+)
+
+const (
+	// fieldLength is goal line to goal line on a CFL field
+	fieldLength = 110
+	// redZoneYards is how close to the opponent's goal line the red zone starts
+	redZoneYards = 20
+	// bigPlayYards is the gain that counts as a big play when the feed
+	// doesn't flag one itself
+	bigPlayYards = 30
+	// quarterSeconds is the length of a CFL quarter
+	quarterSeconds = 15 * 60
+	// maxTimelinePlays caps the play-by-play kept on Game.Events, which is
+	// sent to web clients with every games list
+	maxTimelinePlays = 100
+)
+
+// cflDrive is a drive and the plays that make it up, in order.
+type cflDrive struct {
+	state models.DriveState
+	plays []cflPlay
+	home  bool
+	known bool
+}
+
+// sideName is how the feed names a side's half of the field.
+func sideName(home bool) string {
+	if home {
+		return "Home"
+	}
+	return "Away"
+}
+
+// fieldPosition converts a yard line in one side's half into yards from the
+// offence's own goal line. It returns 0 when the side isn't known.
+func fieldPosition(side string, yard int, offenceHome bool) int {
+	switch {
+	case side == "" || yard <= 0:
+		return 0
+	case strings.EqualFold(side, sideName(offenceHome)):
+		return yard
+	default:
+		return fieldLength - yard
+	}
+}
+
+// inRedZone reports whether a yard line is within redZoneYards of the goal
+// line the offence is attacking.
+func inRedZone(side string, yard int, offenceHome bool) bool {
+	position := fieldPosition(side, yard, offenceHome)
+	return position > 0 && position >= fieldLength-redZoneYards
+}
+
+// gameSeconds is how far into the game a quarter and its countdown clock are.
+func gameSeconds(quarter int, clock string) (int, bool) {
+	minutes, seconds, ok := strings.Cut(clock, ":")
+	if !ok || quarter <= 0 {
+		return 0, false
+	}
+	m, errM := strconv.Atoi(strings.TrimSpace(minutes))
+	sec, errS := strconv.Atoi(strings.TrimSpace(seconds))
+	if errM != nil || errS != nil {
+		return 0, false
+	}
+	return (quarter-1)*quarterSeconds + quarterSeconds - (m*60 + sec), true
+}
+
+func playQuarter(play cflPlay, state models.GameState) int {
+	if play.Quarter > 0 {
+		return play.Quarter
+	}
+	return state.Period
+}
+
+// countsAsSnap reports whether a play belongs in a drive's play and yard
+// counts. Kickoffs and converts are run between drives.
+func countsAsSnap(play cflPlay) bool {
+	kind := strings.ToLower(play.Type)
+	if strings.Contains(kind, "kickoff") {
+		return false
+	}
+	scoringType, _, _ := classifyPlay(play)
+	return scoringType != models.ScoringTypeConvert
+}
+
+func isPunt(play cflPlay) bool {
+	return strings.Contains(strings.ToLower(play.Type), "punt")
+}
+
+func isFieldGoalTry(play cflPlay) bool {
+	kind := strings.NewReplacer(" ", "", "_", "").Replace(strings.ToLower(play.Type))
+	return strings.Contains(kind, "fieldgoal")
+}
+
+// isTurnover reports whether the offence gave the ball away on a play, by
+// interception, fumble or on downs. Punts, field goal tries and kickoffs
+// change possession without being turnovers.
+func isTurnover(play cflPlay) bool {
+	if isPunt(play) || isFieldGoalTry(play) || !countsAsSnap(play) {
+		return false
+	}
+	if play.ScoringKnown {
+		scoringType, _, _ := classifyPlay(play)
+		return play.ChangeOfPossession && (!play.Scoring || scoringType == models.ScoringTypeDefensiveTouchdown)
+	}
+	text := strings.ToLower(play.Type + " " + play.Description)
+	return strings.Contains(text, "intercept") || strings.Contains(text, "fumble_lost") ||
+		strings.Contains(text, "fumble lost") || strings.Contains(text, "turnover")
+}
+
+func isBigPlay(play cflPlay) bool {
+	return play.BigPlay || play.Yards >= bigPlayYards
+}
+
+// driveResult names how a play ended its drive, or returns "" when the
+// drive carried on after it.
+func driveResult(play cflPlay) string {
+	scoringType, _, _ := classifyPlay(play)
+	switch scoringType {
+	case models.ScoringTypeTouchdown:
+		return models.DriveResultTouchdown
+	case models.ScoringTypeDefensiveTouchdown:
+		return models.DriveResultTurnover
+	case models.ScoringTypeFieldGoal:
+		return models.DriveResultFieldGoal
+	case models.ScoringTypeSingle:
+		return models.DriveResultSingle
+	case models.ScoringTypeSafety:
+		return models.DriveResultSafety
+	}
+	switch {
+	case isPunt(play):
+		return models.DriveResultPunt
+	case isFieldGoalTry(play):
+		return models.DriveResultMissedFieldGoal
+	case isTurnover(play):
+		text := strings.ToLower(play.Description)
+		lostBall := strings.Contains(text, "intercept") || strings.Contains(text, "fumble")
+		if strings.Contains(text, "downs") || (play.Down == 3 && !lostBall) {
+			return models.DriveResultDowns
+		}
+		return models.DriveResultTurnover
+	}
+	return ""
+}
+
+// drivesFromPlays groups the plays into drives and summarises each. A drive
+// with no play that ended it is still in progress, unless a later drive has
+// started since: then a drive that ran out the second or fourth quarter
+// ended with the half.
+func drivesFromPlays(plays []cflPlay, sides cflSides, state models.GameState) []cflDrive {
+	var drives []cflDrive
+	for _, play := range plays {
+		if len(drives) == 0 || drives[len(drives)-1].state.Id != play.DriveID {
+			drives = append(drives, cflDrive{state: models.DriveState{Id: play.DriveID}})
+		}
+		drive := &drives[len(drives)-1]
+		drive.plays = append(drive.plays, play)
+	}
+
+	for i := range drives {
+		drive := &drives[i]
+		// A drive can open with the kickoff that started it, which the
+		// other side ran, so the offence and start come from the first snap
+		first := drive.plays[0]
+		for _, play := range drive.plays {
+			if countsAsSnap(play) {
+				first = play
+				break
+			}
+		}
+		drive.home, drive.known = sides.offenceIsHome(first)
+		if drive.known {
+			drive.state.TeamCode = sides.awayCode
+			if drive.home {
+				drive.state.TeamCode = sides.homeCode
+			}
+		}
+		drive.state.Period = playQuarter(first, state)
+
+		drive.state.StartYardLine = fieldPosition(first.Side, first.YardLine, drive.home)
+		for _, play := range drive.plays {
+			if drive.state.Result == "" {
+				drive.state.Result = driveResult(play)
+			}
+			if !countsAsSnap(play) {
+				continue
+			}
+			drive.state.Plays++
+			drive.state.Yards += play.Yards
+			if inRedZone(play.Side, play.YardLine, drive.home) ||
+				(!play.ChangeOfPossession && inRedZone(play.NextSide, play.NextYardLine, drive.home)) {
+				drive.state.RedZone = true
+			}
+		}
+
+		last := drive.plays[len(drive.plays)-1]
+		end, endQuarter := last, playQuarter(last, state)
+		if i+1 < len(drives) {
+			end = drives[i+1].plays[0]
+			endQuarter = playQuarter(end, state)
+			lastQuarter := playQuarter(last, state)
+			if drive.state.Result == "" && endQuarter > lastQuarter && (lastQuarter == 2 || lastQuarter == 4) {
+				drive.state.Result = models.DriveResultEndOfHalf
+			}
+		}
+		startAt, okStart := gameSeconds(drive.state.Period, first.Clock)
+		endAt, okEnd := gameSeconds(endQuarter, end.Clock)
+		if okStart && okEnd && endAt >= startAt {
+			elapsed := endAt - startAt
+			drive.state.TimeConsumed = fmt.Sprintf("%d:%02d", elapsed/60, elapsed%60)
+		}
+	}
+	return drives
+}
+
+// playEventsSince returns the red zone entries, turnovers and big plays at or
+// after cursor as game events, each carrying its drive. As with scoring
+// plays, cursor 0 means the plays haven't been read before and none are
+// reported.
+func playEventsSince(drives []cflDrive, cursor int, current models.GameState) []models.GameEvent {
+	var events []models.GameEvent
+	if cursor == 0 {
+		return events
+	}
+	for _, drive := range drives {
+		if !drive.known {
+			continue
+		}
+		team := current.Away.Team
+		if drive.home {
+			team = current.Home.Team
+		}
+		summary := drive.state
+
+		enteredRedZone := false
+		for _, play := range drive.plays {
+			var types []models.EventType
+			scoringType, _, _ := classifyPlay(play)
+			switch {
+			case isTurnover(play):
+				types = append(types, models.EventTypeTurnover)
+			case scoringType == "" && countsAsSnap(play):
+				// A drive that starts in the red zone enters it on its first
+				// snap; otherwise it's the play that leaves the ball there
+				if !enteredRedZone && (inRedZone(play.Side, play.YardLine, drive.home) ||
+					(!play.ChangeOfPossession && inRedZone(play.NextSide, play.NextYardLine, drive.home))) {
+					enteredRedZone = true
+					types = append(types, models.EventTypeRedZoneEntry)
+				}
+				if isBigPlay(play) {
+					types = append(types, models.EventTypeBigPlay)
+				}
+			}
+			if play.Seq < cursor {
+				continue
+			}
+			for _, eventType := range types {
+				events = append(events, driveGameEvent(play, eventType, team, &summary, current))
+			}
+		}
+	}
+	return events
+}
+
+func driveGameEvent(play cflPlay, eventType models.EventType, team models.Team, drive *models.DriveState, state models.GameState) models.GameEvent {
+	return models.GameEvent{
+		Id:          play.ID,
+		Type:        eventType,
+		Period:      playQuarter(play, state),
+		Clock:       play.Clock,
+		Description: play.Description,
+		Team:        team,
+		Details: models.EventDetails{
+			YardLine:    play.YardLine,
+			Down:        play.Down,
+			Distance:    play.Distance,
+			YardsGained: play.Yards,
+			Possession:  team.TeamCode,
+			Drive:       drive,
+		},
+	}
+}
+
+// timelineFromDrives lists the most recent plays for Game.Events, each typed
+// by what it did.
+func timelineFromDrives(drives []cflDrive, current models.GameState) []models.GameEvent {
+	timeline := []models.GameEvent{}
+	for _, drive := range drives {
+		team := models.Team{}
+		if drive.known {
+			team = current.Away.Team
+			if drive.home {
+				team = current.Home.Team
+			}
+		}
+		for _, play := range drive.plays {
+			eventType := models.EventTypePlay
+			scoringType, conversion, points := classifyPlay(play)
+			switch {
+			case scoringType != "":
+				eventType = scoringEventType(scoringType)
+			case isTurnover(play):
+				eventType = models.EventTypeTurnover
+			case isBigPlay(play) && countsAsSnap(play):
+				eventType = models.EventTypeBigPlay
+			}
+			event := driveGameEvent(play, eventType, team, nil, current)
+			event.Details.ScoringType = scoringType
+			event.Details.Conversion = conversion
+			event.Details.Points = points
+			timeline = append(timeline, event)
+		}
+	}
+	if len(timeline) > maxTimelinePlays {
+		timeline = timeline[len(timeline)-maxTimelinePlays:]
+	}
+	return timeline
+}
+
+// currentDrive is the latest drive, for GameState.Drive.
+func currentDrive(drives []cflDrive) *models.DriveState {
+	if len(drives) == 0 {
+		return nil
+	}
+	drive := drives[len(drives)-1].state
+	return &drive
+}
+
+// getPlayEvents fires the red zone entries, turnovers and big plays the
+// update carries. A turnover is reported for the team that gave the ball
+// away.
+func (s CFLService) getPlayEvents(update models.GameUpdate) []models.Event {
+	events := []models.Event{}
+	for _, play := range update.Events {
+		switch play.Type {
+		case models.EventTypeRedZoneEntry, models.EventTypeTurnover, models.EventTypeBigPlay:
+		default:
+			continue
+		}
+		team, opponent := play.Team, update.NewState.Home.Team
+		if team.TeamCode == opponent.TeamCode {
+			opponent = update.NewState.Away.Team
+		}
+		description := play.Description
+		if description == "" {
+			description = fmt.Sprintf("%s for %s", playEventLabel(play.Type), team.TeamCode)
+		}
+		events = append(events, models.Event{
+			Id:           play.Id,
+			Type:         play.Type,
+			Description:  description,
+			TeamCode:     team.TeamCode,
+			TeamName:     team.TeamName,
+			TeamHash:     team.GetTeamHash(),
+			LeagueId:     models.LeagueIdCFL,
+			LeagueName:   s.GetLeagueName(),
+			Period:       play.Period,
+			PeriodType:   update.NewState.PeriodType,
+			Clock:        play.Clock,
+			OpponentCode: opponent.TeamCode,
+			OpponentName: opponent.TeamName,
+			OpponentHash: opponent.GetTeamHash(),
+			Details:      play.Details,
+			Score: models.ScoreUpdate{
+				HomeScore: update.NewState.Home.Score,
+				AwayScore: update.NewState.Away.Score,
+				HomeTeam:  update.NewState.Home.Team.TeamCode,
+				AwayTeam:  update.NewState.Away.Team.TeamCode,
+			},
+		})
+	}
+	return events
+}
+
+func playEventLabel(eventType models.EventType) string {
+	switch eventType {
+	case models.EventTypeRedZoneEntry:
+		return "Red zone entry"
+	case models.EventTypeTurnover:
+		return "Turnover"
+	default:
+		return "Big play"
+	}
+}
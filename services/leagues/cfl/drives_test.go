@@ -0,0 +1,111 @@
+package cfl
+
+import (
+	"goalfeed/models"
+	"testing"
+)
+
+// touchdownDrive is an OTT (home, "100") touchdown drive followed by the MTL
+// (away, "200") drive that was intercepted on its first snap.
+const touchdownDrive = `[
+	{"id": "7-1", "type": "Kickoff", "teamId": "200", "drive": {"id": "7"}, "phaseQualifier": 1, "clock": "10:00", "playDetails": {"isChangeOfPossession": true}, "playDescription": "#6 S.Whyte kickoff 65 yards"},
+	{"id": "7-2", "type": "Run", "teamId": "100", "drive": {"id": "7"}, "phaseQualifier": 1, "clock": "09:55", "playDetails": {"yards": 5, "downNumber": 1, "yardsToGo": 10, "scrimmageLocation": {"sideOfPitch": "Home", "scrimmageYard": 35, "nextSideOfPitch": "Home", "nextScrimmageYard": 40}}, "playDescription": "#34 W.Hamilton rush for 5 yards"},
+	{"id": "7-3", "type": "Pass", "teamId": "100", "drive": {"id": "7"}, "phaseQualifier": 1, "clock": "09:20", "isBigPlay": true, "playDetails": {"yards": 45, "downNumber": 2, "yardsToGo": 5, "scrimmageLocation": {"sideOfPitch": "Home", "scrimmageYard": 40, "nextSideOfPitch": "Away", "nextScrimmageYard": 25}}, "playDescription": "#4 D.Brown pass complete to #88 J.Ellis for 45 yards"},
+	{"id": "7-4", "type": "Run", "teamId": "100", "drive": {"id": "7"}, "phaseQualifier": 1, "clock": "08:45", "playDetails": {"yards": 10, "downNumber": 1, "yardsToGo": 10, "scrimmageLocation": {"sideOfPitch": "Away", "scrimmageYard": 25, "nextSideOfPitch": "Away", "nextScrimmageYard": 15}}, "playDescription": "#34 W.Hamilton rush for 10 yards"},
+	{"id": "7-5", "type": "Pass", "teamId": "100", "drive": {"id": "7"}, "phaseQualifier": 1, "clock": "08:10", "playDetails": {"isScoring": true, "yards": 15, "downNumber": 1, "yardsToGo": 10, "scrimmageLocation": {"sideOfPitch": "Away", "scrimmageYard": 15}}, "playDescription": "#4 D.Brown pass complete to #88 J.Ellis for 15 yards, TOUCHDOWN"},
+	{"id": "7-6", "type": "Convert", "teamId": "100", "drive": {"id": "7"}, "phaseQualifier": 1, "clock": "08:10", "playDetails": {"isScoring": true}, "playDescription": "#15 L.Haggerty convert is good"},
+	{"id": "8-1", "type": "Kickoff", "teamId": "100", "drive": {"id": "8"}, "phaseQualifier": 1, "clock": "08:10", "playDetails": {"isChangeOfPossession": true}, "playDescription": "#15 L.Haggerty kickoff 70 yards"},
+	{"id": "8-2", "type": "Pass", "teamId": "200", "drive": {"id": "8"}, "phaseQualifier": 1, "clock": "08:00", "playDetails": {"isChangeOfPossession": true, "downNumber": 1, "yardsToGo": 10, "scrimmageLocation": {"sideOfPitch": "Away", "scrimmageYard": 30}}, "playDescription": "#1 C.Fajardo pass intercepted by #20 A.Ola"}
+]`
+
+func TestDrivesFromPlays(t *testing.T) {
+	liveGame := liveGameWithActions(t, 7, 0, touchdownDrive)
+	state := cflTestGame(7, 0, 0).CurrentState
+	drives := drivesFromPlays(playsFromLiveGame(liveGame), sidesFromLiveGame(liveGame, state), state)
+
+	if len(drives) != 2 {
+		t.Fatalf("expected 2 drives, got %d", len(drives))
+	}
+	expected := models.DriveState{Id: "7", TeamCode: "OTT", Period: 1, StartYardLine: 35, Plays: 4, Yards: 75, TimeConsumed: "1:45", RedZone: true, Result: models.DriveResultTouchdown}
+	if drives[0].state != expected {
+		t.Errorf("expected %+v, got %+v", expected, drives[0].state)
+	}
+	second := drives[1].state
+	if second.TeamCode != "MTL" || second.StartYardLine != 30 || second.Plays != 1 || second.Result != models.DriveResultTurnover {
+		t.Errorf("unexpected second drive %+v", second)
+	}
+}
+
+func TestDrivesFromPlays_EndOfHalf(t *testing.T) {
+	actions := `[
+		{"id": "12-1", "type": "Run", "teamId": "100", "phaseQualifier": 2, "clock": "00:20", "playDetails": {"yards": 3, "scrimmageLocation": {"sideOfPitch": "Away", "scrimmageYard": 50}}},
+		{"id": "12-2", "type": "Pass", "teamId": "100", "phaseQualifier": 2, "clock": "00:00", "playDetails": {"yards": 12, "scrimmageLocation": {"sideOfPitch": "Away", "scrimmageYard": 47}}},
+		{"id": "13-1", "type": "Run", "teamId": "200", "phaseQualifier": 3, "clock": "14:40", "playDetails": {"yards": 2, "scrimmageLocation": {"sideOfPitch": "Away", "scrimmageYard": 40}}}
+	]`
+	liveGame := liveGameWithActions(t, 0, 0, actions)
+	state := cflTestGame(0, 0, 0).CurrentState
+	drives := drivesFromPlays(playsFromLiveGame(liveGame), sidesFromLiveGame(liveGame, state), state)
+
+	if len(drives) != 2 || drives[0].state.Result != models.DriveResultEndOfHalf {
+		t.Fatalf("expected the first drive to end with the half, got %+v", drives)
+	}
+	if drives[0].state.StartYardLine != 60 {
+		t.Errorf("expected a start 60 yards from OTT's goal line, got %d", drives[0].state.StartYardLine)
+	}
+	if drives[1].state.Result != "" {
+		t.Errorf("expected the drive in progress to have no result, got %q", drives[1].state.Result)
+	}
+}
+
+func TestCFLService_GetGameUpdate_DriveEvents(t *testing.T) {
+	update, events := getCFLUpdate(t, liveGameWithActions(t, 7, 0, touchdownDrive), cflTestGame(0, 0, 7002))
+
+	var types []models.EventType
+	for _, event := range events {
+		types = append(types, event.Type)
+	}
+	expected := []models.EventType{
+		models.EventTypeTouchdown, models.EventTypeConvert,
+		models.EventTypeBigPlay, models.EventTypeRedZoneEntry, models.EventTypeTurnover,
+	}
+	if len(types) != len(expected) {
+		t.Fatalf("expected %v, got %v", expected, types)
+	}
+	for i := range expected {
+		if types[i] != expected[i] {
+			t.Fatalf("expected %v, got %v", expected, types)
+		}
+	}
+
+	redZone := events[3]
+	if redZone.TeamCode != "OTT" || redZone.Id != "7-4" || redZone.Details.Drive == nil || redZone.Details.Drive.Id != "7" {
+		t.Errorf("unexpected red zone entry %+v", redZone)
+	}
+	turnover := events[4]
+	if turnover.TeamCode != "MTL" || turnover.OpponentCode != "OTT" || turnover.Details.Drive.Result != models.DriveResultTurnover {
+		t.Errorf("unexpected turnover %+v", turnover)
+	}
+
+	if update.NewState.Drive == nil || update.NewState.Drive.Id != "8" || update.NewState.Drive.TeamCode != "MTL" {
+		t.Errorf("expected MTL's drive as the current drive, got %+v", update.NewState.Drive)
+	}
+	if len(update.Plays) != 8 {
+		t.Fatalf("expected all 8 plays on the timeline, got %d", len(update.Plays))
+	}
+	if update.Plays[0].Type != models.EventTypePlay || update.Plays[2].Type != models.EventTypeBigPlay || update.Plays[4].Type != models.EventTypeTouchdown {
+		t.Errorf("unexpected timeline types %v %v %v", update.Plays[0].Type, update.Plays[2].Type, update.Plays[4].Type)
+	}
+}
+
+func TestCFLService_GetGameUpdate_DriveEventsReportedOnce(t *testing.T) {
+	_, events := getCFLUpdate(t, liveGameWithActions(t, 7, 0, touchdownDrive), cflTestGame(7, 0, 8001))
+
+	if len(events) != 1 || events[0].Type != models.EventTypeTurnover {
+		t.Errorf("expected only the new turnover, got %+v", events)
+	}
+
+	_, events = getCFLUpdate(t, liveGameWithActions(t, 7, 0, touchdownDrive), cflTestGame(7, 0, 0))
+	if len(events) != 0 {
+		t.Errorf("expected nothing on the first read, got %+v", events)
+	}
+}
@@ -0,0 +1,420 @@
+package cfl
+
+import (
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"goalfeed/clients/leagues/cfl"
+	"goalfeed/models"
+)
+
+// cflPlay is one play from the live game, read from court.matchActions when
+// the feed has them and from liveStream.actions otherwise.
+type cflPlay struct {
+	// Seq orders plays across the game. Court actions are numbered
+	// drive*1000+play from their "<drive>-<play>" id; stream actions by
+	// position.
+	Seq                int
+	ID                 string
+	DriveID            string
+	Type               string
+	SubType            string
+	Description        string
+	Clock              string
+	Quarter            int
+	TeamID             string // competitor in possession at the snap (court actions)
+	Possession         string // team code in possession (stream actions)
+	Scoring            bool
+	ScoringKnown       bool
+	ChangeOfPossession bool
+	Yards              int
+	Down               int
+	Distance           int
+	YardLine           int
+	// Side is whose half the line of scrimmage is in, "Home" or "Away", with
+	// Next* giving where the play left the ball (court actions only)
+	Side         string
+	NextSide     string
+	NextYardLine int
+	BigPlay      bool
+	PlayerID     int
+}
+
+// playsFromLiveGame returns the game's plays in the order they were run.
+func playsFromLiveGame(liveGame cfl.CFLLiveGameResponse) []cflPlay {
+	var plays []cflPlay
+	if actions := liveGame.Data.Court.Actions(); len(actions) > 0 {
+		for i, action := range actions {
+			description := action.PlayByPlayInfo.Description
+			if description == "" {
+				description = action.Description
+			}
+			clock := action.PlayByPlayInfo.Clock
+			if clock == "" {
+				clock = action.Clock
+			}
+			teamID := action.TeamID
+			if teamID == "" {
+				teamID = action.Drive.TeamInPossessionID
+			}
+			driveID := action.Drive.ID
+			if driveID == "" {
+				driveID, _, _ = strings.Cut(action.ID, "-")
+			}
+			location := action.PlayDetails.ScrimmageLocation
+			plays = append(plays, cflPlay{
+				Seq:                actionSeq(action.ID, i),
+				ID:                 action.ID,
+				DriveID:            driveID,
+				Type:               action.Type,
+				SubType:            action.SubType,
+				Description:        description,
+				Clock:              clock,
+				Quarter:            int(action.PhaseQualifier),
+				TeamID:             teamID,
+				Scoring:            action.PlayDetails.IsScoring,
+				ScoringKnown:       true,
+				ChangeOfPossession: action.PlayDetails.IsChangeOfPossession,
+				Yards:              int(action.PlayDetails.Yards),
+				Down:               int(action.PlayDetails.DownNumber),
+				Distance:           int(action.PlayDetails.YardsToGo),
+				YardLine:           int(location.ScrimmageYard),
+				Side:               location.SideOfPitch,
+				NextSide:           location.NextSideOfPitch,
+				NextYardLine:       int(location.NextScrimmageYard),
+				BigPlay:            action.IsBigPlay,
+			})
+		}
+	} else {
+		// Stream actions don't say which drive they belong to, so a new one
+		// starts whenever possession changes hands
+		drive, possession := 0, ""
+		for i, action := range liveGame.Data.LiveStream.Actions {
+			if drive == 0 || !strings.EqualFold(action.Possession, possession) {
+				drive++
+				possession = action.Possession
+			}
+			plays = append(plays, cflPlay{
+				Seq:         i + 1,
+				ID:          strconv.Itoa(i + 1),
+				DriveID:     strconv.Itoa(drive),
+				Type:        action.ActionType,
+				Description: action.Description,
+				Clock:       action.Clock,
+				Possession:  action.Possession,
+				Yards:       action.YardsGained,
+				Down:        action.Down,
+				Distance:    action.Distance,
+				YardLine:    action.YardLine,
+				PlayerID:    action.PlayerId,
+			})
+		}
+	}
+	sort.SliceStable(plays, func(i, j int) bool { return plays[i].Seq < plays[j].Seq })
+	return plays
+}
+
+// actionSeq numbers a court action from its "<drive>-<play>" id, falling back
+// to its position in the feed.
+func actionSeq(id string, index int) int {
+	drive, play, ok := strings.Cut(id, "-")
+	if ok {
+		d, errD := strconv.Atoi(drive)
+		p, errP := strconv.Atoi(play)
+		if errD == nil && errP == nil {
+			return d*1000 + p
+		}
+	}
+	return index + 1
+}
+
+// nextPlayCursor is the cursor to store once plays have been read: one past
+// the last play, and never 0 so a game with no plays yet is still marked as
+// read.
+func nextPlayCursor(plays []cflPlay, cursor int) int {
+	for _, play := range plays {
+		if play.Seq+1 > cursor {
+			cursor = play.Seq + 1
+		}
+	}
+	if cursor == 0 {
+		cursor = 1
+	}
+	return cursor
+}
+
+// classifyPlay reads what a play scored. It returns an empty scoring type
+// for plays that didn't score or whose score it can't name.
+func classifyPlay(play cflPlay) (string, string, int) {
+	if play.ScoringKnown && !play.Scoring {
+		return "", "", 0
+	}
+	text := strings.ToLower(play.Type + " " + play.SubType + " " + play.Description)
+	kind := strings.NewReplacer(" ", "", "_", "", "-", "").Replace(strings.ToLower(play.Type + play.SubType))
+	failed := strings.Contains(text, "no good") || strings.Contains(text, "missed") ||
+		strings.Contains(text, "failed") || strings.Contains(text, "blocked")
+
+	switch {
+	case strings.Contains(text, "touchdown"):
+		if play.ChangeOfPossession || strings.Contains(text, "intercept") || strings.Contains(text, "fumble return") {
+			return models.ScoringTypeDefensiveTouchdown, "", 6
+		}
+		return models.ScoringTypeTouchdown, "", 6
+	case strings.Contains(kind, "twopoint") || strings.Contains(text, "two-point") || strings.Contains(text, "2-point"):
+		if failed {
+			return "", "", 0
+		}
+		return models.ScoringTypeConvert, models.ConversionTwoPoint, 2
+	case kind == "pat" || strings.Contains(kind, "convert") || strings.Contains(kind, "extrapoint") || strings.Contains(kind, "onepoint"):
+		if failed {
+			return "", "", 0
+		}
+		return models.ScoringTypeConvert, models.ConversionExtraPoint, 1
+	case strings.Contains(text, "single") || strings.Contains(text, "rouge"):
+		return models.ScoringTypeSingle, "", 1
+	case strings.Contains(text, "safety"):
+		return models.ScoringTypeSafety, "", 2
+	case strings.Contains(kind, "fieldgoal") || strings.Contains(text, "field goal"):
+		if failed {
+			return "", "", 0
+		}
+		return models.ScoringTypeFieldGoal, "", 3
+	}
+	return "", "", 0
+}
+
+// classifyCFLPoints names the score behind points no play explains, and
+// returns the points it accounts for. One or two points straight after the
+// team's touchdown are its convert; otherwise they're a single or a safety.
+func classifyCFLPoints(points int, last string) (string, string, int) {
+	afterTouchdown := last == models.ScoringTypeTouchdown || last == models.ScoringTypeDefensiveTouchdown
+	switch {
+	case points == 8:
+		return models.ScoringTypeTouchdown, models.ConversionTwoPoint, 8
+	case points >= 7:
+		return models.ScoringTypeTouchdown, models.ConversionExtraPoint, 7
+	case points == 6:
+		return models.ScoringTypeTouchdown, "", 6
+	case points >= 3:
+		return models.ScoringTypeFieldGoal, "", 3
+	case points == 2 && afterTouchdown:
+		return models.ScoringTypeConvert, models.ConversionTwoPoint, 2
+	case points == 2:
+		return models.ScoringTypeSafety, "", 2
+	case afterTouchdown:
+		return models.ScoringTypeConvert, models.ConversionExtraPoint, 1
+	default:
+		return models.ScoringTypeSingle, "", 1
+	}
+}
+
+func scoringEventType(scoringType string) models.EventType {
+	switch scoringType {
+	case models.ScoringTypeFieldGoal:
+		return models.EventTypeFieldGoal
+	case models.ScoringTypeSafety:
+		return models.EventTypeSafety
+	case models.ScoringTypeSingle:
+		return models.EventTypeSingle
+	case models.ScoringTypeConvert:
+		return models.EventTypeConvert
+	default:
+		return models.EventTypeTouchdown
+	}
+}
+
+func scoringDescription(scoringType string, conversion string) string {
+	switch scoringType {
+	case models.ScoringTypeDefensiveTouchdown:
+		return "Defensive touchdown"
+	case models.ScoringTypeFieldGoal:
+		return "Field goal"
+	case models.ScoringTypeSafety:
+		return "Safety"
+	case models.ScoringTypeSingle:
+		return "Single"
+	case models.ScoringTypeConvert:
+		if conversion == models.ConversionTwoPoint {
+			return "Two-point convert"
+		}
+		return "Convert"
+	}
+	switch conversion {
+	case models.ConversionExtraPoint:
+		return "Touchdown and convert"
+	case models.ConversionTwoPoint:
+		return "Touchdown and two-point convert"
+	default:
+		return "Touchdown"
+	}
+}
+
+var (
+	// Genius descriptions name players as "#34 M.Anderson"; a pass names its
+	// receiver after "to".
+	passTarget  = regexp.MustCompile(`(?i)\bto\s+#\d+\s+([A-Z][\w.'-]+)`)
+	firstPlayer = regexp.MustCompile(`#\d+\s+([A-Z][\w.'-]+)`)
+)
+
+// scorerName picks the scoring player out of a play description. Safeties
+// and defensive scores are left unnamed since the description leads with
+// the offence.
+func scorerName(play cflPlay, scoringType string) string {
+	if scoringType == models.ScoringTypeSafety || scoringType == models.ScoringTypeDefensiveTouchdown {
+		return ""
+	}
+	if m := passTarget.FindStringSubmatch(play.Description); m != nil {
+		return m[1]
+	}
+	if m := firstPlayer.FindStringSubmatch(play.Description); m != nil {
+		return m[1]
+	}
+	return ""
+}
+
+// cflSides resolves which side a play's team is on.
+type cflSides struct {
+	homeID, awayID     string
+	homeCode, awayCode string
+}
+
+func sidesFromLiveGame(liveGame cfl.CFLLiveGameResponse, state models.GameState) cflSides {
+	return cflSides{
+		homeID:   liveGame.Data.MatchInfo.HomeTeam.CompetitorID,
+		awayID:   liveGame.Data.MatchInfo.AwayTeam.CompetitorID,
+		homeCode: state.Home.Team.TeamCode,
+		awayCode: state.Away.Team.TeamCode,
+	}
+}
+
+// offenceIsHome reports whether the team with the ball on a play is home.
+func (s cflSides) offenceIsHome(play cflPlay) (bool, bool) {
+	switch {
+	case play.TeamID != "" && play.TeamID == s.homeID:
+		return true, true
+	case play.TeamID != "" && play.TeamID == s.awayID:
+		return false, true
+	case play.Possession != "" && strings.EqualFold(play.Possession, s.homeCode):
+		return true, true
+	case play.Possession != "" && strings.EqualFold(play.Possession, s.awayCode):
+		return false, true
+	}
+	return false, false
+}
+
+// scoringPlaysSince classifies the scoring plays at or after cursor as game
+// events. Each is credited to the side that scored it, checked against how
+// far that side's score actually moved, and any points the plays don't
+// explain are classified by size. cursor 0 means the plays haven't been read
+// before, so only the points are reported.
+func scoringPlaysSince(plays []cflPlay, cursor int, sides cflSides, old models.GameState, current models.GameState) []models.GameEvent {
+	homeLeft := current.Home.Score - old.Home.Score
+	awayLeft := current.Away.Score - old.Away.Score
+	lastHome, lastAway := "", ""
+	var events []models.GameEvent
+
+	for _, play := range plays {
+		scoringType, conversion, points := classifyPlay(play)
+		if scoringType == "" {
+			continue
+		}
+		offenceHome, known := sides.offenceIsHome(play)
+		home := offenceHome
+		if scoringType == models.ScoringTypeSafety || scoringType == models.ScoringTypeDefensiveTouchdown {
+			home = !offenceHome
+		}
+
+		if cursor == 0 || play.Seq < cursor {
+			if known {
+				if home {
+					lastHome = scoringType
+				} else {
+					lastAway = scoringType
+				}
+			}
+			continue
+		}
+
+		// Credit the expected side when its score moved enough, else the
+		// other side; a play neither score reflects isn't reported
+		switch {
+		case known && home && homeLeft >= points, known && !home && awayLeft >= points:
+		case homeLeft >= points && (awayLeft < points || !known):
+			home = true
+		case awayLeft >= points:
+			home = false
+		default:
+			continue
+		}
+
+		team := current.Away.Team
+		if home {
+			team = current.Home.Team
+			homeLeft -= points
+			lastHome = scoringType
+		} else {
+			awayLeft -= points
+			lastAway = scoringType
+		}
+		events = append(events, scoringGameEvent(play, team, scoringType, conversion, points, current))
+	}
+
+	events = append(events, scoringFromPoints(homeLeft, lastHome, current.Home.Team, current)...)
+	events = append(events, scoringFromPoints(awayLeft, lastAway, current.Away.Team, current)...)
+	return events
+}
+
+func scoringGameEvent(play cflPlay, team models.Team, scoringType string, conversion string, points int, state models.GameState) models.GameEvent {
+	period := play.Quarter
+	if period == 0 {
+		period = state.Period
+	}
+	player := models.Player{Name: scorerName(play, scoringType), Team: team}
+	if play.PlayerID != 0 {
+		player.Id = strconv.Itoa(play.PlayerID)
+	}
+	return models.GameEvent{
+		Id:          play.ID,
+		Type:        scoringEventType(scoringType),
+		Period:      period,
+		Clock:       play.Clock,
+		Description: play.Description,
+		Team:        team,
+		Player:      player,
+		Details: models.EventDetails{
+			ScoringType: scoringType,
+			Conversion:  conversion,
+			Points:      points,
+			YardLine:    play.YardLine,
+			Down:        play.Down,
+			Distance:    play.Distance,
+			YardsGained: play.Yards,
+		},
+	}
+}
+
+// scoringFromPoints stands in for scoring plays the feed didn't list,
+// classifying the points by size. last is the side's latest score, which
+// tells a convert from a single.
+func scoringFromPoints(points int, last string, team models.Team, state models.GameState) []models.GameEvent {
+	var events []models.GameEvent
+	for points > 0 {
+		scoringType, conversion, used := classifyCFLPoints(points, last)
+		points -= used
+		last = scoringType
+		events = append(events, models.GameEvent{
+			Type:   scoringEventType(scoringType),
+			Period: state.Period,
+			Clock:  state.Clock,
+			Team:   team,
+			Details: models.EventDetails{
+				ScoringType: scoringType,
+				Conversion:  conversion,
+				Points:      used,
+			},
+		})
+	}
+	return events
+}
@@ -0,0 +1,144 @@
+package cfl
+
+import (
+	"encoding/json"
+	"goalfeed/clients/leagues/cfl"
+	"goalfeed/models"
+	"strconv"
+	"testing"
+)
+
+// liveGameWithActions builds a live game for MTL @ OTT, OTT being competitor
+// "100" and MTL "200", with the given court match actions.
+func liveGameWithActions(t *testing.T, homeScore int, awayScore int, actions string) cfl.CFLLiveGameResponse {
+	t.Helper()
+	raw := `{"data": {
+		"scoreboardInfo": {"homeScore": ` + strconv.Itoa(homeScore) + `, "awayScore": ` + strconv.Itoa(awayScore) + `, "matchStatus": "Live", "currentPhase": "Q2"},
+		"matchInfo": {"homeTeam": {"competitorId": "100"}, "awayTeam": {"competitorId": "200"}},
+		"court": {"matchActions": ` + actions + `}
+	}}`
+	var liveGame cfl.CFLLiveGameResponse
+	if err := json.Unmarshal([]byte(raw), &liveGame); err != nil {
+		t.Fatalf("bad live game: %v", err)
+	}
+	return liveGame
+}
+
+func cflTestGame(homeScore int, awayScore int, cursor int) models.Game {
+	return models.Game{
+		GameCode: "13419716",
+		LeagueId: models.LeagueIdCFL,
+		CurrentState: models.GameState{
+			Home:       models.TeamState{Team: models.Team{TeamCode: "OTT", TeamName: "Ottawa Redblacks", ExtID: "100"}, Score: homeScore},
+			Away:       models.TeamState{Team: models.Team{TeamCode: "MTL", TeamName: "Montreal Alouettes", ExtID: "200"}, Score: awayScore},
+			Status:     models.StatusActive,
+			PlayCursor: cursor,
+		},
+	}
+}
+
+func getCFLUpdate(t *testing.T, liveGame cfl.CFLLiveGameResponse, game models.Game) (models.GameUpdate, []models.Event) {
+	t.Helper()
+	service := CFLService{Client: cfl.MockCFLApiClient{LiveGameResponse: liveGame}}
+	updates := make(chan models.GameUpdate)
+	go service.GetGameUpdate(game, updates)
+	update := <-updates
+	events := make(chan []models.Event)
+	go service.GetEvents(update, events)
+	return update, <-events
+}
+
+const scoringDrive = `[
+	{"id": "4-1", "type": "Run", "teamId": "100", "phaseQualifier": 2, "playDetails": {"isScoring": false}, "playDescription": "#34 W.Hamilton run for 4 yards"},
+	{"id": "4-2", "type": "Pass", "teamId": "100", "phaseQualifier": 2, "clock": "08:12", "playDetails": {"isScoring": true, "yards": 22, "downNumber": 1, "yardsToGo": 10, "scrimmageLocation": {"scrimmageYard": 22}},
+		"playDescription": "#4 D.Brown pass complete to #88 J.Ellis for 22 yards, TOUCHDOWN"},
+	{"id": "4-3", "type": "Convert", "teamId": "100", "phaseQualifier": 2, "clock": "08:12", "playDetails": {"isScoring": true}, "playDescription": "#15 L.Haggerty convert is good"},
+	{"id": "5-1", "type": "Punt", "teamId": "100", "phaseQualifier": 2, "clock": "06:40", "playDetails": {"isScoring": false}, "playDescription": "#15 L.Haggerty punt 51 yards"},
+	{"id": "6-3", "type": "Punt", "teamId": "200", "phaseQualifier": 2, "clock": "03:05", "playDetails": {"isScoring": true}, "playDescription": "#19 J.Cheverie punt 62 yards into the end zone, single"}
+]`
+
+func TestCFLService_GetGameUpdate_ClassifiesScoringPlays(t *testing.T) {
+	update, events := getCFLUpdate(t, liveGameWithActions(t, 7, 1, scoringDrive), cflTestGame(0, 0, 4001))
+
+	if update.NewState.PlayCursor != 6004 {
+		t.Errorf("expected cursor past the last play, got %d", update.NewState.PlayCursor)
+	}
+	if len(events) != 3 {
+		t.Fatalf("expected touchdown, convert and single, got %d events: %+v", len(events), events)
+	}
+
+	td := events[0]
+	if td.Type != models.EventTypeTouchdown || td.TeamCode != "OTT" || td.PlayerName != "J.Ellis" || td.Id != "4-2" {
+		t.Errorf("unexpected touchdown %+v", td)
+	}
+	if td.Details.Points != 6 || td.Details.YardsGained != 22 || td.Period != 2 || td.Clock != "08:12" {
+		t.Errorf("unexpected touchdown details %+v", td.Details)
+	}
+	if events[1].Type != models.EventTypeConvert || events[1].Details.Conversion != models.ConversionExtraPoint || events[1].TeamCode != "OTT" {
+		t.Errorf("unexpected convert %+v", events[1])
+	}
+	single := events[2]
+	if single.Type != models.EventTypeSingle || single.TeamCode != "MTL" || single.Details.Points != 1 || single.OpponentCode != "OTT" {
+		t.Errorf("unexpected single %+v", single)
+	}
+}
+
+func TestCFLService_GetGameUpdate_FirstReadOnlyReportsPoints(t *testing.T) {
+	update, events := getCFLUpdate(t, liveGameWithActions(t, 7, 1, scoringDrive), cflTestGame(7, 0, 0))
+
+	if len(update.Events) != 1 || update.Events[0].Details.ScoringType != models.ScoringTypeSingle {
+		t.Fatalf("expected only the unexplained point, got %+v", update.Events)
+	}
+	if len(events) != 1 || events[0].TeamCode != "MTL" || events[0].Description != "Single for MTL" {
+		t.Errorf("unexpected events %+v", events)
+	}
+	if update.NewState.PlayCursor != 6004 {
+		t.Errorf("expected cursor past the last play, got %d", update.NewState.PlayCursor)
+	}
+}
+
+func TestCFLService_GetGameUpdate_SafetyCreditedToDefence(t *testing.T) {
+	actions := `[{"id": "9-2", "type": "Run", "teamId": "200", "phaseQualifier": 3, "playDetails": {"isScoring": true}, "playDescription": "#1 C.Fajardo sacked in the end zone, safety"}]`
+	_, events := getCFLUpdate(t, liveGameWithActions(t, 2, 0, actions), cflTestGame(0, 0, 9001))
+
+	if len(events) != 1 || events[0].Type != models.EventTypeSafety || events[0].TeamCode != "OTT" || events[0].PlayerName != "" {
+		t.Errorf("expected an unnamed OTT safety, got %+v", events)
+	}
+}
+
+func TestCFLService_GetGameUpdate_StreamActionsFallback(t *testing.T) {
+	liveGame := cfl.CFLLiveGameResponse{}
+	liveGame.Data.ScoreboardInfo.HomeScore = 3
+	liveGame.Data.LiveStream.Actions = []cfl.CFLAction{
+		{ActionType: "field_goal", Description: "44 yard field goal is GOOD", Possession: "OTT"},
+	}
+	_, events := getCFLUpdate(t, liveGame, cflTestGame(0, 0, 1))
+
+	if len(events) != 1 || events[0].Type != models.EventTypeFieldGoal || events[0].TeamCode != "OTT" {
+		t.Errorf("expected an OTT field goal, got %+v", events)
+	}
+}
+
+func TestClassifyCFLPoints(t *testing.T) {
+	cases := []struct {
+		points     int
+		last       string
+		scoring    string
+		conversion string
+	}{
+		{1, "", models.ScoringTypeSingle, ""},
+		{1, models.ScoringTypeTouchdown, models.ScoringTypeConvert, models.ConversionExtraPoint},
+		{1, models.ScoringTypeFieldGoal, models.ScoringTypeSingle, ""},
+		{2, "", models.ScoringTypeSafety, ""},
+		{2, models.ScoringTypeTouchdown, models.ScoringTypeConvert, models.ConversionTwoPoint},
+		{3, "", models.ScoringTypeFieldGoal, ""},
+		{7, "", models.ScoringTypeTouchdown, models.ConversionExtraPoint},
+		{8, "", models.ScoringTypeTouchdown, models.ConversionTwoPoint},
+	}
+	for _, c := range cases {
+		scoring, conversion, _ := classifyCFLPoints(c.points, c.last)
+		if scoring != c.scoring || conversion != c.conversion {
+			t.Errorf("%d points after %q: expected %s/%s, got %s/%s", c.points, c.last, c.scoring, c.conversion, scoring, conversion)
+		}
+	}
+}
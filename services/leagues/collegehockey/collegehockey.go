@@ -0,0 +1,265 @@
+// Package collegehockey follows NCAA men's and women's hockey from ESPN.
+// The scoreboard and summary documents have the same shape as the NFL's,
+// so they are read with the models in clients/leagues/nfl; goals and
+// overtime go through the shared hockey package.
+package collegehockey
+
+import (
+	"goalfeed/clients/leagues/nfl"
+	"goalfeed/models"
+	"goalfeed/services/leagues/espn"
+	"goalfeed/services/leagues/hockey"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// regulationPeriods is the number of regulation periods; ESPN numbers each
+// overtime after them.
+const regulationPeriods = 3
+
+// CollegeHockeyService follows one ESPN college hockey league; League is
+// models.LeagueIdNCAAMHockey or models.LeagueIdNCAAWHockey.
+type CollegeHockeyService struct {
+	Client nfl.INFLAPIClient
+	League models.League
+}
+
+func (s CollegeHockeyService) GetLeagueName() string {
+	if s.League == models.LeagueIdNCAAWHockey {
+		return "NCAAWH"
+	}
+	return "NCAAMH"
+}
+
+func (s CollegeHockeyService) GetActiveGames(ret chan []models.Game) {
+	var activeGames []models.Game
+	for _, event := range learnConferences(s.Client.GetNFLSchedule().Events) {
+		if espn.GameStatus(event.Status.Type.State, event.Status.Type.Completed, event.Status.Type.Name) == models.StatusActive {
+			activeGames = append(activeGames, s.gameFromEvent(event))
+		}
+	}
+	ret <- activeGames
+}
+
+func (s CollegeHockeyService) GetUpcomingGames(ret chan []models.Game) {
+	var upcomingGames []models.Game
+	for _, event := range learnConferences(s.Client.GetNFLSchedule().Events) {
+		if espn.GameStatus(event.Status.Type.State, event.Status.Type.Completed, event.Status.Type.Name) == models.StatusUpcoming {
+			upcomingGames = append(upcomingGames, s.gameFromEvent(event))
+		}
+	}
+	ret <- upcomingGames
+}
+
+func (s CollegeHockeyService) GetGamesByDate(date string, ret chan []models.Game) {
+	// Convert YYYY-MM-DD to YYYYMMDD for ESPN
+	schedule := s.Client.GetNFLScheduleByDate(strings.ReplaceAll(date, "-", ""))
+	var games []models.Game
+	for _, event := range learnConferences(schedule.Events) {
+		games = append(games, s.gameFromEvent(event))
+	}
+	ret <- games
+}
+
+func (s CollegeHockeyService) GetGameUpdate(game models.Game, ret chan models.GameUpdate) {
+	summary := s.Client.GetNFLScoreBoard(game.GameCode)
+	newState := s.gameStateFromSummary(game.CurrentState, summary)
+	ret <- models.GameUpdate{
+		OldState: game.CurrentState,
+		NewState: newState,
+		Events:   scoringPlaysFromSummary(summary.ScoringPlays, newState),
+	}
+}
+
+func (s CollegeHockeyService) GetEvents(update models.GameUpdate, ret chan []models.Event) {
+	ret <- hockey.GetEvents(update, int(s.League), s.GetLeagueName())
+}
+
+// periodType reads overtime from the period number and a shootout from the
+// status detail ("Final/SO"), as ESPN has no separate period for it.
+func periodType(period int, detail string) string {
+	parts := strings.FieldsFunc(strings.ToUpper(detail), func(r rune) bool { return r == '/' || r == ' ' })
+	switch {
+	case slices.Contains(parts, "SO"):
+		return hockey.PeriodTypeFromCode("SO")
+	case period > regulationPeriods:
+		return hockey.PeriodTypeFromCode("OT")
+	}
+	return models.PeriodTypeRegular
+}
+
+// clockFromStatus shows intermissions ("End of 2nd") in place of a stopped
+// clock.
+func clockFromStatus(displayClock string, shortDetail string) string {
+	if strings.HasPrefix(strings.ToLower(shortDetail), "end") || strings.Contains(strings.ToLower(shortDetail), "intermission") {
+		return shortDetail
+	}
+	return displayClock
+}
+
+func (s CollegeHockeyService) team(id, code, name, logo, conferenceID string) models.Team {
+	return models.Team{
+		TeamName:   name,
+		TeamCode:   code,
+		ExtID:      id,
+		LeagueID:   int(s.League),
+		LogoURL:    logo,
+		Conference: conferenceName(conferenceID),
+	}
+}
+
+func (s CollegeHockeyService) gameFromEvent(event nfl.NFLScheduleEvent) models.Game {
+	var home, away nfl.NFLCompetitor
+	var venue models.Venue
+	if len(event.Competitions) > 0 {
+		competition := event.Competitions[0]
+		home, away = espn.CompetitorsBySide(competition.Competitors, func(c nfl.NFLCompetitor) string { return c.HomeAway })
+		venue = models.Venue{
+			Name:  competition.Venue.FullName,
+			City:  competition.Venue.Address.City,
+			State: competition.Venue.Address.State,
+		}
+	}
+	homeScore, _ := strconv.Atoi(home.Score)
+	awayScore, _ := strconv.Atoi(away.Score)
+	gameDate := espn.ParseDate(event.Date)
+
+	state := models.GameState{
+		ExtTimestamp: event.Date,
+		Home: models.TeamState{
+			Team:  s.team(home.Team.ID, home.Team.Abbreviation, home.Team.DisplayName, home.Team.Logo, home.Team.ConferenceID),
+			Score: homeScore,
+			Rank:  home.CuratedRank.Rank(),
+		},
+		Away: models.TeamState{
+			Team:  s.team(away.Team.ID, away.Team.Abbreviation, away.Team.DisplayName, away.Team.Logo, away.Team.ConferenceID),
+			Score: awayScore,
+			Rank:  away.CuratedRank.Rank(),
+		},
+		Status:     espn.GameStatus(event.Status.Type.State, event.Status.Type.Completed, event.Status.Type.Name),
+		FetchedAt:  time.Now(),
+		Period:     event.Status.Period,
+		PeriodType: periodType(event.Status.Period, event.Status.Type.ShortDetail),
+		Clock:      clockFromStatus(event.Status.DisplayClock, event.Status.Type.ShortDetail),
+		Venue:      venue,
+	}
+	gameTime := "TBD"
+	if !gameDate.IsZero() {
+		gameTime = gameDate.Format("3:04 PM")
+	}
+	if state.Status == models.StatusUpcoming {
+		state.Clock = gameTime
+	}
+
+	return models.Game{
+		CurrentState: state,
+		GameCode:     event.ID,
+		ExtTimestamp: event.Date,
+		LeagueId:     s.League,
+		GameDetails: models.GameDetails{
+			GameId:     event.ID,
+			Season:     strconv.Itoa(event.Season.Year),
+			SeasonType: "REGULAR",
+			GameDate:   gameDate,
+			GameTime:   gameTime,
+			Timezone:   "UTC",
+		},
+	}
+}
+
+// gameStateFromSummary builds the game's new state from a summary's header.
+// The old state is returned unchanged when the summary carries no
+// competitors. Rankings and conferences the summary leaves out are kept
+// from the old state.
+func (s CollegeHockeyService) gameStateFromSummary(old models.GameState, summary nfl.NFLScoreboardResponse) models.GameState {
+	if len(summary.Header.Competitions) == 0 {
+		return old
+	}
+	competition := summary.Header.Competitions[0]
+	learnConference(competition.Groups)
+	home, away := espn.CompetitorsBySide(competition.Competitors, func(c nfl.NFLScoreboardCompetitor) string { return c.HomeAway })
+	if home.Team.Abbreviation == "" || away.Team.Abbreviation == "" {
+		return old
+	}
+	homeScore, _ := strconv.Atoi(home.Score)
+	awayScore, _ := strconv.Atoi(away.Score)
+	status := competition.Status
+
+	state := old
+	state.Home = models.TeamState{
+		Team:  s.team(home.Team.ID, home.Team.Abbreviation, home.Team.DisplayName, home.Team.Logo, home.Team.ConferenceID),
+		Score: homeScore,
+		Rank:  home.CuratedRank.Rank(),
+	}
+	state.Away = models.TeamState{
+		Team:  s.team(away.Team.ID, away.Team.Abbreviation, away.Team.DisplayName, away.Team.Logo, away.Team.ConferenceID),
+		Score: awayScore,
+		Rank:  away.CuratedRank.Rank(),
+	}
+	keepCollegeInfo(&state.Home, old.Home)
+	keepCollegeInfo(&state.Away, old.Away)
+	state.Status = espn.GameStatus(status.Type.State, status.Type.Completed, status.Type.Name)
+	state.FetchedAt = time.Now()
+	state.Period = status.Period
+	state.PeriodType = periodType(status.Period, status.Type.ShortDetail)
+	state.Clock = clockFromStatus(status.DisplayClock, status.Type.ShortDetail)
+	if summary.GameInfo.Venue.FullName != "" {
+		state.Venue.Name = summary.GameInfo.Venue.FullName
+	}
+	return state
+}
+
+func keepCollegeInfo(team *models.TeamState, previous models.TeamState) {
+	if team.Team.TeamCode != previous.Team.TeamCode {
+		return
+	}
+	if team.Rank == 0 {
+		team.Rank = previous.Rank
+	}
+	if team.Team.LogoURL == "" {
+		team.Team.LogoURL = previous.Team.LogoURL
+	}
+	if team.Team.Conference == "" {
+		team.Team.Conference = previous.Team.Conference
+	}
+}
+
+// scoringPlaysFromSummary turns the summary's scoring plays into goal
+// events. ESPN gives the play as text, so the goal type is read from it.
+func scoringPlaysFromSummary(plays []nfl.NFLScoringPlay, state models.GameState) []models.GameEvent {
+	var events []models.GameEvent
+	for _, play := range plays {
+		team := state.Away.Team
+		if play.Team.ID == state.Home.Team.ExtID || strings.EqualFold(play.Team.Abbreviation, state.Home.Team.TeamCode) {
+			team = state.Home.Team
+		}
+		events = append(events, models.GameEvent{
+			Id:          play.ID,
+			Type:        models.EventTypeGoal,
+			Period:      play.Period.Number,
+			Time:        play.Clock.DisplayValue,
+			Clock:       play.Clock.DisplayValue,
+			Description: play.Text,
+			Team:        team,
+			Details: models.EventDetails{
+				GoalType: goalTypeFromText(play.Type.Text + " " + play.Text),
+			},
+		})
+	}
+	return events
+}
+
+func goalTypeFromText(text string) string {
+	text = strings.ToLower(text)
+	switch {
+	case strings.Contains(text, "empty net"), strings.Contains(text, "empty-net"):
+		return models.GoalTypeEmptyNet
+	case strings.Contains(text, "power play"), strings.Contains(text, "power-play"):
+		return models.GoalTypePowerPlay
+	case strings.Contains(text, "short handed"), strings.Contains(text, "shorthanded"), strings.Contains(text, "short-handed"):
+		return models.GoalTypeShortHanded
+	}
+	return models.GoalTypeEvenStrength
+}
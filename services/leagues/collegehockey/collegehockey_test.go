@@ -0,0 +1,166 @@
+package collegehockey
+
+import (
+	"goalfeed/clients/leagues/nfl"
+	"goalfeed/models"
+	"goalfeed/services/leagues/espn"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// stubClient serves fixed scoreboard and summary documents.
+type stubClient struct {
+	nfl.NFLMockClient
+	schedule nfl.NFLScheduleResponse
+	summary  nfl.NFLScoreboardResponse
+}
+
+func (c stubClient) GetNFLSchedule() nfl.NFLScheduleResponse { return c.schedule }
+
+func (c stubClient) GetNFLScheduleByDate(date string) nfl.NFLScheduleResponse { return c.schedule }
+
+func (c stubClient) GetNFLScoreBoard(gameId string) nfl.NFLScoreboardResponse { return c.summary }
+
+func scheduleEvent(id string, state string) nfl.NFLScheduleEvent {
+	event := nfl.NFLScheduleEvent{ID: id, Date: "2026-10-17T23:00Z"}
+	event.Status.Type.State = state
+	home := nfl.NFLCompetitor{HomeAway: "home", Score: "1"}
+	home.Team.ID = "2"
+	home.Team.Abbreviation = "BC"
+	home.Team.DisplayName = "Boston College Eagles"
+	home.Team.ConferenceID = "41"
+	home.CuratedRank.Current = 1
+	away := nfl.NFLCompetitor{HomeAway: "away", Score: "0"}
+	away.Team.ID = "3"
+	away.Team.Abbreviation = "BU"
+	away.Team.DisplayName = "Boston University Terriers"
+	away.Team.ConferenceID = "41"
+	away.CuratedRank.Current = 99
+	event.Competitions = []nfl.NFLCompetition{{Competitors: []nfl.NFLCompetitor{home, away}}}
+	return event
+}
+
+func summary(homeScore, awayScore string, period int, detail string, plays ...nfl.NFLScoringPlay) nfl.NFLScoreboardResponse {
+	home := nfl.NFLScoreboardCompetitor{HomeAway: "home", Score: homeScore}
+	home.Team.ID = "2"
+	home.Team.Abbreviation = "BC"
+	away := nfl.NFLScoreboardCompetitor{HomeAway: "away", Score: awayScore}
+	away.Team.ID = "3"
+	away.Team.Abbreviation = "BU"
+	competition := nfl.NFLSummaryCompetition{Competitors: []nfl.NFLScoreboardCompetitor{home, away}}
+	competition.Status.Period = period
+	competition.Status.DisplayClock = "04:12"
+	competition.Status.Type.State = espn.StateActive
+	competition.Status.Type.ShortDetail = detail
+	response := nfl.NFLScoreboardResponse{ScoringPlays: plays}
+	response.Header.Competitions = []nfl.NFLSummaryCompetition{competition}
+	return response
+}
+
+func scoringPlay(id string, teamID string, period int, text string) nfl.NFLScoringPlay {
+	play := nfl.NFLScoringPlay{ID: id, Text: text}
+	play.Team.ID = teamID
+	play.Period.Number = period
+	play.Clock.DisplayValue = "04:12"
+	return play
+}
+
+func TestGetLeagueName(t *testing.T) {
+	assert.Equal(t, "NCAAMH", CollegeHockeyService{League: models.LeagueIdNCAAMHockey}.GetLeagueName())
+	assert.Equal(t, "NCAAWH", CollegeHockeyService{League: models.LeagueIdNCAAWHockey}.GetLeagueName())
+}
+
+func TestGetActiveAndUpcomingGames(t *testing.T) {
+	client := stubClient{schedule: nfl.NFLScheduleResponse{Events: []nfl.NFLScheduleEvent{
+		scheduleEvent("1", espn.StateActive),
+		scheduleEvent("2", espn.StateUpcoming),
+		scheduleEvent("3", espn.StateFinal),
+	}}}
+	service := CollegeHockeyService{Client: client, League: models.LeagueIdNCAAMHockey}
+
+	games := make(chan []models.Game)
+	go service.GetActiveGames(games)
+	active := <-games
+	assert.Len(t, active, 1)
+	assert.Equal(t, "1", active[0].GameCode)
+	assert.Equal(t, models.League(models.LeagueIdNCAAMHockey), active[0].LeagueId)
+	assert.Equal(t, models.LeagueIdNCAAMHockey, active[0].CurrentState.Home.Team.LeagueID)
+	assert.Equal(t, 1, active[0].CurrentState.Home.Rank)
+	assert.Equal(t, 0, active[0].CurrentState.Away.Rank)
+	assert.Equal(t, "41", active[0].CurrentState.Home.Team.Conference)
+
+	go service.GetUpcomingGames(games)
+	upcoming := <-games
+	assert.Len(t, upcoming, 1)
+	assert.Equal(t, "2", upcoming[0].GameCode)
+}
+
+func TestPeriodType(t *testing.T) {
+	assert.Equal(t, models.PeriodTypeRegular, periodType(3, "04:12 - 3rd"))
+	assert.Equal(t, models.PeriodTypeOvertime, periodType(4, "02:00 - OT"))
+	assert.Equal(t, models.PeriodTypeOvertime, periodType(4, "Final/OT"))
+	assert.Equal(t, models.PeriodTypeShootout, periodType(4, "Final/SO"))
+	assert.Equal(t, models.PeriodTypeRegular, periodType(0, "Postponed"))
+}
+
+func TestGameUpdateFiresGoalWithType(t *testing.T) {
+	client := stubClient{summary: summary("1", "1", 2, "04:12 - 2nd",
+		scoringPlay("10", "2", 1, "Gabe Perreault Goal (Power Play)"),
+		scoringPlay("11", "3", 2, "Cole Eiserman Goal (Short Handed)"),
+	)}
+	service := CollegeHockeyService{Client: client, League: models.LeagueIdNCAAMHockey}
+	game := service.gameFromEvent(scheduleEvent("1", espn.StateActive))
+
+	updates := make(chan models.GameUpdate)
+	go service.GetGameUpdate(game, updates)
+	update := <-updates
+	// The summary carries no ranking or conference; they're kept
+	assert.Equal(t, 1, update.NewState.Home.Rank)
+	assert.Equal(t, "41", update.NewState.Away.Team.Conference)
+
+	events := make(chan []models.Event)
+	go service.GetEvents(update, events)
+	got := <-events
+	assert.Len(t, got, 1)
+	assert.Equal(t, "BU", got[0].TeamCode)
+	assert.Equal(t, "BC", got[0].OpponentCode)
+	assert.Equal(t, "NCAAMH", got[0].LeagueName)
+	assert.Equal(t, models.LeagueIdNCAAMHockey, got[0].LeagueId)
+	assert.Equal(t, models.GoalTypeShortHanded, got[0].Details.GoalType)
+}
+
+func TestGameUpdateKeepsStateWithoutSummary(t *testing.T) {
+	service := CollegeHockeyService{Client: stubClient{}, League: models.LeagueIdNCAAWHockey}
+	game := service.gameFromEvent(scheduleEvent("1", espn.StateActive))
+	updates := make(chan models.GameUpdate)
+	go service.GetGameUpdate(game, updates)
+	update := <-updates
+	assert.Equal(t, game.CurrentState, update.NewState)
+}
+
+func TestClockFromStatus(t *testing.T) {
+	assert.Equal(t, "End of 2nd", clockFromStatus("0:00", "End of 2nd"))
+	assert.Equal(t, "12:01", clockFromStatus("12:01", "12:01 - 1st"))
+}
+
+func TestConferenceNamesComeFromTheFeed(t *testing.T) {
+	conferenceNames = map[string]string{}
+	t.Cleanup(func() { conferenceNames = map[string]string{} })
+	service := CollegeHockeyService{League: models.LeagueIdNCAAMHockey}
+
+	// "5" is football's Big Ten; a hockey conference with that ID isn't
+	nonConference := scheduleEvent("1", espn.StateUpcoming)
+	nonConference.Competitions[0].Competitors[0].Team.ConferenceID = "5"
+	assert.Equal(t, "5", service.gameFromEvent(nonConference).CurrentState.Home.Team.Conference)
+
+	conference := scheduleEvent("2", espn.StateUpcoming)
+	conference.Competitions[0].Competitors[0].Team.ConferenceID = "5"
+	conference.Competitions[0].Groups = nfl.NFLCompetitionGroup{ID: "5", Name: "Hockey East Association", ShortName: "Hockey East", IsConference: true}
+	service.Client = stubClient{schedule: nfl.NFLScheduleResponse{Events: []nfl.NFLScheduleEvent{nonConference, conference}}}
+	ch := make(chan []models.Game, 1)
+	service.GetUpcomingGames(ch)
+	games := <-ch
+	assert.Equal(t, "Hockey East", games[0].CurrentState.Home.Team.Conference, "named by the conference game in the same schedule")
+	assert.True(t, games[0].CurrentState.Home.Team.MatchesWatchEntry("conf:hockey east"))
+}
@@ -0,0 +1,52 @@
+package collegehockey
+
+import (
+	"goalfeed/clients/leagues/nfl"
+	"sync"
+)
+
+// conferenceNames is the name of each ESPN college hockey conference ID seen
+// so far. Hockey's IDs aren't football's, and the feed only names a
+// conference in the "groups" of a conference game, so the names are learned
+// from those games as they're read.
+var (
+	conferencesMu   sync.Mutex
+	conferenceNames = map[string]string{}
+)
+
+// learnConference records the name of the conference a game is played in.
+func learnConference(group nfl.NFLCompetitionGroup) {
+	name := group.ShortName
+	if name == "" {
+		name = group.Name
+	}
+	if !group.IsConference || group.ID == "" || name == "" {
+		return
+	}
+	conferencesMu.Lock()
+	conferenceNames[group.ID] = name
+	conferencesMu.Unlock()
+}
+
+// learnConferences learns the conferences of a schedule's conference games
+// before any of its games are read, so a team's non-conference game is
+// named too.
+func learnConferences(events []nfl.NFLScheduleEvent) []nfl.NFLScheduleEvent {
+	for _, event := range events {
+		for _, competition := range event.Competitions {
+			learnConference(competition.Groups)
+		}
+	}
+	return events
+}
+
+// conferenceName is the name of a conference ID, or the ID until a
+// conference game has named it. A team with no conference gets "".
+func conferenceName(id string) string {
+	conferencesMu.Lock()
+	defer conferencesMu.Unlock()
+	if name, ok := conferenceNames[id]; ok {
+		return name
+	}
+	return id
+}
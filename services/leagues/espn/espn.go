@@ -0,0 +1,61 @@
+// Package espn holds what the league services reading ESPN's site API
+// share: how its statuses, sides and dates are written.
+package espn
+
+import (
+	"goalfeed/models"
+	"strings"
+	"time"
+)
+
+// ESPN's status.type.state values.
+const (
+	StateUpcoming = "pre"
+	StateActive   = "in"
+	StateFinal    = "post"
+)
+
+// GameStatus maps an event's status.type onto models.GameStatus. A
+// postponed or delayed game, which ESPN leaves in "pre", is delayed.
+func GameStatus(state string, completed bool, name string) models.GameStatus {
+	name = strings.ToLower(name)
+	switch {
+	case completed || state == StateFinal:
+		return models.StatusEnded
+	case state == StateActive:
+		return models.StatusActive
+	case strings.Contains(name, "postponed"), strings.Contains(name, "delayed"):
+		return models.StatusDelayed
+	}
+	return models.StatusUpcoming
+}
+
+// CompetitorsBySide returns a competition's home and away competitors, by
+// the homeAway each one gives.
+func CompetitorsBySide[C any](competitors []C, homeAway func(C) string) (home, away C) {
+	for _, competitor := range competitors {
+		switch homeAway(competitor) {
+		case "home":
+			home = competitor
+		case "away":
+			away = competitor
+		}
+	}
+	return home, away
+}
+
+// ParseDate reads an event date, which ESPN often writes without seconds
+// ("2025-01-04T19:00Z"). It's the zero time when the date can't be read.
+func ParseDate(date string) time.Time {
+	layouts := []string{
+		time.RFC3339,
+		"2006-01-02T15:04Z",
+		"2006-01-02T15:04:05Z",
+	}
+	for _, layout := range layouts {
+		if parsed, err := time.Parse(layout, date); err == nil {
+			return parsed
+		}
+	}
+	return time.Time{}
+}
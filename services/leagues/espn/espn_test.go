@@ -0,0 +1,36 @@
+package espn
+
+import (
+	"goalfeed/models"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGameStatus(t *testing.T) {
+	assert.EqualValues(t, models.StatusUpcoming, GameStatus(StateUpcoming, false, "STATUS_SCHEDULED"))
+	assert.EqualValues(t, models.StatusActive, GameStatus(StateActive, false, "STATUS_IN_PROGRESS"))
+	assert.EqualValues(t, models.StatusEnded, GameStatus(StateFinal, true, "STATUS_FINAL"))
+	assert.EqualValues(t, models.StatusEnded, GameStatus(StateActive, true, ""), "completed wins over the state")
+	assert.EqualValues(t, models.StatusDelayed, GameStatus(StateUpcoming, false, "STATUS_POSTPONED"))
+	assert.EqualValues(t, models.StatusUpcoming, GameStatus("", false, ""))
+}
+
+func TestCompetitorsBySide(t *testing.T) {
+	type competitor struct{ code, homeAway string }
+	home, away := CompetitorsBySide([]competitor{{"TOR", "away"}, {"WPG", "home"}}, func(c competitor) string { return c.homeAway })
+	assert.Equal(t, "WPG", home.code)
+	assert.Equal(t, "TOR", away.code)
+
+	_, away = CompetitorsBySide([]competitor{{"WPG", "home"}}, func(c competitor) string { return c.homeAway })
+	assert.Empty(t, away.code, "a missing side is left empty")
+}
+
+func TestParseDate(t *testing.T) {
+	want := time.Date(2025, 1, 4, 19, 0, 0, 0, time.UTC)
+	assert.True(t, want.Equal(ParseDate("2025-01-04T19:00Z")))
+	assert.True(t, want.Equal(ParseDate("2025-01-04T19:00:00Z")))
+	assert.True(t, want.Equal(ParseDate("2025-01-04T14:00:00-05:00")))
+	assert.True(t, ParseDate("tomorrow").IsZero())
+}
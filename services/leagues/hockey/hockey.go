@@ -0,0 +1,259 @@
+// Package hockey holds the goal, overtime and shootout logic shared by every
+// hockey league service (NHL, IIHF, ...). League services map their feed into
+// models.GameState, including GameState.Shootout, and hand the resulting
+// GameUpdate to GetEvents.
+package hockey
+
+import (
+	"fmt"
+	"goalfeed/models"
+)
+
+// GetEvents returns the goal, shootout-attempt, shootout-result and
+// goalie-pulled events for a hockey game update. When a service puts the
+// feed's scoring plays in update.Events, goals pick up their scorer, assists
+// and goal type from the matching play.
+func GetEvents(update models.GameUpdate, leagueId int, leagueName string) []models.Event {
+	events := append(
+		getGoalEvents(update, update.OldState.Home, update.NewState.Home, update.NewState.Away, leagueId, leagueName),
+		getGoalEvents(update, update.OldState.Away, update.NewState.Away, update.NewState.Home, leagueId, leagueName)...,
+	)
+	events = append(events, getShootoutEvents(update, leagueId, leagueName)...)
+	return append(events, getGoalieEvents(update, leagueId, leagueName)...)
+}
+
+// PeriodTypeFromCode maps the period type codes used by hockey feeds onto
+// the models.PeriodType* values.
+func PeriodTypeFromCode(code string) string {
+	switch code {
+	case "OT":
+		return models.PeriodTypeOvertime
+	case "SO", "GWS":
+		return models.PeriodTypeShootout
+	default:
+		return models.PeriodTypeRegular
+	}
+}
+
+// ShootoutBonus returns the point a feed adds to the shootout winner's final
+// score. It is only counted once the winner is known and the feed has
+// actually broken the tie, since some feeds leave the score level.
+func ShootoutBonus(state models.GameState, team models.TeamState, opponent models.TeamState) int {
+	if state.Shootout == nil || state.Shootout.Winner == "" {
+		return 0
+	}
+	if state.Shootout.Winner == team.Team.TeamCode && team.Score > opponent.Score {
+		return 1
+	}
+	return 0
+}
+
+// ShootoutFromAttempts builds a ShootoutState from a feed's attempt list.
+// winner is the team code the feed credits with the shootout win; when it is
+// empty and the game has ended, the team with more shootout goals wins.
+func ShootoutFromAttempts(attempts []models.ShootoutAttempt, home, away models.Team, winner string, ended bool) *models.ShootoutState {
+	state := &models.ShootoutState{Attempts: attempts}
+	for _, attempt := range attempts {
+		if attempt.Result != "goal" {
+			continue
+		}
+		switch attempt.TeamCode {
+		case home.TeamCode:
+			state.HomeGoals++
+		case away.TeamCode:
+			state.AwayGoals++
+		}
+	}
+	if !ended {
+		return state
+	}
+	if winner != "" {
+		state.Winner = winner
+	} else if state.HomeGoals > state.AwayGoals {
+		state.Winner = home.TeamCode
+	} else if state.AwayGoals > state.HomeGoals {
+		state.Winner = away.TeamCode
+	}
+	return state
+}
+
+func getGoalEvents(update models.GameUpdate, oldState, newState, opponent models.TeamState, leagueId int, leagueName string) []models.Event {
+	events := []models.Event{}
+	// Nothing scored during a shootout counts as a goal; attempts are
+	// reported separately by getShootoutEvents.
+	if update.OldState.PeriodType == models.PeriodTypeShootout && update.NewState.PeriodType == models.PeriodTypeShootout {
+		return events
+	}
+
+	oldOpponent := update.OldState.Away
+	if oldState.Team.TeamCode == update.OldState.Away.Team.TeamCode {
+		oldOpponent = update.OldState.Home
+	}
+	diff := (newState.Score - ShootoutBonus(update.NewState, newState, opponent)) -
+		(oldState.Score - ShootoutBonus(update.OldState, oldState, oldOpponent))
+	if diff <= 0 {
+		return events
+	}
+
+	team := newState.Team
+	scored := oldState.Score - ShootoutBonus(update.OldState, oldState, oldOpponent)
+	for i := 0; i < diff; i++ {
+		event := models.Event{
+			Type:         models.EventTypeGoal,
+			TeamCode:     team.TeamCode,
+			TeamName:     team.TeamName,
+			TeamHash:     team.GetTeamHash(),
+			LeagueId:     leagueId,
+			LeagueName:   leagueName,
+			Period:       update.NewState.Period,
+			PeriodType:   update.NewState.PeriodType,
+			Clock:        update.NewState.Clock,
+			OpponentCode: opponent.Team.TeamCode,
+			OpponentName: opponent.Team.TeamName,
+			OpponentHash: opponent.Team.GetTeamHash(),
+		}
+		if play, ok := scoringPlay(update.Events, team.TeamCode, scored+i); ok {
+			event.PlayerName = play.Player.Name
+			event.PlayerNumber = play.Player.Number
+			event.Details = play.Details
+			if play.Period > 0 {
+				event.Period = play.Period
+			}
+			if play.Clock != "" {
+				event.Clock = play.Clock
+			}
+		}
+		event.Description = fmt.Sprintf("%s for %s", goalDescription(event), team.TeamCode)
+		events = append(events, event)
+	}
+	return events
+}
+
+// scoringPlay returns the nth (zero-based) scoring play credited to teamCode.
+func scoringPlay(plays []models.GameEvent, teamCode string, n int) (models.GameEvent, bool) {
+	for _, play := range plays {
+		if play.Type != models.EventTypeGoal || play.Team.TeamCode != teamCode {
+			continue
+		}
+		if n == 0 {
+			return play, true
+		}
+		n--
+	}
+	return models.GameEvent{}, false
+}
+
+func goalDescription(event models.Event) string {
+	switch {
+	case event.PeriodType == models.PeriodTypeOvertime:
+		return "Overtime goal"
+	case event.Details.GoalType == models.GoalTypeEmptyNet:
+		return "Empty-net goal"
+	default:
+		return "Goal"
+	}
+}
+
+// getGoalieEvents fires when a team pulls its goalie for an extra attacker
+// and again when the goalie goes back in. Nothing fires once the game is over,
+// since feeds reset the situation at the final horn.
+func getGoalieEvents(update models.GameUpdate, leagueId int, leagueName string) []models.Event {
+	events := []models.Event{}
+	if update.NewState.Status != models.StatusActive {
+		return events
+	}
+	sides := []struct{ old, new, opponent models.TeamState }{
+		{update.OldState.Home, update.NewState.Home, update.NewState.Away},
+		{update.OldState.Away, update.NewState.Away, update.NewState.Home},
+	}
+	for _, side := range sides {
+		if side.old.GoaliePulled == side.new.GoaliePulled {
+			continue
+		}
+		team := side.new.Team
+		eventType := models.EventTypeGoalieReturned
+		description := fmt.Sprintf("%s goalie back in net", team.TeamCode)
+		if side.new.GoaliePulled {
+			eventType = models.EventTypeGoaliePulled
+			description = fmt.Sprintf("%s pulled the goalie", team.TeamCode)
+		}
+		event := newEvent(eventType, description, team, side.opponent.Team, update.NewState, leagueId, leagueName, models.EventDetails{}, "")
+		event.Clock = update.NewState.Clock
+		events = append(events, event)
+	}
+	return events
+}
+
+func getShootoutEvents(update models.GameUpdate, leagueId int, leagueName string) []models.Event {
+	events := []models.Event{}
+	shootout := update.NewState.Shootout
+	if shootout == nil {
+		return events
+	}
+
+	seen := 0
+	previousWinner := ""
+	if update.OldState.Shootout != nil {
+		seen = len(update.OldState.Shootout.Attempts)
+		previousWinner = update.OldState.Shootout.Winner
+	}
+
+	for i := seen; i < len(shootout.Attempts); i++ {
+		attempt := shootout.Attempts[i]
+		team, opponent, ok := teamsForCode(update.NewState, attempt.TeamCode)
+		if !ok {
+			continue
+		}
+		events = append(events, newEvent(models.EventTypeShootoutAttempt,
+			fmt.Sprintf("Shootout attempt by %s (%s): %s", attempt.Player.Name, team.TeamCode, attempt.Result),
+			team, opponent, update.NewState, leagueId, leagueName, models.EventDetails{
+				ShootoutRound:  (attempt.Sequence + 1) / 2,
+				ShootoutResult: attempt.Result,
+			}, attempt.Player.Name))
+	}
+
+	if shootout.Winner != "" && previousWinner == "" {
+		if team, opponent, ok := teamsForCode(update.NewState, shootout.Winner); ok {
+			events = append(events, newEvent(models.EventTypeShootoutResult,
+				fmt.Sprintf("%s win the shootout %d-%d", team.TeamCode, max(shootout.HomeGoals, shootout.AwayGoals), min(shootout.HomeGoals, shootout.AwayGoals)),
+				team, opponent, update.NewState, leagueId, leagueName, models.EventDetails{}, ""))
+		}
+	}
+	return events
+}
+
+func teamsForCode(state models.GameState, teamCode string) (models.Team, models.Team, bool) {
+	switch teamCode {
+	case state.Home.Team.TeamCode:
+		return state.Home.Team, state.Away.Team, true
+	case state.Away.Team.TeamCode:
+		return state.Away.Team, state.Home.Team, true
+	default:
+		return models.Team{}, models.Team{}, false
+	}
+}
+
+func newEvent(eventType models.EventType, description string, team, opponent models.Team, state models.GameState, leagueId int, leagueName string, details models.EventDetails, playerName string) models.Event {
+	return models.Event{
+		Type:         eventType,
+		Description:  description,
+		TeamCode:     team.TeamCode,
+		TeamName:     team.TeamName,
+		TeamHash:     team.GetTeamHash(),
+		PlayerName:   playerName,
+		LeagueId:     leagueId,
+		LeagueName:   leagueName,
+		Period:       state.Period,
+		PeriodType:   state.PeriodType,
+		OpponentCode: opponent.TeamCode,
+		OpponentName: opponent.TeamName,
+		OpponentHash: opponent.GetTeamHash(),
+		Details:      details,
+		Score: models.ScoreUpdate{
+			HomeScore: state.Home.Score,
+			AwayScore: state.Away.Score,
+			HomeTeam:  state.Home.Team.TeamCode,
+			AwayTeam:  state.Away.Team.TeamCode,
+		},
+	}
+}
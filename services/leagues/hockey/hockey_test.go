@@ -0,0 +1,195 @@
+package hockey
+
+import (
+	"goalfeed/models"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var (
+	homeTeam = models.Team{TeamCode: "WPG", TeamName: "Winnipeg", LeagueID: models.LeagueIdNHL}
+	awayTeam = models.Team{TeamCode: "TOR", TeamName: "Toronto", LeagueID: models.LeagueIdNHL}
+)
+
+func stateWithScore(home, away int, period int, periodType string) models.GameState {
+	return models.GameState{
+		Home:       models.TeamState{Team: homeTeam, Score: home},
+		Away:       models.TeamState{Team: awayTeam, Score: away},
+		Status:     models.StatusActive,
+		Period:     period,
+		PeriodType: periodType,
+	}
+}
+
+func TestGetEvents_RegulationGoal(t *testing.T) {
+	update := models.GameUpdate{
+		OldState: stateWithScore(1, 1, 2, models.PeriodTypeRegular),
+		NewState: stateWithScore(2, 1, 2, models.PeriodTypeRegular),
+	}
+	events := GetEvents(update, models.LeagueIdNHL, "NHL")
+	assert.Len(t, events, 1)
+	assert.Equal(t, models.EventTypeGoal, events[0].Type)
+	assert.Equal(t, "WPG", events[0].TeamCode)
+	assert.Equal(t, "TOR", events[0].OpponentCode)
+	assert.Equal(t, models.PeriodTypeRegular, events[0].PeriodType)
+}
+
+func TestGetEvents_OvertimeGoal(t *testing.T) {
+	update := models.GameUpdate{
+		OldState: stateWithScore(2, 2, 4, models.PeriodTypeOvertime),
+		NewState: stateWithScore(2, 3, 4, models.PeriodTypeOvertime),
+	}
+	update.NewState.Status = models.StatusEnded
+	events := GetEvents(update, models.LeagueIdNHL, "NHL")
+	assert.Len(t, events, 1)
+	assert.Equal(t, "TOR", events[0].TeamCode)
+	assert.Equal(t, models.PeriodTypeOvertime, events[0].PeriodType)
+	assert.Equal(t, 4, events[0].Period)
+	assert.Contains(t, events[0].Description, "Overtime goal")
+}
+
+func TestGetEvents_ShootoutAttempts(t *testing.T) {
+	old := stateWithScore(2, 2, 5, models.PeriodTypeShootout)
+	old.Shootout = &models.ShootoutState{Attempts: []models.ShootoutAttempt{
+		{Sequence: 1, TeamCode: "TOR", Result: "save"},
+	}}
+	next := stateWithScore(2, 2, 5, models.PeriodTypeShootout)
+	next.Shootout = &models.ShootoutState{Attempts: []models.ShootoutAttempt{
+		{Sequence: 1, TeamCode: "TOR", Result: "save"},
+		{Sequence: 2, TeamCode: "WPG", Result: "goal", Player: models.Player{Name: "Kyle Connor"}},
+		{Sequence: 3, TeamCode: "TOR", Result: "miss"},
+	}, HomeGoals: 1}
+
+	events := GetEvents(models.GameUpdate{OldState: old, NewState: next}, models.LeagueIdNHL, "NHL")
+	assert.Len(t, events, 2)
+	assert.Equal(t, models.EventTypeShootoutAttempt, events[0].Type)
+	assert.Equal(t, "WPG", events[0].TeamCode)
+	assert.Equal(t, "Kyle Connor", events[0].PlayerName)
+	assert.Equal(t, "goal", events[0].Details.ShootoutResult)
+	assert.Equal(t, 1, events[0].Details.ShootoutRound)
+	assert.Equal(t, "TOR", events[1].TeamCode)
+	assert.Equal(t, "miss", events[1].Details.ShootoutResult)
+	assert.Equal(t, 2, events[1].Details.ShootoutRound)
+}
+
+func TestGetEvents_ShootoutFinalHasNoPhantomGoal(t *testing.T) {
+	attempts := []models.ShootoutAttempt{
+		{Sequence: 1, TeamCode: "TOR", Result: "save"},
+		{Sequence: 2, TeamCode: "WPG", Result: "goal"},
+	}
+	old := stateWithScore(2, 2, 5, models.PeriodTypeShootout)
+	old.Shootout = &models.ShootoutState{Attempts: attempts, HomeGoals: 1}
+	next := stateWithScore(3, 2, 5, models.PeriodTypeShootout)
+	next.Status = models.StatusEnded
+	next.Shootout = ShootoutFromAttempts(attempts, homeTeam, awayTeam, "", true)
+
+	events := GetEvents(models.GameUpdate{OldState: old, NewState: next}, models.LeagueIdNHL, "NHL")
+	assert.Len(t, events, 1)
+	assert.Equal(t, models.EventTypeShootoutResult, events[0].Type)
+	assert.Equal(t, "WPG", events[0].TeamCode)
+	assert.Equal(t, 3, events[0].Score.HomeScore)
+}
+
+func TestGetEvents_MissedPollIntoShootoutFinal(t *testing.T) {
+	// Last poll was tied in overtime; the next one already shows the final
+	// with the shootout point added. Only the result should fire.
+	attempts := []models.ShootoutAttempt{
+		{Sequence: 1, TeamCode: "TOR", Result: "goal"},
+		{Sequence: 2, TeamCode: "WPG", Result: "save"},
+	}
+	old := stateWithScore(1, 1, 4, models.PeriodTypeOvertime)
+	next := stateWithScore(1, 2, 5, models.PeriodTypeShootout)
+	next.Status = models.StatusEnded
+	next.Shootout = ShootoutFromAttempts(attempts, homeTeam, awayTeam, "TOR", true)
+
+	events := GetEvents(models.GameUpdate{OldState: old, NewState: next}, models.LeagueIdNHL, "NHL")
+	var types []models.EventType
+	for _, e := range events {
+		types = append(types, e.Type)
+	}
+	assert.NotContains(t, types, models.EventTypeGoal)
+	assert.Equal(t, []models.EventType{models.EventTypeShootoutAttempt, models.EventTypeShootoutAttempt, models.EventTypeShootoutResult}, types)
+}
+
+func TestShootoutFromAttempts(t *testing.T) {
+	attempts := []models.ShootoutAttempt{
+		{TeamCode: "WPG", Result: "goal"},
+		{TeamCode: "TOR", Result: "goal"},
+		{TeamCode: "WPG", Result: "miss"},
+	}
+	live := ShootoutFromAttempts(attempts, homeTeam, awayTeam, "", false)
+	assert.Equal(t, 1, live.HomeGoals)
+	assert.Equal(t, 1, live.AwayGoals)
+	assert.Empty(t, live.Winner)
+
+	final := ShootoutFromAttempts(attempts, homeTeam, awayTeam, "TOR", true)
+	assert.Equal(t, "TOR", final.Winner)
+}
+
+func TestShootoutBonus_TiedFinalScore(t *testing.T) {
+	// Feeds that leave the final score level don't add a bonus point
+	state := stateWithScore(2, 2, 5, models.PeriodTypeShootout)
+	state.Shootout = &models.ShootoutState{Winner: "WPG"}
+	assert.Equal(t, 0, ShootoutBonus(state, state.Home, state.Away))
+	state.Home.Score = 3
+	assert.Equal(t, 1, ShootoutBonus(state, state.Home, state.Away))
+	assert.Equal(t, 0, ShootoutBonus(state, state.Away, state.Home))
+}
+
+func TestPeriodTypeFromCode(t *testing.T) {
+	assert.Equal(t, models.PeriodTypeRegular, PeriodTypeFromCode("REG"))
+	assert.Equal(t, models.PeriodTypeRegular, PeriodTypeFromCode("2"))
+	assert.Equal(t, models.PeriodTypeOvertime, PeriodTypeFromCode("OT"))
+	assert.Equal(t, models.PeriodTypeShootout, PeriodTypeFromCode("SO"))
+	assert.Equal(t, models.PeriodTypeShootout, PeriodTypeFromCode("GWS"))
+}
+
+func TestGetEvents_EmptyNetGoalFromScoringPlays(t *testing.T) {
+	update := models.GameUpdate{
+		OldState: stateWithScore(2, 1, 3, models.PeriodTypeRegular),
+		NewState: stateWithScore(3, 1, 3, models.PeriodTypeRegular),
+		Events: []models.GameEvent{
+			{Type: models.EventTypeGoal, Team: homeTeam, Details: models.EventDetails{GoalType: models.GoalTypeEvenStrength}},
+			{Type: models.EventTypeGoal, Team: awayTeam},
+			{Type: models.EventTypeGoal, Team: homeTeam, Details: models.EventDetails{GoalType: models.GoalTypePowerPlay}},
+			{Type: models.EventTypeGoal, Team: homeTeam, Period: 3, Clock: "00:41",
+				Player:  models.Player{Name: "Kyle Connor", Number: 81},
+				Details: models.EventDetails{GoalType: models.GoalTypeEmptyNet}},
+		},
+	}
+	update.OldState.Away.GoaliePulled = true
+	update.NewState.Away.GoaliePulled = true
+
+	events := GetEvents(update, models.LeagueIdNHL, "NHL")
+	assert.Len(t, events, 1)
+	assert.Equal(t, models.GoalTypeEmptyNet, events[0].Details.GoalType)
+	assert.Equal(t, "Kyle Connor", events[0].PlayerName)
+	assert.Equal(t, 81, events[0].PlayerNumber)
+	assert.Equal(t, "00:41", events[0].Clock)
+	assert.Equal(t, "Empty-net goal for WPG", events[0].Description)
+}
+
+func TestGetEvents_GoaliePulledAndReturned(t *testing.T) {
+	old := stateWithScore(1, 2, 3, models.PeriodTypeRegular)
+	pulled := stateWithScore(1, 2, 3, models.PeriodTypeRegular)
+	pulled.Home.GoaliePulled = true
+	pulled.Clock = "01:58"
+
+	events := GetEvents(models.GameUpdate{OldState: old, NewState: pulled}, models.LeagueIdNHL, "NHL")
+	assert.Len(t, events, 1)
+	assert.Equal(t, models.EventTypeGoaliePulled, events[0].Type)
+	assert.Equal(t, "WPG", events[0].TeamCode)
+	assert.Equal(t, "TOR", events[0].OpponentCode)
+	assert.Equal(t, "01:58", events[0].Clock)
+
+	back := stateWithScore(1, 2, 3, models.PeriodTypeRegular)
+	events = GetEvents(models.GameUpdate{OldState: pulled, NewState: back}, models.LeagueIdNHL, "NHL")
+	assert.Len(t, events, 1)
+	assert.Equal(t, models.EventTypeGoalieReturned, events[0].Type)
+
+	// The final horn resets the situation; that isn't the goalie coming back
+	back.Status = models.StatusEnded
+	events = GetEvents(models.GameUpdate{OldState: pulled, NewState: back}, models.LeagueIdNHL, "NHL")
+	assert.Empty(t, events)
+}
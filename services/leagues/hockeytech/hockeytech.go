@@ -0,0 +1,307 @@
+// Package hockeytech follows the hockey leagues whose stats are hosted by
+// HockeyTech (PWHL, AHL). Goals, overtime and shootouts go through the
+// shared hockey package, as for the NHL and IIHF.
+package hockeytech
+
+import (
+	"fmt"
+	"goalfeed/clients/leagues/hockeytech"
+	"goalfeed/models"
+	"goalfeed/services/leagues/hockey"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// regulationPeriodSeconds is the length of a regulation period; overtime
+// length varies by league and season, so only regulation goals get a clock.
+const regulationPeriodSeconds = 20 * 60
+
+// HockeyTechService follows one HockeyTech league; League is
+// models.LeagueIdPWHL or models.LeagueIdAHL.
+type HockeyTechService struct {
+	Client hockeytech.IHockeyTechApiClient
+	League models.League
+}
+
+func (s HockeyTechService) GetLeagueName() string {
+	if s.League == models.LeagueIdAHL {
+		return "AHL"
+	}
+	return "PWHL"
+}
+
+// GetActiveGames looks back a day as well, so games running past midnight
+// are still found.
+func (s HockeyTechService) GetActiveGames(ret chan []models.Game) {
+	var activeGames []models.Game
+	for _, game := range s.Client.GetScorebar(1, 0).SiteKit.Scorebar {
+		if gameStatusFromScorebar(game) == models.StatusActive {
+			activeGames = append(activeGames, s.gameFromScorebar(game))
+		}
+	}
+	ret <- activeGames
+}
+
+func (s HockeyTechService) GetUpcomingGames(ret chan []models.Game) {
+	var upcomingGames []models.Game
+	for _, game := range s.Client.GetScorebar(0, 7).SiteKit.Scorebar {
+		if gameStatusFromScorebar(game) == models.StatusUpcoming {
+			upcomingGames = append(upcomingGames, s.gameFromScorebar(game))
+		}
+	}
+	ret <- upcomingGames
+}
+
+// GetGamesByDate reads the scorebar window reaching the date (YYYY-MM-DD),
+// since the feed counts days from today rather than taking a date.
+func (s HockeyTechService) GetGamesByDate(date string, ret chan []models.Game) {
+	day, err := time.Parse("2006-01-02", date)
+	if err != nil {
+		ret <- []models.Game{}
+		return
+	}
+	today, _ := time.Parse("2006-01-02", time.Now().Format("2006-01-02"))
+	offset := int(day.Sub(today).Hours() / 24)
+	var games []models.Game
+	for _, game := range s.Client.GetScorebar(max(0, -offset), max(0, offset)).SiteKit.Scorebar {
+		if game.Date == date {
+			games = append(games, s.gameFromScorebar(game))
+		}
+	}
+	ret <- games
+}
+
+// GetGameUpdate reads the score, period and clock from the scorebar and the
+// goals and shootout from the game summary. A game missing from the
+// scorebar keeps its old state.
+func (s HockeyTechService) GetGameUpdate(game models.Game, ret chan models.GameUpdate) {
+	newState := game.CurrentState
+	for _, scorebarGame := range s.Client.GetScorebar(1, 0).SiteKit.Scorebar {
+		if scorebarGame.ID == game.GameCode {
+			newState = s.gameStateFromScorebar(scorebarGame)
+			break
+		}
+	}
+	summary := s.Client.GetGameSummary(game.GameCode).SiteKit.Gamesummary
+	newState.Shootout = shootoutFromSummary(summary, newState)
+	ret <- models.GameUpdate{
+		OldState: game.CurrentState,
+		NewState: newState,
+		Events:   scoringPlaysFromSummary(summary, newState.Home.Team, newState.Away.Team),
+	}
+}
+
+func (s HockeyTechService) GetEvents(update models.GameUpdate, ret chan []models.Event) {
+	ret <- hockey.GetEvents(update, int(s.League), s.GetLeagueName())
+}
+
+// gameStatusFromScorebar reads the numeric status, letting the status text
+// catch finals ("Final OT", "Unofficial Final") and postponements.
+func gameStatusFromScorebar(game hockeytech.HockeyTechScorebarGame) models.GameStatus {
+	status := strings.ToLower(game.GameStatusString)
+	switch {
+	case game.GameStatus == "4", strings.Contains(status, "final"):
+		return models.StatusEnded
+	case strings.Contains(status, "postponed"), strings.Contains(status, "delayed"):
+		return models.StatusDelayed
+	case game.GameStatus == "2", game.GameStatus == "3":
+		return models.StatusActive
+	}
+	return models.StatusUpcoming
+}
+
+// periodTypeFromName maps the scorebar's period names ("1st", "OT", "2OT",
+// "SO") onto the models.PeriodType* values.
+func periodTypeFromName(name string) string {
+	name = strings.ToUpper(strings.TrimSpace(name))
+	switch {
+	case strings.Contains(name, "SO"):
+		return hockey.PeriodTypeFromCode("SO")
+	case strings.Contains(name, "OT"):
+		return hockey.PeriodTypeFromCode("OT")
+	}
+	return models.PeriodTypeRegular
+}
+
+func (s HockeyTechService) team(id, code, city, nickname, longName, logo string) models.Team {
+	name := strings.TrimSpace(city + " " + nickname)
+	if city == "" || nickname == "" {
+		name = longName
+	}
+	return models.Team{
+		TeamName: name,
+		TeamCode: code,
+		ExtID:    id,
+		LeagueID: int(s.League),
+		LogoURL:  logo,
+	}
+}
+
+func (s HockeyTechService) gameStateFromScorebar(game hockeytech.HockeyTechScorebarGame) models.GameState {
+	homeScore, _ := strconv.Atoi(game.HomeGoals)
+	awayScore, _ := strconv.Atoi(game.VisitorGoals)
+	period, _ := strconv.Atoi(game.Period)
+	state := models.GameState{
+		Home: models.TeamState{
+			Team:  s.team(game.HomeID, game.HomeCode, game.HomeCity, game.HomeNickname, game.HomeLongName, game.HomeLogo),
+			Score: homeScore,
+		},
+		Away: models.TeamState{
+			Team:  s.team(game.VisitorID, game.VisitorCode, game.VisitorCity, game.VisitorNickname, game.VisitorLongName, game.VisitorLogo),
+			Score: awayScore,
+		},
+		Status:       gameStatusFromScorebar(game),
+		FetchedAt:    time.Now(),
+		ExtTimestamp: game.GameDateISO8601,
+		Period:       period,
+		PeriodType:   periodTypeFromName(game.PeriodNameShort),
+		Clock:        game.GameClock,
+		Venue: models.Venue{
+			Name: game.VenueName,
+			City: game.VenueLocation,
+		},
+	}
+	switch {
+	case state.Status == models.StatusUpcoming:
+		state.Clock = game.ScheduledFormattedTime
+	case state.Status == models.StatusActive && game.Intermission == "1":
+		state.Clock = "Intermission"
+	}
+	state.TimeRemaining = state.Clock
+	return state
+}
+
+func (s HockeyTechService) gameFromScorebar(game hockeytech.HockeyTechScorebarGame) models.Game {
+	gameDate, _ := time.Parse(time.RFC3339, game.GameDateISO8601)
+	return models.Game{
+		CurrentState: s.gameStateFromScorebar(game),
+		GameCode:     game.ID,
+		ExtTimestamp: game.GameDateISO8601,
+		LeagueId:     s.League,
+		GameDetails: models.GameDetails{
+			GameId:   game.ID,
+			Season:   game.SeasonID,
+			GameDate: gameDate,
+			GameTime: game.ScheduledFormattedTime,
+			Timezone: game.Timezone,
+		},
+	}
+}
+
+// scoringPlaysFromSummary lists the summary's goals as scoring plays,
+// skipping any the summary files under the shootout period.
+func scoringPlaysFromSummary(summary hockeytech.HockeyTechGameSummary, home, away models.Team) []models.GameEvent {
+	var plays []models.GameEvent
+	for _, goal := range summary.Goals {
+		period, _ := strconv.Atoi(goal.PeriodID)
+		if summary.Meta.Shootout == "1" && period >= 5 {
+			continue
+		}
+		team := away
+		if goal.TeamID == home.ExtID {
+			team = home
+		}
+		clock := ""
+		if elapsed, ok := secondsFromTime(goal.Time); ok && period <= 3 {
+			remaining := regulationPeriodSeconds - elapsed
+			clock = fmt.Sprintf("%02d:%02d", remaining/60, remaining%60)
+		}
+		plays = append(plays, models.GameEvent{
+			Id:     goal.ID,
+			Type:   models.EventTypeGoal,
+			Period: period,
+			Time:   goal.Time,
+			Clock:  clock,
+			Team:   team,
+			Player: playerFromHockeyTech(goal.GoalScorer),
+			Details: models.EventDetails{
+				Assist1:  playerFromHockeyTech(goal.Assist1),
+				Assist2:  playerFromHockeyTech(goal.Assist2),
+				GoalType: goalTypeFromGoal(goal),
+			},
+		})
+	}
+	return plays
+}
+
+func goalTypeFromGoal(goal hockeytech.HockeyTechGoal) string {
+	switch {
+	case goal.EmptyNet == "1":
+		return models.GoalTypeEmptyNet
+	case goal.PowerPlay == "1":
+		return models.GoalTypePowerPlay
+	case goal.ShortHanded == "1":
+		return models.GoalTypeShortHanded
+	default:
+		return models.GoalTypeEvenStrength
+	}
+}
+
+// secondsFromTime reads a "M:SS" time into seconds.
+func secondsFromTime(value string) (int, bool) {
+	minutes, seconds, ok := strings.Cut(strings.TrimSpace(value), ":")
+	if !ok {
+		return 0, false
+	}
+	m, errM := strconv.Atoi(minutes)
+	sec, errS := strconv.Atoi(seconds)
+	if errM != nil || errS != nil || m*60+sec > regulationPeriodSeconds {
+		return 0, false
+	}
+	return m*60 + sec, true
+}
+
+func playerFromHockeyTech(player hockeytech.HockeyTechPlayer) models.Player {
+	name := strings.TrimSpace(player.FirstName + " " + player.LastName)
+	if name == "" {
+		return models.Player{}
+	}
+	number, _ := strconv.Atoi(player.JerseyNumber)
+	return models.Player{
+		Id:     player.PlayerID,
+		Name:   name,
+		Number: number,
+	}
+}
+
+// shootoutFromSummary collects the shootout attempts, alternating between
+// the visitors and the home team as the summary lists each side's shots
+// separately. It returns nil until the game reaches a shootout.
+func shootoutFromSummary(summary hockeytech.HockeyTechGameSummary, state models.GameState) *models.ShootoutState {
+	details := summary.ShootoutDetails
+	if state.PeriodType != models.PeriodTypeShootout && summary.Meta.Shootout != "1" {
+		return nil
+	}
+	var attempts []models.ShootoutAttempt
+	for i := 0; i < max(len(details.HomeShots), len(details.VisitingShots)); i++ {
+		if i < len(details.VisitingShots) {
+			attempts = append(attempts, shootoutAttempt(details.VisitingShots[i], state.Away.Team, len(attempts)+1))
+		}
+		if i < len(details.HomeShots) {
+			attempts = append(attempts, shootoutAttempt(details.HomeShots[i], state.Home.Team, len(attempts)+1))
+		}
+	}
+	winner := ""
+	switch details.WinningTeamID {
+	case "":
+	case state.Home.Team.ExtID:
+		winner = state.Home.Team.TeamCode
+	case state.Away.Team.ExtID:
+		winner = state.Away.Team.TeamCode
+	}
+	return hockey.ShootoutFromAttempts(attempts, state.Home.Team, state.Away.Team, winner, state.Status == models.StatusEnded)
+}
+
+func shootoutAttempt(shot hockeytech.HockeyTechShootoutShot, team models.Team, sequence int) models.ShootoutAttempt {
+	result := "miss"
+	if shot.IsGoal == "1" {
+		result = "goal"
+	}
+	return models.ShootoutAttempt{
+		Sequence: sequence,
+		TeamCode: team.TeamCode,
+		Player:   playerFromHockeyTech(shot.Shooter),
+		Result:   result,
+	}
+}
@@ -0,0 +1,208 @@
+package hockeytech
+
+import (
+	"goalfeed/clients/leagues/hockeytech"
+	"goalfeed/models"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func scorebarGame(status string, homeGoals string, visitorGoals string) hockeytech.HockeyTechScorebarGame {
+	return hockeytech.HockeyTechScorebarGame{
+		ID:                     "210",
+		Date:                   "2026-01-10",
+		GameDateISO8601:        "2026-01-10T19:00:00-05:00",
+		ScheduledFormattedTime: "7:00 pm EST",
+		Timezone:               "Canada/Eastern",
+		SeasonID:               "8",
+		HomeID:                 "6",
+		HomeCode:               "TOR",
+		HomeCity:               "Toronto",
+		HomeNickname:           "Sceptres",
+		HomeGoals:              homeGoals,
+		VisitorID:              "3",
+		VisitorCode:            "MTL",
+		VisitorCity:            "Montréal",
+		VisitorNickname:        "Victoire",
+		VisitorGoals:           visitorGoals,
+		Period:                 "2",
+		PeriodNameShort:        "2nd",
+		GameClock:              "08:21",
+		GameStatus:             status,
+		VenueName:              "Coca-Cola Coliseum",
+		VenueLocation:          "Toronto, ON",
+	}
+}
+
+func scorebar(games ...hockeytech.HockeyTechScorebarGame) hockeytech.HockeyTechScorebarResponse {
+	response := hockeytech.HockeyTechScorebarResponse{}
+	response.SiteKit.Scorebar = games
+	return response
+}
+
+func summary(goals ...hockeytech.HockeyTechGoal) hockeytech.HockeyTechGameSummaryResponse {
+	response := hockeytech.HockeyTechGameSummaryResponse{}
+	response.SiteKit.Gamesummary.Goals = goals
+	return response
+}
+
+func TestGetLeagueName(t *testing.T) {
+	assert.Equal(t, "PWHL", HockeyTechService{League: models.LeagueIdPWHL}.GetLeagueName())
+	assert.Equal(t, "AHL", HockeyTechService{League: models.LeagueIdAHL}.GetLeagueName())
+}
+
+func TestGetActiveAndUpcomingGames(t *testing.T) {
+	upcoming := scorebarGame("1", "0", "0")
+	upcoming.ID = "211"
+	final := scorebarGame("4", "3", "1")
+	final.ID = "209"
+	service := HockeyTechService{
+		Client: hockeytech.MockHockeyTechApiClient{ScorebarResponse: scorebar(final, scorebarGame("2", "1", "0"), upcoming)},
+		League: models.LeagueIdPWHL,
+	}
+
+	activeChan := make(chan []models.Game)
+	go service.GetActiveGames(activeChan)
+	active := <-activeChan
+	assert.Len(t, active, 1)
+	assert.Equal(t, "210", active[0].GameCode)
+	assert.Equal(t, models.League(models.LeagueIdPWHL), active[0].LeagueId)
+	assert.Equal(t, "Toronto Sceptres", active[0].CurrentState.Home.Team.TeamName)
+	assert.Equal(t, models.LeagueIdPWHL, active[0].CurrentState.Home.Team.LeagueID)
+	assert.Equal(t, 1, active[0].CurrentState.Home.Score)
+	assert.Equal(t, 2, active[0].CurrentState.Period)
+	assert.Equal(t, "08:21", active[0].CurrentState.Clock)
+	assert.Equal(t, "Coca-Cola Coliseum", active[0].CurrentState.Venue.Name)
+
+	upcomingChan := make(chan []models.Game)
+	go service.GetUpcomingGames(upcomingChan)
+	games := <-upcomingChan
+	assert.Len(t, games, 1)
+	assert.Equal(t, "211", games[0].GameCode)
+	assert.Equal(t, "7:00 pm EST", games[0].CurrentState.Clock)
+}
+
+func TestGetGamesByDate(t *testing.T) {
+	other := scorebarGame("1", "0", "0")
+	other.Date = "2026-01-11"
+	service := HockeyTechService{
+		Client: hockeytech.MockHockeyTechApiClient{ScorebarResponse: scorebar(scorebarGame("4", "2", "1"), other)},
+		League: models.LeagueIdAHL,
+	}
+	gamesChan := make(chan []models.Game)
+	go service.GetGamesByDate("2026-01-10", gamesChan)
+	games := <-gamesChan
+	assert.Len(t, games, 1)
+	assert.Equal(t, "210", games[0].GameCode)
+
+	go service.GetGamesByDate("not-a-date", gamesChan)
+	assert.Empty(t, <-gamesChan)
+}
+
+func TestGameStatusFromScorebar(t *testing.T) {
+	tests := []struct {
+		status string
+		text   string
+		want   models.GameStatus
+	}{
+		{"1", "7:00 pm EST", models.StatusUpcoming},
+		{"2", "2nd", models.StatusActive},
+		{"3", "Unofficial Final", models.StatusEnded},
+		{"4", "Final OT", models.StatusEnded},
+		{"1", "Postponed", models.StatusDelayed},
+	}
+	for _, tt := range tests {
+		game := hockeytech.HockeyTechScorebarGame{GameStatus: tt.status, GameStatusString: tt.text}
+		assert.Equal(t, tt.want, gameStatusFromScorebar(game), tt.text)
+	}
+}
+
+func TestPeriodTypeFromName(t *testing.T) {
+	assert.Equal(t, models.PeriodTypeRegular, periodTypeFromName("3rd"))
+	assert.Equal(t, models.PeriodTypeOvertime, periodTypeFromName("OT"))
+	assert.Equal(t, models.PeriodTypeOvertime, periodTypeFromName("2OT"))
+	assert.Equal(t, models.PeriodTypeShootout, periodTypeFromName("SO"))
+}
+
+func TestGetEventsGoalDetails(t *testing.T) {
+	goal := hockeytech.HockeyTechGoal{
+		ID:         "1001",
+		TeamID:     "3",
+		PeriodID:   "2",
+		Time:       "11:39",
+		PowerPlay:  "1",
+		GoalScorer: hockeytech.HockeyTechPlayer{PlayerID: "29", JerseyNumber: "29", FirstName: "Marie-Philip", LastName: "Poulin"},
+		Assist1:    hockeytech.HockeyTechPlayer{PlayerID: "17", FirstName: "Laura", LastName: "Stacey"},
+	}
+	service := HockeyTechService{
+		Client: hockeytech.MockHockeyTechApiClient{
+			ScorebarResponse:    scorebar(scorebarGame("2", "1", "1")),
+			GameSummaryResponse: summary(goal),
+		},
+		League: models.LeagueIdPWHL,
+	}
+	game := service.gameFromScorebar(scorebarGame("2", "1", "0"))
+
+	updateChan := make(chan models.GameUpdate)
+	go service.GetGameUpdate(game, updateChan)
+	update := <-updateChan
+	eventChan := make(chan []models.Event)
+	go service.GetEvents(update, eventChan)
+	events := <-eventChan
+
+	assert.Len(t, events, 1)
+	assert.Equal(t, "MTL", events[0].TeamCode)
+	assert.Equal(t, "TOR", events[0].OpponentCode)
+	assert.Equal(t, "PWHL", events[0].LeagueName)
+	assert.Equal(t, models.LeagueIdPWHL, events[0].LeagueId)
+	assert.Equal(t, "Marie-Philip Poulin", events[0].PlayerName)
+	assert.Equal(t, "Laura Stacey", events[0].Details.Assist1.Name)
+	assert.Equal(t, models.GoalTypePowerPlay, events[0].Details.GoalType)
+	assert.Equal(t, "08:21", events[0].Clock)
+}
+
+func TestGameUpdateKeepsStateWhenMissingFromScorebar(t *testing.T) {
+	service := HockeyTechService{
+		Client: hockeytech.MockHockeyTechApiClient{},
+		League: models.LeagueIdAHL,
+	}
+	game := service.gameFromScorebar(scorebarGame("2", "1", "0"))
+	updateChan := make(chan models.GameUpdate)
+	go service.GetGameUpdate(game, updateChan)
+	update := <-updateChan
+	assert.Equal(t, game.CurrentState.Home.Score, update.NewState.Home.Score)
+	assert.Equal(t, game.CurrentState.Status, update.NewState.Status)
+	assert.Nil(t, update.NewState.Shootout)
+}
+
+func TestShootoutFromSummary(t *testing.T) {
+	ended := scorebarGame("4", "3", "2")
+	ended.PeriodNameShort = "SO"
+	ended.Period = "5"
+	response := summary(hockeytech.HockeyTechGoal{ID: "1", TeamID: "6", PeriodID: "5", Time: "0:00"})
+	response.SiteKit.Gamesummary.Meta.Shootout = "1"
+	response.SiteKit.Gamesummary.ShootoutDetails = hockeytech.HockeyTechShootoutDetails{
+		VisitingShots: []hockeytech.HockeyTechShootoutShot{{IsGoal: "0"}, {IsGoal: "0"}},
+		HomeShots:     []hockeytech.HockeyTechShootoutShot{{IsGoal: "1", Shooter: hockeytech.HockeyTechPlayer{FirstName: "Natalie", LastName: "Spooner"}}, {IsGoal: "0"}},
+		WinningTeamID: "6",
+	}
+	service := HockeyTechService{
+		Client: hockeytech.MockHockeyTechApiClient{ScorebarResponse: scorebar(ended), GameSummaryResponse: response},
+		League: models.LeagueIdPWHL,
+	}
+	game := service.gameFromScorebar(scorebarGame("2", "2", "2"))
+	updateChan := make(chan models.GameUpdate)
+	go service.GetGameUpdate(game, updateChan)
+	update := <-updateChan
+
+	shootout := update.NewState.Shootout
+	assert.NotNil(t, shootout)
+	assert.Len(t, shootout.Attempts, 4)
+	assert.Equal(t, "MTL", shootout.Attempts[0].TeamCode)
+	assert.Equal(t, "TOR", shootout.Attempts[1].TeamCode)
+	assert.Equal(t, "Natalie Spooner", shootout.Attempts[1].Player.Name)
+	assert.Equal(t, 1, shootout.HomeGoals)
+	assert.Equal(t, "TOR", shootout.Winner)
+	assert.Empty(t, update.Events)
+}
@@ -1,32 +1,59 @@
 package iihf
 
 import (
+	"fmt"
 	"goalfeed/clients/leagues/iihf"
+	"goalfeed/config"
 	"goalfeed/models"
+	"goalfeed/services/leagues/hockey"
+	"goalfeed/utils"
+	"strconv"
+	"strings"
+	"sync"
 	"time"
 )
 
+var logger = utils.GetLogger()
+
+// IIHFService follows one IIHF event. With no Tournament set it follows the
+// default event as the IIHF league.
 type IIHFService struct {
-	Client iihf.IIIHFApiClient
+	Client     iihf.IIIHFApiClient
+	Tournament config.Tournament
 }
 
 const STATUS_UPCOMING = "UPCOMING"
 const STATUS_ACTIVE = "LIVE"
 const STATUS_FINAL = "FINAL"
 
-// const IIHF_LEAGUE_ID = 4
+// defaultEventId is the event followed when no tournament is configured
+const defaultEventId = "503"
 
 func (s IIHFService) getSchedule() iihf.IIHFScheduleResponse {
 
 	//todo implement caching
-	//todo support multiple active events
 	//todo support some method of determining active events programmatically
-	return s.Client.GetIIHFSchedule("503")
+	eventId := s.Tournament.EventID
+	if eventId == "" {
+		eventId = defaultEventId
+	}
+	return s.Client.GetIIHFSchedule(eventId)
 }
 func (s IIHFService) GetLeagueName() string {
+	if s.Tournament.Name != "" {
+		return s.Tournament.Name
+	}
 	return "IIHF"
 }
 
+// leagueId is the tournament's league id, or the IIHF league's.
+func (s IIHFService) leagueId() models.League {
+	if s.Tournament.LeagueID != 0 {
+		return s.Tournament.LeagueID
+	}
+	return models.LeagueIdIIHF
+}
+
 func (s IIHFService) GetGamesByDate(date string, ret chan []models.Game) {
 	// IIHF API doesn't support date-based queries yet
 	// Return empty for now
@@ -38,9 +65,8 @@ func (s IIHFService) GetActiveGames(ret chan []models.Game) {
 	schedule := s.getSchedule()
 	var activeGames []models.Game
 	for _, game := range schedule {
-		gameFromSchedule(game)
 		if gameStatusFromScheduleGame(game) == models.StatusActive {
-			activeGames = append(activeGames, gameFromSchedule(game))
+			activeGames = append(activeGames, s.gameFromSchedule(game))
 		}
 	}
 	ret <- activeGames
@@ -52,7 +78,7 @@ func (s IIHFService) GetUpcomingGames(ret chan []models.Game) {
 	var upcomingGames []models.Game
 	for _, game := range schedule {
 		if gameStatusFromScheduleGame(game) == models.StatusUpcoming {
-			upcomingGames = append(upcomingGames, gameFromSchedule(game))
+			upcomingGames = append(upcomingGames, s.gameFromSchedule(game))
 		}
 	}
 	ret <- upcomingGames
@@ -61,24 +87,264 @@ func (s IIHFService) GetUpcomingGames(ret chan []models.Game) {
 // GetActiveGames Returns a GameUpdate
 func (s IIHFService) GetGameUpdate(game models.Game, ret chan models.GameUpdate) {
 	scoreboard := s.Client.GetIIHFScoreBoard(game.GameCode)
+	status := gameStatusFromScoreboard(scoreboard, game.CurrentState.Status)
+	clock := clockFromScoreboard(scoreboard, status)
 	newState := models.GameState{
 		Home: models.TeamState{
 			Team:  game.CurrentState.Home.Team,
-			Score: scoreboard.CurrentScore.Home,
+			Score: int(scoreboard.CurrentScore.Home),
 		},
 		Away: models.TeamState{
 			Team:  game.CurrentState.Away.Team,
-			Score: scoreboard.CurrentScore.Away,
+			Score: int(scoreboard.CurrentScore.Away),
 		},
-		Status: game.CurrentState.Status, //TODO: Update using scoreboard status
+		Status:        status,
+		FetchedAt:     time.Now(),
+		Period:        periodFromScoreboard(scoreboard),
+		PeriodType:    periodTypeFromScoreboard(scoreboard),
+		Clock:         clock,
+		TimeRemaining: clock, // Set both for frontend compatibility
 	}
+	newState.Shootout = shootoutFromScoreboard(scoreboard, newState.Home.Team, newState.Away.Team, newState.PeriodType)
 	ret <- models.GameUpdate{
 		OldState: game.CurrentState,
 		NewState: newState,
+		Events:   scoringPlaysFromScoreboard(scoreboard, newState.Home.Team, newState.Away.Team),
+	}
+}
+
+// gameStatusFromScoreboard maps the game state's status text onto a game
+// status. An empty status, as on a failed fetch, keeps the old one, and so
+// does one the feed hasn't been seen to use, which is logged once.
+func gameStatusFromScoreboard(scoreboard iihf.IIHFGameScoreResponse, old models.GameStatus) models.GameStatus {
+	status := strings.ToUpper(strings.TrimSpace(scoreboard.Status))
+	switch {
+	case scoreboard.IsGameCompleted, strings.HasPrefix(status, STATUS_FINAL), strings.Contains(status, "COMPLETED"):
+		return models.StatusEnded
+	case status == "":
+		return old
+	case status == STATUS_UPCOMING, status == "SCHEDULED", strings.HasPrefix(status, "PRE"):
+		return models.StatusUpcoming
+	case status == STATUS_ACTIVE, strings.HasPrefix(status, "PERIOD"), strings.HasPrefix(status, "END OF"),
+		status == "INTERMISSION", status == "OVERTIME", status == "GAME WINNING SHOTS", status == "SHOOTOUT":
+		return models.StatusActive
+	}
+	if _, logged := unknownStatuses.LoadOrStore(status, true); !logged {
+		logger.Warn(fmt.Sprintf("IIHF: unknown game status %q, keeping the game's last status", scoreboard.Status))
+	}
+	return old
+}
+
+// unknownStatuses are the statuses gameStatusFromScoreboard has logged.
+var unknownStatuses sync.Map
+
+// periodFromScoreboard numbers the latest period the game state lists, so
+// overtime is period 4 and the game winning shots period 5, as on the NHL.
+func periodFromScoreboard(scoreboard iihf.IIHFGameScoreResponse) int {
+	period := 0
+	for _, p := range scoreboard.Periods {
+		if p.PeriodCode == "" || p.PeriodCode == "TOT" {
+			continue
+		}
+		period++
+	}
+	return period
+}
+
+// clockFromScoreboard returns the time left in the period as "MM:SS". It is
+// empty once the game is over or when the feed has no clock.
+func clockFromScoreboard(scoreboard iihf.IIHFGameScoreResponse, status models.GameStatus) string {
+	if status != models.StatusActive {
+		return ""
+	}
+	elapsed, ok := secondsFromGameTime(scoreboard.GameTime.PlayTime)
+	if !ok {
+		return ""
+	}
+	length, err := strconv.Atoi(scoreboard.GameTime.TimeMaxvalue)
+	if err != nil || length <= 0 {
+		length = 20
+	}
+	return clockFromElapsed(elapsed, length)
+}
+
+// clockFromElapsed formats the time left in a period of length minutes.
+func clockFromElapsed(elapsed int, length int) string {
+	remaining := max(length*60-elapsed, 0)
+	return fmt.Sprintf("%02d:%02d", remaining/60, remaining%60)
+}
+
+// secondsFromGameTime reads a period time given as "MM:SS" or whole minutes.
+func secondsFromGameTime(value string) (int, bool) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return 0, false
+	}
+	minutes, seconds, found := strings.Cut(value, ":")
+	m, err := strconv.Atoi(minutes)
+	if err != nil {
+		return 0, false
+	}
+	sec := 0
+	if found {
+		if sec, err = strconv.Atoi(seconds); err != nil {
+			return 0, false
+		}
+	}
+	return m*60 + sec, true
+}
+
+// scoringPlaysFromScoreboard lists the game's goals in order, leaving out
+// game winning shots attempts.
+func scoringPlaysFromScoreboard(scoreboard iihf.IIHFGameScoreResponse, home, away models.Team) []models.GameEvent {
+	var plays []models.GameEvent
+	number := 0
+	for _, period := range scoreboard.Periods {
+		if period.PeriodCode == "" || period.PeriodCode == "TOT" {
+			continue
+		}
+		number++
+		periodType := hockey.PeriodTypeFromCode(period.PeriodCode)
+		if periodType == models.PeriodTypeShootout {
+			continue
+		}
+		for _, action := range period.Actions {
+			if !isGoalAction(action) {
+				continue
+			}
+			team := away
+			if action.IsExecutedByHomeTeam || (action.ExecutedByShortTeamName != "" && action.ExecutedByShortTeamName == home.TeamCode) {
+				team = home
+			}
+			// Overtime length varies by tournament, so only regulation
+			// goals get a clock; the rest use the game's
+			clock := ""
+			if elapsed, ok := secondsFromGameTime(action.TimeOfPlay); ok && periodType == models.PeriodTypeRegular {
+				clock = clockFromElapsed(elapsed, 20)
+			}
+			plays = append(plays, models.GameEvent{
+				Id:     action.ID,
+				Type:   models.EventTypeGoal,
+				Period: number,
+				Time:   action.TimeOfPlay,
+				Clock:  clock,
+				Team:   team,
+				Player: playerFromAthlete(action.Athlete),
+				Details: models.EventDetails{
+					Assist1:  playerFromAthlete(action.Assistant1),
+					Assist2:  playerFromAthlete(action.Assistant2),
+					GoalType: goalTypeFromSituation(action.SituationType),
+				},
+			})
+		}
+	}
+	return plays
+}
+
+func isGoalAction(action iihf.IIHFAction) bool {
+	return strings.HasSuffix(action.FullTypeName, ".GoalActionModel") || action.Code == "GOAL"
+}
+
+func playerFromAthlete(athlete iihf.IIHFAthlete) models.Player {
+	if athlete.ReportingName == "" {
+		return models.Player{}
+	}
+	number, _ := strconv.Atoi(athlete.Number)
+	return models.Player{
+		Id:       athlete.AthleteID,
+		Name:     athlete.ReportingName,
+		Number:   number,
+		Position: athlete.Position,
+	}
+}
+
+// goalTypeFromSituation maps the feed's strength codes ("EQ", "PP1", "PP2",
+// "SH1", "SH2", "EN") onto the models.GoalType* values. A code can carry
+// more than one, "PP1 EN" or "PP1+EN", of which the empty net wins.
+func goalTypeFromSituation(situation string) string {
+	codes := strings.FieldsFunc(strings.ToUpper(situation), func(r rune) bool {
+		return r == ' ' || r == '+' || r == ',' || r == '/'
+	})
+	goalType := ""
+	for _, code := range codes {
+		switch code {
+		case "EN", "ENG":
+			return models.GoalTypeEmptyNet
+		case "PP1", "PP2":
+			goalType = models.GoalTypePowerPlay
+		case "SH1", "SH2":
+			goalType = models.GoalTypeShortHanded
+		default:
+			if goalType == "" {
+				goalType = models.GoalTypeEvenStrength
+			}
+		}
+	}
+	return goalType
+}
+
+// periodTypeFromScoreboard reports the type of the latest period the game
+// state lists, skipping the "TOT" totals entry.
+func periodTypeFromScoreboard(scoreboard iihf.IIHFGameScoreResponse) string {
+	periodType := ""
+	for _, period := range scoreboard.Periods {
+		if period.PeriodCode == "" || period.PeriodCode == "TOT" {
+			continue
+		}
+		periodType = hockey.PeriodTypeFromCode(period.PeriodCode)
+	}
+	return periodType
+}
+
+// shootoutFromScoreboard collects the attempts listed under the game winning
+// shots period. It returns nil until the game reaches a shootout.
+func shootoutFromScoreboard(scoreboard iihf.IIHFGameScoreResponse, home, away models.Team, periodType string) *models.ShootoutState {
+	if periodType != models.PeriodTypeShootout {
+		return nil
+	}
+	var attempts []models.ShootoutAttempt
+	for _, period := range scoreboard.Periods {
+		if hockey.PeriodTypeFromCode(period.PeriodCode) != models.PeriodTypeShootout {
+			continue
+		}
+		for _, action := range period.Actions {
+			if action.Athlete.ReportingName == "" {
+				continue
+			}
+			teamCode := action.ExecutedByShortTeamName
+			if teamCode == "" {
+				teamCode = away.TeamCode
+				if action.IsExecutedByHomeTeam {
+					teamCode = home.TeamCode
+				}
+			}
+			number, _ := strconv.Atoi(action.Athlete.Number)
+			attempts = append(attempts, models.ShootoutAttempt{
+				Sequence: len(attempts) + 1,
+				TeamCode: teamCode,
+				Player: models.Player{
+					Id:     action.Athlete.AthleteID,
+					Name:   action.Athlete.ReportingName,
+					Number: number,
+				},
+				Result: shootoutResultFromAction(action),
+			})
+		}
+	}
+	return hockey.ShootoutFromAttempts(attempts, home, away, "", scoreboard.IsGameCompleted)
+}
+
+// shootoutResultFromAction classifies a game winning shots action. The feed
+// only distinguishes scored and unscored attempts.
+func shootoutResultFromAction(action iihf.IIHFAction) string {
+	title := strings.ToLower(action.Displays.Default.Title)
+	if strings.Contains(action.FullTypeName, "Goal") || (strings.Contains(title, "goal") && !strings.Contains(title, "no goal")) {
+		return "goal"
 	}
+	return "miss"
 }
 
-func teamFromScheduleTeam(scheduleTeam iihf.IIHFScheduleTeam) models.Team {
+func (s IIHFService) teamFromScheduleTeam(scheduleTeam iihf.IIHFScheduleTeam) models.Team {
 
 	// todo store/retrieve from DB
 	// todo fill out model
@@ -90,23 +356,24 @@ func teamFromScheduleTeam(scheduleTeam iihf.IIHFScheduleTeam) models.Team {
 		TeamName: scheduleTeam.TeamCode,
 		TeamCode: scheduleTeam.TeamCode,
 		ExtID:    scheduleTeam.TeamCode,
-		LeagueID: models.LeagueIdIIHF,
+		LeagueID: int(s.leagueId()),
 		LogoURL:  "", // IIHF doesn't provide logo URLs
 	}
 	return team
 
 }
-func gameFromSchedule(scheduleGame iihf.IIHFScheduleResponseGame) models.Game {
+func (s IIHFService) gameFromSchedule(scheduleGame iihf.IIHFScheduleResponseGame) models.Game {
 
 	return models.Game{
 		CurrentState: models.GameState{
-			Home:      models.TeamState{Team: teamFromScheduleTeam(scheduleGame.HomeTeam), Score: scheduleGame.HomeTeam.Points},
-			Away:      models.TeamState{Team: teamFromScheduleTeam(scheduleGame.GuestTeam), Score: scheduleGame.GuestTeam.Points},
+			Home:      models.TeamState{Team: s.teamFromScheduleTeam(scheduleGame.HomeTeam), Score: int(scheduleGame.HomeTeam.Points)},
+			Away:      models.TeamState{Team: s.teamFromScheduleTeam(scheduleGame.GuestTeam), Score: int(scheduleGame.GuestTeam.Points)},
 			Status:    gameStatusFromScheduleGame(scheduleGame),
 			FetchedAt: time.Now(),
 		},
-		GameCode: scheduleGame.GameID,
-		LeagueId: models.LeagueIdIIHF,
+		GameCode:   scheduleGame.GameID,
+		LeagueId:   s.leagueId(),
+		LeagueName: s.GetLeagueName(),
 	}
 }
 func gameStatusFromScheduleGame(scheduleGame iihf.IIHFScheduleResponseGame) models.GameStatus {
@@ -123,31 +390,5 @@ func gameStatusFromScheduleGame(scheduleGame iihf.IIHFScheduleResponseGame) mode
 	}
 }
 func (s IIHFService) GetEvents(update models.GameUpdate, ret chan []models.Event) {
-	events := append(
-		s.getGoalEvents(update.OldState.Home, update.NewState.Home, update.OldState.Away.Team),
-		s.getGoalEvents(update.OldState.Away, update.NewState.Away, update.OldState.Home.Team)...,
-	)
-	ret <- events
-}
-func (s IIHFService) getGoalEvents(oldState models.TeamState, newState models.TeamState, opponent models.Team) []models.Event {
-	events := []models.Event{}
-	diff := newState.Score - oldState.Score
-	if diff <= 0 {
-		return events
-	}
-	team := newState.Team
-
-	for i := 0; i < diff; i++ {
-		events = append(events, models.Event{
-			TeamCode:     team.TeamCode,
-			TeamName:     team.TeamName,
-			TeamHash:     team.GetTeamHash(),
-			LeagueId:     models.LeagueIdIIHF,
-			LeagueName:   s.GetLeagueName(),
-			OpponentCode: opponent.TeamCode,
-			OpponentName: opponent.TeamName,
-			OpponentHash: opponent.GetTeamHash(),
-		})
-	}
-	return events
+	ret <- hockey.GetEvents(update, int(s.leagueId()), s.GetLeagueName())
 }
@@ -1,7 +1,9 @@
 package iihf
 
 import (
+	"encoding/json"
 	iihf "goalfeed/clients/leagues/iihf"
+	"goalfeed/config"
 	"goalfeed/models"
 	"goalfeed/services/leagues"
 	"testing"
@@ -113,3 +115,182 @@ func TestGameStatusFromScheduleGame(t *testing.T) {
 	}
 	assert.Equal(t, models.GameStatus(models.StatusActive), gameStatusFromScheduleGame(unknownGame))
 }
+
+func TestShootoutFromScoreboard(t *testing.T) {
+	home := models.Team{TeamCode: "CAN"}
+	away := models.Team{TeamCode: "FIN"}
+	var scoreboard iihf.IIHFGameScoreResponse
+	scoreboard.IsGameCompleted = true
+	scoreboard.Periods = []iihf.IIHFPeriod{
+		{PeriodCode: "3"},
+		{PeriodCode: "OT"},
+		{PeriodCode: "GWS", Actions: []iihf.IIHFAction{
+			{FullTypeName: "ShootoutMissActionModel", ExecutedByShortTeamName: "FIN", Athlete: iihf.IIHFAthlete{ReportingName: "AHO Sebastian"}},
+			{FullTypeName: "ShootoutGoalActionModel", IsExecutedByHomeTeam: true, Athlete: iihf.IIHFAthlete{ReportingName: "McDAVID Connor", Number: "97"}},
+			{FullTypeName: "PeriodEndActionModel"},
+		}},
+		{PeriodCode: "TOT"},
+	}
+
+	periodType := periodTypeFromScoreboard(scoreboard)
+	assert.Equal(t, models.PeriodTypeShootout, periodType)
+
+	shootout := shootoutFromScoreboard(scoreboard, home, away, periodType)
+	if assert.NotNil(t, shootout) {
+		assert.Len(t, shootout.Attempts, 2)
+		assert.Equal(t, "FIN", shootout.Attempts[0].TeamCode)
+		assert.Equal(t, "miss", shootout.Attempts[0].Result)
+		assert.Equal(t, "CAN", shootout.Attempts[1].TeamCode)
+		assert.Equal(t, 97, shootout.Attempts[1].Player.Number)
+		assert.Equal(t, "CAN", shootout.Winner)
+	}
+	assert.Nil(t, shootoutFromScoreboard(scoreboard, home, away, models.PeriodTypeOvertime))
+}
+
+type fixtureIIHFClient struct {
+	iihf.MockIIHFApiClient
+	scoreboard string
+}
+
+func (c fixtureIIHFClient) GetIIHFScoreBoard(sGameId string) iihf.IIHFGameScoreResponse {
+	var response iihf.IIHFGameScoreResponse
+	if err := json.Unmarshal([]byte(c.scoreboard), &response); err != nil {
+		panic(err)
+	}
+	return response
+}
+
+const secondPeriodScoreboard = `{
+	"GameId": "9193",
+	"Status": "Period 2",
+	"GameTime": {"TimedGameStatus": "Period 2", "PlayTime": "12:30", "Time": "20", "TimeMaxvalue": "20"},
+	"IsGameCompleted": false,
+	"CurrentScore": {"Home": "1", "Away": "0"},
+	"Periods": [
+		{"PeriodCode": "1", "Score": {"Home": "0", "Away": "0"}, "Actions": []},
+		{"PeriodCode": "2", "Score": {"Home": "1", "Away": "0"}, "Actions": [
+			{"Id": "1223301", "FullTypeName": "DD.IIHF.Core.Models.DTO.Gamecenter.PlayByPlay.ActionModels.GoalActionModel", "TimeOfPlay": "08:15",
+				"IsExecutedByHomeTeam": true, "ExecutedByShortTeamName": "USA", "SituationType": "PP1",
+				"Athlete": {"IH_Athlete_Id": "50001", "Number": "16", "Position": "F", "ReportingName": "HUGHES Jack"},
+				"Assistant1": {"IH_Athlete_Id": "50002", "Number": "43", "ReportingName": "HUGHES Quinn"},
+				"Assistant2": {}}
+		]},
+		{"PeriodCode": "TOT", "Score": {"Home": "1", "Away": "0"}, "Actions": []}
+	]
+}`
+
+func TestGetGameUpdate_StatusPeriodClockAndGoals(t *testing.T) {
+	service := IIHFService{Client: fixtureIIHFClient{scoreboard: secondPeriodScoreboard}}
+	game := getActiveGame(service)
+
+	updates := make(chan models.GameUpdate)
+	go service.GetGameUpdate(game, updates)
+	update := <-updates
+
+	assert.Equal(t, models.GameStatus(models.StatusActive), update.NewState.Status)
+	assert.Equal(t, 2, update.NewState.Period)
+	assert.Equal(t, models.PeriodTypeRegular, update.NewState.PeriodType)
+	assert.Equal(t, "07:30", update.NewState.Clock)
+	assert.Equal(t, 1, update.NewState.Home.Score)
+
+	events := make(chan []models.Event)
+	go service.GetEvents(update, events)
+	goals := <-events
+	if assert.Len(t, goals, 1) {
+		assert.Equal(t, "USA", goals[0].TeamCode)
+		assert.Equal(t, "HUGHES Jack", goals[0].PlayerName)
+		assert.Equal(t, 16, goals[0].PlayerNumber)
+		assert.Equal(t, "HUGHES Quinn", goals[0].Details.Assist1.Name)
+		assert.Equal(t, models.GoalTypePowerPlay, goals[0].Details.GoalType)
+		assert.Equal(t, 2, goals[0].Period)
+		assert.Equal(t, "11:45", goals[0].Clock)
+	}
+}
+
+func TestGetGameUpdate_FinalEndsGame(t *testing.T) {
+	final := `{"Status": "Final (OT)", "IsGameCompleted": true, "GameTime": {"PlayTime": "3"},
+		"CurrentScore": {"Home": 3, "Away": 2},
+		"Periods": [{"PeriodCode": "1"}, {"PeriodCode": "2"}, {"PeriodCode": "3"}, {"PeriodCode": "OT"}, {"PeriodCode": "TOT"}]}`
+	service := IIHFService{Client: fixtureIIHFClient{scoreboard: final}}
+	game := getActiveGame(service)
+
+	updates := make(chan models.GameUpdate)
+	go service.GetGameUpdate(game, updates)
+	update := <-updates
+
+	assert.Equal(t, models.GameStatus(models.StatusEnded), update.NewState.Status)
+	assert.Equal(t, 4, update.NewState.Period)
+	assert.Equal(t, models.PeriodTypeOvertime, update.NewState.PeriodType)
+	assert.Empty(t, update.NewState.Clock)
+	assert.Equal(t, 3, update.NewState.Home.Score)
+}
+
+func TestGameStatusFromScoreboard(t *testing.T) {
+	cases := []struct {
+		status    string
+		completed bool
+		old       models.GameStatus
+		expected  models.GameStatus
+	}{
+		{"Period 1", false, models.StatusUpcoming, models.StatusActive},
+		{"Intermission", false, models.StatusActive, models.StatusActive},
+		{"Game Winning Shots", false, models.StatusActive, models.StatusActive},
+		{"Final", false, models.StatusActive, models.StatusEnded},
+		{"Period 3", true, models.StatusActive, models.StatusEnded},
+		{"UPCOMING", false, models.StatusUpcoming, models.StatusUpcoming},
+		{"", false, models.StatusActive, models.StatusActive},
+		{"Suspended", false, models.StatusUpcoming, models.StatusUpcoming},
+		{"Suspended", false, models.StatusActive, models.StatusActive},
+	}
+	for _, c := range cases {
+		scoreboard := iihf.IIHFGameScoreResponse{Status: c.status, IsGameCompleted: c.completed}
+		assert.Equal(t, c.expected, gameStatusFromScoreboard(scoreboard, c.old), "status %q", c.status)
+	}
+}
+
+type eventRecordingClient struct {
+	iihf.MockIIHFApiClient
+	eventIds *[]string
+}
+
+func (c eventRecordingClient) GetIIHFSchedule(sEventId string) iihf.IIHFScheduleResponse {
+	*c.eventIds = append(*c.eventIds, sEventId)
+	return c.MockIIHFApiClient.GetIIHFSchedule(sEventId)
+}
+
+func TestIIHFService_FollowsTournament(t *testing.T) {
+	var eventIds []string
+	tournament := config.Tournament{Key: "worlds", Name: "IIHF World Championship", EventID: "520", LeagueID: 100}
+	service := IIHFService{Client: eventRecordingClient{eventIds: &eventIds}, Tournament: tournament}
+
+	game := getActiveGame(service)
+	assert.Equal(t, []string{"520"}, eventIds)
+	assert.Equal(t, models.League(100), game.LeagueId)
+	assert.Equal(t, "IIHF World Championship", game.LeagueName)
+	assert.Equal(t, 100, game.CurrentState.Home.Team.LeagueID)
+	assert.Equal(t, "IIHF World Championship", service.GetLeagueName())
+
+	iihf.MockIIHFApiClient{}.SetHomeScore(1)
+	iihf.MockIIHFApiClient{}.SetAwayScore(0)
+	t.Cleanup(func() { iihf.MockIIHFApiClient{}.SetHomeScore(0) })
+	updates := make(chan models.GameUpdate)
+	go service.GetGameUpdate(game, updates)
+	events := make(chan []models.Event)
+	go service.GetEvents(<-updates, events)
+	goals := <-events
+	if assert.Len(t, goals, 1) {
+		assert.Equal(t, 100, goals[0].LeagueId)
+		assert.Equal(t, "IIHF World Championship", goals[0].LeagueName)
+	}
+}
+
+func TestGoalTypeFromSituation(t *testing.T) {
+	assert.Equal(t, models.GoalTypeEvenStrength, goalTypeFromSituation("EQ"))
+	assert.Equal(t, models.GoalTypePowerPlay, goalTypeFromSituation("pp2"))
+	assert.Equal(t, models.GoalTypeShortHanded, goalTypeFromSituation("SH1"))
+	assert.Equal(t, models.GoalTypeEmptyNet, goalTypeFromSituation("EN"))
+	assert.Equal(t, models.GoalTypeEmptyNet, goalTypeFromSituation("PP1 EN"))
+	assert.Equal(t, models.GoalTypeEmptyNet, goalTypeFromSituation("SH1+EN"))
+	assert.Equal(t, models.GoalTypeEvenStrength, goalTypeFromSituation("PEN"), "a penalty shot isn't an empty net")
+	assert.Empty(t, goalTypeFromSituation(""))
+}
@@ -0,0 +1,134 @@
+package mlb
+
+import (
+	"encoding/json"
+	"goalfeed/clients/leagues/mlb"
+	"goalfeed/utils"
+	"sync"
+)
+
+// liveFeed is a game's full live feed document, kept current by applying
+// diff patches to it. scoreboard is the typed view of doc.
+type liveFeed struct {
+	doc        interface{}
+	timestamp  string
+	scoreboard mlb.MLBScoreboardResponse
+}
+
+// liveFeeds caches the live feed of each game being watched. Polls of the
+// same game can overlap, so GetGameUpdate holds that game's lock while it
+// reads and patches the cached copy.
+var liveFeeds = struct {
+	sync.Mutex
+	feeds map[string]liveFeed
+	locks map[string]*liveFeedLock
+}{
+	feeds: map[string]liveFeed{},
+	locks: map[string]*liveFeedLock{},
+}
+
+// liveFeedLock is a game's lock, with how many polls hold or wait for it so
+// the last one out can drop it.
+type liveFeedLock struct {
+	sync.Mutex
+	users int
+}
+
+func newLiveFeed(raw []byte) (liveFeed, error) {
+	doc, err := utils.DecodeJSONDocument(raw)
+	if err != nil {
+		return liveFeed{}, err
+	}
+	var scoreboard mlb.MLBScoreboardResponse
+	if err := json.Unmarshal(raw, &scoreboard); err != nil {
+		return liveFeed{}, err
+	}
+	return liveFeed{doc: doc, timestamp: scoreboard.MetaData.TimeStamp, scoreboard: scoreboard}, nil
+}
+
+// apply patches the document with each diff set in order and refreshes the
+// typed view. An empty diff leaves the feed untouched.
+func (f *liveFeed) apply(diff mlb.MLBDiffPatch) error {
+	applied := 0
+	for _, set := range diff {
+		ops := make([]utils.JSONPatchOp, 0, len(set.Diff))
+		for _, item := range set.Diff {
+			ops = append(ops, utils.JSONPatchOp{Op: item.Op, Path: item.Path, From: item.From, Value: item.Value})
+		}
+		doc, err := utils.ApplyJSONPatch(f.doc, ops)
+		if err != nil {
+			return err
+		}
+		f.doc = doc
+		applied += len(ops)
+	}
+	if applied == 0 {
+		return nil
+	}
+
+	raw, err := json.Marshal(f.doc)
+	if err != nil {
+		return err
+	}
+	var scoreboard mlb.MLBScoreboardResponse
+	if err := json.Unmarshal(raw, &scoreboard); err != nil {
+		return err
+	}
+	f.scoreboard = scoreboard
+	f.timestamp = scoreboard.MetaData.TimeStamp
+	return nil
+}
+
+// lockLiveFeed takes a game's lock and returns its unlock.
+func lockLiveFeed(gameCode string) func() {
+	liveFeeds.Lock()
+	lock, ok := liveFeeds.locks[gameCode]
+	if !ok {
+		lock = &liveFeedLock{}
+		liveFeeds.locks[gameCode] = lock
+	}
+	lock.users++
+	liveFeeds.Unlock()
+	lock.Lock()
+	return func() {
+		lock.Unlock()
+		liveFeeds.Lock()
+		lock.users--
+		if lock.users == 0 {
+			delete(liveFeeds.locks, gameCode)
+		}
+		liveFeeds.Unlock()
+	}
+}
+
+func cachedLiveFeed(gameCode string) (liveFeed, bool) {
+	liveFeeds.Lock()
+	defer liveFeeds.Unlock()
+	feed, ok := liveFeeds.feeds[gameCode]
+	return feed, ok
+}
+
+func storeLiveFeed(gameCode string, feed liveFeed) {
+	liveFeeds.Lock()
+	defer liveFeeds.Unlock()
+	liveFeeds.feeds[gameCode] = feed
+}
+
+func forgetLiveFeed(gameCode string) {
+	liveFeeds.Lock()
+	defer liveFeeds.Unlock()
+	delete(liveFeeds.feeds, gameCode)
+}
+
+// forgetLiveFeedsExcept drops the cached feed of every game not in active,
+// so one that leaves the schedule without being seen to end (postponed,
+// or polled for the last time before its final) isn't kept forever.
+func forgetLiveFeedsExcept(active map[string]bool) {
+	liveFeeds.Lock()
+	defer liveFeeds.Unlock()
+	for gameCode := range liveFeeds.feeds {
+		if !active[gameCode] {
+			delete(liveFeeds.feeds, gameCode)
+		}
+	}
+}
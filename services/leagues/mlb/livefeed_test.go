@@ -0,0 +1,145 @@
+package mlb
+
+import (
+	"encoding/json"
+	"testing"
+
+	mlbc "goalfeed/clients/leagues/mlb"
+	"goalfeed/models"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// diffMockClient serves a live feed once and then a diff patch, recording
+// the timestamps diffs were requested from
+type diffMockClient struct {
+	playsMockClient
+	liveFeedReads *int
+	diffFrom      *[]string
+}
+
+func (c diffMockClient) GetMLBLiveFeed(sGameId string) (json.RawMessage, error) {
+	*c.liveFeedReads++
+	return c.playsMockClient.GetMLBLiveFeed(sGameId)
+}
+
+func (c diffMockClient) GetDiffPatch(gameId string, timestamp string) (mlbc.MLBDiffPatch, error) {
+	*c.diffFrom = append(*c.diffFrom, timestamp)
+	return c.diff, nil
+}
+
+func newDiffMockClient(diff string) diffMockClient {
+	client := diffMockClient{liveFeedReads: new(int), diffFrom: &[]string{}}
+	client.scoreboard.MetaData.TimeStamp = "20240401_180000"
+	client.scoreboard.GameData.Status.StatusCode = "I"
+	client.scoreboard.LiveData.Linescore.Teams.Home.Runs = 1
+	client.scoreboard.LiveData.Linescore.Currentinning = 3
+	client.scoreboard.LiveData.Plays.AllPlays = []mlbc.Play{
+		testPlay(0, "single", true, true),
+		testPlay(1, "", false, false),
+	}
+	_ = json.Unmarshal([]byte(diff), &client.diff)
+	return client
+}
+
+func TestGetGameUpdate_AppliesDiffToCachedLiveFeed(t *testing.T) {
+	nextPlay, _ := json.Marshal(testPlay(2, "", false, false))
+	client := newDiffMockClient(`[
+		{"diff": [
+			{"op": "replace", "path": "/metaData/timeStamp", "value": "20240401_180112"},
+			{"op": "replace", "path": "/liveData/plays/allPlays/1/result/eventType", "value": "strikeout"},
+			{"op": "replace", "path": "/liveData/plays/allPlays/1/about/isComplete", "value": true}
+		]},
+		{"diff": [
+			{"op": "add", "path": "/liveData/plays/allPlays/-", "value": ` + string(nextPlay) + `},
+			{"op": "replace", "path": "/liveData/linescore/teams/home/runs", "value": 2},
+			{"op": "replace", "path": "/liveData/linescore/outs", "value": 1}
+		]}
+	]`)
+	service := MLBService{Client: client}
+	game := playsTestGame()
+	game.GameCode = "diff-applies"
+	game.CurrentState.PlayCursor = 0
+	defer forgetLiveFeed(game.GameCode)
+
+	ret := make(chan models.GameUpdate, 1)
+	service.GetGameUpdate(game, ret)
+	first := <-ret
+	assert.Equal(t, 1, first.NewState.Home.Score)
+	assert.Equal(t, 1, first.NewState.PlayCursor)
+
+	game.CurrentState = first.NewState
+	service.GetGameUpdate(game, ret)
+	update := <-ret
+
+	assert.Equal(t, 1, *client.liveFeedReads, "the second poll should only fetch a diff")
+	assert.Equal(t, []string{"20240401_180000"}, *client.diffFrom)
+	assert.Equal(t, 2, update.NewState.Home.Score)
+	assert.Equal(t, 1, update.NewState.Details.Outs)
+	assert.Equal(t, "20240401_180112", update.NewState.ExtTimestamp)
+	assert.Equal(t, 2, update.NewState.PlayCursor)
+	if assert.Len(t, update.Events, 1) {
+		assert.Equal(t, models.EventTypeStrikeout, update.Events[0].Type)
+	}
+
+	feed, ok := cachedLiveFeed(game.GameCode)
+	assert.True(t, ok)
+	assert.Equal(t, "20240401_180112", feed.timestamp)
+	assert.Len(t, feed.scoreboard.LiveData.Plays.AllPlays, 3)
+}
+
+func TestGetGameUpdate_ReloadsLiveFeedWhenDiffDoesNotApply(t *testing.T) {
+	client := newDiffMockClient(`[{"diff": [
+		{"op": "replace", "path": "/liveData/plays/allPlays/9/result/eventType", "value": "walk"}
+	]}]`)
+	service := MLBService{Client: client}
+	game := playsTestGame()
+	game.GameCode = "diff-reloads"
+	defer forgetLiveFeed(game.GameCode)
+
+	ret := make(chan models.GameUpdate, 1)
+	service.GetGameUpdate(game, ret)
+	<-ret
+	service.GetGameUpdate(game, ret)
+	update := <-ret
+
+	assert.Equal(t, 2, *client.liveFeedReads)
+	assert.Equal(t, 1, update.NewState.Home.Score)
+}
+
+func TestGetGameUpdate_ForgetsLiveFeedWhenGameEnds(t *testing.T) {
+	client := newDiffMockClient(`[]`)
+	client.scoreboard.GameData.Status.StatusCode = "7"
+	game := playsTestGame()
+	game.GameCode = "diff-final"
+
+	ret := make(chan models.GameUpdate, 1)
+	MLBService{Client: client}.GetGameUpdate(game, ret)
+	update := <-ret
+	assert.Equal(t, models.GameStatus(models.StatusEnded), update.NewState.Status)
+	_, ok := cachedLiveFeed(game.GameCode)
+	assert.False(t, ok)
+}
+
+func TestLiveFeedLocksAndFeedsAreDropped(t *testing.T) {
+	client := newDiffMockClient(`[]`)
+	game := playsTestGame()
+	game.GameCode = "diff-dropped"
+	defer forgetLiveFeed(game.GameCode)
+
+	ret := make(chan models.GameUpdate, 1)
+	MLBService{Client: client}.GetGameUpdate(game, ret)
+	<-ret
+	liveFeeds.Lock()
+	_, locked := liveFeeds.locks[game.GameCode]
+	liveFeeds.Unlock()
+	assert.False(t, locked, "the lock goes once no poll holds it")
+
+	storeLiveFeed("diff-still-active", liveFeed{})
+	defer forgetLiveFeed("diff-still-active")
+	forgetLiveFeedsExcept(map[string]bool{"diff-still-active": true})
+	_, ok := cachedLiveFeed(game.GameCode)
+	assert.False(t, ok, "a game off the active list loses its feed")
+	_, ok = cachedLiveFeed("diff-still-active")
+	assert.True(t, ok)
+}
@@ -1,7 +1,6 @@
 package mlb
 
 import (
-	"encoding/json"
 	"fmt"
 	"goalfeed/clients/leagues/mlb"
 	"goalfeed/models"
@@ -41,6 +40,7 @@ func (s MLBService) GetLeagueName() string {
 func (s MLBService) GetActiveGames(ret chan []models.Game) {
 	schedule := s.getSchedule()
 	var activeGames []models.Game
+	active := map[string]bool{}
 
 	for _, date := range schedule.Dates {
 		for _, game := range date.Games {
@@ -48,10 +48,17 @@ func (s MLBService) GetActiveGames(ret chan []models.Game) {
 			_ = tmpGame
 			status := gameStatusFromScheduleGame(game)
 			if status == models.StatusActive || status == models.StatusDelayed {
-				activeGames = append(activeGames, s.gameFromSchedule(game))
+				activeGame := s.gameFromSchedule(game)
+				activeGames = append(activeGames, activeGame)
+				active[activeGame.GameCode] = true
 			}
 		}
 	}
+	// An empty schedule is as likely a failed fetch as a quiet day, so the
+	// cache is only swept against a real one
+	if len(schedule.Dates) > 0 {
+		forgetLiveFeedsExcept(active)
+	}
 	ret <- activeGames
 }
 
@@ -86,9 +93,10 @@ func (s MLBService) GetGamesByDate(date string, ret chan []models.Game) {
 
 // GetActiveGames Returns a GameUpdate
 func (s MLBService) GetGameUpdate(game models.Game, ret chan models.GameUpdate) {
-	if game.CurrentState.ExtTimestamp != "" {
+	unlock := lockLiveFeed(game.GameCode)
+	defer unlock()
+	if _, ok := cachedLiveFeed(game.GameCode); ok {
 		s.getGameUpdateFromDiffPatch(game, ret)
-		// s.getGameUpdateFromScoreboard(game, ret)
 	} else {
 		s.getGameUpdateFromScoreboard(game, ret)
 	}
@@ -104,87 +112,57 @@ func fudgeTimestamp(extTimestamp string) string {
 
 }
 
+// getGameUpdateFromDiffPatch brings the cached live feed up to date by
+// applying every diff since its timestamp. If the diff can't be fetched or
+// doesn't apply cleanly, the cached copy is dropped and the full feed is
+// read again.
 func (s MLBService) getGameUpdateFromDiffPatch(game models.Game, ret chan models.GameUpdate) {
-
-	diff, err := s.Client.GetDiffPatch(game.GameCode, fudgeTimestamp(game.CurrentState.ExtTimestamp))
-	if err != nil {
+	feed, ok := cachedLiveFeed(game.GameCode)
+	if !ok {
 		s.getGameUpdateFromScoreboard(game, ret)
 		return
 	}
-	timestampPath := "/metaData/timeStamp"
-	homeGoalPath := "/liveData/linescore/teams/home/runs"
-	awayGoalPath := "/liveData/linescore/teams/away/runs"
-	statusCodePath := "/gameData/status/statusCode"
-	var extTimestamp string
-	var homeScore int
-	var awayScore int
-	var statusCode string
-	var status models.GameStatus
-
-	for _, set := range diff {
-		for _, item := range set.Diff {
-			logger.Debug(fmt.Sprintf("Path: %s", item.Path))
-			if item.Path == timestampPath {
-				json.Unmarshal(item.Value, &extTimestamp)
-			} else if item.Path == homeGoalPath {
-				logger.Info(fmt.Sprintf("Home score change - %s", game.CurrentState.Home.Team.TeamName))
-				json.Unmarshal(item.Value, &homeScore)
-			} else if item.Path == awayGoalPath {
-				logger.Info(fmt.Sprintf("Away score change - %s", game.CurrentState.Away.Team.TeamName))
-				json.Unmarshal(item.Value, &awayScore)
-			} else if item.Path == statusCodePath {
-				logger.Info("Status Code")
-				json.Unmarshal(item.Value, &statusCode)
-			}
-		}
-	}
 
-	if homeScore == 0 {
-		homeScore = game.CurrentState.Home.Score
-	}
-	if awayScore == 0 {
-		awayScore = game.CurrentState.Away.Score
-	}
-	if extTimestamp == "" {
-		extTimestamp = game.CurrentState.ExtTimestamp
-	}
-	if statusCode == "" {
-		status = game.CurrentState.Status
-	} else {
-		status = gameStatusFromStatusCode(statusCode)
+	// When the timestamp is too old the endpoint sends the full feed rather
+	// than a diff list, which fails to decode here and takes the reload path
+	diff, err := s.Client.GetDiffPatch(game.GameCode, feed.timestamp)
+	if err != nil {
+		logger.Info(fmt.Sprintf("MLB diff patch unavailable for game %s, reloading live feed: %s", game.GameCode, err))
+		forgetLiveFeed(game.GameCode)
+		s.getGameUpdateFromScoreboard(game, ret)
+		return
 	}
-
-	newState := models.GameState{
-		Home: models.TeamState{
-			Team:  game.CurrentState.Home.Team,
-			Score: homeScore,
-		},
-		Away: models.TeamState{
-			Team:  game.CurrentState.Away.Team,
-			Score: awayScore,
-		},
-		Status:       status,
-		ExtTimestamp: extTimestamp,
-		// Preserve existing detailed game state when using diff patch updates
-		Period:        game.CurrentState.Period,
-		PeriodType:    game.CurrentState.PeriodType,
-		TimeRemaining: game.CurrentState.TimeRemaining,
-		Clock:         game.CurrentState.Clock,
-		Venue:         game.CurrentState.Venue,
-		Details:       game.CurrentState.Details,
-		Statistics:    game.CurrentState.Statistics,
-	}
-
-	ret <- models.GameUpdate{
-		OldState: game.CurrentState,
-		NewState: newState,
+	if err := feed.apply(diff); err != nil {
+		logger.Warn(fmt.Sprintf("MLB diff patch for game %s did not apply, reloading live feed: %s", game.GameCode, err))
+		forgetLiveFeed(game.GameCode)
+		s.getGameUpdateFromScoreboard(game, ret)
+		return
 	}
+	storeLiveFeed(game.GameCode, feed)
+	ret <- s.gameUpdateFromLiveFeed(game, feed.scoreboard)
 }
 
+// getGameUpdateFromScoreboard reads the full live feed and caches it for
+// later diff patches.
 func (s MLBService) getGameUpdateFromScoreboard(game models.Game, ret chan models.GameUpdate) {
 	logger.Info(fmt.Sprintf("Getting scoreboard update for game %s", game.GameCode))
-	scoreboard := s.Client.GetMLBScoreBoard(game.GameCode)
+	var scoreboard mlb.MLBScoreboardResponse
+	raw, err := s.Client.GetMLBLiveFeed(game.GameCode)
+	if err == nil {
+		var feed liveFeed
+		if feed, err = newLiveFeed(raw); err == nil {
+			storeLiveFeed(game.GameCode, feed)
+			scoreboard = feed.scoreboard
+		}
+	}
+	if err != nil {
+		logger.Warn(fmt.Sprintf("MLB live feed unavailable for game %s: %s", game.GameCode, err))
+		scoreboard = s.Client.GetMLBScoreBoard(game.GameCode)
+	}
+	ret <- s.gameUpdateFromLiveFeed(game, scoreboard)
+}
 
+func (s MLBService) gameUpdateFromLiveFeed(game models.Game, scoreboard mlb.MLBScoreboardResponse) models.GameUpdate {
 	// Extract inning information
 	inning := scoreboard.LiveData.Linescore.Currentinning
 	isTopInning := scoreboard.LiveData.Linescore.Istopinning
@@ -291,9 +269,22 @@ func (s MLBService) getGameUpdateFromScoreboard(game models.Game, ret chan model
 			newState.Status = models.StatusActive
 		}
 	}
-	ret <- models.GameUpdate{
+
+	// The first live feed read only sets the cursor, so picking up a game in
+	// progress doesn't replay every at-bat so far
+	completed, cursor := completedPlaysSince(scoreboard.LiveData.Plays.AllPlays, game.CurrentState.PlayCursor)
+	newState.PlayCursor = cursor
+	var plays []models.GameEvent
+	if game.CurrentState.ExtTimestamp != "" {
+		plays = gameEventsFromPlays(completed, newState.Home.Team, newState.Away.Team)
+	}
+	if newState.Status == models.StatusEnded {
+		forgetLiveFeed(game.GameCode)
+	}
+	return models.GameUpdate{
 		OldState: game.CurrentState,
 		NewState: newState,
+		Events:   plays,
 	}
 }
 
@@ -559,7 +550,7 @@ func (s MLBService) GetEvents(update models.GameUpdate, ret chan []models.Event)
 		s.getGoalEvents(update.OldState.Home, update.NewState.Home, update.OldState.Away.Team),
 		s.getGoalEvents(update.OldState.Away, update.NewState.Away, update.OldState.Home.Team)...,
 	)
-	ret <- events
+	ret <- append(events, s.getPlayEvents(update)...)
 }
 func (s MLBService) getGoalEvents(oldState models.TeamState, newState models.TeamState, opponent models.Team) []models.Event {
 	events := []models.Event{}
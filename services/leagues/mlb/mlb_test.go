@@ -1,6 +1,7 @@
 package mlb
 
 import (
+	"encoding/json"
 	"errors"
 	mlb "goalfeed/clients/leagues/mlb"
 	"goalfeed/models"
@@ -29,6 +30,11 @@ func (c MockMLBApiClientWithError) GetMLBScoreBoard(sGameId string) mlb.MLBScore
 	return mockClient.GetMLBScoreBoard(sGameId)
 }
 
+func (c MockMLBApiClientWithError) GetMLBLiveFeed(sGameId string) (json.RawMessage, error) {
+	var mockClient = mlb.MockMLBApiClient{}
+	return mockClient.GetMLBLiveFeed(sGameId)
+}
+
 func (c MockMLBApiClientWithError) GetTeam(sLink string) mlb.MLBTeamResponse {
 	var mockClient = mlb.MockMLBApiClient{}
 	return mockClient.GetTeam(sLink)
@@ -191,17 +197,22 @@ func TestGetGameUpdateFromDiffPatchWithEmptyValues(t *testing.T) {
 	activeGame.CurrentState.Away.Score = 2
 	activeGame.CurrentState.ExtTimestamp = "20230101_123456"
 
-	// Create a specific diff patch response that covers empty values branch
-	mockClient.SetHomeScore(0) // This will trigger the empty homeScore branch
-	mockClient.SetAwayScore(0) // This will trigger the empty awayScore branch
+	// The mock diff patch doesn't apply to the mock live feed, so the update
+	// has to come from a fresh read of the full feed rather than keeping the
+	// old scores
+	mockClient.SetHomeScore(0)
+	mockClient.SetAwayScore(0)
 
 	var updateChan chan models.GameUpdate = make(chan models.GameUpdate)
 	go service.GetGameUpdate(activeGame, updateChan)
 	update := <-updateChan
+	go service.GetGameUpdate(activeGame, updateChan)
+	update = <-updateChan
 
-	// Should use the original scores when diff patch returns 0
-	assert.Equal(t, activeGame.CurrentState.Home.Score, update.NewState.Home.Score)
-	assert.Equal(t, activeGame.CurrentState.Away.Score, update.NewState.Away.Score)
+	assert.Equal(t, 0, update.NewState.Home.Score)
+	assert.Equal(t, 0, update.NewState.Away.Score)
+	_, cached := cachedLiveFeed(activeGame.GameCode)
+	assert.True(t, cached)
 }
 
 func TestGetGameUpdateErrorFallback(t *testing.T) {
@@ -0,0 +1,144 @@
+package mlb
+
+import (
+	"fmt"
+	"goalfeed/clients/leagues/mlb"
+	"goalfeed/models"
+	"sort"
+	"strconv"
+)
+
+// playEventType maps a live feed result.eventType onto the events we report.
+func playEventType(eventType string) (models.EventType, bool) {
+	switch eventType {
+	case "home_run":
+		return models.EventTypeHomeRun, true
+	case "strikeout", "strikeout_double_play", "strikeout_triple_play":
+		return models.EventTypeStrikeout, true
+	case "walk", "intent_walk":
+		return models.EventTypeWalk, true
+	case "field_error":
+		return models.EventTypeError, true
+	default:
+		return "", false
+	}
+}
+
+// completedPlaysSince returns the completed plays at or after cursor in at-bat
+// order, and the cursor to store once they have been reported. When the same
+// at-bat appears more than once, the last copy wins.
+func completedPlaysSince(plays []mlb.Play, cursor int) ([]mlb.Play, int) {
+	byIndex := map[int]mlb.Play{}
+	next := cursor
+	for _, play := range plays {
+		if !play.About.Iscomplete || play.About.Atbatindex < cursor {
+			continue
+		}
+		byIndex[play.About.Atbatindex] = play
+		if play.About.Atbatindex+1 > next {
+			next = play.About.Atbatindex + 1
+		}
+	}
+	completed := make([]mlb.Play, 0, len(byIndex))
+	for _, play := range byIndex {
+		completed = append(completed, play)
+	}
+	sort.Slice(completed, func(i, j int) bool {
+		return completed[i].About.Atbatindex < completed[j].About.Atbatindex
+	})
+	return completed, next
+}
+
+// gameEventsFromPlays turns completed plays into the play-level events
+// GetEvents reports. Each is credited to the team whose player made the play:
+// the batter for home runs and walks, the pitcher for strikeouts and the
+// fielding side for errors.
+func gameEventsFromPlays(plays []mlb.Play, home, away models.Team) []models.GameEvent {
+	var events []models.GameEvent
+	for _, play := range plays {
+		eventType, ok := playEventType(play.Result.EventType)
+		if !ok {
+			continue
+		}
+		batting, fielding := home, away
+		if play.About.Istopinning {
+			batting, fielding = away, home
+		}
+		batter := models.Player{Id: strconv.Itoa(play.Matchup.Batter.ID), Name: play.Matchup.Batter.FullName, Team: batting}
+		pitcher := models.Player{Id: strconv.Itoa(play.Matchup.Pitcher.ID), Name: play.Matchup.Pitcher.FullName, Team: fielding}
+
+		team, player := batting, batter
+		switch eventType {
+		case models.EventTypeStrikeout:
+			team, player = fielding, pitcher
+		case models.EventTypeError:
+			team, player = fielding, models.Player{}
+		}
+		events = append(events, models.GameEvent{
+			Id:          strconv.Itoa(play.About.Atbatindex),
+			Type:        eventType,
+			Period:      play.About.Inning,
+			Clock:       inningDisplay(play.About.Inning, play.About.Istopinning),
+			Description: play.Result.Description,
+			Team:        team,
+			Player:      player,
+			Details: models.EventDetails{
+				Inning:  play.About.Inning,
+				Batter:  batter,
+				Pitcher: pitcher,
+			},
+			Timestamp: play.About.Endtime,
+		})
+	}
+	return events
+}
+
+func inningDisplay(inning int, isTopInning bool) string {
+	if inning <= 0 {
+		return ""
+	}
+	if isTopInning {
+		return fmt.Sprintf("Top %d", inning)
+	}
+	return fmt.Sprintf("Bot %d", inning)
+}
+
+func (s MLBService) getPlayEvents(update models.GameUpdate) []models.Event {
+	events := []models.Event{}
+	for _, play := range update.Events {
+		opponent := update.NewState.Home.Team
+		if play.Team.TeamCode == opponent.TeamCode {
+			opponent = update.NewState.Away.Team
+		}
+		description := play.Description
+		if description == "" {
+			description = fmt.Sprintf("%s for %s", play.Type, play.Team.TeamCode)
+		}
+		events = append(events, models.Event{
+			Id:           play.Id,
+			Type:         play.Type,
+			Timestamp:    play.Timestamp,
+			Description:  description,
+			TeamCode:     play.Team.TeamCode,
+			TeamName:     play.Team.TeamName,
+			TeamHash:     play.Team.GetTeamHash(),
+			PlayerName:   play.Player.Name,
+			LeagueId:     models.LeagueIdMLB,
+			LeagueName:   s.GetLeagueName(),
+			Period:       play.Period,
+			PeriodType:   "INNING",
+			Clock:        play.Clock,
+			OpponentCode: opponent.TeamCode,
+			OpponentName: opponent.TeamName,
+			OpponentHash: opponent.GetTeamHash(),
+			Details:      play.Details,
+			Score: models.ScoreUpdate{
+				HomeScore: update.NewState.Home.Score,
+				AwayScore: update.NewState.Away.Score,
+				HomeTeam:  update.NewState.Home.Team.TeamCode,
+				AwayTeam:  update.NewState.Away.Team.TeamCode,
+			},
+		})
+	}
+	return events
+}
@@ -0,0 +1,124 @@
+package mlb
+
+import (
+	"encoding/json"
+	"testing"
+
+	mlbc "goalfeed/clients/leagues/mlb"
+	"goalfeed/models"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// playsMockClient serves a fixed live feed and diff patch
+type playsMockClient struct {
+	mlbc.MockMLBApiClient
+	scoreboard mlbc.MLBScoreboardResponse
+	diff       mlbc.MLBDiffPatch
+}
+
+func (c playsMockClient) GetMLBScoreBoard(sGameId string) mlbc.MLBScoreboardResponse {
+	return c.scoreboard
+}
+
+func (c playsMockClient) GetMLBLiveFeed(sGameId string) (json.RawMessage, error) {
+	return json.Marshal(c.scoreboard)
+}
+
+func (c playsMockClient) GetDiffPatch(gameId string, timestamp string) (mlbc.MLBDiffPatch, error) {
+	return c.diff, nil
+}
+
+func testPlay(index int, eventType string, top bool, complete bool) mlbc.Play {
+	return mlbc.Play{
+		Result: mlbc.Result{EventType: eventType, Description: eventType + " description"},
+		About:  mlbc.About{Atbatindex: index, Inning: 3, Istopinning: top, Iscomplete: complete},
+		Matchup: mlbc.Matchup{
+			Batter:  mlbc.Person{ID: 100 + index, FullName: "Batter"},
+			Pitcher: mlbc.Person{ID: 200 + index, FullName: "Pitcher"},
+		},
+	}
+}
+
+func playsTestGame() models.Game {
+	return models.Game{
+		GameCode: "745804",
+		LeagueId: models.LeagueIdMLB,
+		CurrentState: models.GameState{
+			Home:         models.TeamState{Team: models.Team{TeamCode: "TOR", TeamName: "Toronto Blue Jays"}},
+			Away:         models.TeamState{Team: models.Team{TeamCode: "NYY", TeamName: "New York Yankees"}},
+			Status:       models.StatusActive,
+			ExtTimestamp: "20240401_180000",
+			PlayCursor:   2,
+		},
+	}
+}
+
+func TestGetGameUpdateFromScoreboard_PlayEvents(t *testing.T) {
+	client := playsMockClient{}
+	client.scoreboard.LiveData.Plays.AllPlays = []mlbc.Play{
+		testPlay(0, "strikeout", true, true),
+		testPlay(1, "single", true, true),
+		testPlay(2, "home_run", false, true),
+		testPlay(3, "strikeout", true, true),
+		testPlay(4, "field_error", false, true),
+		testPlay(5, "walk", true, false),
+	}
+	service := MLBService{Client: client}
+
+	ret := make(chan models.GameUpdate, 1)
+	service.getGameUpdateFromScoreboard(playsTestGame(), ret)
+	update := <-ret
+	assert.Equal(t, 5, update.NewState.PlayCursor)
+	if assert.Len(t, update.Events, 3) {
+		// Bottom of the inning: the home side is batting
+		assert.Equal(t, models.EventTypeHomeRun, update.Events[0].Type)
+		assert.Equal(t, "TOR", update.Events[0].Team.TeamCode)
+		assert.Equal(t, "102", update.Events[0].Player.Id)
+		// Strikeouts go to the pitcher's side
+		assert.Equal(t, models.EventTypeStrikeout, update.Events[1].Type)
+		assert.Equal(t, "TOR", update.Events[1].Team.TeamCode)
+		assert.Equal(t, "203", update.Events[1].Player.Id)
+		// Errors go to the fielding side
+		assert.Equal(t, models.EventTypeError, update.Events[2].Type)
+		assert.Equal(t, "NYY", update.Events[2].Team.TeamCode)
+	}
+
+	eventChan := make(chan []models.Event, 1)
+	service.GetEvents(update, eventChan)
+	events := <-eventChan
+	if assert.Len(t, events, 3) {
+		assert.Equal(t, "TOR", events[0].TeamCode)
+		assert.Equal(t, "NYY", events[0].OpponentCode)
+		assert.Equal(t, "Batter", events[0].PlayerName)
+		assert.Equal(t, "Bot 3", events[0].Clock)
+		assert.Equal(t, "102", events[0].Details.Batter.Id)
+		assert.Equal(t, "202", events[0].Details.Pitcher.Id)
+		assert.Equal(t, "home_run description", events[0].Description)
+	}
+}
+
+func TestGetGameUpdateFromScoreboard_FirstReadOnlySetsCursor(t *testing.T) {
+	client := playsMockClient{}
+	client.scoreboard.LiveData.Plays.AllPlays = []mlbc.Play{
+		testPlay(0, "home_run", true, true),
+		testPlay(1, "walk", true, true),
+	}
+	game := playsTestGame()
+	game.CurrentState.ExtTimestamp = ""
+	game.CurrentState.PlayCursor = 0
+
+	ret := make(chan models.GameUpdate, 1)
+	MLBService{Client: client}.getGameUpdateFromScoreboard(game, ret)
+	update := <-ret
+	assert.Equal(t, 2, update.NewState.PlayCursor)
+	assert.Empty(t, update.Events)
+}
+
+func TestPlayEventType(t *testing.T) {
+	eventType, ok := playEventType("intent_walk")
+	assert.True(t, ok)
+	assert.Equal(t, models.EventTypeWalk, eventType)
+	_, ok = playEventType("double")
+	assert.False(t, ok)
+}
@@ -0,0 +1,314 @@
+package nfl
+
+import (
+	"fmt"
+	"strings"
+
+	"goalfeed/clients/leagues/nfl"
+	"goalfeed/models"
+)
+
+// redZoneYards is how close to the goal line the red zone starts
+const redZoneYards = 20
+
+func inRedZone(yardsToEndzone int) bool {
+	return yardsToEndzone > 0 && yardsToEndzone <= redZoneYards
+}
+
+// nflDriveResult maps ESPN's code for how a drive ended onto a drive result
+// and, when the offence gave the ball away, the turnover event to fire.
+func nflDriveResult(drive nfl.DriveCurrent) (string, models.EventType) {
+	result := strings.ToUpper(strings.TrimSpace(drive.Result))
+	if result == "" {
+		result = strings.ToUpper(strings.TrimSpace(drive.DisplayResult))
+	}
+	switch {
+	case result == "":
+		return "", ""
+	case strings.Contains(result, "INT"):
+		return models.DriveResultTurnover, models.EventTypeInterception
+	case strings.Contains(result, "FUMBLE"):
+		return models.DriveResultTurnover, models.EventTypeFumble
+	case strings.Contains(result, "DOWNS"):
+		return models.DriveResultDowns, models.EventTypeTurnover
+	case result == "TD" || strings.Contains(result, "TOUCHDOWN"):
+		return models.DriveResultTouchdown, ""
+	case strings.Contains(result, "MISSED") || strings.Contains(result, "BLOCKED FG"):
+		return models.DriveResultMissedFieldGoal, ""
+	case result == "FG" || strings.Contains(result, "FIELD GOAL"):
+		return models.DriveResultFieldGoal, ""
+	case strings.Contains(result, "PUNT"):
+		return models.DriveResultPunt, ""
+	case result == "SF" || strings.Contains(result, "SAFETY"):
+		return models.DriveResultSafety, ""
+	case strings.Contains(result, "END OF"):
+		return models.DriveResultEndOfHalf, ""
+	}
+	return "", ""
+}
+
+// driveTeamCode names the team on a drive by abbreviation, or by matching
+// its id to a side.
+func driveTeamCode(team nfl.DriveTeam, teamID string, state models.GameState) string {
+	if team.ID == "" {
+		team.ID = teamID
+	}
+	switch {
+	case team.Abbreviation != "":
+		return strings.ToUpper(team.Abbreviation)
+	case team.ID != "" && team.ID == state.Home.Team.ExtID:
+		return state.Home.Team.TeamCode
+	case team.ID != "" && team.ID == state.Away.Team.ExtID:
+		return state.Away.Team.TeamCode
+	}
+	return ""
+}
+
+// nflDriveState summarises a drive from the summary's drive list.
+func nflDriveState(drive nfl.DriveCurrent, state models.GameState) models.DriveState {
+	summary := models.DriveState{
+		Id:           drive.ID,
+		TeamCode:     driveTeamCode(drive.Team, drive.Start.Team.ID, state),
+		Period:       drive.Start.Period.Number,
+		Plays:        drive.OffensivePlays,
+		Yards:        drive.Yards,
+		TimeConsumed: drive.TimeElapsed.DisplayValue,
+		RedZone:      drive.Start.IsRedZone || inRedZone(drive.Start.YardsToEndzone),
+	}
+	summary.Result, _ = nflDriveResult(drive)
+
+	start := drive.Start.YardsToEndzone
+	for _, play := range drive.Plays {
+		if start == 0 {
+			start = play.Start.YardsToEndzone
+		}
+		if summary.Period == 0 {
+			summary.Period = play.Period.Number
+		}
+		if inRedZone(play.Start.YardsToEndzone) {
+			summary.RedZone = true
+		}
+	}
+	if start > 0 {
+		summary.StartYardLine = 100 - start
+	}
+	if summary.Period == 0 {
+		summary.Period = state.Period
+	}
+	if summary.Plays == 0 {
+		summary.Plays = len(drive.Plays)
+	}
+	return summary
+}
+
+// sameDrive reports whether two drive states are the same drive. A drive
+// built from a Fastcast situation has no id, so it's matched by team.
+func sameDrive(a *models.DriveState, b *models.DriveState) bool {
+	if a == nil || b == nil {
+		return false
+	}
+	if a.Id != "" && b.Id != "" {
+		return a.Id == b.Id
+	}
+	return a.TeamCode != "" && a.TeamCode == b.TeamCode && a.Result == ""
+}
+
+// driveFromSummary is the game's current drive. The summary's drive list
+// gives it in full; a Fastcast document only has the live situation, so a
+// drive carries on while the same team has the ball and a new one starts
+// when possession changes. Once a drive has reached the red zone it stays
+// marked, so the entry is only reported once.
+func driveFromSummary(summary nfl.NFLScoreboardResponse, state models.GameState, old *models.DriveState) *models.DriveState {
+	current := summary.Drives.Current
+	var drive models.DriveState
+	switch {
+	case current.ID != "":
+		drive = nflDriveState(current, state)
+	default:
+		situation := current.Start
+		team := driveTeamCode(current.Team, situation.Team.ID, state)
+		if team == "" {
+			return old
+		}
+		if old != nil && old.TeamCode == team && old.Result == "" {
+			drive = *old
+		} else {
+			drive = models.DriveState{TeamCode: team, Period: state.Period}
+			if situation.YardsToEndzone > 0 {
+				drive.StartYardLine = 100 - situation.YardsToEndzone
+			}
+		}
+		drive.RedZone = drive.RedZone || situation.IsRedZone || inRedZone(situation.YardsToEndzone)
+	}
+	if sameDrive(old, &drive) && old.RedZone {
+		drive.RedZone = true
+	}
+	return &drive
+}
+
+// endedDrives lists the drives that finished since old was the current
+// drive. With the summary's drive list they're old and every drive after it;
+// with only a situation to go on, old ended when the other team got the
+// ball, and its result is read from how the score moved.
+func endedDrives(summary nfl.NFLScoreboardResponse, old *models.DriveState, current *models.DriveState, oldState models.GameState, newState models.GameState) []models.DriveState {
+	var ended []models.DriveState
+	if old.Id != "" && len(summary.Drives.Previous) > 0 {
+		for i, drive := range summary.Drives.Previous {
+			if drive.ID != old.Id {
+				continue
+			}
+			for _, finished := range summary.Drives.Previous[i:] {
+				ended = append(ended, nflDriveState(finished, newState))
+			}
+			break
+		}
+		return ended
+	}
+	if summary.Drives.Current.ID != "" || current == nil || old.Result != "" || sameDrive(old, current) {
+		return ended
+	}
+
+	drive := *old
+	scored, conceded := newState.Home.Score-oldState.Home.Score, newState.Away.Score-oldState.Away.Score
+	if drive.TeamCode == newState.Away.Team.TeamCode {
+		scored, conceded = conceded, scored
+	}
+	switch {
+	case scored >= 6:
+		drive.Result = models.DriveResultTouchdown
+	case scored >= 3:
+		drive.Result = models.DriveResultFieldGoal
+	case conceded == 2:
+		drive.Result = models.DriveResultSafety
+	}
+	return append(ended, drive)
+}
+
+// driveEventsSince returns red zone entries, turnovers and drive results
+// since the old state as game events. old nil means the game's drives
+// haven't been read before, so nothing is reported.
+func driveEventsSince(summary nfl.NFLScoreboardResponse, oldState models.GameState, newState models.GameState) []models.GameEvent {
+	var events []models.GameEvent
+	old, current := oldState.Drive, newState.Drive
+	if old == nil {
+		return events
+	}
+
+	raw := map[string]nfl.DriveCurrent{}
+	for _, drive := range summary.Drives.Previous {
+		raw[drive.ID] = drive
+	}
+	currentEnded := false
+	for _, drive := range endedDrives(summary, old, current, oldState, newState) {
+		drive := drive
+		if sameDrive(&drive, current) {
+			currentEnded = true
+		}
+		if drive.RedZone && !(sameDrive(old, &drive) && old.RedZone) {
+			events = append(events, driveGameEvent(models.EventTypeRedZoneEntry, &drive, newState, ""))
+		}
+		_, turnover := nflDriveResult(raw[drive.Id])
+		if turnover != "" {
+			events = append(events, driveGameEvent(turnover, &drive, newState, raw[drive.Id].Description))
+		}
+		if drive.Result != "" {
+			events = append(events, driveGameEvent(models.EventTypeDriveResult, &drive, newState, raw[drive.Id].Description))
+		}
+	}
+
+	if current != nil && !currentEnded && current.RedZone && current.Result == "" && !(sameDrive(old, current) && old.RedZone) {
+		events = append(events, driveGameEvent(models.EventTypeRedZoneEntry, current, newState, ""))
+	}
+	return events
+}
+
+func driveGameEvent(eventType models.EventType, drive *models.DriveState, state models.GameState, text string) models.GameEvent {
+	team := state.Away.Team
+	if drive.TeamCode == state.Home.Team.TeamCode {
+		team = state.Home.Team
+	}
+	id := drive.Id
+	if id == "" {
+		id = fmt.Sprintf("%s-%d-%d", drive.TeamCode, drive.Period, drive.StartYardLine)
+	}
+	return models.GameEvent{
+		Id:          fmt.Sprintf("%s-%s", id, eventType),
+		Type:        eventType,
+		Period:      state.Period,
+		Clock:       state.Clock,
+		Description: text,
+		Team:        team,
+		Details: models.EventDetails{
+			YardLine:   state.Details.YardLine,
+			Down:       state.Details.Down,
+			Distance:   state.Details.Distance,
+			Possession: state.Details.Possession,
+			Drive:      drive,
+		},
+	}
+}
+
+func driveEventLabel(eventType models.EventType, drive *models.DriveState) string {
+	switch eventType {
+	case models.EventTypeRedZoneEntry:
+		return "Red zone entry"
+	case models.EventTypeInterception:
+		return "Interception thrown"
+	case models.EventTypeFumble:
+		return "Fumble lost"
+	case models.EventTypeTurnover:
+		return "Turnover on downs"
+	}
+	if drive != nil && drive.Result != "" {
+		return "Drive ended: " + strings.ReplaceAll(drive.Result, "_", " ")
+	}
+	return "Drive ended"
+}
+
+// getDriveEvents fires the red zone entries, turnovers and drive results the
+// update carries, each for the team that had the ball.
+func (s NFLService) getDriveEvents(update models.GameUpdate) []models.Event {
+	events := []models.Event{}
+	for _, play := range update.Events {
+		switch play.Type {
+		case models.EventTypeRedZoneEntry, models.EventTypeTurnover, models.EventTypeInterception,
+			models.EventTypeFumble, models.EventTypeDriveResult:
+		default:
+			continue
+		}
+		team, opponent := play.Team, update.NewState.Home.Team
+		if team.TeamCode == opponent.TeamCode {
+			opponent = update.NewState.Away.Team
+		}
+		// The game event's description is ESPN's drive summary, e.g. "8
+		// plays, 75 yards, 4:12"
+		description := fmt.Sprintf("%s for %s", driveEventLabel(play.Type, play.Details.Drive), team.TeamCode)
+		if play.Description != "" {
+			description = fmt.Sprintf("%s (%s)", description, play.Description)
+		}
+		events = append(events, models.Event{
+			Id:           play.Id,
+			Type:         play.Type,
+			Description:  description,
+			TeamCode:     team.TeamCode,
+			TeamName:     team.TeamName,
+			TeamHash:     team.GetTeamHash(),
+			LeagueId:     int(s.league()),
+			LeagueName:   s.GetLeagueName(),
+			Period:       play.Period,
+			PeriodType:   update.NewState.PeriodType,
+			Clock:        play.Clock,
+			OpponentCode: opponent.TeamCode,
+			OpponentName: opponent.TeamName,
+			OpponentHash: opponent.GetTeamHash(),
+			Details:      play.Details,
+			Score: models.ScoreUpdate{
+				HomeScore: update.NewState.Home.Score,
+				AwayScore: update.NewState.Away.Score,
+				HomeTeam:  update.NewState.Home.Team.TeamCode,
+				AwayTeam:  update.NewState.Away.Team.TeamCode,
+			},
+		})
+	}
+	return events
+}
@@ -0,0 +1,151 @@
+package nfl
+
+import (
+	"encoding/json"
+	"testing"
+
+	nflc "goalfeed/clients/leagues/nfl"
+	"goalfeed/models"
+)
+
+func TestNFLDriveResult(t *testing.T) {
+	cases := []struct {
+		result   string
+		expected string
+		turnover models.EventType
+	}{
+		{"TD", models.DriveResultTouchdown, ""},
+		{"FG", models.DriveResultFieldGoal, ""},
+		{"MISSED FG", models.DriveResultMissedFieldGoal, ""},
+		{"PUNT", models.DriveResultPunt, ""},
+		{"INT", models.DriveResultTurnover, models.EventTypeInterception},
+		{"INT TD", models.DriveResultTurnover, models.EventTypeInterception},
+		{"FUMBLE", models.DriveResultTurnover, models.EventTypeFumble},
+		{"DOWNS", models.DriveResultDowns, models.EventTypeTurnover},
+		{"SF", models.DriveResultSafety, ""},
+		{"END OF HALF", models.DriveResultEndOfHalf, ""},
+		{"", "", ""},
+	}
+	for _, c := range cases {
+		result, turnover := nflDriveResult(nflc.DriveCurrent{Result: c.result})
+		if result != c.expected || turnover != c.turnover {
+			t.Errorf("%q: expected %s/%s, got %s/%s", c.result, c.expected, c.turnover, result, turnover)
+		}
+	}
+}
+
+// summaryWithDrives is the in-progress LV @ HOU fixture with the given
+// drives.
+func summaryWithDrives(t *testing.T, drives string) nflc.NFLScoreboardResponse {
+	t.Helper()
+	summary := loadNFLFixture(t, "nfl_summary_before_score_change.json")
+	if err := json.Unmarshal([]byte(drives), &summary.Drives); err != nil {
+		t.Fatalf("bad drives: %v", err)
+	}
+	return summary
+}
+
+func nflDriveUpdate(t *testing.T, summary nflc.NFLScoreboardResponse, old *models.DriveState) (models.GameUpdate, []models.Event) {
+	t.Helper()
+	service := NFLService{Client: fixtureNFLClient{resp: summary}}
+	game := service.GameFromScoreboard("401873286")
+	game.CurrentState.Drive = old
+
+	updates := make(chan models.GameUpdate)
+	go service.getGameUpdateFromScoreboard(game, updates)
+	update := <-updates
+	events := make(chan []models.Event)
+	go service.GetEvents(update, events)
+	return update, <-events
+}
+
+const finishedDrives = `{
+	"previous": [
+		{"id": "41", "description": "5 plays, 30 yards, 2:10", "team": {"abbreviation": "HOU"}, "start": {"yardsToEndzone": 75, "period": {"number": 4}},
+			"yards": 30, "offensivePlays": 5, "timeElapsed": {"displayValue": "2:10"}, "result": "INT", "displayResult": "Interception"},
+		{"id": "42", "description": "3 plays, 35 yards, 1:05", "team": {"abbreviation": "LV"}, "start": {"yardsToEndzone": 35, "period": {"number": 4}},
+			"yards": 35, "offensivePlays": 3, "timeElapsed": {"displayValue": "1:05"}, "result": "TD", "displayResult": "Touchdown",
+			"plays": [{"id": "1", "start": {"yardsToEndzone": 35}}, {"id": "2", "start": {"yardsToEndzone": 12}}, {"id": "3", "start": {"yardsToEndzone": 4}}]}
+	],
+	"current": {"id": "43", "team": {"abbreviation": "HOU"}, "start": {"yardsToEndzone": 75, "period": {"number": 4}}}
+}`
+
+func TestNFLService_DriveEvents_FinishedDrives(t *testing.T) {
+	update, events := nflDriveUpdate(t, summaryWithDrives(t, finishedDrives), &models.DriveState{Id: "41", TeamCode: "HOU"})
+
+	var got []string
+	for _, event := range events {
+		got = append(got, event.TeamCode+" "+string(event.Type))
+	}
+	expected := []string{
+		"HOU interception", "HOU drive_result",
+		"LV red_zone_entry", "LV drive_result",
+	}
+	if len(got) != len(expected) {
+		t.Fatalf("expected %v, got %v", expected, got)
+	}
+	for i := range expected {
+		if got[i] != expected[i] {
+			t.Fatalf("expected %v, got %v", expected, got)
+		}
+	}
+
+	interception := events[0]
+	if interception.OpponentCode != "LV" || interception.Description != "Interception thrown for HOU (5 plays, 30 yards, 2:10)" {
+		t.Errorf("unexpected interception %+v", interception)
+	}
+	touchdown := events[3].Details.Drive
+	if touchdown == nil || touchdown.Result != models.DriveResultTouchdown || touchdown.StartYardLine != 65 || !touchdown.RedZone || touchdown.TimeConsumed != "1:05" {
+		t.Errorf("unexpected touchdown drive %+v", touchdown)
+	}
+	if update.NewState.Drive == nil || update.NewState.Drive.Id != "43" || update.NewState.Drive.StartYardLine != 25 {
+		t.Errorf("expected HOU's new drive from its 25, got %+v", update.NewState.Drive)
+	}
+}
+
+func TestNFLService_DriveEvents_RedZoneEntryOnce(t *testing.T) {
+	drives := `{"current": {"id": "43", "team": {"abbreviation": "HOU"}, "start": {"yardsToEndzone": 75},
+		"plays": [{"id": "1", "start": {"yardsToEndzone": 75}}, {"id": "2", "start": {"yardsToEndzone": 18}}]}}`
+	summary := summaryWithDrives(t, drives)
+
+	update, events := nflDriveUpdate(t, summary, &models.DriveState{Id: "43", TeamCode: "HOU"})
+	if len(events) != 1 || events[0].Type != models.EventTypeRedZoneEntry || events[0].TeamCode != "HOU" {
+		t.Fatalf("expected one HOU red zone entry, got %+v", events)
+	}
+
+	_, events = nflDriveUpdate(t, summary, update.NewState.Drive)
+	if len(events) != 0 {
+		t.Errorf("expected the entry to be reported once, got %+v", events)
+	}
+
+	_, events = nflDriveUpdate(t, summary, nil)
+	if len(events) != 0 {
+		t.Errorf("expected nothing on the first read, got %+v", events)
+	}
+}
+
+// A Fastcast document only has the situation, so a drive ends when the
+// other team gets the ball and its result comes from the score.
+func TestDriveEventsSince_Situation(t *testing.T) {
+	oldState := models.GameState{
+		Home:  models.TeamState{Team: models.Team{TeamCode: "HOU", ExtID: "34"}, Score: 20},
+		Away:  models.TeamState{Team: models.Team{TeamCode: "LV", ExtID: "13"}, Score: 15},
+		Drive: &models.DriveState{TeamCode: "LV", Period: 4, StartYardLine: 30, RedZone: true},
+	}
+	var summary nflc.NFLScoreboardResponse
+	summary.Drives.Current.Start.Team.ID = "34"
+	summary.Drives.Current.Start.YardsToEndzone = 75
+
+	newState := oldState
+	newState.Away.Score = 22
+	newState.Drive = driveFromSummary(summary, newState, oldState.Drive)
+	if newState.Drive.TeamCode != "HOU" || newState.Drive.StartYardLine != 25 || newState.Drive.RedZone {
+		t.Fatalf("expected a new HOU drive, got %+v", newState.Drive)
+	}
+
+	events := driveEventsSince(summary, oldState, newState)
+	if len(events) != 1 || events[0].Type != models.EventTypeDriveResult || events[0].Team.TeamCode != "LV" ||
+		events[0].Details.Drive.Result != models.DriveResultTouchdown {
+		t.Errorf("expected LV's touchdown drive to end, got %+v", events)
+	}
+}
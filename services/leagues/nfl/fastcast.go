@@ -5,6 +5,8 @@ import (
 	"fmt"
 	"net/http"
 	"regexp"
+	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/gorilla/websocket"
@@ -28,13 +30,6 @@ type wsMsg struct {
 	Mid int64           `json:"mid,omitempty"`
 }
 
-type patchOp struct {
-	Op    string      `json:"op"`
-	Path  string      `json:"path"`
-	From  string      `json:"from,omitempty"`
-	Value interface{} `json:"value,omitempty"`
-}
-
 // FastcastConfig holds configuration for NFL Fastcast connection
 type FastcastConfig struct {
 	ReconnectBaseMs int
@@ -50,6 +45,65 @@ type FastcastConnection struct {
 	sid            string
 	stopSubs       chan struct{}
 	lastMidByTopic map[string]int64
+	topics         map[string]*topicQueue
+	// workers counts the topic goroutines still applying messages, which
+	// CloseConnection waits for so the next connection's can't race them
+	// on the same documents
+	workers sync.WaitGroup
+	closed  sync.Once
+}
+
+// topicQueueSize is how many messages a topic may have waiting, say behind a
+// checkpoint download, before it's dropped until its next checkpoint.
+const topicQueueSize = 64
+
+// topicQueue applies one topic's messages in order, away from the read
+// loop, so a checkpoint download holds up only its own topic.
+type topicQueue struct {
+	messages chan json.RawMessage
+	// overflowed is set when a message couldn't be queued. Everything
+	// queued with it patches a document that's missing a change, so the
+	// worker drops it all and waits for the topic's next checkpoint.
+	overflowed atomic.Bool
+}
+
+func (fc *FastcastConnection) startTopicQueue(topic string) *topicQueue {
+	q := &topicQueue{messages: make(chan json.RawMessage, topicQueueSize)}
+	fc.workers.Add(1)
+	go func() {
+		defer fc.workers.Done()
+		resyncing := false
+		for pl := range q.messages {
+			if q.overflowed.Swap(false) {
+				forgetFastcastDoc(topic)
+				q.drain()
+				resyncing = true
+				continue
+			}
+			if resyncing {
+				if _, checkpoint, _ := decodeFastcastPayload(pl); checkpoint == "" {
+					continue
+				}
+				resyncing = false
+			}
+			applyNFLPatches(pl, topic)
+		}
+	}()
+	return q
+}
+
+// drain drops every message waiting in the queue.
+func (q *topicQueue) drain() {
+	for {
+		select {
+		case _, ok := <-q.messages:
+			if !ok {
+				return
+			}
+		default:
+			return
+		}
+	}
 }
 
 var nflEventPath = regexp.MustCompile(`e:(\d+)`)
@@ -140,13 +194,6 @@ func runNFLFastcast() {
 	RunNFLFastcastRefactored()
 }
 
-func applyNFLPatches(pl json.RawMessage, topic string) {
-	// Placeholder implementation - this was refactored but the refactored version was removed
-	// For now, just ensure no panic occurs
-	_ = pl
-	_ = topic
-}
-
 // FetchFastcastHost retrieves the Fastcast host information
 func FetchFastcastHost() (*fastcastHost, error) {
 	// This function already exists in the original file
@@ -172,6 +219,7 @@ func NewFastcastConnection(config FastcastConfig) *FastcastConnection {
 		config:         config,
 		stopSubs:       make(chan struct{}),
 		lastMidByTopic: make(map[string]int64),
+		topics:         make(map[string]*topicQueue),
 	}
 }
 
@@ -221,7 +269,7 @@ func (fc *FastcastConnection) SubscribeToActiveGames() {
 
 	for _, g := range memoryStore.GetAllGames() {
 		if g.LeagueId == models.LeagueIdNFL {
-			tc := "gp-football-nfl-" + g.GameCode
+			tc := gamePackageTopicPrefix + g.GameCode
 			b, _ := json.Marshal(wsMsg{Op: "S", Sid: fc.sid, Tc: tc})
 			_ = fc.conn.WriteMessage(websocket.TextMessage, b)
 		}
@@ -266,27 +314,52 @@ func (fc *FastcastConnection) HandleConnectionMessage(msg []byte) bool {
 			}
 			fc.lastMidByTopic[m.Tc] = m.Mid
 		}
-		// Log debug message (removed logger dependency for now)
-		applyNFLPatches(m.Pl, m.Tc)
+		fc.queuePatches(m.Tc, m.Pl)
 	}
 
 	return true // Continue processing
 }
 
-// CloseConnection closes the WebSocket connection and cleanup
-func (fc *FastcastConnection) CloseConnection() {
-	if fc.conn != nil {
-		_ = fc.conn.Close()
+// queuePatches hands a message to its topic's queue without waiting for
+// it to be applied.
+func (fc *FastcastConnection) queuePatches(topic string, pl json.RawMessage) {
+	q, ok := fc.topics[topic]
+	if !ok {
+		q = fc.startTopicQueue(topic)
+		fc.topics[topic] = q
 	}
-	close(fc.stopSubs)
+	select {
+	case q.messages <- pl:
+	default:
+		logger.Warn(fmt.Sprintf("Fastcast: %s fell behind; dropping it until the next checkpoint", topic))
+		q.overflowed.Store(true)
+	}
+}
+
+// CloseConnection closes the WebSocket connection and cleanup. Messages
+// already queued are still applied; it returns once they have been, so a
+// reconnect starts with the documents to itself. Closing again does
+// nothing.
+func (fc *FastcastConnection) CloseConnection() {
+	fc.closed.Do(func() {
+		if fc.conn != nil {
+			_ = fc.conn.Close()
+		}
+		close(fc.stopSubs)
+		for topic, q := range fc.topics {
+			close(q.messages)
+			delete(fc.topics, topic)
+		}
+		fc.workers.Wait()
+	})
 }
 
 // RunConnectionLoop runs the main connection loop
 func (fc *FastcastConnection) RunConnectionLoop() {
+	defer fc.CloseConnection()
 	for {
 		_, msg, err := fc.conn.ReadMessage()
 		if err != nil {
-			fc.CloseConnection()
 			break
 		}
 
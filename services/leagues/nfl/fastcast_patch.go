@@ -0,0 +1,349 @@
+package nfl
+
+import (
+	"bytes"
+	"compress/zlib"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/spf13/viper"
+
+	"goalfeed/clients/leagues/nfl"
+	"goalfeed/models"
+	"goalfeed/targets/memoryStore"
+	"goalfeed/utils"
+)
+
+const gamePackageTopicPrefix = "gp-football-nfl-"
+
+// fastcastEvent is a game document as Fastcast pushes it, either as the whole
+// gp-football-nfl-<id> topic or keyed "e:<id>" inside a league topic.
+type fastcastEvent struct {
+	ID          string                        `json:"id"`
+	Date        string                        `json:"date"`
+	FullStatus  nfl.NFLSummaryStatus          `json:"fullStatus"`
+	Competitors []nfl.NFLScoreboardCompetitor `json:"competitors"`
+	Situation   nfl.DriveStart                `json:"situation"`
+}
+
+// fastcastState holds the last document seen on each topic and when each game
+// was last updated from the stream. Each topic's messages are applied one at
+// a time by its own worker, which alone touches that topic's document, but
+// GetGameUpdate reads the update times from the pollers.
+var fastcastState = struct {
+	sync.Mutex
+	docs    map[string]interface{}
+	updated map[string]time.Time
+}{
+	docs:    map[string]interface{}{},
+	updated: map[string]time.Time{},
+}
+
+// fastcastUpdateHandler receives each game update built from the stream. The
+// default only stores the new state; main replaces it so pushed updates fire
+// events the same way polled ones do.
+var fastcastUpdateHandler = storeFastcastUpdate
+
+// fetchFastcastCheckpoint downloads the full document a checkpoint message
+// points at. Replaced in tests.
+var fetchFastcastCheckpoint = func(url string) ([]byte, error) {
+	client := &http.Client{Timeout: 10 * time.Second}
+	req, _ := http.NewRequest("GET", url, nil)
+	req.Header.Set("User-Agent", "Mozilla/5.0 (Goalfeed)")
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != 200 {
+		return nil, fmt.Errorf("fastcast checkpoint status %s", resp.Status)
+	}
+	return io.ReadAll(resp.Body)
+}
+
+// SetFastcastUpdateHandler sets the function that applies game updates pushed
+// over Fastcast.
+func SetFastcastUpdateHandler(handler func(game models.Game, update models.GameUpdate)) {
+	if handler == nil {
+		handler = storeFastcastUpdate
+	}
+	fastcastUpdateHandler = handler
+}
+
+func storeFastcastUpdate(game models.Game, update models.GameUpdate) {
+	game.CurrentState = update.NewState
+	memoryStore.SetGame(game)
+}
+
+// fastcastIsCurrent reports whether the stream updated the game recently
+// enough that polling it would only race the push.
+func fastcastIsCurrent(gameCode string) bool {
+	staleAfter := viper.GetInt("nfl.fastcast.stale_after_sec")
+	if staleAfter <= 0 {
+		staleAfter = 60
+	}
+	fastcastState.Lock()
+	defer fastcastState.Unlock()
+	updated, ok := fastcastState.updated[gameCode]
+	return ok && time.Since(updated) < time.Duration(staleAfter)*time.Second
+}
+
+// decodeFastcastPayload unwraps a "P" message payload into patch ops, or the
+// URL of a checkpoint holding the whole document. Payloads arrive as a bare
+// ops array, a {"ts","~c","pl"} wrapper, or base64 zlib-compressed text of
+// either, possibly quoted as a JSON string.
+func decodeFastcastPayload(pl json.RawMessage) ([]utils.JSONPatchOp, string, error) {
+	pl = bytes.TrimSpace(pl)
+	if len(pl) == 0 {
+		return nil, "", nil
+	}
+	switch pl[0] {
+	case '[':
+		var ops []utils.JSONPatchOp
+		if err := json.Unmarshal(pl, &ops); err != nil {
+			return nil, "", err
+		}
+		return ops, "", nil
+	case '{':
+		var wrapper struct {
+			Pl json.RawMessage `json:"pl"`
+		}
+		if err := json.Unmarshal(pl, &wrapper); err != nil {
+			return nil, "", err
+		}
+		return decodeFastcastPayload(wrapper.Pl)
+	case '"':
+		var text string
+		if err := json.Unmarshal(pl, &text); err != nil {
+			return nil, "", err
+		}
+		text = strings.TrimSpace(text)
+		switch {
+		case text == "":
+			return nil, "", nil
+		case strings.HasPrefix(text, "http://") || strings.HasPrefix(text, "https://"):
+			return nil, text, nil
+		case strings.HasPrefix(text, "[") || strings.HasPrefix(text, "{"):
+			return decodeFastcastPayload(json.RawMessage(text))
+		}
+		inflated, err := inflateFastcastPayload(text)
+		if err != nil {
+			return nil, "", err
+		}
+		return decodeFastcastPayload(inflated)
+	default:
+		return nil, "", fmt.Errorf("unrecognised fastcast payload")
+	}
+}
+
+func inflateFastcastPayload(text string) ([]byte, error) {
+	compressed, err := base64.StdEncoding.DecodeString(text)
+	if err != nil {
+		return nil, err
+	}
+	reader, err := zlib.NewReader(bytes.NewReader(compressed))
+	if err != nil {
+		return nil, err
+	}
+	defer reader.Close()
+	return io.ReadAll(reader)
+}
+
+// applyNFLPatches brings the topic's cached document up to date from one
+// message and pushes the result to every watched game the message touched. A
+// patch that doesn't apply drops the document; the next resubscribe delivers
+// a fresh checkpoint, and polling covers the game until then.
+func applyNFLPatches(pl json.RawMessage, topic string) {
+	ops, checkpoint, err := decodeFastcastPayload(pl)
+	if err != nil {
+		logger.Debug(fmt.Sprintf("Fastcast: ignoring undecodable payload on %s: %v", topic, err))
+		return
+	}
+
+	var doc interface{}
+	if checkpoint != "" {
+		raw, err := fetchFastcastCheckpoint(checkpoint)
+		if err == nil {
+			doc, err = utils.DecodeJSONDocument(raw)
+		}
+		if err != nil {
+			logger.Warn(fmt.Sprintf("Fastcast: failed to load checkpoint for %s: %v", topic, err))
+			forgetFastcastDoc(topic)
+			return
+		}
+	} else {
+		if len(ops) == 0 {
+			return
+		}
+		fastcastState.Lock()
+		doc = fastcastState.docs[topic]
+		fastcastState.Unlock()
+		if doc, err = utils.ApplyJSONPatch(doc, ops); err != nil {
+			logger.Debug(fmt.Sprintf("Fastcast: dropping %s until the next checkpoint: %v", topic, err))
+			forgetFastcastDoc(topic)
+			return
+		}
+	}
+
+	fastcastState.Lock()
+	fastcastState.docs[topic] = doc
+	fastcastState.Unlock()
+
+	for gameCode, summary := range fastcastSummaries(doc, topic, touchedEventIDs(ops, checkpoint != "")) {
+		updateGameFromFastcast(gameCode, summary)
+	}
+}
+
+func forgetFastcastDoc(topic string) {
+	fastcastState.Lock()
+	defer fastcastState.Unlock()
+	delete(fastcastState.docs, topic)
+}
+
+// touchedEventIDs lists the "e:<id>" events a message changed. nil means the
+// whole document changed, as with a checkpoint or a root replace.
+func touchedEventIDs(ops []utils.JSONPatchOp, checkpoint bool) map[string]bool {
+	if checkpoint {
+		return nil
+	}
+	touched := map[string]bool{}
+	for _, op := range ops {
+		m := nflEventPath.FindStringSubmatch(op.Path)
+		if m == nil {
+			return nil
+		}
+		touched[m[1]] = true
+	}
+	return touched
+}
+
+// fastcastSummaries reads the game documents out of a topic document, keyed
+// by game code, in the summary shape gameStateFromSummary consumes. A league
+// topic keys its games "e:<id>"; a gp topic is either a single game document
+// or a full summary.
+func fastcastSummaries(doc interface{}, topic string, touched map[string]bool) map[string]nfl.NFLScoreboardResponse {
+	summaries := map[string]nfl.NFLScoreboardResponse{}
+	root, ok := doc.(map[string]interface{})
+	if !ok {
+		return summaries
+	}
+
+	keyed := false
+	for key, value := range root {
+		m := nflEventPath.FindStringSubmatch(key)
+		if m == nil || m[0] != key {
+			continue
+		}
+		keyed = true
+		if touched != nil && !touched[m[1]] {
+			continue
+		}
+		var event fastcastEvent
+		if err := remarshal(value, &event); err == nil {
+			summaries[m[1]] = event.summary()
+		}
+	}
+
+	gameCode := strings.TrimPrefix(topic, gamePackageTopicPrefix)
+	if keyed || gameCode == topic || gameCode == "" {
+		return summaries
+	}
+	if _, isSummary := root["header"]; isSummary {
+		var summary nfl.NFLScoreboardResponse
+		if err := remarshal(doc, &summary); err == nil {
+			summaries[gameCode] = summary
+		}
+		return summaries
+	}
+	var event fastcastEvent
+	if err := remarshal(doc, &event); err == nil {
+		summaries[gameCode] = event.summary()
+	}
+	return summaries
+}
+
+func (e fastcastEvent) summary() nfl.NFLScoreboardResponse {
+	var summary nfl.NFLScoreboardResponse
+	summary.Header.ID = e.ID
+	summary.Header.Competitions = []nfl.NFLSummaryCompetition{{
+		ID:          e.ID,
+		Date:        e.Date,
+		Competitors: e.Competitors,
+		Status:      e.FullStatus,
+	}}
+	summary.Drives.Current.Start = e.Situation
+	return summary
+}
+
+func remarshal(in interface{}, out interface{}) error {
+	raw, err := json.Marshal(in)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(raw, out)
+}
+
+// updateGameFromFastcast turns a pushed game document into a game update for
+// the stored game. Games we aren't watching are ignored.
+func updateGameFromFastcast(gameCode string, summary nfl.NFLScoreboardResponse) {
+	game, err := memoryStore.GetGameByGameKey(models.Game{LeagueId: models.LeagueIdNFL, GameCode: gameCode}.GetGameKey())
+	if err != nil {
+		return
+	}
+	if len(summary.Header.Competitions) == 0 || len(summary.Header.Competitions[0].Competitors) < 2 {
+		return
+	}
+	fillCompetitorTeams(summary.Header.Competitions[0].Competitors, game)
+
+	newState := NFLService{}.gameStateFromSummary(game, summary)
+	fastcastState.Lock()
+	if newState.Status == models.StatusEnded {
+		delete(fastcastState.updated, gameCode)
+		delete(fastcastState.docs, gamePackageTopicPrefix+gameCode)
+	} else {
+		fastcastState.updated[gameCode] = time.Now()
+	}
+	fastcastState.Unlock()
+
+	fastcastUpdateHandler(game, withLastScoring(models.GameUpdate{
+		OldState: game.CurrentState,
+		NewState: newState,
+		Events: append(
+			scoringPlaysSince(summary.ScoringPlays, game.CurrentState, newState),
+			driveEventsSince(summary, game.CurrentState, newState)...,
+		),
+	}))
+}
+
+// fillCompetitorTeams fills team details a pushed document left out from the
+// stored game, so a push carrying only ids and scores doesn't blank the names.
+func fillCompetitorTeams(competitors []nfl.NFLScoreboardCompetitor, game models.Game) {
+	for i := range competitors {
+		known := game.CurrentState.Away.Team
+		if competitors[i].HomeAway == "home" {
+			known = game.CurrentState.Home.Team
+		}
+		team := &competitors[i].Team
+		if team.ID != "" && known.ExtID != "" && team.ID != known.ExtID {
+			continue
+		}
+		if team.Abbreviation == "" {
+			team.Abbreviation = known.TeamCode
+		}
+		if team.DisplayName == "" {
+			team.DisplayName = known.TeamName
+		}
+		if team.ID == "" {
+			team.ID = known.ExtID
+		}
+		if team.Logo == "" {
+			team.Logo = known.LogoURL
+		}
+	}
+}
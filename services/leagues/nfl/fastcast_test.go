@@ -1,8 +1,17 @@
 package nfl
 
 import (
+	"bytes"
+	"compress/zlib"
+	"encoding/base64"
 	"encoding/json"
+	"fmt"
+	"strings"
 	"testing"
+	"time"
+
+	"goalfeed/models"
+	"goalfeed/targets/memoryStore"
 
 	"github.com/spf13/viper"
 	"github.com/stretchr/testify/assert"
@@ -570,3 +579,271 @@ func TestApplyNFLPatches_EmptyTeamIDValue(t *testing.T) {
 
 	// Should not panic
 }
+
+func fastcastTestGame() models.Game {
+	return models.Game{
+		GameCode: "401547417",
+		LeagueId: models.LeagueIdNFL,
+		CurrentState: models.GameState{
+			Home:   models.TeamState{Team: models.Team{TeamCode: "KC", TeamName: "Kansas City Chiefs", ExtID: "12"}},
+			Away:   models.TeamState{Team: models.Team{TeamCode: "BUF", TeamName: "Buffalo Bills", ExtID: "2"}},
+			Status: models.StatusActive,
+		},
+	}
+}
+
+const fastcastTestEvent = `{"id":"401547417","fullStatus":{"period":2,"displayClock":"8:12","type":{"state":"in","shortDetail":"8:12 - 2nd"}},` +
+	`"competitors":[{"homeAway":"home","score":"7","team":{"id":"12","abbreviation":"KC"}},{"homeAway":"away","score":"3","team":{"id":"2","abbreviation":"BUF"}}]}`
+
+// captureFastcastUpdates stores the game and records the updates pushed for it
+func captureFastcastUpdates(t *testing.T, game models.Game) *[]models.GameUpdate {
+	memoryStore.SetGame(game)
+	var updates []models.GameUpdate
+	SetFastcastUpdateHandler(func(game models.Game, update models.GameUpdate) {
+		updates = append(updates, update)
+		storeFastcastUpdate(game, update)
+	})
+	t.Cleanup(func() {
+		SetFastcastUpdateHandler(nil)
+		fastcastState.Lock()
+		fastcastState.docs = map[string]interface{}{}
+		fastcastState.updated = map[string]time.Time{}
+		fastcastState.Unlock()
+	})
+	return &updates
+}
+
+func compressFastcastPayload(t *testing.T, payload string) string {
+	var buf bytes.Buffer
+	w := zlib.NewWriter(&buf)
+	_, err := w.Write([]byte(payload))
+	assert.NoError(t, err)
+	assert.NoError(t, w.Close())
+	return base64.StdEncoding.EncodeToString(buf.Bytes())
+}
+
+func TestDecodeFastcastPayload(t *testing.T) {
+	ops := `[{"op":"replace","path":"/fullStatus/displayClock","value":"7:00"}]`
+	compressed := compressFastcastPayload(t, ops)
+
+	for name, payload := range map[string]string{
+		"bare ops":           ops,
+		"wrapper":            `{"ts":1,"~c":0,"pl":` + ops + `}`,
+		"compressed wrapper": `{"ts":1,"~c":1,"pl":"` + compressed + `"}`,
+		"compressed string":  `"` + compressed + `"`,
+	} {
+		decoded, checkpoint, err := decodeFastcastPayload(json.RawMessage(payload))
+		assert.NoError(t, err, name)
+		assert.Empty(t, checkpoint, name)
+		if assert.Len(t, decoded, 1, name) {
+			assert.Equal(t, "/fullStatus/displayClock", decoded[0].Path, name)
+		}
+	}
+
+	_, checkpoint, err := decodeFastcastPayload(json.RawMessage(`"https://fcast.espncdn.com/checkpoint/1"`))
+	assert.NoError(t, err)
+	assert.Equal(t, "https://fcast.espncdn.com/checkpoint/1", checkpoint)
+}
+
+func TestApplyNFLPatches_CheckpointThenPatch(t *testing.T) {
+	game := fastcastTestGame()
+	updates := captureFastcastUpdates(t, game)
+	fetch := fetchFastcastCheckpoint
+	fetchFastcastCheckpoint = func(url string) ([]byte, error) { return []byte(fastcastTestEvent), nil }
+	defer func() { fetchFastcastCheckpoint = fetch }()
+
+	topic := gamePackageTopicPrefix + game.GameCode
+	applyNFLPatches(json.RawMessage(`"https://fcast.espncdn.com/checkpoint/1"`), topic)
+	applyNFLPatches(json.RawMessage(`[{"op":"replace","path":"/competitors/0/score","value":"14"},`+
+		`{"op":"replace","path":"/fullStatus/displayClock","value":"2:01"}]`), topic)
+
+	if assert.Len(t, *updates, 2) {
+		assert.Equal(t, 7, (*updates)[0].NewState.Home.Score)
+		assert.Equal(t, 3, (*updates)[0].NewState.Away.Score)
+		assert.Equal(t, 7, (*updates)[1].OldState.Home.Score)
+		assert.Equal(t, 14, (*updates)[1].NewState.Home.Score)
+		assert.Equal(t, "2:01", (*updates)[1].NewState.Clock)
+		// Names the push left out come from the stored game
+		assert.Equal(t, "Kansas City Chiefs", (*updates)[1].NewState.Home.Team.TeamName)
+	}
+
+	stored, err := memoryStore.GetGameByGameKey(game.GetGameKey())
+	assert.NoError(t, err)
+	assert.Equal(t, 14, stored.CurrentState.Home.Score)
+
+	// Polling stands down while the stream is current
+	ret := make(chan models.GameUpdate, 1)
+	NFLService{}.GetGameUpdate(stored, ret)
+	update := <-ret
+	assert.Equal(t, 14, update.NewState.Home.Score)
+	assert.Equal(t, update.OldState, update.NewState)
+}
+
+func TestHandleConnectionMessage_CheckpointDoesNotHoldUpReads(t *testing.T) {
+	game := fastcastTestGame()
+	captureFastcastUpdates(t, game)
+	applied := make(chan models.GameUpdate, 2)
+	SetFastcastUpdateHandler(func(game models.Game, update models.GameUpdate) {
+		storeFastcastUpdate(game, update)
+		applied <- update
+	})
+	release := make(chan struct{})
+	fetch := fetchFastcastCheckpoint
+	fetchFastcastCheckpoint = func(url string) ([]byte, error) {
+		<-release
+		return []byte(fastcastTestEvent), nil
+	}
+	defer func() { fetchFastcastCheckpoint = fetch }()
+
+	fc := NewFastcastConnection(FastcastConfig{})
+	defer fc.CloseConnection()
+	topic := gamePackageTopicPrefix + game.GameCode
+	handled := make(chan struct{})
+	go func() {
+		fc.HandleConnectionMessage([]byte(`{"op":"P","tc":"` + topic + `","mid":1,"pl":"https://fcast.espncdn.com/checkpoint/1"}`))
+		fc.HandleConnectionMessage([]byte(`{"op":"P","tc":"` + topic + `","mid":2,"pl":[{"op":"replace","path":"/competitors/0/score","value":"14"}]}`))
+		close(handled)
+	}()
+	select {
+	case <-handled:
+	case <-time.After(time.Second):
+		t.Fatal("the read loop waited for the checkpoint download")
+	}
+
+	close(release)
+	for _, score := range []int{7, 14} {
+		select {
+		case update := <-applied:
+			assert.Equal(t, score, update.NewState.Home.Score, "the patch waits for its checkpoint")
+		case <-time.After(time.Second):
+			t.Fatal("the topic's messages weren't applied")
+		}
+	}
+}
+
+func TestCloseConnection_ReconnectWaitsForTopicWorkers(t *testing.T) {
+	game := fastcastTestGame()
+	captureFastcastUpdates(t, game)
+	release := make(chan struct{})
+	fetch := fetchFastcastCheckpoint
+	fetchFastcastCheckpoint = func(url string) ([]byte, error) {
+		<-release
+		return []byte(fastcastTestEvent), nil
+	}
+	defer func() { fetchFastcastCheckpoint = fetch }()
+	topic := gamePackageTopicPrefix + game.GameCode
+
+	first := NewFastcastConnection(FastcastConfig{})
+	first.HandleConnectionMessage([]byte(`{"op":"P","tc":"` + topic + `","mid":1,"pl":"https://fcast.espncdn.com/checkpoint/1"}`))
+	first.HandleConnectionMessage([]byte(`{"op":"P","tc":"` + topic + `","mid":2,"pl":[{"op":"replace","path":"/competitors/0/score","value":"10"}]}`))
+	closed := make(chan struct{})
+	go func() {
+		first.CloseConnection()
+		close(closed)
+	}()
+	select {
+	case <-closed:
+		t.Fatal("CloseConnection returned while the topic was still being applied")
+	case <-time.After(50 * time.Millisecond):
+	}
+	close(release)
+	<-closed
+	first.CloseConnection()
+
+	second := NewFastcastConnection(FastcastConfig{})
+	second.HandleConnectionMessage([]byte(`{"op":"P","tc":"` + topic + `","mid":1,"pl":[{"op":"replace","path":"/competitors/0/score","value":"14"}]}`))
+	second.CloseConnection()
+
+	stored, err := memoryStore.GetGameByGameKey(game.GetGameKey())
+	assert.NoError(t, err)
+	assert.Equal(t, 14, stored.CurrentState.Home.Score, "the new connection patches the document the old one finished")
+}
+
+func TestHandleConnectionMessage_OverflowWaitsForNextCheckpoint(t *testing.T) {
+	game := fastcastTestGame()
+	captureFastcastUpdates(t, game)
+	scores := make(chan int, topicQueueSize+4)
+	SetFastcastUpdateHandler(func(game models.Game, update models.GameUpdate) {
+		storeFastcastUpdate(game, update)
+		scores <- update.NewState.Home.Score
+	})
+	release := make(chan struct{})
+	fetch := fetchFastcastCheckpoint
+	fetchFastcastCheckpoint = func(url string) ([]byte, error) {
+		if strings.HasSuffix(url, "/1") {
+			<-release
+			return []byte(fastcastTestEvent), nil
+		}
+		return []byte(strings.Replace(fastcastTestEvent, `"score":"7"`, `"score":"21"`, 1)), nil
+	}
+	defer func() { fetchFastcastCheckpoint = fetch }()
+	topic := gamePackageTopicPrefix + game.GameCode
+	message := func(mid int, pl string) []byte {
+		return []byte(fmt.Sprintf(`{"op":"P","tc":"%s","mid":%d,"pl":%s}`, topic, mid, pl))
+	}
+
+	fc := NewFastcastConnection(FastcastConfig{})
+	fc.HandleConnectionMessage(message(1, `"https://fcast.espncdn.com/checkpoint/1"`))
+	// The worker takes the checkpoint and waits on it, so one more than the
+	// queue holds overflows it
+	assert.Eventually(t, func() bool { return len(fc.topics[topic].messages) == 0 }, time.Second, time.Millisecond)
+	for mid := 2; mid <= topicQueueSize+2; mid++ {
+		fc.HandleConnectionMessage(message(mid, fmt.Sprintf(`[{"op":"replace","path":"/competitors/0/score","value":"%d"}]`, mid)))
+	}
+	close(release)
+	assert.Equal(t, 7, <-scores)
+	fc.HandleConnectionMessage(message(topicQueueSize+3, `[{"op":"replace","path":"/competitors/0/score","value":"99"}]`))
+	fc.HandleConnectionMessage(message(topicQueueSize+4, `"https://fcast.espncdn.com/checkpoint/2"`))
+	fc.CloseConnection()
+
+	close(scores)
+	var rest []int
+	for score := range scores {
+		rest = append(rest, score)
+	}
+	assert.Equal(t, []int{21}, rest, "nothing queued with the lost message, or after it, is applied before the next checkpoint")
+}
+
+func TestApplyNFLPatches_PatchWithoutDocumentWaitsForCheckpoint(t *testing.T) {
+	game := fastcastTestGame()
+	updates := captureFastcastUpdates(t, game)
+
+	topic := gamePackageTopicPrefix + game.GameCode
+	applyNFLPatches(json.RawMessage(`[{"op":"replace","path":"/competitors/0/score","value":"14"}]`), topic)
+	assert.Empty(t, *updates)
+	assert.False(t, fastcastIsCurrent(game.GameCode))
+
+	// A whole-document replace seeds the topic without a checkpoint
+	applyNFLPatches(json.RawMessage(`[{"op":"replace","path":"","value":`+fastcastTestEvent+`}]`), topic)
+	assert.Len(t, *updates, 1)
+	assert.True(t, fastcastIsCurrent(game.GameCode))
+}
+
+func TestApplyNFLPatches_LeagueTopicUpdatesTouchedGames(t *testing.T) {
+	game := fastcastTestGame()
+	updates := captureFastcastUpdates(t, game)
+	other := `{"id":"401547418","competitors":[{"homeAway":"home","score":"0"},{"homeAway":"away","score":"0"}]}`
+
+	applyNFLPatches(json.RawMessage(`[{"op":"replace","path":"","value":{"e:401547417":`+fastcastTestEvent+`,"e:401547418":`+other+`}}]`), "event-football-nfl")
+	applyNFLPatches(json.RawMessage(`[{"op":"replace","path":"/e:401547418/competitors/0/score","value":"7"}]`), "event-football-nfl")
+	applyNFLPatches(json.RawMessage(`[{"op":"replace","path":"/e:401547417/competitors/1/score","value":"10"}]`), "event-football-nfl")
+
+	// 401547418 isn't being watched, so only the seed and the last patch land
+	if assert.Len(t, *updates, 2) {
+		assert.Equal(t, 10, (*updates)[1].NewState.Away.Score)
+	}
+}
+
+func TestApplyNFLPatches_BadPatchDropsDocument(t *testing.T) {
+	game := fastcastTestGame()
+	captureFastcastUpdates(t, game)
+
+	topic := gamePackageTopicPrefix + game.GameCode
+	applyNFLPatches(json.RawMessage(`[{"op":"replace","path":"","value":`+fastcastTestEvent+`}]`), topic)
+	applyNFLPatches(json.RawMessage(`[{"op":"remove","path":"/missing"}]`), topic)
+
+	fastcastState.Lock()
+	_, cached := fastcastState.docs[topic]
+	fastcastState.Unlock()
+	assert.False(t, cached)
+}
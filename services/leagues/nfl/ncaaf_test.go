@@ -0,0 +1,87 @@
+package nfl
+
+import (
+	"testing"
+
+	nflc "goalfeed/clients/leagues/nfl"
+	"goalfeed/models"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func collegeCompetitor(homeAway, id, code, conferenceID string, rank int, score string) nflc.NFLScoreboardCompetitor {
+	c := nflc.NFLScoreboardCompetitor{HomeAway: homeAway, Score: score}
+	c.Team.ID = id
+	c.Team.Abbreviation = code
+	c.Team.DisplayName = code
+	c.Team.ConferenceID = conferenceID
+	c.CuratedRank.Current = rank
+	return c
+}
+
+func collegeSummary(homeScore, awayScore string) nflc.NFLScoreboardResponse {
+	resp := nflc.NFLScoreboardResponse{}
+	resp.Header.Competitions = []nflc.NFLSummaryCompetition{{
+		Competitors: []nflc.NFLScoreboardCompetitor{
+			collegeCompetitor("home", "194", "OSU", "", 0, homeScore),
+			collegeCompetitor("away", "130", "MICH", "", 0, awayScore),
+		},
+	}}
+	resp.Header.Competitions[0].Status.Period = 2
+	resp.Header.Competitions[0].Status.DisplayClock = "5:00"
+	resp.Header.Competitions[0].Status.Type.State = STATUS_ACTIVE
+	return resp
+}
+
+func TestNCAAF_GetLeagueName(t *testing.T) {
+	assert.Equal(t, "NCAAF", NFLService{League: models.LeagueIdNCAAF}.GetLeagueName())
+	assert.Equal(t, "NFL", NFLService{League: models.LeagueIdNFL}.GetLeagueName())
+}
+
+func TestNCAAF_GameFromEventReadsRankAndConference(t *testing.T) {
+	svc := NFLService{Client: nflc.NFLMockClient{}, League: models.LeagueIdNCAAF}
+	ev := nflc.NFLScheduleEvent{ID: "401"}
+	ev.Status.Type.State = STATUS_UPCOMING
+	home := nflc.NFLCompetitor{HomeAway: "home"}
+	home.Team.Abbreviation = "OSU"
+	home.Team.ConferenceID = "5"
+	home.CuratedRank.Current = 3
+	away := nflc.NFLCompetitor{HomeAway: "away"}
+	away.Team.Abbreviation = "MICH"
+	away.Team.ConferenceID = "5"
+	away.CuratedRank.Current = 99
+	ev.Competitions = []nflc.NFLCompetition{{Competitors: []nflc.NFLCompetitor{home, away}}}
+
+	g := svc.gameFromEvent(ev)
+	assert.Equal(t, models.League(models.LeagueIdNCAAF), g.LeagueId)
+	assert.Equal(t, models.LeagueIdNCAAF, g.CurrentState.Home.Team.LeagueID)
+	assert.Equal(t, 3, g.CurrentState.Home.Rank)
+	assert.Equal(t, 0, g.CurrentState.Away.Rank)
+	assert.Equal(t, "Big Ten", g.CurrentState.Home.Team.Conference)
+}
+
+func TestNCAAF_GameUpdateKeepsRankAndFiresCollegeEvents(t *testing.T) {
+	svc := NFLService{Client: fixtureNFLClient{resp: collegeSummary("7", "0")}, League: models.LeagueIdNCAAF}
+	game := models.Game{
+		GameCode: "401",
+		LeagueId: models.LeagueIdNCAAF,
+		CurrentState: models.GameState{
+			Home:   models.TeamState{Team: models.Team{TeamCode: "OSU", LeagueID: models.LeagueIdNCAAF, Conference: "Big Ten"}, Rank: 3},
+			Away:   models.TeamState{Team: models.Team{TeamCode: "MICH", LeagueID: models.LeagueIdNCAAF, Conference: "Big Ten"}},
+			Status: models.StatusActive,
+		},
+	}
+	updates := make(chan models.GameUpdate)
+	go svc.GetGameUpdate(game, updates)
+	update := <-updates
+	assert.Equal(t, 3, update.NewState.Home.Rank)
+	assert.Equal(t, "Big Ten", update.NewState.Home.Team.Conference)
+
+	events := make(chan []models.Event)
+	go svc.GetEvents(update, events)
+	got := <-events
+	assert.NotEmpty(t, got)
+	assert.Equal(t, "OSU", got[0].TeamCode)
+	assert.Equal(t, models.LeagueIdNCAAF, got[0].LeagueId)
+	assert.Equal(t, "NCAAF", got[0].LeagueName)
+}
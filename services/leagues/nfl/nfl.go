@@ -16,16 +16,29 @@ const (
 	STATUS_FINAL    = "post"
 )
 
+// NFLService follows ESPN football; League is models.LeagueIdNFL or
+// models.LeagueIdNCAAF, and the zero value follows the NFL.
 type NFLService struct {
 	Client nfl.INFLAPIClient
+	League models.League
 }
 
 var logger = utils.GetLogger()
 
 func (s NFLService) GetLeagueName() string {
+	if s.league() == models.LeagueIdNCAAF {
+		return "NCAAF"
+	}
 	return "NFL"
 }
 
+func (s NFLService) league() models.League {
+	if s.League == 0 {
+		return models.LeagueIdNFL
+	}
+	return s.League
+}
+
 func (s NFLService) getSchedule() nfl.NFLScheduleResponse {
 	return s.Client.GetNFLSchedule()
 }
@@ -115,6 +128,12 @@ func (s NFLService) GetGamesByDate(date string, ret chan []models.Game) {
 }
 
 func (s NFLService) GetGameUpdate(game models.Game, ret chan models.GameUpdate) {
+	if fastcastIsCurrent(game.GameCode) {
+		// Fastcast pushed this game recently and already applied its
+		// changes; polling takes over again once the stream goes quiet.
+		ret <- models.GameUpdate{OldState: game.CurrentState, NewState: game.CurrentState}
+		return
+	}
 	s.getGameUpdateFromScoreboard(game, ret)
 }
 
@@ -181,7 +200,22 @@ func extractSummarySnapshot(sb nfl.NFLScoreboardResponse) nflSummarySnapshot {
 
 func (s NFLService) getGameUpdateFromScoreboard(game models.Game, ret chan models.GameUpdate) {
 	scoreboard := s.Client.GetNFLScoreBoard(game.GameCode)
+	newState := s.gameStateFromSummary(game, scoreboard)
 
+	ret <- withLastScoring(models.GameUpdate{
+		OldState: game.CurrentState,
+		NewState: newState,
+		Events: append(
+			scoringPlaysSince(scoreboard.ScoringPlays, game.CurrentState, newState),
+			driveEventsSince(scoreboard, game.CurrentState, newState)...,
+		),
+	})
+}
+
+// gameStateFromSummary builds the game's new state from a summary document,
+// whether it was polled or assembled from a Fastcast push. The current state
+// is returned unchanged when the summary carries no competitors.
+func (s NFLService) gameStateFromSummary(game models.Game, scoreboard nfl.NFLScoreboardResponse) models.GameState {
 	// Extract game info from scoreboard
 	var newState models.GameState = game.CurrentState
 	snap := extractSummarySnapshot(scoreboard)
@@ -226,10 +260,12 @@ func (s NFLService) getGameUpdateFromScoreboard(game models.Game, ret chan model
 			Home: models.TeamState{
 				Team:  s.teamFromCompetitor(homeTeam),
 				Score: homeScore,
+				Rank:  homeTeam.CuratedRank.Rank(),
 			},
 			Away: models.TeamState{
 				Team:  s.teamFromCompetitor(awayTeam),
 				Score: awayScore,
+				Rank:  awayTeam.CuratedRank.Rank(),
 			},
 			Status:     derivedStatus,
 			Period:     snap.Period,
@@ -258,6 +294,11 @@ func (s NFLService) getGameUpdateFromScoreboard(game models.Game, ret chan model
 			},
 		}
 
+		keepCollegeInfo(&newState.Home, game.CurrentState.Home)
+		keepCollegeInfo(&newState.Away, game.CurrentState.Away)
+
+		newState.Drive = driveFromSummary(scoreboard, newState, game.CurrentState.Drive)
+
 		// Label halftime when appropriate
 		if strings.Contains(strings.ToLower(snap.ShortDetail), "halftime") ||
 			(newState.Period == 2 && newState.Clock == "0:00") {
@@ -283,10 +324,7 @@ func (s NFLService) getGameUpdateFromScoreboard(game models.Game, ret chan model
 		}
 	}
 
-	ret <- models.GameUpdate{
-		OldState: game.CurrentState,
-		NewState: newState,
-	}
+	return newState
 }
 
 // parseSituationShortDetail parses strings like "1st & 10 at CLE 25" into components
@@ -321,11 +359,24 @@ func parseSituationShortDetail(s string) (down int, distance int, possession str
 
 func (s NFLService) teamFromCompetitor(competitor nfl.NFLScoreboardCompetitor) models.Team {
 	return models.Team{
-		TeamName: competitor.Team.DisplayName,
-		TeamCode: competitor.Team.Abbreviation,
-		ExtID:    competitor.Team.ID,
-		LeagueID: models.LeagueIdNFL,
-		LogoURL:  competitor.Team.Logo,
+		TeamName:   competitor.Team.DisplayName,
+		TeamCode:   competitor.Team.Abbreviation,
+		ExtID:      competitor.Team.ID,
+		LeagueID:   int(s.league()),
+		LogoURL:    competitor.Team.Logo,
+		Conference: nfl.ConferenceName(competitor.Team.ConferenceID),
+	}
+}
+
+// keepCollegeInfo carries a team's ranking and conference over from its
+// previous state when the summary leaves them out; neither changes during
+// a game.
+func keepCollegeInfo(team *models.TeamState, previous models.TeamState) {
+	if team.Rank == 0 {
+		team.Rank = previous.Rank
+	}
+	if team.Team.Conference == "" && team.Team.TeamCode == previous.Team.TeamCode {
+		team.Team.Conference = previous.Team.Conference
 	}
 }
 
@@ -486,10 +537,12 @@ func (s NFLService) gameFromEvent(event nfl.NFLScheduleEvent) models.Game {
 			Home: models.TeamState{
 				Team:  s.teamFromScheduleCompetitor(homeTeam),
 				Score: homeScore,
+				Rank:  homeTeam.CuratedRank.Rank(),
 			},
 			Away: models.TeamState{
 				Team:  s.teamFromScheduleCompetitor(awayTeam),
 				Score: awayScore,
+				Rank:  awayTeam.CuratedRank.Rank(),
 			},
 			Status: func() models.GameStatus {
 				st := gameStatusFromEvent(event)
@@ -507,7 +560,7 @@ func (s NFLService) gameFromEvent(event nfl.NFLScheduleEvent) models.Game {
 		},
 		GameCode:     event.ID,
 		ExtTimestamp: event.Date,
-		LeagueId:     models.LeagueIdNFL,
+		LeagueId:     s.league(),
 		GameDetails: models.GameDetails{
 			GameId:     event.ID,
 			Season:     strconv.Itoa(event.Season.Year),
@@ -533,7 +586,7 @@ func (s NFLService) GameFromScoreboard(eventID string) models.Game {
 		// Fallback: minimal game using IDs only
 		return models.Game{
 			GameCode: eventID,
-			LeagueId: models.LeagueIdNFL,
+			LeagueId: s.league(),
 		}
 	}
 	var awayC, homeC nfl.NFLScoreboardCompetitor
@@ -597,8 +650,8 @@ func (s NFLService) GameFromScoreboard(eventID string) models.Game {
 
 	g := models.Game{
 		CurrentState: models.GameState{
-			Home:       models.TeamState{Team: s.teamFromCompetitor(homeC), Score: homeScore},
-			Away:       models.TeamState{Team: s.teamFromCompetitor(awayC), Score: awayScore},
+			Home:       models.TeamState{Team: s.teamFromCompetitor(homeC), Score: homeScore, Rank: homeC.CuratedRank.Rank()},
+			Away:       models.TeamState{Team: s.teamFromCompetitor(awayC), Score: awayScore, Rank: awayC.CuratedRank.Rank()},
 			Status:     st,
 			FetchedAt:  time.Now(),
 			Period:     period,
@@ -608,7 +661,7 @@ func (s NFLService) GameFromScoreboard(eventID string) models.Game {
 			Details:    details,
 		},
 		GameCode: eventID,
-		LeagueId: models.LeagueIdNFL,
+		LeagueId: s.league(),
 		GameDetails: models.GameDetails{
 			GameId:     eventID,
 			Season:     strconv.Itoa(snap.SeasonYear),
@@ -628,11 +681,12 @@ func (s NFLService) GameFromScoreboard(eventID string) models.Game {
 
 func (s NFLService) teamFromScheduleCompetitor(competitor nfl.NFLCompetitor) models.Team {
 	return models.Team{
-		TeamName: competitor.Team.DisplayName,
-		TeamCode: competitor.Team.Abbreviation,
-		ExtID:    competitor.Team.ID,
-		LeagueID: models.LeagueIdNFL,
-		LogoURL:  competitor.Team.Logo,
+		TeamName:   competitor.Team.DisplayName,
+		TeamCode:   competitor.Team.Abbreviation,
+		ExtID:      competitor.Team.ID,
+		LeagueID:   int(s.league()),
+		LogoURL:    competitor.Team.Logo,
+		Conference: nfl.ConferenceName(competitor.Team.ConferenceID),
 	}
 }
 
@@ -668,30 +722,9 @@ func gameStatusFromEventStatus(status struct {
 
 func (s NFLService) GetEvents(update models.GameUpdate, ret chan []models.Event) {
 	events := append(
-		s.getTouchdownEvents(update.OldState.Home, update.NewState.Home, update.OldState.Away.Team),
-		s.getTouchdownEvents(update.OldState.Away, update.NewState.Away, update.OldState.Home.Team)...,
+		s.getScoringEvents(update, update.OldState.Home, update.NewState.Home, update.OldState.Away.Team),
+		s.getScoringEvents(update, update.OldState.Away, update.NewState.Away, update.OldState.Home.Team)...,
 	)
+	events = append(events, s.getDriveEvents(update)...)
 	ret <- events
 }
-
-func (s NFLService) getTouchdownEvents(oldState models.TeamState, newState models.TeamState, opponent models.Team) []models.Event {
-	events := []models.Event{}
-	diff := newState.Score - oldState.Score
-	team := newState.Team
-
-	// In NFL, we track touchdowns (7 points) and field goals (3 points)
-	// For simplicity, we'll create events for each point scored
-	for i := 0; i < diff; i++ {
-		events = append(events, models.Event{
-			TeamCode:     team.TeamCode,
-			TeamName:     team.TeamName,
-			TeamHash:     team.GetTeamHash(),
-			LeagueId:     models.LeagueIdNFL,
-			LeagueName:   s.GetLeagueName(),
-			OpponentCode: opponent.TeamCode,
-			OpponentName: opponent.TeamName,
-			OpponentHash: opponent.GetTeamHash(),
-		})
-	}
-	return events
-}
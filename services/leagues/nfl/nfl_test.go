@@ -111,8 +111,8 @@ func TestNFLService_GetEvents_ScoringDiff(t *testing.T) {
 	ch := make(chan []models.Event)
 	go svc.GetEvents(upd, ch)
 	ev := <-ch
-	if len(ev) != 3 { // 3 point diff emits 3 events (per current simple logic)
-		t.Fatalf("expected 3 events for 3-point diff, got %d", len(ev))
+	if len(ev) != 1 || ev[0].Type != models.EventTypeFieldGoal { // 3 point diff with no scoring plays reads as a field goal
+		t.Fatalf("expected 1 field goal event for 3-point diff, got %+v", ev)
 	}
 }
 
@@ -125,11 +125,10 @@ func TestNFLService_GameFromScoreboard_FallbackWhenEmpty(t *testing.T) {
 	}
 }
 
-// TestNFLService_GetEvents_SevenPointSwingEmitsSevenGoalEvents pins down the
-// README's documented behavior: NFL goal detection is a raw score diff, not a
-// play-by-play feed, so a touchdown-plus-conversion (7 points) fires seven
-// separate events in one tick rather than one "touchdown" event.
-func TestNFLService_GetEvents_SevenPointSwingEmitsSevenGoalEvents(t *testing.T) {
+// TestNFLService_GetEvents_SevenPointSwingEmitsOneTouchdown pins down that a
+// touchdown-plus-conversion fires a single touchdown event rather than one
+// event per point, even when the summary has no scoring plays to read.
+func TestNFLService_GetEvents_SevenPointSwingEmitsOneTouchdown(t *testing.T) {
 	svc := NFLService{}
 	upd := models.GameUpdate{
 		OldState: models.GameState{
@@ -145,20 +144,17 @@ func TestNFLService_GetEvents_SevenPointSwingEmitsSevenGoalEvents(t *testing.T)
 	go svc.GetEvents(upd, ch)
 	events := <-ch
 
-	if len(events) != 7 {
-		t.Fatalf("expected a 7-point score swing to emit 7 separate events, got %d", len(events))
-	}
-	for i, ev := range events {
-		if ev.TeamCode != "BUF" {
-			t.Fatalf("event %d: expected scoring team BUF, got %s", i, ev.TeamCode)
-		}
-		// NFL does not distinguish touchdown/field goal/safety: every event from
-		// the raw score diff leaves Type and Description unset.
-		if ev.Type != "" {
-			t.Fatalf("event %d: expected empty Type (NFL doesn't know what scored), got %q", i, ev.Type)
-		}
-		if ev.Description != "" {
-			t.Fatalf("event %d: expected empty Description, got %q", i, ev.Description)
-		}
+	if len(events) != 1 {
+		t.Fatalf("expected a 7-point score swing to emit 1 event, got %d", len(events))
+	}
+	ev := events[0]
+	if ev.TeamCode != "BUF" || ev.Type != models.EventTypeTouchdown {
+		t.Fatalf("expected a BUF touchdown, got %s %q", ev.TeamCode, ev.Type)
+	}
+	if ev.Details.Conversion != models.ConversionExtraPoint || ev.Details.Points != 7 {
+		t.Fatalf("expected the extra point to be counted, got %+v", ev.Details)
+	}
+	if ev.Description != "Touchdown and extra point for BUF" {
+		t.Fatalf("unexpected description %q", ev.Description)
 	}
 }
@@ -0,0 +1,303 @@
+package nfl
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"goalfeed/clients/leagues/nfl"
+	"goalfeed/models"
+)
+
+// conversionText is the bracketed attempt after a touchdown, e.g.
+// "(Harrison Butker Kick)" or "(Two-Point Pass Conversion Failed)".
+var conversionText = regexp.MustCompile(`\(([^()]*)\)\s*$`)
+
+// scorerName is the player a scoring play's text opens with, e.g. "Travis
+// Kelce" in "Travis Kelce 12 Yd pass from Patrick Mahomes (Harrison Butker Kick)".
+var scorerName = regexp.MustCompile(`^(.+?)\s+(?:\d+\s+(?:Yd|Yds|Yard|Yards)\b|Safety\b)`)
+
+// classifyScoringPlay reads the kind of score and any conversion from an ESPN
+// scoring play. points is what the play added to the scoring team's total,
+// which settles conversions the text doesn't spell out.
+func classifyScoringPlay(play nfl.NFLScoringPlay, points int) (string, string) {
+	kind := strings.ToLower(play.ScoringType.Name)
+	typeText := strings.ToLower(play.Type.Text)
+	switch {
+	case kind == "touchdown" || strings.Contains(typeText, "touchdown"):
+		scoringType := models.ScoringTypeTouchdown
+		if strings.Contains(typeText, "interception") || strings.Contains(typeText, "fumble return") {
+			scoringType = models.ScoringTypeDefensiveTouchdown
+		}
+		return scoringType, touchdownConversion(play.Text, points)
+	case kind == "field-goal" || strings.Contains(typeText, "field goal"):
+		return models.ScoringTypeFieldGoal, ""
+	case kind == "safety" || strings.Contains(typeText, "safety"):
+		return models.ScoringTypeSafety, ""
+	}
+	scoringType, conversion, _ := classifyPoints(points, "")
+	return scoringType, conversion
+}
+
+// touchdownConversion reads the try after a touchdown from the play text,
+// falling back to the points the play added when the text has none.
+func touchdownConversion(text string, points int) string {
+	if m := conversionText.FindStringSubmatch(text); m != nil {
+		attempt := strings.ToLower(m[1])
+		failed := strings.Contains(attempt, "fail") || strings.Contains(attempt, "no good") ||
+			strings.Contains(attempt, "blocked") || strings.Contains(attempt, "missed")
+		switch {
+		case strings.Contains(attempt, "two-point") || strings.Contains(attempt, "two point"):
+			if failed {
+				return models.ConversionTwoPointFailed
+			}
+			return models.ConversionTwoPoint
+		case strings.Contains(attempt, "kick") || strings.Contains(attempt, "pat"):
+			if failed {
+				return models.ConversionExtraPointMissed
+			}
+			return models.ConversionExtraPoint
+		}
+	}
+	_, conversion, _ := classifyPoints(points, "")
+	return conversion
+}
+
+// classifyPoints guesses the score behind a points increase when there's no
+// scoring play to read, and returns the points it accounts for. last is the
+// team's latest score: two points straight after its touchdown are the
+// two-point conversion rather than a safety. A single point is the try after
+// a touchdown that was already reported, so it has no scoring type of its own.
+func classifyPoints(points int, last string) (string, string, int) {
+	afterTouchdown := last == models.ScoringTypeTouchdown || last == models.ScoringTypeDefensiveTouchdown
+	switch {
+	case points == 8:
+		return models.ScoringTypeTouchdown, models.ConversionTwoPoint, 8
+	case points >= 7:
+		return models.ScoringTypeTouchdown, models.ConversionExtraPoint, 7
+	case points == 6:
+		return models.ScoringTypeTouchdown, "", 6
+	case points >= 3:
+		return models.ScoringTypeFieldGoal, "", 3
+	case points == 2 && afterTouchdown:
+		return models.ScoringTypeConvert, models.ConversionTwoPoint, 2
+	case points == 2:
+		return models.ScoringTypeSafety, "", 2
+	case points == 1 && afterTouchdown:
+		return "", models.ConversionExtraPoint, 1
+	default:
+		return "", "", points
+	}
+}
+
+// scoredAs is what a score leaves as the team's LastScoringType: a
+// touchdown whose try is settled counts as the convert, so points after it
+// aren't taken for the try again.
+func scoredAs(scoringType string, conversion string) string {
+	if conversion != "" {
+		return models.ScoringTypeConvert
+	}
+	return scoringType
+}
+
+func scoringEventType(scoringType string) models.EventType {
+	switch scoringType {
+	case models.ScoringTypeFieldGoal:
+		return models.EventTypeFieldGoal
+	case models.ScoringTypeSafety:
+		return models.EventTypeSafety
+	case models.ScoringTypeConvert:
+		return models.EventTypeConvert
+	default:
+		return models.EventTypeTouchdown
+	}
+}
+
+func scoringDescription(scoringType string, conversion string) string {
+	switch scoringType {
+	case models.ScoringTypeDefensiveTouchdown:
+		return "Defensive touchdown"
+	case models.ScoringTypeFieldGoal:
+		return "Field goal"
+	case models.ScoringTypeSafety:
+		return "Safety"
+	case models.ScoringTypeConvert:
+		return "Two-point conversion"
+	}
+	switch conversion {
+	case models.ConversionExtraPoint:
+		return "Touchdown and extra point"
+	case models.ConversionTwoPoint:
+		return "Touchdown and two-point conversion"
+	default:
+		return "Touchdown"
+	}
+}
+
+// scoringPlaysSince returns the scoring plays made after old, up to the
+// score in current, as game events. A play is new when the scoring team's
+// total before it was at least its old score, so a touchdown reported before
+// its extra point isn't reported again once the point is added. Plays ahead
+// of the current score wait for the score to catch up.
+func scoringPlaysSince(plays []nfl.NFLScoringPlay, old models.GameState, current models.GameState) []models.GameEvent {
+	var events []models.GameEvent
+	prevHome, prevAway := 0, 0
+	for _, play := range plays {
+		homePoints, awayPoints := play.HomeScore-prevHome, play.AwayScore-prevAway
+		beforeHome, beforeAway := prevHome, prevAway
+		prevHome, prevAway = play.HomeScore, play.AwayScore
+
+		home := scoringPlayIsHome(play, current, homePoints, awayPoints)
+		team, points, before, after, oldScore, newScore := current.Away.Team, awayPoints, beforeAway, play.AwayScore, old.Away.Score, current.Away.Score
+		if home {
+			team, points, before, after, oldScore, newScore = current.Home.Team, homePoints, beforeHome, play.HomeScore, old.Home.Score, current.Home.Score
+		}
+		if points <= 0 || before < oldScore || after > newScore {
+			continue
+		}
+
+		scoringType, conversion := classifyScoringPlay(play, points)
+		if scoringType == "" {
+			continue
+		}
+		player := models.Player{Team: team}
+		if m := scorerName.FindStringSubmatch(play.Text); m != nil && !strings.EqualFold(m[1], "team") {
+			player.Name = strings.TrimSpace(m[1])
+		}
+		events = append(events, models.GameEvent{
+			Id:          play.ID,
+			Type:        scoringEventType(scoringType),
+			Period:      play.Period.Number,
+			Clock:       play.Clock.DisplayValue,
+			Description: play.Text,
+			Team:        team,
+			Player:      player,
+			Details: models.EventDetails{
+				ScoringType: scoringType,
+				Conversion:  conversion,
+				Points:      points,
+			},
+		})
+	}
+	return events
+}
+
+// scoringPlayIsHome matches a scoring play to a side by its team, or by
+// whose score it changed when the team isn't given.
+func scoringPlayIsHome(play nfl.NFLScoringPlay, state models.GameState, homePoints int, awayPoints int) bool {
+	switch {
+	case play.Team.Abbreviation != "" && strings.EqualFold(play.Team.Abbreviation, state.Home.Team.TeamCode),
+		play.Team.ID != "" && play.Team.ID == state.Home.Team.ExtID:
+		return true
+	case play.Team.Abbreviation != "" && strings.EqualFold(play.Team.Abbreviation, state.Away.Team.TeamCode),
+		play.Team.ID != "" && play.Team.ID == state.Away.Team.ExtID:
+		return false
+	default:
+		return homePoints > awayPoints
+	}
+}
+
+// scoringPlaysFromPoints stands in for scoring plays the summary didn't
+// list yet, classifying the team's unexplained points by size and by its
+// last score. It also returns the team's last score afterwards.
+func scoringPlaysFromPoints(points int, last string, team models.Team, state models.GameState) ([]models.GameEvent, string) {
+	var events []models.GameEvent
+	for points > 0 {
+		scoringType, conversion, used := classifyPoints(points, last)
+		points -= used
+		if scoringType != "" || conversion != "" {
+			last = scoredAs(scoringType, conversion)
+		}
+		if scoringType == "" {
+			continue
+		}
+		events = append(events, models.GameEvent{
+			Type:   scoringEventType(scoringType),
+			Period: state.Period,
+			Clock:  state.Clock,
+			Team:   team,
+			Details: models.EventDetails{
+				ScoringType: scoringType,
+				Conversion:  conversion,
+				Points:      used,
+			},
+		})
+	}
+	return events, last
+}
+
+// teamScoringPlays is the team's scoring plays in an update: those the
+// update carries, then stand-ins for any points they don't explain. It also
+// returns the team's last score once they're counted.
+func teamScoringPlays(update models.GameUpdate, oldState models.TeamState, newState models.TeamState) ([]models.GameEvent, string) {
+	last := oldState.LastScoringType
+	diff := newState.Score - oldState.Score
+	if diff <= 0 {
+		return nil, last
+	}
+	team := newState.Team
+
+	var plays []models.GameEvent
+	explained := 0
+	for _, play := range update.Events {
+		if play.Team.TeamCode == team.TeamCode && play.Details.Points > 0 {
+			plays = append(plays, play)
+			explained += play.Details.Points
+			last = scoredAs(play.Details.ScoringType, play.Details.Conversion)
+		}
+	}
+	if explained < diff {
+		var guessed []models.GameEvent
+		guessed, last = scoringPlaysFromPoints(diff-explained, last, team, update.NewState)
+		plays = append(plays, guessed...)
+	}
+	return plays, last
+}
+
+// withLastScoring records each team's last score on the update's new state,
+// for classifying the points of the next one.
+func withLastScoring(update models.GameUpdate) models.GameUpdate {
+	_, update.NewState.Home.LastScoringType = teamScoringPlays(update, update.OldState.Home, update.NewState.Home)
+	_, update.NewState.Away.LastScoringType = teamScoringPlays(update, update.OldState.Away, update.NewState.Away)
+	return update
+}
+
+// getScoringEvents fires one event per scoring play by the team, using the
+// plays the update carries and classifying any points they don't explain.
+func (s NFLService) getScoringEvents(update models.GameUpdate, oldState models.TeamState, newState models.TeamState, opponent models.Team) []models.Event {
+	events := []models.Event{}
+	team := newState.Team
+	plays, _ := teamScoringPlays(update, oldState, newState)
+
+	for _, play := range plays {
+		description := play.Description
+		if description == "" {
+			description = fmt.Sprintf("%s for %s", scoringDescription(play.Details.ScoringType, play.Details.Conversion), team.TeamCode)
+		}
+		events = append(events, models.Event{
+			Id:           play.Id,
+			Type:         play.Type,
+			Description:  description,
+			TeamCode:     team.TeamCode,
+			TeamName:     team.TeamName,
+			TeamHash:     team.GetTeamHash(),
+			PlayerName:   play.Player.Name,
+			LeagueId:     int(s.league()),
+			LeagueName:   s.GetLeagueName(),
+			Period:       play.Period,
+			PeriodType:   update.NewState.PeriodType,
+			Clock:        play.Clock,
+			OpponentCode: opponent.TeamCode,
+			OpponentName: opponent.TeamName,
+			OpponentHash: opponent.GetTeamHash(),
+			Details:      play.Details,
+			Score: models.ScoreUpdate{
+				HomeScore: update.NewState.Home.Score,
+				AwayScore: update.NewState.Away.Score,
+				HomeTeam:  update.NewState.Home.Team.TeamCode,
+				AwayTeam:  update.NewState.Away.Team.TeamCode,
+			},
+		})
+	}
+	return events
+}
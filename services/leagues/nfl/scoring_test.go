@@ -0,0 +1,142 @@
+package nfl
+
+import (
+	"testing"
+
+	"goalfeed/clients/leagues/nfl"
+	"goalfeed/models"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func testScoringPlay(id string, typeText string, kind string, text string, team string, away int, home int) nfl.NFLScoringPlay {
+	play := nfl.NFLScoringPlay{ID: id, Text: text, AwayScore: away, HomeScore: home}
+	play.Type.Text = typeText
+	play.ScoringType.Name = kind
+	play.Team.Abbreviation = team
+	play.Period.Number = 2
+	play.Clock.DisplayValue = "4:12"
+	return play
+}
+
+func scoringTestState(away int, home int) models.GameState {
+	return models.GameState{
+		Home:       models.TeamState{Team: models.Team{TeamCode: "KC", TeamName: "Kansas City Chiefs"}, Score: home},
+		Away:       models.TeamState{Team: models.Team{TeamCode: "BUF", TeamName: "Buffalo Bills"}, Score: away},
+		Status:     models.StatusActive,
+		PeriodType: "QUARTER",
+	}
+}
+
+var scoringTestPlays = []nfl.NFLScoringPlay{
+	testScoringPlay("1", "Passing Touchdown", "touchdown", "Travis Kelce 12 Yd pass from Patrick Mahomes (Harrison Butker Kick)", "KC", 0, 7),
+	testScoringPlay("2", "Field Goal Good", "field-goal", "Tyler Bass 45 Yd Field Goal", "BUF", 3, 7),
+	testScoringPlay("3", "Rushing Touchdown", "touchdown", "James Cook 3 Yd Run (Two-Point Run Conversion Failed)", "BUF", 9, 7),
+	testScoringPlay("4", "Safety", "safety", "Team Safety", "KC", 9, 9),
+	testScoringPlay("5", "Interception Return Touchdown", "touchdown", "Trent McDuffie 38 Yd Interception Return (Josh Allen Pass to Dalton Kincaid for Two-Point Conversion)", "KC", 9, 17),
+}
+
+func TestScoringPlaysSince_ClassifiesEachPlay(t *testing.T) {
+	events := scoringPlaysSince(scoringTestPlays, scoringTestState(0, 0), scoringTestState(9, 17))
+	if !assert.Len(t, events, 5) {
+		return
+	}
+
+	assert.Equal(t, models.EventTypeTouchdown, events[0].Type)
+	assert.Equal(t, models.ConversionExtraPoint, events[0].Details.Conversion)
+	assert.Equal(t, 7, events[0].Details.Points)
+	assert.Equal(t, "Travis Kelce", events[0].Player.Name)
+	assert.Equal(t, "KC", events[0].Team.TeamCode)
+
+	assert.Equal(t, models.EventTypeFieldGoal, events[1].Type)
+	assert.Equal(t, "Tyler Bass", events[1].Player.Name)
+	assert.Equal(t, 3, events[1].Details.Points)
+
+	assert.Equal(t, models.ConversionTwoPointFailed, events[2].Details.Conversion)
+	assert.Equal(t, 6, events[2].Details.Points)
+
+	assert.Equal(t, models.EventTypeSafety, events[3].Type)
+	assert.Empty(t, events[3].Player.Name)
+
+	assert.Equal(t, models.ScoringTypeDefensiveTouchdown, events[4].Details.ScoringType)
+	assert.Equal(t, models.ConversionTwoPoint, events[4].Details.Conversion)
+	assert.Equal(t, 8, events[4].Details.Points)
+}
+
+func TestScoringPlaysSince_SkipsReportedAndFuturePlays(t *testing.T) {
+	// Only the field goal is between the old score and the current one; the
+	// touchdown after it isn't in the score yet
+	events := scoringPlaysSince(scoringTestPlays, scoringTestState(0, 7), scoringTestState(3, 7))
+	if assert.Len(t, events, 1) {
+		assert.Equal(t, "2", events[0].Id)
+	}
+
+	// A touchdown reported at 6 isn't reported again when its extra point lands
+	events = scoringPlaysSince(scoringTestPlays[:1], scoringTestState(0, 6), scoringTestState(0, 7))
+	assert.Empty(t, events)
+}
+
+func TestGetEvents_OneEventPerScoringPlay(t *testing.T) {
+	old, current := scoringTestState(0, 0), scoringTestState(9, 7)
+	update := models.GameUpdate{
+		OldState: old,
+		NewState: current,
+		Events:   scoringPlaysSince(scoringTestPlays, old, current),
+	}
+	ch := make(chan []models.Event, 1)
+	NFLService{}.GetEvents(update, ch)
+	events := <-ch
+
+	if assert.Len(t, events, 3) {
+		assert.Equal(t, "KC", events[0].TeamCode)
+		assert.Equal(t, "BUF", events[0].OpponentCode)
+		assert.Equal(t, "Travis Kelce", events[0].PlayerName)
+		assert.Equal(t, "Travis Kelce 12 Yd pass from Patrick Mahomes (Harrison Butker Kick)", events[0].Description)
+		assert.Equal(t, "4:12", events[0].Clock)
+		assert.Equal(t, models.EventTypeFieldGoal, events[1].Type)
+		assert.Equal(t, "BUF", events[1].TeamCode)
+		assert.Equal(t, models.EventTypeTouchdown, events[2].Type)
+		assert.Equal(t, 9, events[2].Score.AwayScore)
+	}
+}
+
+func TestGetEvents_PointsWithoutScoringPlays(t *testing.T) {
+	cases := []struct {
+		points int
+		types  []models.EventType
+	}{
+		{1, nil},
+		{2, []models.EventType{models.EventTypeSafety}},
+		{3, []models.EventType{models.EventTypeFieldGoal}},
+		{6, []models.EventType{models.EventTypeTouchdown}},
+		{8, []models.EventType{models.EventTypeTouchdown}},
+		{10, []models.EventType{models.EventTypeTouchdown, models.EventTypeFieldGoal}},
+	}
+	for _, tc := range cases {
+		update := models.GameUpdate{OldState: scoringTestState(0, 0), NewState: scoringTestState(0, tc.points)}
+		ch := make(chan []models.Event, 1)
+		NFLService{}.GetEvents(update, ch)
+		var types []models.EventType
+		for _, event := range <-ch {
+			types = append(types, event.Type)
+		}
+		assert.Equal(t, tc.types, types, "%d points", tc.points)
+	}
+}
+
+func TestGetEvents_TwoPointConversionInALaterPoll(t *testing.T) {
+	service := NFLService{}
+	state := scoringTestState(0, 0)
+	var types []models.EventType
+	for _, score := range []int{6, 8, 10} {
+		update := withLastScoring(models.GameUpdate{OldState: state, NewState: scoringTestState(0, score)})
+		ch := make(chan []models.Event, 1)
+		service.GetEvents(update, ch)
+		for _, event := range <-ch {
+			types = append(types, event.Type)
+		}
+		state = update.NewState
+	}
+	assert.Equal(t, []models.EventType{models.EventTypeTouchdown, models.EventTypeConvert, models.EventTypeSafety}, types,
+		"the +2 after the touchdown is its conversion; the next +2 is a safety")
+}
@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"goalfeed/clients/leagues/nhl"
 	"goalfeed/models"
+	"goalfeed/services/leagues/hockey"
 	"goalfeed/utils"
 	"strconv"
 	"strings"
@@ -126,20 +127,17 @@ func (s NHLService) gameFromScoreboard(gameId string) models.Game {
 	if scoreboard.PeriodDescriptor.Number > 0 {
 		period = scoreboard.PeriodDescriptor.Number
 		// Map period type: "REG" -> "REGULAR", "OT" -> "OVERTIME", etc.
-		switch scoreboard.PeriodDescriptor.PeriodType {
-		case "REG":
-			periodType = "REGULAR"
-		case "OT":
-			periodType = "OVERTIME"
-		case "SO":
-			periodType = "SHOOTOUT"
-		default:
-			periodType = "REGULAR"
-		}
+		periodType = hockey.PeriodTypeFromCode(scoreboard.PeriodDescriptor.PeriodType)
 	} else if scoreboard.GameState == "LIVE" {
 		// Fallback to period 1 if periodDescriptor is missing but game is live
 		period = 1
-		periodType = "REGULAR"
+		periodType = models.PeriodTypeRegular
+	}
+
+	// A final game's outcome says how it was decided, even if the period
+	// descriptor has already been reset
+	if scoreboard.GameOutcome.LastPeriodType != "" && gameStatusFromGameState(scoreboard.GameState) == models.StatusEnded {
+		periodType = hockey.PeriodTypeFromCode(scoreboard.GameOutcome.LastPeriodType)
 	}
 
 	// Extract clock time from clock object
@@ -149,29 +147,32 @@ func (s NHLService) gameFromScoreboard(gameId string) models.Game {
 		clock = "LIVE"
 	}
 
+	homeTeam := models.Team{
+		TeamName: scoreboard.HomeTeam.PlaceName.Default,
+		TeamCode: scoreboard.HomeTeam.Abbrev,
+		ExtID:    scoreboard.HomeTeam.Abbrev,
+		LeagueID: models.LeagueIdNHL,
+		LogoURL:  scoreboard.HomeTeam.Logo,
+	}
+	awayTeam := models.Team{
+		TeamName: scoreboard.AwayTeam.PlaceName.Default,
+		TeamCode: scoreboard.AwayTeam.Abbrev,
+		ExtID:    scoreboard.AwayTeam.Abbrev,
+		LeagueID: models.LeagueIdNHL,
+		LogoURL:  scoreboard.AwayTeam.Logo,
+	}
+
 	return models.Game{
 		CurrentState: models.GameState{
 			Home: models.TeamState{
-				Team: models.Team{
-					TeamName: scoreboard.HomeTeam.PlaceName.Default,
-					TeamCode: scoreboard.HomeTeam.Abbrev,
-					ExtID:    scoreboard.HomeTeam.Abbrev,
-					LeagueID: models.LeagueIdNHL,
-					LogoURL:  scoreboard.HomeTeam.Logo,
-				},
+				Team:  homeTeam,
 				Score: scoreboard.HomeTeam.Score,
 				Statistics: models.TeamStats{
 					Shots: scoreboard.HomeTeam.Sog,
 				},
 			},
 			Away: models.TeamState{
-				Team: models.Team{
-					TeamName: scoreboard.AwayTeam.PlaceName.Default,
-					TeamCode: scoreboard.AwayTeam.Abbrev,
-					ExtID:    scoreboard.AwayTeam.Abbrev,
-					LeagueID: models.LeagueIdNHL,
-					LogoURL:  scoreboard.AwayTeam.Logo,
-				},
+				Team:  awayTeam,
 				Score: scoreboard.AwayTeam.Score,
 				Statistics: models.TeamStats{
 					Shots: scoreboard.AwayTeam.Sog,
@@ -186,6 +187,7 @@ func (s NHLService) gameFromScoreboard(gameId string) models.Game {
 			Venue: models.Venue{
 				Name: scoreboard.Venue.Default,
 			},
+			Shootout: shootoutFromScoreboard(scoreboard, homeTeam, awayTeam, periodType),
 		},
 		GameCode: strconv.Itoa(scoreboard.ID),
 		LeagueId: models.LeagueIdNHL,
@@ -211,20 +213,17 @@ func (s NHLService) getGameUpdateFromScoreboard(game models.Game, ret chan model
 	if scoreboard.PeriodDescriptor.Number > 0 {
 		period = scoreboard.PeriodDescriptor.Number
 		// Map period type: "REG" -> "REGULAR", "OT" -> "OVERTIME", etc.
-		switch scoreboard.PeriodDescriptor.PeriodType {
-		case "REG":
-			periodType = "REGULAR"
-		case "OT":
-			periodType = "OVERTIME"
-		case "SO":
-			periodType = "SHOOTOUT"
-		default:
-			periodType = "REGULAR"
-		}
+		periodType = hockey.PeriodTypeFromCode(scoreboard.PeriodDescriptor.PeriodType)
 	} else if scoreboard.GameState == "LIVE" {
 		// Fallback to period 1 if periodDescriptor is missing but game is live
 		period = 1
-		periodType = "REGULAR"
+		periodType = models.PeriodTypeRegular
+	}
+
+	// A final game's outcome says how it was decided, even if the period
+	// descriptor has already been reset
+	if scoreboard.GameOutcome.LastPeriodType != "" && gameStatusFromGameState(scoreboard.GameState) == models.StatusEnded {
+		periodType = hockey.PeriodTypeFromCode(scoreboard.GameOutcome.LastPeriodType)
 	}
 
 	// Extract clock time from clock object
@@ -257,11 +256,199 @@ func (s NHLService) getGameUpdateFromScoreboard(game models.Game, ret chan model
 		Venue: models.Venue{
 			Name: scoreboard.Venue.Default,
 		},
+		Shootout: shootoutFromScoreboard(scoreboard, game.CurrentState.Home.Team, game.CurrentState.Away.Team, periodType),
+	}
+
+	// The play-by-play is only read when it's needed: for the goals behind a
+	// score change, and for the goalies late in a live game, when a team
+	// might pull one
+	var plays []models.GameEvent
+	late := newState.Status == models.StatusActive && (period >= 3 || periodType != models.PeriodTypeRegular)
+	scored := newState.Home.Score != game.CurrentState.Home.Score || newState.Away.Score != game.CurrentState.Away.Score
+	if newState.Status != models.StatusUpcoming && (scored || late) {
+		playByPlay := s.Client.GetNHLPlayByPlay(game.GameCode)
+		plays = scoringPlaysFromPlayByPlay(playByPlay, newState.Home.Team, newState.Away.Team)
+		if late {
+			// Mid delayed penalty, the goalies stay as they were
+			newState.Home.GoalieOutPolls = game.CurrentState.Home.GoalieOutPolls
+			newState.Away.GoalieOutPolls = game.CurrentState.Away.GoalieOutPolls
+			if situation, ok := latestSituation(playByPlay); ok && !delayedPenaltyPending(playByPlay) {
+				newState.Home.GoalieOutPolls = goalieOutPolls(game.CurrentState.Home, situation.homeGoalie == 0)
+				newState.Away.GoalieOutPolls = goalieOutPolls(game.CurrentState.Away, situation.awayGoalie == 0)
+			}
+		}
 	}
+	newState.Home.GoaliePulled = newState.Home.GoalieOutPolls >= goaliePulledPolls
+	newState.Away.GoaliePulled = newState.Away.GoalieOutPolls >= goaliePulledPolls
 	ret <- models.GameUpdate{
 		OldState: game.CurrentState,
 		NewState: newState,
+		Events:   plays,
+	}
+}
+
+// situation is a decoded play-by-play situationCode.
+type situation struct {
+	awayGoalie, awaySkaters, homeSkaters, homeGoalie int
+}
+
+func parseSituationCode(code string) (situation, bool) {
+	if len(code) != 4 {
+		return situation{}, false
+	}
+	digits := [4]int{}
+	for i, c := range code {
+		if c < '0' || c > '9' {
+			return situation{}, false
+		}
+		digits[i] = int(c - '0')
+	}
+	return situation{awayGoalie: digits[0], awaySkaters: digits[1], homeSkaters: digits[2], homeGoalie: digits[3]}, true
+}
+
+// latestSituation returns the on-ice situation as of the most recent play
+// that reports one.
+func latestSituation(playByPlay nhl.NHLPlayByPlayResponse) (situation, bool) {
+	for i := len(playByPlay.Plays) - 1; i >= 0; i-- {
+		if sit, ok := parseSituationCode(playByPlay.Plays[i].SituationCode); ok {
+			return sit, true
+		}
 	}
+	return situation{}, false
+}
+
+// goaliePulledPolls is how many polls in a row have to see a goalie out
+// before it's reported pulled, so a goalie off for a moment isn't.
+const goaliePulledPolls = 2
+
+// goalieOutPolls counts the polls in a row that have seen a team's goalie
+// out, from the team's last state.
+func goalieOutPolls(last models.TeamState, out bool) int {
+	if !out {
+		return 0
+	}
+	return last.GoalieOutPolls + 1
+}
+
+// delayedPenaltyPending reports whether a delayed penalty has been called
+// and play hasn't stopped for it yet. The team about to go on the power
+// play has usually pulled its goalie until then, which isn't the pull for
+// an extra attacker that goalie_pulled is about.
+func delayedPenaltyPending(playByPlay nhl.NHLPlayByPlayResponse) bool {
+	for i := len(playByPlay.Plays) - 1; i >= 0; i-- {
+		switch playByPlay.Plays[i].TypeDescKey {
+		case "delayed-penalty":
+			return true
+		case "penalty", "stoppage", "faceoff", "goal", "period-end":
+			return false
+		}
+	}
+	return false
+}
+
+// goalTypeFromSituation classifies a goal by the situation it was scored in.
+// An empty net takes precedence over manpower. A scoring team with its own
+// goalie pulled counts its extra attacker out, so 6-on-5 is even strength.
+func goalTypeFromSituation(sit situation, scoredByHome bool) string {
+	scoring, scoringGoalie, defending, defendingGoalie := sit.awaySkaters, sit.awayGoalie, sit.homeSkaters, sit.homeGoalie
+	if scoredByHome {
+		scoring, scoringGoalie, defending, defendingGoalie = sit.homeSkaters, sit.homeGoalie, sit.awaySkaters, sit.awayGoalie
+	}
+	if scoringGoalie == 0 {
+		scoring--
+	}
+	switch {
+	case defendingGoalie == 0:
+		return models.GoalTypeEmptyNet
+	case scoring > defending:
+		return models.GoalTypePowerPlay
+	case scoring < defending:
+		return models.GoalTypeShortHanded
+	default:
+		return models.GoalTypeEvenStrength
+	}
+}
+
+// scoringPlaysFromPlayByPlay lists the game's goals in order, leaving out
+// shootout attempts.
+func scoringPlaysFromPlayByPlay(playByPlay nhl.NHLPlayByPlayResponse, home, away models.Team) []models.GameEvent {
+	players := make(map[int]nhl.NHLRosterSpot, len(playByPlay.RosterSpots))
+	for _, spot := range playByPlay.RosterSpots {
+		players[spot.PlayerID] = spot
+	}
+	player := func(id int) models.Player {
+		spot, ok := players[id]
+		if !ok {
+			return models.Player{}
+		}
+		return models.Player{
+			Id:       strconv.Itoa(id),
+			Name:     strings.TrimSpace(string(spot.FirstName) + " " + string(spot.LastName)),
+			Number:   spot.SweaterNumber,
+			Position: spot.PositionCode,
+		}
+	}
+
+	var plays []models.GameEvent
+	for _, play := range playByPlay.Plays {
+		if play.TypeDescKey != "goal" || hockey.PeriodTypeFromCode(play.PeriodDescriptor.PeriodType) == models.PeriodTypeShootout {
+			continue
+		}
+		var team models.Team
+		switch play.Details.EventOwnerTeamID {
+		case playByPlay.HomeTeam.ID:
+			team = home
+		case playByPlay.AwayTeam.ID:
+			team = away
+		default:
+			continue
+		}
+		details := models.EventDetails{
+			Assist1: player(play.Details.Assist1PlayerID),
+			Assist2: player(play.Details.Assist2PlayerID),
+		}
+		if sit, ok := parseSituationCode(play.SituationCode); ok {
+			details.GoalType = goalTypeFromSituation(sit, team.TeamCode == home.TeamCode)
+		}
+		plays = append(plays, models.GameEvent{
+			Id:      strconv.Itoa(play.EventID),
+			Type:    models.EventTypeGoal,
+			Period:  play.PeriodDescriptor.Number,
+			Time:    play.TimeInPeriod,
+			Clock:   play.TimeRemaining,
+			Team:    team,
+			Player:  player(play.Details.ScoringPlayerID),
+			Details: details,
+		})
+	}
+	return plays
+}
+
+// shootoutFromScoreboard maps the landing summary's shootout attempts onto
+// the game's teams. It returns nil until the game reaches a shootout.
+func shootoutFromScoreboard(scoreboard nhl.NHLScoreboardResponse, home, away models.Team, periodType string) *models.ShootoutState {
+	if periodType != models.PeriodTypeShootout && len(scoreboard.Summary.Shootout) == 0 {
+		return nil
+	}
+	attempts := make([]models.ShootoutAttempt, 0, len(scoreboard.Summary.Shootout))
+	winner := ""
+	for _, a := range scoreboard.Summary.Shootout {
+		teamCode := string(a.TeamAbbrev)
+		attempts = append(attempts, models.ShootoutAttempt{
+			Sequence: a.Sequence,
+			TeamCode: teamCode,
+			Player: models.Player{
+				Id:   strconv.Itoa(a.PlayerID),
+				Name: strings.TrimSpace(string(a.FirstName) + " " + string(a.LastName)),
+			},
+			Result: a.Result,
+		})
+		if a.GameWinner {
+			winner = teamCode
+		}
+	}
+	ended := gameStatusFromGameState(scoreboard.GameState) == models.StatusEnded
+	return hockey.ShootoutFromAttempts(attempts, home, away, winner, ended)
 }
 
 func (s NHLService) teamFromScheduleTeam(scheduleTeam nhl.NHLScheduleTeam) models.Team {
@@ -284,20 +471,11 @@ func (s NHLService) gameFromSchedule(scheduleGame nhl.NHLScheduleResponseGame) m
 	if scheduleGame.PeriodDescriptor.Number > 0 {
 		period = scheduleGame.PeriodDescriptor.Number
 		// Map period type: "REG" -> "REGULAR", "OT" -> "OVERTIME", etc.
-		switch scheduleGame.PeriodDescriptor.PeriodType {
-		case "REG":
-			periodType = "REGULAR"
-		case "OT":
-			periodType = "OVERTIME"
-		case "SO":
-			periodType = "SHOOTOUT"
-		default:
-			periodType = "REGULAR"
-		}
+		periodType = hockey.PeriodTypeFromCode(scheduleGame.PeriodDescriptor.PeriodType)
 	} else if scheduleGame.GameState == "LIVE" {
 		// Fallback to period 1 if periodDescriptor is missing but game is live
 		period = 1
-		periodType = "REGULAR"
+		periodType = models.PeriodTypeRegular
 		clock = "LIVE"
 	}
 
@@ -385,29 +563,5 @@ func gameStatusFromStatusCode(statusCode string) models.GameStatus {
 }
 
 func (s NHLService) GetEvents(update models.GameUpdate, ret chan []models.Event) {
-	events := append(
-		s.getGoalEvents(update.OldState.Home, update.NewState.Home, update.OldState.Away.Team),
-		s.getGoalEvents(update.OldState.Away, update.NewState.Away, update.OldState.Home.Team)...,
-	)
-	ret <- events
-}
-
-func (s NHLService) getGoalEvents(oldState models.TeamState, newState models.TeamState, opponent models.Team) []models.Event {
-	events := []models.Event{}
-	diff := newState.Score - oldState.Score
-	team := newState.Team
-
-	for i := 0; i < diff; i++ {
-		events = append(events, models.Event{
-			TeamCode:     team.TeamCode,
-			TeamName:     team.TeamName,
-			TeamHash:     team.GetTeamHash(),
-			LeagueId:     models.LeagueIdNHL,
-			LeagueName:   s.GetLeagueName(),
-			OpponentCode: opponent.TeamCode,
-			OpponentName: opponent.TeamName,
-			OpponentHash: opponent.GetTeamHash(),
-		})
-	}
-	return events
+	ret <- hockey.GetEvents(update, models.LeagueIdNHL, s.GetLeagueName())
 }
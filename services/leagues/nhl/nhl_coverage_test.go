@@ -1,6 +1,7 @@
 package nhl
 
 import (
+	"encoding/json"
 	nhlClients "goalfeed/clients/leagues/nhl"
 	"goalfeed/models"
 	"testing"
@@ -14,6 +15,7 @@ type TestMockClientWithCustomScoreboard struct {
 	nhlClients.MockNHLApiClient
 	customScoreboard nhlClients.NHLScoreboardResponse
 	useCustom        bool
+	playByPlayReads  int
 }
 
 func (m *TestMockClientWithCustomScoreboard) GetNHLScoreBoard(gameId string) nhlClients.NHLScoreboardResponse {
@@ -23,6 +25,11 @@ func (m *TestMockClientWithCustomScoreboard) GetNHLScoreBoard(gameId string) nhl
 	return m.MockNHLApiClient.GetNHLScoreBoard(gameId)
 }
 
+func (m *TestMockClientWithCustomScoreboard) GetNHLPlayByPlay(gameId string) nhlClients.NHLPlayByPlayResponse {
+	m.playByPlayReads++
+	return m.MockNHLApiClient.GetNHLPlayByPlay(gameId)
+}
+
 func TestGameFromScoreboard_Overtime(t *testing.T) {
 	// Test gameFromScoreboard with overtime period type
 	mockClient := &TestMockClientWithCustomScoreboard{
@@ -422,3 +429,209 @@ func TestGetGameUpdateFromScoreboard_NoPeriodDescriptor(t *testing.T) {
 	assert.Equal(t, "REGULAR", update.NewState.PeriodType)
 	assert.Equal(t, "LIVE", update.NewState.Clock)
 }
+
+func TestGetGameUpdateFromScoreboard_ShootoutFinal(t *testing.T) {
+	// Landing payload for a game decided in a shootout: the final score
+	// includes the shootout point and summary.shootout lists every attempt.
+	var scoreboard nhlClients.NHLScoreboardResponse
+	err := json.Unmarshal([]byte(`{
+		"id": 2023020193,
+		"gameState": "FINAL",
+		"periodDescriptor": {"number": 5, "periodType": "SO"},
+		"gameOutcome": {"lastPeriodType": "SO"},
+		"homeTeam": {"abbrev": "BOS", "score": 3},
+		"awayTeam": {"abbrev": "NYI", "score": 2},
+		"summary": {"shootout": [
+			{"sequence": 1, "playerId": 1, "teamAbbrev": {"default": "NYI"}, "firstName": {"default": "Mathew"}, "lastName": {"default": "Barzal"}, "result": "save"},
+			{"sequence": 2, "playerId": 2, "teamAbbrev": "BOS", "firstName": "David", "lastName": "Pastrnak", "result": "goal", "gameWinner": true}
+		]}
+	}`), &scoreboard)
+	assert.NoError(t, err)
+
+	mockClient := &TestMockClientWithCustomScoreboard{useCustom: true, customScoreboard: scoreboard}
+	service := NHLService{Client: mockClient}
+	game := models.Game{
+		GameCode: "2023020193",
+		LeagueId: models.LeagueIdNHL,
+		CurrentState: models.GameState{
+			Home:       models.TeamState{Team: models.Team{TeamCode: "BOS"}, Score: 2},
+			Away:       models.TeamState{Team: models.Team{TeamCode: "NYI"}, Score: 2},
+			Status:     models.StatusActive,
+			Period:     4,
+			PeriodType: models.PeriodTypeOvertime,
+		},
+	}
+
+	updateChan := make(chan models.GameUpdate)
+	go service.GetGameUpdate(game, updateChan)
+	update := <-updateChan
+
+	assert.Equal(t, models.PeriodTypeShootout, update.NewState.PeriodType)
+	if assert.NotNil(t, update.NewState.Shootout) {
+		assert.Len(t, update.NewState.Shootout.Attempts, 2)
+		assert.Equal(t, "Mathew Barzal", update.NewState.Shootout.Attempts[0].Player.Name)
+		assert.Equal(t, "NYI", update.NewState.Shootout.Attempts[0].TeamCode)
+		assert.Equal(t, 1, update.NewState.Shootout.HomeGoals)
+		assert.Equal(t, "BOS", update.NewState.Shootout.Winner)
+	}
+
+	eventChan := make(chan []models.Event)
+	go service.GetEvents(update, eventChan)
+	for _, event := range <-eventChan {
+		assert.NotEqual(t, models.EventTypeGoal, event.Type, "shootout winner's extra point must not fire a goal")
+	}
+}
+
+func TestGetGameUpdateFromScoreboard_GoaliePulledAndEmptyNet(t *testing.T) {
+	var playByPlay nhlClients.NHLPlayByPlayResponse
+	err := json.Unmarshal([]byte(`{
+		"homeTeam": {"id": 52, "abbrev": "WPG"},
+		"awayTeam": {"id": 10, "abbrev": "TOR"},
+		"plays": [
+			{"eventId": 101, "periodDescriptor": {"number": 1, "periodType": "REG"}, "timeRemaining": "12:00",
+			 "situationCode": "1451", "typeDescKey": "goal", "details": {"eventOwnerTeamId": 52, "scoringPlayerId": 1}},
+			{"eventId": 150, "periodDescriptor": {"number": 3, "periodType": "REG"}, "timeRemaining": "01:40",
+			 "situationCode": "0651", "typeDescKey": "goal", "details": {"eventOwnerTeamId": 52, "scoringPlayerId": 2, "assist1PlayerId": 1}},
+			{"eventId": 151, "periodDescriptor": {"number": 3, "periodType": "REG"}, "timeRemaining": "01:40",
+			 "situationCode": "0651", "typeDescKey": "faceoff"}
+		],
+		"rosterSpots": [
+			{"teamId": 52, "playerId": 1, "firstName": {"default": "Mark"}, "lastName": {"default": "Scheifele"}, "sweaterNumber": 55},
+			{"teamId": 52, "playerId": 2, "firstName": {"default": "Kyle"}, "lastName": {"default": "Connor"}, "sweaterNumber": 81}
+		]
+	}`), &playByPlay)
+	assert.NoError(t, err)
+
+	mockClient := &TestMockClientWithCustomScoreboard{useCustom: true, customScoreboard: nhlClients.NHLScoreboardResponse{
+		GameState:        "LIVE",
+		PeriodDescriptor: nhlClients.PeriodDescriptor{Number: 3, PeriodType: "REG"},
+		HomeTeam:         nhlClients.NHLScheduleTeam{Score: 2},
+		AwayTeam:         nhlClients.NHLScheduleTeam{Score: 0},
+	}}
+	mockClient.SetPlayByPlay(playByPlay)
+	defer mockClient.SetPlayByPlay(nhlClients.NHLPlayByPlayResponse{})
+
+	service := NHLService{Client: mockClient}
+	game := models.Game{
+		GameCode: "2023020001",
+		LeagueId: models.LeagueIdNHL,
+		CurrentState: models.GameState{
+			Home: models.TeamState{Team: models.Team{TeamCode: "WPG"}, Score: 1},
+			// The poll before saw the goalie out too
+			Away:   models.TeamState{Team: models.Team{TeamCode: "TOR"}, Score: 0, GoalieOutPolls: 1},
+			Status: models.StatusActive,
+			Period: 3,
+		},
+	}
+
+	updateChan := make(chan models.GameUpdate)
+	go service.GetGameUpdate(game, updateChan)
+	update := <-updateChan
+	assert.True(t, update.NewState.Away.GoaliePulled)
+	assert.False(t, update.NewState.Home.GoaliePulled)
+	if assert.Len(t, update.Events, 2) {
+		assert.Equal(t, models.GoalTypePowerPlay, update.Events[0].Details.GoalType)
+		assert.Equal(t, models.GoalTypeEmptyNet, update.Events[1].Details.GoalType)
+	}
+
+	eventChan := make(chan []models.Event)
+	go service.GetEvents(update, eventChan)
+	events := <-eventChan
+	var goal, pulled *models.Event
+	for i := range events {
+		switch events[i].Type {
+		case models.EventTypeGoal:
+			goal = &events[i]
+		case models.EventTypeGoaliePulled:
+			pulled = &events[i]
+		}
+	}
+	if assert.NotNil(t, goal) {
+		assert.Equal(t, models.GoalTypeEmptyNet, goal.Details.GoalType)
+		assert.Equal(t, "Kyle Connor", goal.PlayerName)
+		assert.Equal(t, "Mark Scheifele", goal.Details.Assist1.Name)
+	}
+	if assert.NotNil(t, pulled) {
+		assert.Equal(t, "TOR", pulled.TeamCode)
+	}
+}
+
+func TestGetGameUpdateFromScoreboard_GoaliePulledNeedsLateGameAndTwoPolls(t *testing.T) {
+	var playByPlay nhlClients.NHLPlayByPlayResponse
+	assert.NoError(t, json.Unmarshal([]byte(`{"plays": [
+		{"eventId": 151, "periodDescriptor": {"number": 3, "periodType": "REG"}, "situationCode": "0651", "typeDescKey": "faceoff"}
+	]}`), &playByPlay))
+	mockClient := &TestMockClientWithCustomScoreboard{useCustom: true, customScoreboard: nhlClients.NHLScoreboardResponse{
+		GameState:        "LIVE",
+		PeriodDescriptor: nhlClients.PeriodDescriptor{Number: 3, PeriodType: "REG"},
+	}}
+	mockClient.SetPlayByPlay(playByPlay)
+	defer mockClient.SetPlayByPlay(nhlClients.NHLPlayByPlayResponse{})
+	service := NHLService{Client: mockClient}
+	game := models.Game{GameCode: "2023020001", LeagueId: models.LeagueIdNHL, CurrentState: models.GameState{Status: models.StatusActive, Period: 3}}
+	updateChan := make(chan models.GameUpdate, 1)
+
+	service.GetGameUpdate(game, updateChan)
+	update := <-updateChan
+	assert.False(t, update.NewState.Away.GoaliePulled, "one poll isn't enough")
+	assert.Equal(t, 1, update.NewState.Away.GoalieOutPolls)
+
+	game.CurrentState = update.NewState
+	service.GetGameUpdate(game, updateChan)
+	update = <-updateChan
+	assert.True(t, update.NewState.Away.GoaliePulled)
+
+	// Earlier in the game, with the score unchanged, the play-by-play isn't
+	// even read
+	reads := mockClient.playByPlayReads
+	mockClient.customScoreboard.PeriodDescriptor.Number = 2
+	game.CurrentState = models.GameState{Status: models.StatusActive, Period: 2, Away: models.TeamState{GoalieOutPolls: 1}}
+	service.GetGameUpdate(game, updateChan)
+	update = <-updateChan
+	assert.False(t, update.NewState.Away.GoaliePulled)
+	assert.Equal(t, reads, mockClient.playByPlayReads)
+
+	// Nor once the game is over
+	mockClient.customScoreboard.GameState = "OFF"
+	game.CurrentState = update.NewState
+	service.GetGameUpdate(game, updateChan)
+	<-updateChan
+	assert.Equal(t, reads, mockClient.playByPlayReads)
+}
+
+func TestDelayedPenaltyPending(t *testing.T) {
+	var playByPlay nhlClients.NHLPlayByPlayResponse
+	assert.NoError(t, json.Unmarshal([]byte(`{"plays": [
+		{"eventId": 1, "situationCode": "1551", "typeDescKey": "faceoff"},
+		{"eventId": 2, "situationCode": "1551", "typeDescKey": "delayed-penalty"},
+		{"eventId": 3, "situationCode": "1560", "typeDescKey": "shot-on-goal"}
+	]}`), &playByPlay))
+	assert.True(t, delayedPenaltyPending(playByPlay), "the goalie's out for the delayed call")
+
+	playByPlay.Plays = append(playByPlay.Plays, nhlClients.NHLPlay{TypeDescKey: "penalty", SituationCode: "1451"})
+	assert.False(t, delayedPenaltyPending(playByPlay), "the penalty was called")
+}
+
+func TestParseSituationCode(t *testing.T) {
+	sit, ok := parseSituationCode("1560")
+	assert.True(t, ok)
+	assert.Equal(t, situation{awayGoalie: 1, awaySkaters: 5, homeSkaters: 6, homeGoalie: 0}, sit)
+	_, ok = parseSituationCode("15x1")
+	assert.False(t, ok)
+	_, ok = parseSituationCode("")
+	assert.False(t, ok)
+}
+
+func TestGoalTypeFromSituation_ExtraAttacker(t *testing.T) {
+	// Away scores 6-on-5 with its own goalie pulled
+	sit, _ := parseSituationCode("0651")
+	assert.Equal(t, models.GoalTypeEvenStrength, goalTypeFromSituation(sit, false))
+	// Home scores 6-on-5 with its own goalie pulled
+	sit, _ = parseSituationCode("1560")
+	assert.Equal(t, models.GoalTypeEvenStrength, goalTypeFromSituation(sit, true))
+	// Still a power play when the extra attacker is on top of one
+	sit, _ = parseSituationCode("1460")
+	assert.Equal(t, models.GoalTypePowerPlay, goalTypeFromSituation(sit, true))
+	sit, _ = parseSituationCode("1551")
+	assert.Equal(t, models.GoalTypeEvenStrength, goalTypeFromSituation(sit, true))
+}
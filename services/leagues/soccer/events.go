@@ -0,0 +1,307 @@
+package soccer
+
+import (
+	"fmt"
+	"goalfeed/clients/leagues/soccer"
+	"goalfeed/models"
+	"strings"
+)
+
+// gameEventsFromKeyEvents types each of a summary's key events for the
+// game's timeline and for GetEvents. Goals are credited to the team that
+// benefits, so an own goal goes to the other side from the player's.
+func gameEventsFromKeyEvents(keyEvents []soccer.SoccerKeyEvent, state models.GameState) []models.GameEvent {
+	events := []models.GameEvent{}
+	for _, keyEvent := range keyEvents {
+		team, opponent := teamsForExtID(state, keyEvent.Team.ID)
+		eventType, details := classifyKeyEvent(keyEvent)
+		if details.GoalType == models.GoalTypeOwnGoal {
+			team = opponent
+		}
+		clock, _ := matchClock(keyEvent.Clock.DisplayValue)
+		event := models.GameEvent{
+			Id:          keyEvent.ID,
+			Type:        eventType,
+			Period:      keyEvent.Period.Number,
+			Time:        clock,
+			Clock:       clock,
+			Description: keyEvent.Text,
+			Team:        team,
+			Details:     details,
+		}
+		if len(keyEvent.Participants) > 0 {
+			athlete := keyEvent.Participants[0].Athlete
+			event.Player = models.Player{Id: athlete.ID, Name: athlete.DisplayName, Team: team}
+		}
+		events = append(events, event)
+	}
+	return events
+}
+
+// classifyKeyEvent reads what a key event was from its type and text. Key
+// events goalfeed has nothing to say about, like substitutions, are plays.
+func classifyKeyEvent(keyEvent soccer.SoccerKeyEvent) (models.EventType, models.EventDetails) {
+	kind := strings.ToLower(keyEvent.Type.Text)
+	text := kind + " " + strings.ToLower(keyEvent.Text)
+	switch {
+	case keyEvent.Shootout || (keyEvent.Period.Number >= shootoutPeriod && strings.Contains(kind, "penalty")):
+		result := "miss"
+		switch {
+		case keyEvent.ScoringPlay || strings.Contains(kind, "scored"):
+			result = "goal"
+		case strings.Contains(kind, "saved"):
+			result = "save"
+		}
+		return models.EventTypeShootoutAttempt, models.EventDetails{ShootoutResult: result}
+	case isVAROverturn(kind, text):
+		return models.EventTypeVAROverturn, models.EventDetails{}
+	case keyEvent.ScoringPlay || kind == "goal" || strings.HasPrefix(kind, "goal -") || strings.Contains(kind, "own goal"):
+		details := models.EventDetails{Points: 1}
+		switch {
+		case strings.Contains(kind, "own goal"):
+			details.GoalType = models.GoalTypeOwnGoal
+		case strings.Contains(kind, "penalty"):
+			details.GoalType = models.GoalTypePenalty
+		}
+		return models.EventTypeGoal, details
+	case strings.Contains(kind, "red card"):
+		return models.EventTypeRedCard, models.EventDetails{}
+	case strings.Contains(kind, "penalty") && strings.Contains(kind, "missed"):
+		return models.EventTypePenalty, models.EventDetails{PenaltyType: "missed"}
+	case strings.Contains(kind, "penalty") && strings.Contains(kind, "saved"):
+		return models.EventTypePenalty, models.EventDetails{PenaltyType: "saved"}
+	}
+	return models.EventTypePlay, models.EventDetails{}
+}
+
+// isVAROverturn reports whether a key event is a video review that reversed
+// the call on the pitch.
+func isVAROverturn(kind string, text string) bool {
+	if !strings.HasPrefix(kind, "var") && !strings.Contains(text, "var decision") && !strings.Contains(text, "var review") {
+		return false
+	}
+	for _, reversed := range []string{"disallowed", "overturned", "cancelled", "canceled", "no goal", "rescinded", "ruled out"} {
+		if strings.Contains(text, reversed) {
+			return true
+		}
+	}
+	return false
+}
+
+func (s SoccerService) goalEvents(update models.GameUpdate, oldState, newState, opponent models.TeamState) []models.Event {
+	events := []models.Event{}
+	diff := newState.Score - oldState.Score
+	team := newState.Team
+	for i := 0; i < diff; i++ {
+		event := s.newEvent(models.EventTypeGoal, team, opponent.Team, update.NewState, models.EventDetails{Points: 1})
+		event.Clock = update.NewState.Clock
+		if play, ok := scoringPlay(update.Events, team.TeamCode, oldState.Score+i); ok {
+			event.Id = play.Id
+			event.PlayerName = play.Player.Name
+			event.Details = play.Details
+			if play.Period > 0 {
+				event.Period = play.Period
+				event.PeriodType = periodType(play.Period)
+			}
+			if play.Clock != "" {
+				event.Clock = play.Clock
+			}
+		}
+		event.Description = fmt.Sprintf("%s for %s", goalDescription(event), team.TeamCode)
+		events = append(events, event)
+	}
+	return events
+}
+
+// scoringPlay returns the nth (zero-based) goal credited to teamCode.
+func scoringPlay(plays []models.GameEvent, teamCode string, n int) (models.GameEvent, bool) {
+	for _, play := range plays {
+		if play.Type != models.EventTypeGoal || play.Team.TeamCode != teamCode {
+			continue
+		}
+		if n == 0 {
+			return play, true
+		}
+		n--
+	}
+	return models.GameEvent{}, false
+}
+
+func goalDescription(event models.Event) string {
+	switch {
+	case event.Details.GoalType == models.GoalTypeOwnGoal:
+		return "Own goal"
+	case event.Details.GoalType == models.GoalTypePenalty:
+		return "Penalty goal"
+	case event.PeriodType == models.PeriodTypeOvertime:
+		return "Extra-time goal"
+	default:
+		return "Goal"
+	}
+}
+
+// keyEventsSince reports the red cards, missed and saved penalties, VAR
+// reversals and shootout kicks among the key events past the old state's
+// cursor. Goals aren't reported here: they come from the score.
+func (s SoccerService) keyEventsSince(update models.GameUpdate) []models.Event {
+	events := []models.Event{}
+	end := min(update.NewState.PlayCursor, len(update.Events))
+	// The feed can drop key events, as when a goal is struck off; nothing
+	// past the shorter list is new
+	start := min(update.OldState.PlayCursor, end)
+	for _, play := range update.Events[start:end] {
+		team, opponent, ok := teamsForCode(update.NewState, play.Team.TeamCode)
+		if !ok {
+			continue
+		}
+		player := play.Player.Name
+		event := s.newEvent(play.Type, team, opponent, update.NewState, play.Details)
+		event.Id = play.Id
+		event.PlayerName = player
+		event.Clock = play.Clock
+		if play.Period > 0 {
+			event.Period = play.Period
+			event.PeriodType = periodType(play.Period)
+		}
+		switch play.Type {
+		case models.EventTypeRedCard:
+			event.Description = fmt.Sprintf("Red card for %s", withPlayer(player, team.TeamCode))
+		case models.EventTypePenalty:
+			event.Description = fmt.Sprintf("Penalty %s: %s", play.Details.PenaltyType, withPlayer(player, team.TeamCode))
+		case models.EventTypeVAROverturn:
+			event.Description = varDescription(play, team.TeamCode)
+		case models.EventTypeShootoutAttempt:
+			event.Description = fmt.Sprintf("Shootout attempt by %s: %s", withPlayer(player, team.TeamCode), play.Details.ShootoutResult)
+			event.Details.ShootoutRound = shootoutRound(update.Events[:end], play)
+		default:
+			continue
+		}
+		events = append(events, event)
+	}
+	return events
+}
+
+// overturnedGoalEvents fires when a team's score goes down, which is how a
+// goal struck off by VAR shows up when the feed has no key event saying so.
+func (s SoccerService) overturnedGoalEvents(update models.GameUpdate) []models.Event {
+	events := []models.Event{}
+	if update.OldState.Status != models.StatusActive {
+		return events
+	}
+	reviewed := map[string]bool{}
+	for _, event := range s.keyEventsSince(update) {
+		if event.Type == models.EventTypeVAROverturn {
+			reviewed[event.TeamCode] = true
+		}
+	}
+	sides := []struct{ old, new, opponent models.TeamState }{
+		{update.OldState.Home, update.NewState.Home, update.NewState.Away},
+		{update.OldState.Away, update.NewState.Away, update.NewState.Home},
+	}
+	for _, side := range sides {
+		team := side.new.Team
+		if side.new.Score >= side.old.Score || reviewed[team.TeamCode] {
+			continue
+		}
+		event := s.newEvent(models.EventTypeVAROverturn, team, side.opponent.Team, update.NewState, models.EventDetails{})
+		event.Clock = update.NewState.Clock
+		event.Description = fmt.Sprintf("%s goal ruled out by VAR, %d-%d", team.TeamCode, update.NewState.Home.Score, update.NewState.Away.Score)
+		events = append(events, event)
+	}
+	return events
+}
+
+func varDescription(play models.GameEvent, teamCode string) string {
+	text := strings.ToLower(play.Description)
+	switch {
+	case strings.Contains(text, "goal"):
+		return fmt.Sprintf("%s goal ruled out by VAR", teamCode)
+	case strings.Contains(text, "red card"):
+		return fmt.Sprintf("%s red card overturned by VAR", teamCode)
+	case strings.Contains(text, "penalty"):
+		return fmt.Sprintf("%s penalty overturned by VAR", teamCode)
+	}
+	return fmt.Sprintf("VAR overturns the call on %s", teamCode)
+}
+
+// shootoutRound is the number of kicks the play's team has taken in the
+// shootout, counting this one.
+func shootoutRound(plays []models.GameEvent, kick models.GameEvent) int {
+	round := 0
+	for _, play := range plays {
+		if play.Type == models.EventTypeShootoutAttempt && play.Team.TeamCode == kick.Team.TeamCode {
+			round++
+		}
+		if play.Id == kick.Id {
+			break
+		}
+	}
+	return round
+}
+
+func (s SoccerService) shootoutResultEvent(update models.GameUpdate) (models.Event, bool) {
+	shootout := update.NewState.Shootout
+	if shootout == nil || shootout.Winner == "" || (update.OldState.Shootout != nil && update.OldState.Shootout.Winner != "") {
+		return models.Event{}, false
+	}
+	team, opponent, ok := teamsForCode(update.NewState, shootout.Winner)
+	if !ok {
+		return models.Event{}, false
+	}
+	event := s.newEvent(models.EventTypeShootoutResult, team, opponent, update.NewState, models.EventDetails{})
+	event.Description = fmt.Sprintf("%s win %d-%d on penalties", team.TeamCode, max(shootout.HomeGoals, shootout.AwayGoals), min(shootout.HomeGoals, shootout.AwayGoals))
+	return event, true
+}
+
+func withPlayer(player string, teamCode string) string {
+	if player == "" {
+		return teamCode
+	}
+	return fmt.Sprintf("%s (%s)", player, teamCode)
+}
+
+func teamsForExtID(state models.GameState, extID string) (models.Team, models.Team) {
+	if extID != "" && extID == state.Away.Team.ExtID {
+		return state.Away.Team, state.Home.Team
+	}
+	if extID != "" && extID == state.Home.Team.ExtID {
+		return state.Home.Team, state.Away.Team
+	}
+	return models.Team{}, models.Team{}
+}
+
+func teamsForCode(state models.GameState, teamCode string) (models.Team, models.Team, bool) {
+	switch teamCode {
+	case "":
+		return models.Team{}, models.Team{}, false
+	case state.Home.Team.TeamCode:
+		return state.Home.Team, state.Away.Team, true
+	case state.Away.Team.TeamCode:
+		return state.Away.Team, state.Home.Team, true
+	default:
+		return models.Team{}, models.Team{}, false
+	}
+}
+
+func (s SoccerService) newEvent(eventType models.EventType, team, opponent models.Team, state models.GameState, details models.EventDetails) models.Event {
+	return models.Event{
+		Type:         eventType,
+		TeamCode:     team.TeamCode,
+		TeamName:     team.TeamName,
+		TeamHash:     team.GetTeamHash(),
+		LeagueId:     int(s.League),
+		LeagueName:   s.GetLeagueName(),
+		Period:       state.Period,
+		PeriodType:   state.PeriodType,
+		OpponentCode: opponent.TeamCode,
+		OpponentName: opponent.TeamName,
+		OpponentHash: opponent.GetTeamHash(),
+		Details:      details,
+		Score: models.ScoreUpdate{
+			HomeScore: state.Home.Score,
+			AwayScore: state.Away.Score,
+			HomeTeam:  state.Home.Team.TeamCode,
+			AwayTeam:  state.Away.Team.TeamCode,
+		},
+	}
+}
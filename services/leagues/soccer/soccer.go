@@ -0,0 +1,283 @@
+package soccer
+
+import (
+	"goalfeed/clients/leagues/soccer"
+	"goalfeed/models"
+	"goalfeed/services/leagues/espn"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ESPN numbers a match's periods as two halves, two halves of extra time,
+// then the penalty shootout.
+const (
+	regulationPeriods = 2
+	shootoutPeriod    = 5
+)
+
+// SoccerService follows one ESPN soccer competition; League is
+// models.LeagueIdEPL, models.LeagueIdMLS or models.LeagueIdUCL.
+type SoccerService struct {
+	Client soccer.ISoccerApiClient
+	League models.League
+}
+
+func (s SoccerService) GetLeagueName() string {
+	switch s.League {
+	case models.LeagueIdMLS:
+		return "MLS"
+	case models.LeagueIdUCL:
+		return "UCL"
+	}
+	return "EPL"
+}
+
+func (s SoccerService) GetActiveGames(ret chan []models.Game) {
+	var activeGames []models.Game
+	for _, event := range s.Client.GetSchedule().Events {
+		if gameStatusFromStatus(event.Status) != models.StatusActive {
+			continue
+		}
+		game := s.gameFromEvent(event)
+		// Start from the summary's state so the first update doesn't report
+		// cards or goals from before the match was picked up
+		if summary := s.Client.GetSummary(event.ID); len(summary.Header.Competitions) > 0 {
+			game.CurrentState = s.gameStateFromSummary(game.CurrentState, summary)
+		}
+		activeGames = append(activeGames, game)
+	}
+	ret <- activeGames
+}
+
+func (s SoccerService) GetUpcomingGames(ret chan []models.Game) {
+	var upcomingGames []models.Game
+	for _, event := range s.Client.GetSchedule().Events {
+		if gameStatusFromStatus(event.Status) == models.StatusUpcoming {
+			upcomingGames = append(upcomingGames, s.gameFromEvent(event))
+		}
+	}
+	ret <- upcomingGames
+}
+
+func (s SoccerService) GetGamesByDate(date string, ret chan []models.Game) {
+	// Convert YYYY-MM-DD to YYYYMMDD for ESPN
+	schedule := s.Client.GetScheduleByDate(strings.ReplaceAll(date, "-", ""))
+	var games []models.Game
+	for _, event := range schedule.Events {
+		games = append(games, s.gameFromEvent(event))
+	}
+	ret <- games
+}
+
+func (s SoccerService) GetGameUpdate(game models.Game, ret chan models.GameUpdate) {
+	summary := s.Client.GetSummary(game.GameCode)
+	update := models.GameUpdate{
+		OldState: game.CurrentState,
+		NewState: s.gameStateFromSummary(game.CurrentState, summary),
+	}
+	if len(summary.Header.Competitions) > 0 {
+		update.Events = gameEventsFromKeyEvents(summary.KeyEvents, update.NewState)
+		update.Plays = update.Events
+	}
+	ret <- update
+}
+
+func (s SoccerService) GetEvents(update models.GameUpdate, ret chan []models.Event) {
+	events := append(
+		s.goalEvents(update, update.OldState.Home, update.NewState.Home, update.NewState.Away),
+		s.goalEvents(update, update.OldState.Away, update.NewState.Away, update.NewState.Home)...,
+	)
+	events = append(events, s.keyEventsSince(update)...)
+	events = append(events, s.overturnedGoalEvents(update)...)
+	if event, ok := s.shootoutResultEvent(update); ok {
+		events = append(events, event)
+	}
+	ret <- events
+}
+
+// gameStatusFromStatus reads an event's status the ESPN way.
+func gameStatusFromStatus(status soccer.SoccerStatus) models.GameStatus {
+	return espn.GameStatus(status.Type.State, status.Type.Completed, status.Type.Name)
+}
+
+// homeAway is the side a competitor plays on, for espn.CompetitorsBySide.
+func homeAway(competitor soccer.SoccerCompetitor) string {
+	return competitor.HomeAway
+}
+
+// periodType labels extra time as overtime and the penalties as a shootout;
+// regulation halves are "HALF".
+func periodType(period int) string {
+	switch {
+	case period >= shootoutPeriod:
+		return models.PeriodTypeShootout
+	case period > regulationPeriods:
+		return models.PeriodTypeOvertime
+	}
+	return "HALF"
+}
+
+// matchClock turns ESPN's match minute ("45'+2'") into the clock shown for
+// the game ("45+2'") and the minutes of stoppage time played so far.
+// Anything that isn't a minute is returned unchanged.
+func matchClock(display string) (string, int) {
+	minute, added, _ := strings.Cut(strings.ReplaceAll(display, "'", ""), "+")
+	if _, err := strconv.Atoi(strings.TrimSpace(minute)); err != nil {
+		return display, 0
+	}
+	minute = strings.TrimSpace(minute)
+	stoppage, err := strconv.Atoi(strings.TrimSpace(added))
+	if err != nil || stoppage <= 0 {
+		return minute + "'", 0
+	}
+	return minute + "+" + strconv.Itoa(stoppage) + "'", stoppage
+}
+
+// setClock fills in the period and match minute from an ESPN status. The
+// clock counts up, so there's no time remaining to report.
+func setClock(state *models.GameState, status soccer.SoccerStatus) {
+	state.Period = status.Period
+	state.PeriodType = periodType(status.Period)
+	state.Clock, state.AddedTime = matchClock(status.DisplayClock)
+	switch {
+	case strings.Contains(strings.ToUpper(status.Type.Name), "HALFTIME"):
+		state.PeriodType = "HALFTIME"
+		state.Clock = "HT"
+		state.AddedTime = 0
+	case gameStatusFromStatus(status) == models.StatusEnded:
+		state.Clock = "FT"
+		state.AddedTime = 0
+	}
+	state.TimeRemaining = ""
+}
+
+func (s SoccerService) teamFromCompetitor(competitor soccer.SoccerCompetitor) models.Team {
+	logo := competitor.Team.Logo
+	if logo == "" && len(competitor.Team.Logos) > 0 {
+		logo = competitor.Team.Logos[0].Href
+	}
+	return models.Team{
+		TeamName: competitor.Team.DisplayName,
+		TeamCode: competitor.Team.Abbreviation,
+		ExtID:    competitor.Team.ID,
+		LeagueID: int(s.League),
+		LogoURL:  logo,
+	}
+}
+
+func venueFromSoccerVenue(venue soccer.SoccerVenue) models.Venue {
+	return models.Venue{
+		Id:      venue.ID,
+		Name:    venue.FullName,
+		City:    venue.Address.City,
+		Country: venue.Address.Country,
+	}
+}
+
+func (s SoccerService) gameFromEvent(event soccer.SoccerEvent) models.Game {
+	var home, away soccer.SoccerCompetitor
+	var venue models.Venue
+	if len(event.Competitions) > 0 {
+		competition := event.Competitions[0]
+		home, away = espn.CompetitorsBySide(competition.Competitors, homeAway)
+		venue = venueFromSoccerVenue(competition.Venue)
+	}
+	homeScore, _ := strconv.Atoi(home.Score)
+	awayScore, _ := strconv.Atoi(away.Score)
+	gameDate := espn.ParseDate(event.Date)
+
+	state := models.GameState{
+		ExtTimestamp: event.Date,
+		Home:         models.TeamState{Team: s.teamFromCompetitor(home), Score: homeScore},
+		Away:         models.TeamState{Team: s.teamFromCompetitor(away), Score: awayScore},
+		Status:       gameStatusFromStatus(event.Status),
+		FetchedAt:    time.Now(),
+		Venue:        venue,
+	}
+	setClock(&state, event.Status)
+
+	gameTime := "TBD"
+	if !gameDate.IsZero() {
+		gameTime = gameDate.Format("3:04 PM")
+	}
+	if state.Status == models.StatusUpcoming {
+		state.Clock = gameTime
+	}
+
+	return models.Game{
+		CurrentState: state,
+		GameCode:     event.ID,
+		ExtTimestamp: event.Date,
+		LeagueId:     s.League,
+		GameDetails: models.GameDetails{
+			GameId:     event.ID,
+			Season:     strconv.Itoa(event.Season.Year),
+			SeasonType: "REGULAR",
+			GameDate:   gameDate,
+			GameTime:   gameTime,
+			Timezone:   "UTC",
+		},
+	}
+}
+
+// gameStateFromSummary builds the game's new state from a summary. The old
+// state is returned unchanged when the summary carries no competitors.
+// PlayCursor counts the key events read so far, so GetEvents reports each
+// card, missed penalty and review once.
+func (s SoccerService) gameStateFromSummary(old models.GameState, summary soccer.SoccerSummaryResponse) models.GameState {
+	if len(summary.Header.Competitions) == 0 {
+		return old
+	}
+	competition := summary.Header.Competitions[0]
+	home, away := espn.CompetitorsBySide(competition.Competitors, homeAway)
+	if home.Team.Abbreviation == "" || away.Team.Abbreviation == "" {
+		return old
+	}
+	homeScore, _ := strconv.Atoi(home.Score)
+	awayScore, _ := strconv.Atoi(away.Score)
+
+	state := old
+	state.Home = models.TeamState{Team: s.teamFromCompetitor(home), Score: homeScore}
+	state.Away = models.TeamState{Team: s.teamFromCompetitor(away), Score: awayScore}
+	state.Status = gameStatusFromStatus(competition.Status)
+	state.FetchedAt = time.Now()
+	setClock(&state, competition.Status)
+	if venue := summary.GameInfo.Venue; venue.FullName != "" {
+		state.Venue = venueFromSoccerVenue(venue)
+	}
+	state.PlayCursor = len(summary.KeyEvents)
+	state.Shootout = shootoutFromSummary(summary.KeyEvents, home, away, state)
+	return state
+}
+
+// shootoutFromSummary tracks a penalty shootout kick by kick, or returns nil
+// when the match hasn't gone to penalties. The winner is only set once the
+// match is over.
+func shootoutFromSummary(keyEvents []soccer.SoccerKeyEvent, home, away soccer.SoccerCompetitor, state models.GameState) *models.ShootoutState {
+	attempts := []models.ShootoutAttempt{}
+	for _, event := range gameEventsFromKeyEvents(keyEvents, state) {
+		if event.Type != models.EventTypeShootoutAttempt {
+			continue
+		}
+		attempts = append(attempts, models.ShootoutAttempt{
+			Sequence: len(attempts) + 1,
+			TeamCode: event.Team.TeamCode,
+			Player:   event.Player,
+			Result:   event.Details.ShootoutResult,
+		})
+	}
+	if len(attempts) == 0 && state.Period < shootoutPeriod && home.ShootoutScore == 0 && away.ShootoutScore == 0 {
+		return nil
+	}
+	shootout := &models.ShootoutState{Attempts: attempts, HomeGoals: home.ShootoutScore, AwayGoals: away.ShootoutScore}
+	if state.Status == models.StatusEnded {
+		switch {
+		case home.Winner:
+			shootout.Winner = state.Home.Team.TeamCode
+		case away.Winner:
+			shootout.Winner = state.Away.Team.TeamCode
+		}
+	}
+	return shootout
+}
@@ -0,0 +1,238 @@
+package soccer
+
+import (
+	"encoding/json"
+	"fmt"
+	"testing"
+
+	soccerClients "goalfeed/clients/leagues/soccer"
+	"goalfeed/models"
+)
+
+// summaryJSON is a CHE @ ARS summary with the given status and key events.
+func summaryJSON(t *testing.T, homeScore int, awayScore int, status string, keyEvents string) soccerClients.SoccerSummaryResponse {
+	t.Helper()
+	body := fmt.Sprintf(`{
+		"header": {"id": "704", "season": {"year": 2025}, "competitions": [{
+			"competitors": [
+				{"homeAway": "home", "score": "%d", "team": {"id": "359", "abbreviation": "ARS", "displayName": "Arsenal", "logos": [{"href": "ars.png"}]}},
+				{"homeAway": "away", "score": "%d", "team": {"id": "363", "abbreviation": "CHE", "displayName": "Chelsea", "logos": [{"href": "che.png"}]}}
+			],
+			"status": %s
+		}]},
+		"gameInfo": {"venue": {"id": "1", "fullName": "Emirates Stadium", "address": {"city": "London", "country": "England"}}},
+		"keyEvents": %s
+	}`, homeScore, awayScore, status, keyEvents)
+	var summary soccerClients.SoccerSummaryResponse
+	if err := json.Unmarshal([]byte(body), &summary); err != nil {
+		t.Fatalf("bad fixture: %v", err)
+	}
+	return summary
+}
+
+const firstHalfStoppage = `{"period": 1, "displayClock": "45'+2'", "type": {"state": "in", "name": "STATUS_FIRST_HALF"}}`
+
+// Saka scores a penalty, Chelsea go down to ten, Palmer misses a penalty,
+// then Saliba puts through his own net.
+const keyEvents = `[
+	{"id": "1", "type": {"text": "Kickoff"}, "period": {"number": 1}, "clock": {"displayValue": "0'"}},
+	{"id": "2", "type": {"text": "Penalty - Scored"}, "scoringPlay": true, "period": {"number": 1}, "clock": {"displayValue": "12'"},
+		"team": {"id": "359"}, "participants": [{"athlete": {"displayName": "Bukayo Saka"}}]},
+	{"id": "3", "type": {"text": "Red Card"}, "period": {"number": 1}, "clock": {"displayValue": "30'"},
+		"team": {"id": "363"}, "participants": [{"athlete": {"displayName": "Moises Caicedo"}}]},
+	{"id": "4", "type": {"text": "Penalty - Missed"}, "period": {"number": 1}, "clock": {"displayValue": "40'"},
+		"team": {"id": "363"}, "participants": [{"athlete": {"displayName": "Cole Palmer"}}]},
+	{"id": "5", "type": {"text": "Own Goal"}, "scoringPlay": true, "period": {"number": 1}, "clock": {"displayValue": "45'+1'"},
+		"team": {"id": "359"}, "participants": [{"athlete": {"displayName": "William Saliba"}}]}
+]`
+
+func getSoccerUpdate(t *testing.T, summary soccerClients.SoccerSummaryResponse, old models.GameState) (models.GameUpdate, []models.Event) {
+	t.Helper()
+	service := SoccerService{Client: soccerClients.MockSoccerApiClient{SummaryResponse: summary}, League: models.LeagueIdEPL}
+	updates := make(chan models.GameUpdate)
+	go service.GetGameUpdate(models.Game{GameCode: "704", LeagueId: models.LeagueIdEPL, CurrentState: old}, updates)
+	update := <-updates
+	events := make(chan []models.Event)
+	go service.GetEvents(update, events)
+	return update, <-events
+}
+
+func soccerState(homeScore int, awayScore int, cursor int) models.GameState {
+	return models.GameState{
+		Home:       models.TeamState{Team: models.Team{TeamCode: "ARS", ExtID: "359"}, Score: homeScore},
+		Away:       models.TeamState{Team: models.Team{TeamCode: "CHE", ExtID: "363"}, Score: awayScore},
+		Status:     models.StatusActive,
+		PlayCursor: cursor,
+	}
+}
+
+func eventTypes(events []models.Event) []string {
+	var types []string
+	for _, event := range events {
+		types = append(types, event.TeamCode+" "+string(event.Type))
+	}
+	return types
+}
+
+func TestGetGameUpdate_StateFromSummary(t *testing.T) {
+	update, _ := getSoccerUpdate(t, summaryJSON(t, 1, 1, firstHalfStoppage, keyEvents), models.GameState{})
+	state := update.NewState
+
+	if state.Home.Team.TeamCode != "ARS" || state.Home.Score != 1 || state.Away.Score != 1 || state.Home.Team.LeagueID != models.LeagueIdEPL {
+		t.Errorf("unexpected teams %+v / %+v", state.Home, state.Away)
+	}
+	if state.Status != models.StatusActive || state.Period != 1 || state.PeriodType != "HALF" || state.Clock != "45+2'" || state.AddedTime != 2 {
+		t.Errorf("unexpected status %v, period %d %s, clock %s +%d", state.Status, state.Period, state.PeriodType, state.Clock, state.AddedTime)
+	}
+	if state.PlayCursor != 5 || len(update.Plays) != 5 || update.Plays[0].Type != models.EventTypePlay {
+		t.Errorf("expected all five key events read, got cursor %d and %d plays", state.PlayCursor, len(update.Plays))
+	}
+	if state.Venue.Name != "Emirates Stadium" || state.Venue.Country != "England" || state.Shootout != nil {
+		t.Errorf("unexpected venue %+v or shootout %+v", state.Venue, state.Shootout)
+	}
+}
+
+func TestGetEvents_GoalsCardsAndPenalties(t *testing.T) {
+	_, events := getSoccerUpdate(t, summaryJSON(t, 1, 1, firstHalfStoppage, keyEvents), soccerState(0, 0, 1))
+
+	got := eventTypes(events)
+	want := []string{"ARS goal", "CHE goal", "CHE red_card", "CHE penalty"}
+	if fmt.Sprint(got) != fmt.Sprint(want) {
+		t.Fatalf("expected %v, got %v", want, got)
+	}
+	if events[0].Description != "Penalty goal for ARS" || events[0].PlayerName != "Bukayo Saka" || events[0].Clock != "12'" || events[0].Details.GoalType != models.GoalTypePenalty {
+		t.Errorf("unexpected penalty goal %+v", events[0])
+	}
+	if events[1].Description != "Own goal for CHE" || events[1].PlayerName != "William Saliba" || events[1].Clock != "45+1'" || events[1].OpponentCode != "ARS" {
+		t.Errorf("unexpected own goal %+v", events[1])
+	}
+	if events[2].Description != "Red card for Moises Caicedo (CHE)" || events[2].LeagueName != "EPL" {
+		t.Errorf("unexpected red card %+v", events[2])
+	}
+	if events[3].Description != "Penalty missed: Cole Palmer (CHE)" || events[3].Details.PenaltyType != "missed" {
+		t.Errorf("unexpected missed penalty %+v", events[3])
+	}
+}
+
+func TestGetEvents_ReportedOnce(t *testing.T) {
+	_, events := getSoccerUpdate(t, summaryJSON(t, 1, 1, firstHalfStoppage, keyEvents), soccerState(1, 1, 5))
+	if len(events) != 0 {
+		t.Errorf("expected nothing new, got %v", eventTypes(events))
+	}
+
+	// A shorter list than last time holds nothing new either
+	_, events = getSoccerUpdate(t, summaryJSON(t, 1, 1, firstHalfStoppage, keyEvents), soccerState(1, 1, 7))
+	if len(events) != 0 {
+		t.Errorf("expected nothing after the feed dropped key events, got %v", eventTypes(events))
+	}
+}
+
+func TestGetEvents_VAROverturn(t *testing.T) {
+	// The score drops with nothing in the feed to explain it
+	_, events := getSoccerUpdate(t, summaryJSON(t, 1, 1, firstHalfStoppage, keyEvents), soccerState(2, 1, 5))
+	if got := eventTypes(events); len(got) != 1 || got[0] != "ARS var_overturn" || events[0].Description != "ARS goal ruled out by VAR, 1-1" {
+		t.Fatalf("expected an ARS VAR overturn, got %v", got)
+	}
+
+	// A VAR key event is reported once, not again for the score drop
+	reviewed := keyEvents[:len(keyEvents)-1] + `,
+		{"id": "6", "type": {"text": "VAR - Goal Disallowed"}, "text": "VAR Decision: Goal Arsenal. Goal disallowed - offside.",
+			"period": {"number": 2}, "clock": {"displayValue": "61'"}, "team": {"id": "359"}}]`
+	_, events = getSoccerUpdate(t, summaryJSON(t, 1, 1, firstHalfStoppage, reviewed), soccerState(2, 1, 5))
+	if got := eventTypes(events); len(got) != 1 || got[0] != "ARS var_overturn" {
+		t.Fatalf("expected one ARS VAR overturn, got %v", got)
+	}
+	if events[0].Description != "ARS goal ruled out by VAR" || events[0].Clock != "61'" || events[0].PeriodType != "HALF" {
+		t.Errorf("unexpected VAR overturn %+v", events[0])
+	}
+}
+
+func TestGetEvents_ExtraTimeAndShootout(t *testing.T) {
+	extraTime := `{"period": 4, "displayClock": "118'", "type": {"state": "in", "name": "STATUS_SECOND_HALF_EXTRA_TIME"}}`
+	goal := `[{"id": "1", "type": {"text": "Goal"}, "scoringPlay": true, "period": {"number": 4}, "clock": {"displayValue": "117'"},
+		"team": {"id": "363"}, "participants": [{"athlete": {"displayName": "Cole Palmer"}}]}]`
+	_, events := getSoccerUpdate(t, summaryJSON(t, 1, 1, extraTime, goal), soccerState(1, 0, 0))
+	if len(events) != 1 || events[0].Description != "Extra-time goal for CHE" || events[0].PeriodType != models.PeriodTypeOvertime {
+		t.Fatalf("expected a CHE extra-time goal, got %+v", events)
+	}
+
+	final := `{"period": 5, "displayClock": "120'", "type": {"state": "post", "completed": true, "name": "STATUS_FINAL_PEN"}}`
+	kicks := `[
+		{"id": "2", "type": {"text": "Penalty - Scored"}, "shootout": true, "scoringPlay": true, "period": {"number": 5}, "team": {"id": "359"}},
+		{"id": "3", "type": {"text": "Penalty - Saved"}, "shootout": true, "period": {"number": 5}, "team": {"id": "363"},
+			"participants": [{"athlete": {"displayName": "Cole Palmer"}}]}
+	]`
+	summary := summaryJSON(t, 1, 1, final, kicks)
+	summary.Header.Competitions[0].Competitors[0].ShootoutScore = 4
+	summary.Header.Competitions[0].Competitors[0].Winner = true
+	summary.Header.Competitions[0].Competitors[1].ShootoutScore = 3
+	update, events := getSoccerUpdate(t, summary, soccerState(1, 1, 0))
+
+	if got := eventTypes(events); fmt.Sprint(got) != "[ARS shootout_attempt CHE shootout_attempt ARS shootout_result]" {
+		t.Fatalf("expected two kicks and the result, got %v", got)
+	}
+	if events[1].Description != "Shootout attempt by Cole Palmer (CHE): save" || events[1].Details.ShootoutRound != 1 {
+		t.Errorf("unexpected kick %+v", events[1])
+	}
+	if events[2].Description != "ARS win 4-3 on penalties" {
+		t.Errorf("unexpected result %+v", events[2])
+	}
+	if shootout := update.NewState.Shootout; shootout == nil || len(shootout.Attempts) != 2 || shootout.Winner != "ARS" || update.NewState.Clock != "FT" {
+		t.Errorf("unexpected shootout %+v at %s", shootout, update.NewState.Clock)
+	}
+}
+
+func TestSetClock(t *testing.T) {
+	for display, want := range map[string]string{"23'": "23'", "90'+4'": "90+4'", "45'": "45'", "": ""} {
+		if clock, _ := matchClock(display); clock != want {
+			t.Errorf("matchClock(%q) = %q, want %q", display, clock, want)
+		}
+	}
+
+	var status soccerClients.SoccerStatus
+	if err := json.Unmarshal([]byte(`{"period": 1, "displayClock": "45'+3'", "type": {"state": "in", "name": "STATUS_HALFTIME"}}`), &status); err != nil {
+		t.Fatalf("bad fixture: %v", err)
+	}
+	var state models.GameState
+	setClock(&state, status)
+	if state.Clock != "HT" || state.PeriodType != "HALFTIME" || state.AddedTime != 0 {
+		t.Errorf("expected halftime, got %s %s +%d", state.PeriodType, state.Clock, state.AddedTime)
+	}
+
+	if periodType(2) != "HALF" || periodType(3) != models.PeriodTypeOvertime || periodType(5) != models.PeriodTypeShootout {
+		t.Errorf("unexpected period types")
+	}
+}
+
+func TestGameFromEvent(t *testing.T) {
+	body := `{"events": [{"id": "704", "date": "2025-08-17T15:30Z", "season": {"year": 2025},
+		"competitions": [{"venue": {"fullName": "Emirates Stadium"}, "competitors": [
+			{"homeAway": "home", "score": "0", "team": {"id": "359", "abbreviation": "ARS", "displayName": "Arsenal", "logo": "ars.png"}},
+			{"homeAway": "away", "score": "0", "team": {"id": "363", "abbreviation": "CHE", "displayName": "Chelsea", "logo": "che.png"}}
+		]}],
+		"status": {"period": 0, "displayClock": "0'", "type": {"state": "pre", "name": "STATUS_SCHEDULED"}}}]}`
+	var schedule soccerClients.SoccerScheduleResponse
+	if err := json.Unmarshal([]byte(body), &schedule); err != nil {
+		t.Fatalf("bad fixture: %v", err)
+	}
+	service := SoccerService{Client: soccerClients.MockSoccerApiClient{ScheduleResponse: schedule}, League: models.LeagueIdUCL}
+
+	games := make(chan []models.Game)
+	go service.GetUpcomingGames(games)
+	upcoming := <-games
+	if len(upcoming) != 1 {
+		t.Fatalf("expected one upcoming game, got %d", len(upcoming))
+	}
+	game := upcoming[0]
+	if game.GameCode != "704" || game.LeagueId != models.LeagueIdUCL || game.CurrentState.Away.Team.TeamCode != "CHE" || game.CurrentState.Home.Team.LogoURL != "ars.png" {
+		t.Errorf("unexpected game %+v", game)
+	}
+	if game.CurrentState.Status != models.StatusUpcoming || game.CurrentState.Clock != "3:30 PM" || service.GetLeagueName() != "UCL" {
+		t.Errorf("expected an upcoming UCL game with a start time, got %+v", game.CurrentState)
+	}
+
+	go service.GetActiveGames(games)
+	if active := <-games; len(active) != 0 {
+		t.Errorf("expected no active games, got %d", len(active))
+	}
+}
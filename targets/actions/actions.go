@@ -0,0 +1,274 @@
+// Package actions runs Home Assistant service calls in reaction to events,
+// from the declarative actions: section of the config. Each action has a
+// filter (when:) and steps that run in order: service calls, and delays
+// between them.
+//
+//	actions:
+//	  - name: Jets goal
+//	    when:
+//	      league: NHL
+//	      team: WPG
+//	      event: goal
+//	    steps:
+//	      - service: light.turn_on
+//	        target: {entity_id: light.living_room}
+//	        data: {rgb_color: "{team_rgb}", flash: long}
+//	      - service: media_player.play_media
+//	        target: {entity_id: media_player.kitchen}
+//	        data: {media_content_id: "http://nas.local/horn.mp3", media_content_type: music}
+//	      - delay: 30s
+//	      - service: scene.turn_on
+//	        target: {entity_id: scene.living_room_normal}
+package actions
+
+import (
+	"fmt"
+	"goalfeed/models"
+	"goalfeed/targets/applog"
+	"goalfeed/targets/homeassistant"
+	"goalfeed/utils"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/spf13/viper"
+)
+
+var logger = utils.GetLogger()
+
+// Action is one entry under actions:.
+type Action struct {
+	Name  string `mapstructure:"name" json:"name"`
+	When  Filter `mapstructure:"when" json:"when"`
+	Steps []Step `mapstructure:"steps" json:"steps"`
+}
+
+// Filter picks the events an action runs for. Each field takes one value or
+// a list; an empty field matches everything.
+type Filter struct {
+	// League names (NHL, NFL, or an IIHF tournament's name) or ids
+	Leagues []string `mapstructure:"league" json:"league,omitempty"`
+	// Team codes of the team the event is for, "*", or conf:<conference>
+	// for a college team
+	Teams []string `mapstructure:"team" json:"team,omitempty"`
+	// Event types: goal, touchdown, red_card, ...
+	Events []string `mapstructure:"event" json:"event,omitempty"`
+	// Period numbers, or "overtime" and "shootout"
+	Periods []string `mapstructure:"period" json:"period,omitempty"`
+	// "home" or "away", for the team the event is for
+	Side string `mapstructure:"side" json:"side,omitempty"`
+}
+
+// Step is a service call or, with Delay set, a pause before the next step.
+type Step struct {
+	Service string                 `mapstructure:"service" json:"service,omitempty"`
+	Target  map[string]interface{} `mapstructure:"target" json:"target,omitempty"`
+	Data    map[string]interface{} `mapstructure:"data" json:"data,omitempty"`
+	Delay   string                 `mapstructure:"delay" json:"delay,omitempty"`
+	delay   time.Duration
+}
+
+// callService is replaced in tests.
+var callService = homeassistant.CallService
+
+// sleep is replaced in tests.
+var sleep = time.Sleep
+
+var (
+	runningMu sync.Mutex
+	running   = map[string]bool{}
+)
+
+// Actions returns the actions configured under actions:. Actions with no
+// steps, or with a step that is neither a domain.service call nor a valid
+// delay, are left out; Warnings says why.
+func Actions() []Action {
+	actions, _ := actionsFromConfig()
+	return actions
+}
+
+// Warnings explains each actions: entry Actions leaves out.
+func Warnings() []string {
+	_, warnings := actionsFromConfig()
+	return warnings
+}
+
+func actionsFromConfig() ([]Action, []string) {
+	var configured []Action
+	if err := viper.UnmarshalKey("actions", &configured); err != nil {
+		return nil, []string{fmt.Sprintf("Ignoring actions: %v", err)}
+	}
+
+	var actions []Action
+	var warnings []string
+	for i, action := range configured {
+		if action.Name == "" {
+			action.Name = fmt.Sprintf("action %d", i+1)
+		}
+		if err := validate(&action); err != nil {
+			warnings = append(warnings, fmt.Sprintf("Skipping %s: %v", action.Name, err))
+			continue
+		}
+		actions = append(actions, action)
+	}
+	return actions, warnings
+}
+
+func validate(action *Action) error {
+	if len(action.Steps) == 0 {
+		return fmt.Errorf("no steps")
+	}
+	side := strings.ToLower(strings.TrimSpace(action.When.Side))
+	if side != "" && side != "home" && side != "away" {
+		return fmt.Errorf("side must be home or away, not %q", action.When.Side)
+	}
+	action.When.Side = side
+	for i := range action.Steps {
+		step := &action.Steps[i]
+		switch {
+		case step.Delay != "" && step.Service != "":
+			return fmt.Errorf("step %d has both a service and a delay", i+1)
+		case step.Delay != "":
+			d, err := time.ParseDuration(step.Delay)
+			if err != nil || d < 0 {
+				return fmt.Errorf("step %d: delay %q is not a duration like 10s", i+1, step.Delay)
+			}
+			step.delay = d
+		default:
+			domain, service, ok := strings.Cut(step.Service, ".")
+			if !ok || domain == "" || service == "" {
+				return fmt.Errorf("step %d: service %q is not domain.service", i+1, step.Service)
+			}
+		}
+	}
+	return nil
+}
+
+// Matches reports whether the event passes the filter.
+func (f Filter) Matches(event models.Event) bool {
+	if len(f.Leagues) > 0 && !anyEqual(f.Leagues, event.LeagueName, strconv.Itoa(event.LeagueId)) {
+		return false
+	}
+	if len(f.Teams) > 0 {
+		team := models.Team{TeamCode: event.TeamCode, Conference: event.TeamConference}
+		matched := false
+		for _, entry := range f.Teams {
+			if team.MatchesWatchEntry(entry) {
+				matched = true
+				break
+			}
+		}
+		if !matched {
+			return false
+		}
+	}
+	if len(f.Events) > 0 && !anyEqual(f.Events, string(event.Type)) {
+		return false
+	}
+	if len(f.Periods) > 0 && !anyEqual(f.Periods, strconv.Itoa(event.Period), event.PeriodType) {
+		return false
+	}
+	if f.Side != "" && f.Side != side(event) {
+		return false
+	}
+	return true
+}
+
+// side is "home" or "away" for the event's team, or "" when the event
+// doesn't say.
+func side(event models.Event) string {
+	switch {
+	case event.TeamCode == "":
+		return ""
+	case strings.EqualFold(event.TeamCode, event.Score.HomeTeam):
+		return "home"
+	case strings.EqualFold(event.TeamCode, event.Score.AwayTeam):
+		return "away"
+	}
+	return ""
+}
+
+func anyEqual(values []string, candidates ...string) bool {
+	for _, v := range values {
+		v = strings.TrimSpace(v)
+		for _, c := range candidates {
+			if c != "" && strings.EqualFold(v, c) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// Handle starts every action matching the event, each in its own goroutine.
+// An action still running from an earlier event isn't started again, so a
+// quick second goal doesn't interleave two horn sequences.
+func Handle(event models.Event) {
+	for _, action := range Actions() {
+		if !action.When.Matches(event) {
+			continue
+		}
+		if !start(action.Name) {
+			logger.Info(fmt.Sprintf("Action %s is still running; skipping it for %s", action.Name, event.Type))
+			continue
+		}
+		go func(action Action) {
+			defer finish(action.Name)
+			Run(action, event)
+		}(action)
+	}
+}
+
+func start(name string) bool {
+	runningMu.Lock()
+	defer runningMu.Unlock()
+	if running[name] {
+		return false
+	}
+	running[name] = true
+	return true
+}
+
+func finish(name string) {
+	runningMu.Lock()
+	defer runningMu.Unlock()
+	delete(running, name)
+}
+
+// Run runs the action's steps in order for the event. A failed service
+// call is logged and the remaining steps still run, so a light left on by
+// an earlier step is still turned back.
+func Run(action Action, event models.Event) {
+	vars := variables(event)
+	for _, step := range action.Steps {
+		if step.Service == "" {
+			sleep(step.delay)
+			continue
+		}
+		data := expandMap(step.Data, vars)
+		target := expandMap(step.Target, vars)
+		err := callService(step.Service, data, target)
+		ok := err == nil
+		entry := models.AppLogEntry{
+			Type:          models.AppLogTypeLogLine,
+			Level:         models.AppLogLevelInfo,
+			LeagueId:      models.League(event.LeagueId),
+			LeagueName:    event.LeagueName,
+			TeamCode:      event.TeamCode,
+			Opponent:      event.OpponentCode,
+			GameCode:      event.GameCode,
+			Message:       fmt.Sprintf("Action %s: %s", action.Name, step.Service),
+			Source:        "actions",
+			Target:        "ha:service:" + step.Service,
+			Success:       &ok,
+			CorrelationId: event.Id,
+		}
+		if err != nil {
+			logger.Warn(fmt.Sprintf("Action %s: %s failed: %v", action.Name, step.Service, err))
+			entry.Level = models.AppLogLevelWarn
+			entry.Error = err.Error()
+		}
+		applog.Append(entry)
+	}
+}
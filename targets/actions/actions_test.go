@@ -0,0 +1,163 @@
+package actions
+
+import (
+	"errors"
+	"goalfeed/models"
+	"goalfeed/targets/applog"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+)
+
+type call struct {
+	service string
+	data    map[string]interface{}
+	target  map[string]interface{}
+}
+
+func stubCalls(t *testing.T, err error) *[]call {
+	applog.SetLogFilePathForTest(filepath.Join(t.TempDir(), "app.log.jsonl"))
+	var calls []call
+	oldCall, oldSleep := callService, sleep
+	callService = func(service string, data, target map[string]interface{}) error {
+		calls = append(calls, call{service, data, target})
+		return err
+	}
+	sleep = func(d time.Duration) {
+		calls = append(calls, call{service: "delay " + d.String()})
+	}
+	t.Cleanup(func() { callService, sleep = oldCall, oldSleep })
+	return &calls
+}
+
+var jetsGoal = models.Event{
+	Id:           "1",
+	Type:         models.EventTypeGoal,
+	TeamCode:     "WPG",
+	TeamName:     "Winnipeg Jets",
+	OpponentCode: "TOR",
+	LeagueId:     int(models.LeagueIdNHL),
+	LeagueName:   "NHL",
+	Period:       2,
+	PlayerName:   "Kyle Connor",
+	Score:        models.ScoreUpdate{HomeTeam: "WPG", AwayTeam: "TOR", HomeScore: 2, AwayScore: 1},
+}
+
+func hornAction() map[string]interface{} {
+	return map[string]interface{}{
+		"name": "Jets goal",
+		"when": map[string]interface{}{"league": "NHL", "team": "WPG", "event": "goal"},
+		"steps": []interface{}{
+			map[string]interface{}{
+				"service": "light.turn_on",
+				"target":  map[string]interface{}{"entity_id": "light.living_room"},
+				"data":    map[string]interface{}{"rgb_color": "{team_rgb}", "flash": "long"},
+			},
+			map[string]interface{}{"delay": "30s"},
+			map[string]interface{}{
+				"service": "notify.mobile_app",
+				"data":    map[string]interface{}{"message": "{player} scores for {team_name} in period {period}"},
+			},
+		},
+	}
+}
+
+func TestActionsFromConfig(t *testing.T) {
+	viper.Reset()
+	viper.Set("actions", []interface{}{
+		hornAction(),
+		map[string]interface{}{"name": "empty"},
+		map[string]interface{}{"name": "bad service", "steps": []interface{}{map[string]interface{}{"service": "turn_on"}}},
+		map[string]interface{}{"name": "bad delay", "steps": []interface{}{map[string]interface{}{"delay": "soon"}}},
+		map[string]interface{}{"name": "bad side", "when": map[string]interface{}{"side": "left"}, "steps": []interface{}{map[string]interface{}{"service": "scene.turn_on"}}},
+	})
+
+	actions := Actions()
+	assert.Len(t, actions, 1)
+	assert.Equal(t, "Jets goal", actions[0].Name)
+	assert.Equal(t, []string{"WPG"}, actions[0].When.Teams, "a single value reads as a list")
+	assert.Equal(t, 30*time.Second, actions[0].Steps[1].delay)
+	assert.Len(t, Warnings(), 4)
+}
+
+func TestFilterMatches(t *testing.T) {
+	assert.True(t, Filter{}.Matches(jetsGoal))
+	assert.True(t, Filter{Leagues: []string{"nhl"}, Teams: []string{"wpg"}, Events: []string{"goal"}}.Matches(jetsGoal))
+	assert.True(t, Filter{Leagues: []string{"1"}}.Matches(jetsGoal), "leagues match by id too")
+	assert.True(t, Filter{Teams: []string{"*"}}.Matches(jetsGoal))
+	assert.False(t, Filter{Teams: []string{"TOR"}}.Matches(jetsGoal))
+	assert.False(t, Filter{Events: []string{"touchdown"}}.Matches(jetsGoal))
+	assert.True(t, Filter{Periods: []string{"1", "2"}}.Matches(jetsGoal))
+	assert.False(t, Filter{Periods: []string{"overtime"}}.Matches(jetsGoal))
+	assert.True(t, Filter{Side: "home"}.Matches(jetsGoal))
+	assert.False(t, Filter{Side: "away"}.Matches(jetsGoal))
+
+	overtime := jetsGoal
+	overtime.Period = 4
+	overtime.PeriodType = models.PeriodTypeOvertime
+	assert.True(t, Filter{Periods: []string{"overtime"}}.Matches(overtime))
+
+	touchdown := models.Event{Type: models.EventTypeTouchdown, TeamCode: "MICH", TeamConference: "Big Ten", LeagueId: int(models.LeagueIdNCAAF), LeagueName: "NCAAF"}
+	assert.True(t, Filter{Teams: []string{"conf:big ten"}}.Matches(touchdown))
+	assert.False(t, Filter{Teams: []string{"conf:SEC"}}.Matches(touchdown))
+	touchdown.TeamConference = ""
+	assert.False(t, Filter{Teams: []string{"conf:Big Ten"}}.Matches(touchdown), "an event without a conference isn't in one")
+}
+
+func TestRunSequencesStepsWithPlaceholders(t *testing.T) {
+	calls := stubCalls(t, nil)
+	viper.Reset()
+	viper.Set("team_colors", map[string]interface{}{"WPG": "#041E42"})
+	viper.Set("actions", []interface{}{hornAction()})
+
+	Run(Actions()[0], jetsGoal)
+	assert.Len(t, *calls, 3)
+	assert.Equal(t, "light.turn_on", (*calls)[0].service)
+	assert.Equal(t, []int{4, 30, 66}, (*calls)[0].data["rgb_color"])
+	assert.Equal(t, "light.living_room", (*calls)[0].target["entity_id"])
+	assert.Equal(t, "delay 30s", (*calls)[1].service)
+	assert.Equal(t, "Kyle Connor scores for Winnipeg Jets in period 2", (*calls)[2].data["message"])
+}
+
+func TestRunLeavesOutUnknownTeamColor(t *testing.T) {
+	calls := stubCalls(t, nil)
+	viper.Reset()
+	viper.Set("actions", []interface{}{hornAction()})
+
+	Run(Actions()[0], jetsGoal)
+	_, ok := (*calls)[0].data["rgb_color"]
+	assert.False(t, ok)
+	assert.Equal(t, "long", (*calls)[0].data["flash"])
+}
+
+func TestRunContinuesAfterFailedCall(t *testing.T) {
+	calls := stubCalls(t, errors.New("500 Internal Server Error"))
+	viper.Reset()
+	viper.Set("actions", []interface{}{hornAction()})
+
+	Run(Actions()[0], jetsGoal)
+	assert.Len(t, *calls, 3)
+}
+
+func TestHandleSkipsRunningAction(t *testing.T) {
+	calls := stubCalls(t, nil)
+	viper.Reset()
+	viper.Set("actions", []interface{}{hornAction()})
+
+	assert.True(t, start("Jets goal"))
+	Handle(jetsGoal)
+	time.Sleep(50 * time.Millisecond)
+	finish("Jets goal")
+	assert.Empty(t, *calls, "nothing runs while the earlier run is going")
+}
+
+func TestHexToRGB(t *testing.T) {
+	rgb, err := hexToRGB("#C8102E")
+	assert.NoError(t, err)
+	assert.Equal(t, []int{200, 16, 46}, rgb)
+	_, err = hexToRGB("blue")
+	assert.Error(t, err)
+}
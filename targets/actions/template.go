@@ -0,0 +1,98 @@
+package actions
+
+import (
+	"fmt"
+	"goalfeed/models"
+	"strconv"
+	"strings"
+
+	"github.com/spf13/viper"
+)
+
+// rgbPlaceholder, as a whole value, becomes the team's color as an
+// [r, g, b] list for light.turn_on's rgb_color. With no color configured
+// for the team the key is left out, so the light keeps its color.
+const rgbPlaceholder = "{team_rgb}"
+
+// variables are the {placeholders} step data and targets can use.
+func variables(event models.Event) map[string]string {
+	return map[string]string{
+		"{team}":          event.TeamCode,
+		"{team_name}":     event.TeamName,
+		"{opponent}":      event.OpponentCode,
+		"{opponent_name}": event.OpponentName,
+		"{player}":        event.PlayerName,
+		"{league}":        event.LeagueName,
+		"{period}":        strconv.Itoa(event.Period),
+		"{event}":         string(event.Type),
+		"{description}":   event.Description,
+		"{team_color}":    teamColor(event.LeagueName, event.TeamCode),
+	}
+}
+
+// teamColor reads the team's hex color from team_colors, where a team is
+// keyed by its code or, to tell leagues apart, league_code (nhl_wpg).
+func teamColor(league, teamCode string) string {
+	colors := viper.GetStringMapString("team_colors")
+	team := strings.ToLower(teamCode)
+	if c, ok := colors[strings.ToLower(league)+"_"+team]; ok {
+		return c
+	}
+	return colors[team]
+}
+
+// hexToRGB turns "#041E42" into [4, 30, 66].
+func hexToRGB(hex string) ([]int, error) {
+	hex = strings.TrimPrefix(strings.TrimSpace(hex), "#")
+	if len(hex) != 6 {
+		return nil, fmt.Errorf("color %q is not #rrggbb", hex)
+	}
+	v, err := strconv.ParseUint(hex, 16, 32)
+	if err != nil {
+		return nil, fmt.Errorf("color %q is not #rrggbb", hex)
+	}
+	return []int{int(v >> 16 & 0xff), int(v >> 8 & 0xff), int(v & 0xff)}, nil
+}
+
+func expandMap(m map[string]interface{}, vars map[string]string) map[string]interface{} {
+	if m == nil {
+		return nil
+	}
+	out := make(map[string]interface{}, len(m))
+	for k, v := range m {
+		if expanded, keep := expand(v, vars); keep {
+			out[k] = expanded
+		}
+	}
+	return out
+}
+
+// expand fills in placeholders in strings, including those nested in maps
+// and lists. keep is false for a {team_rgb} with no color to fill it.
+func expand(v interface{}, vars map[string]string) (interface{}, bool) {
+	switch val := v.(type) {
+	case string:
+		if strings.TrimSpace(val) == rgbPlaceholder {
+			rgb, err := hexToRGB(vars["{team_color}"])
+			if err != nil {
+				return nil, false
+			}
+			return rgb, true
+		}
+		for placeholder, value := range vars {
+			val = strings.ReplaceAll(val, placeholder, value)
+		}
+		return val, true
+	case map[string]interface{}:
+		return expandMap(val, vars), true
+	case []interface{}:
+		out := make([]interface{}, 0, len(val))
+		for _, item := range val {
+			if expanded, keep := expand(item, vars); keep {
+				out = append(out, expanded)
+			}
+		}
+		return out, true
+	}
+	return v, true
+}
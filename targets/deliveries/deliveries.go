@@ -0,0 +1,478 @@
+// Package deliveries keeps target deliveries that failed, such as a goal
+// event posted while Home Assistant was restarting, and retries them with
+// exponential backoff. A delivery that is still failing after
+// deliveries.max_age is dropped, since a goal horn that late is worse than
+// none. One that failed in a way retrying won't fix (a rejected token)
+// moves to the dead-letter list, where it waits for a manual retry or
+// purge through /api/deliveries.
+//
+// The queue is a JSON file (deliveries.path) rewritten on every change, so
+// pending deliveries survive a Goalfeed restart.
+package deliveries
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"goalfeed/models"
+	"goalfeed/targets/applog"
+	"goalfeed/utils"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/spf13/viper"
+)
+
+var logger = utils.GetLogger()
+
+// Status is where a delivery is in the queue.
+type Status string
+
+const (
+	StatusPending Status = "pending"
+	StatusDead    Status = "dead"
+)
+
+// Delivery is one failed send, with what's needed to send it again.
+type Delivery struct {
+	Id string `json:"id"`
+	// Kind picks the sender registered with RegisterSender
+	Kind string `json:"kind"`
+	// Target names the destination in the app log, e.g. ha:event:goal
+	Target string `json:"target"`
+	// Name is the kind-specific destination, such as the Home Assistant event type
+	Name        string          `json:"name"`
+	Payload     json.RawMessage `json:"payload"`
+	Event       *models.Event   `json:"event,omitempty"`
+	Status      Status          `json:"status"`
+	Attempts    int             `json:"attempts"`
+	CreatedAt   time.Time       `json:"createdAt"`
+	NextAttempt time.Time       `json:"nextAttempt"`
+	LastError   string          `json:"lastError,omitempty"`
+	// sending is set while an attempt is out, so a manual retry and the
+	// retry loop never send it twice
+	sending bool
+}
+
+// Sender sends a delivery again. Wrap errors retrying won't fix with
+// Permanent.
+type Sender func(Delivery) error
+
+// ErrNotFound is returned for an unknown delivery id.
+var ErrNotFound = errors.New("delivery not found")
+
+// ErrInFlight is returned when retrying a delivery that is being sent.
+var ErrInFlight = errors.New("delivery is already being sent")
+
+type permanentError struct{ err error }
+
+func (e permanentError) Error() string { return e.err.Error() }
+func (e permanentError) Unwrap() error { return e.err }
+
+// Permanent marks an error as one that retrying won't fix, so the delivery
+// goes straight to the dead-letter list.
+func Permanent(err error) error {
+	if err == nil {
+		return nil
+	}
+	return permanentError{err}
+}
+
+// IsPermanent reports whether err was marked with Permanent.
+func IsPermanent(err error) bool {
+	var p permanentError
+	return errors.As(err, &p)
+}
+
+var (
+	mu      sync.Mutex
+	queue   []*Delivery
+	path    string
+	seq     int
+	senders = map[string]Sender{}
+	startMu sync.Mutex
+	started bool
+)
+
+// now is replaced in tests.
+var now = time.Now
+
+// RegisterSender sets the sender for a kind of delivery. Targets register
+// themselves in init, so this package doesn't import them.
+func RegisterSender(kind string, sender Sender) {
+	mu.Lock()
+	defer mu.Unlock()
+	senders[kind] = sender
+}
+
+// Start loads the queue from deliveries.path and starts retrying. Until it
+// is called, failed deliveries are only kept in memory.
+func Start() {
+	startMu.Lock()
+	defer startMu.Unlock()
+	if started {
+		return
+	}
+	started = true
+
+	p := viper.GetString("deliveries.path")
+	if p == "" {
+		p = "deliveries.json"
+	}
+	if err := load(p); err != nil {
+		logger.Warn(fmt.Sprintf("Couldn't load the delivery queue from %s: %v", p, err))
+	}
+	go func() {
+		ticker := time.NewTicker(time.Second)
+		defer ticker.Stop()
+		for range ticker.C {
+			processDue()
+		}
+	}()
+}
+
+// SetPathForTest points the queue at a file for tests only, starting from
+// whatever the file holds.
+func SetPathForTest(p string) {
+	mu.Lock()
+	queue = nil
+	mu.Unlock()
+	_ = load(p)
+}
+
+func load(p string) error {
+	if dir := filepath.Dir(p); dir != "." && dir != "" {
+		_ = os.MkdirAll(dir, 0o755)
+	}
+	mu.Lock()
+	defer mu.Unlock()
+	path = p
+	b, err := os.ReadFile(p)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	var loaded []*Delivery
+	if err := json.Unmarshal(b, &loaded); err != nil {
+		return err
+	}
+	// Deliveries queued before Start are newer than those on disk
+	queue = append(loaded, queue...)
+	return saveLocked()
+}
+
+// saveLocked trims the dead-letter list and rewrites the queue file through
+// a temporary file, so a crash mid-write leaves the old queue rather than
+// half of the new one.
+func saveLocked() error {
+	trimDeadLocked()
+	if path == "" {
+		return nil
+	}
+	b, err := json.MarshalIndent(queue, "", "  ")
+	if err != nil {
+		return err
+	}
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, b, 0o644); err != nil {
+		return err
+	}
+	return os.Rename(tmp, path)
+}
+
+func save() {
+	if err := saveLocked(); err != nil {
+		logger.Warn(fmt.Sprintf("Couldn't save the delivery queue: %v", err))
+	}
+}
+
+// Enqueue records a delivery whose first attempt failed with err. It's
+// retried after the backoff, or dead-lettered at once when err is
+// Permanent.
+func Enqueue(d Delivery, err error) {
+	t := now()
+	d.CreatedAt = t
+	d.Attempts = 1
+	d.Status = StatusPending
+	if err != nil {
+		d.LastError = err.Error()
+	}
+	d.NextAttempt = t.Add(backoff(d.Attempts))
+
+	mu.Lock()
+	defer mu.Unlock()
+	seq++
+	d.Id = fmt.Sprintf("%d-%d-%s", t.UnixNano(), seq, d.Name)
+	queue = append(queue, &d)
+	if IsPermanent(err) {
+		markDeadLocked(&d, d.LastError)
+	} else {
+		logger.Info(fmt.Sprintf("Queued %s for retry in %s", d.Target, backoff(d.Attempts)))
+	}
+	save()
+}
+
+// List returns every delivery, oldest first.
+func List() []Delivery {
+	mu.Lock()
+	defer mu.Unlock()
+	out := make([]Delivery, 0, len(queue))
+	for _, d := range queue {
+		out = append(out, *d)
+	}
+	return out
+}
+
+// Retry sends a delivery, pending or dead, right away. It leaves the queue
+// when the send works; otherwise it stays where it was with the new error.
+// One already being sent, by the retry loop or another Retry, isn't sent
+// again.
+func Retry(id string) error {
+	mu.Lock()
+	d := findLocked(id)
+	if d == nil {
+		mu.Unlock()
+		return ErrNotFound
+	}
+	if d.sending {
+		mu.Unlock()
+		return ErrInFlight
+	}
+	d.sending = true
+	attempt := *d
+	sender := senders[attempt.Kind]
+	mu.Unlock()
+
+	err := send(sender, attempt)
+
+	mu.Lock()
+	defer mu.Unlock()
+	if d = findLocked(id); d == nil {
+		return err
+	}
+	d.sending = false
+	d.Attempts++
+	if err == nil {
+		delivered(d)
+		removeLocked(id)
+	} else {
+		d.LastError = err.Error()
+	}
+	save()
+	return err
+}
+
+// Purge removes a delivery without sending it.
+func Purge(id string) error {
+	mu.Lock()
+	defer mu.Unlock()
+	if findLocked(id) == nil {
+		return ErrNotFound
+	}
+	removeLocked(id)
+	save()
+	return nil
+}
+
+// PurgeDead empties the dead-letter list and returns how many it held.
+func PurgeDead() int {
+	mu.Lock()
+	defer mu.Unlock()
+	kept := queue[:0]
+	for _, d := range queue {
+		if d.Status != StatusDead {
+			kept = append(kept, d)
+		}
+	}
+	purged := len(queue) - len(kept)
+	queue = kept
+	save()
+	return purged
+}
+
+// processDue retries the pending deliveries that are due, oldest first, and
+// drops those older than deliveries.max_age.
+func processDue() {
+	t := now()
+	maxAge := maxAge()
+
+	mu.Lock()
+	var due []Delivery
+	var stale []*Delivery
+	for _, d := range queue {
+		if d.Status != StatusPending || d.sending || t.Before(d.NextAttempt) {
+			continue
+		}
+		if maxAge > 0 && t.Sub(d.CreatedAt) > maxAge {
+			stale = append(stale, d)
+			continue
+		}
+		d.sending = true
+		due = append(due, *d)
+	}
+	for _, d := range stale {
+		droppedLocked(d, fmt.Sprintf("stale after %s: %s", maxAge, d.LastError))
+	}
+	if len(stale) > 0 {
+		save()
+	}
+	mu.Unlock()
+
+	for _, attempt := range due {
+		mu.Lock()
+		sender := senders[attempt.Kind]
+		mu.Unlock()
+		err := send(sender, attempt)
+
+		mu.Lock()
+		d := findLocked(attempt.Id)
+		if d != nil {
+			d.sending = false
+		}
+		if d != nil && d.Status == StatusPending {
+			d.Attempts++
+			switch {
+			case err == nil:
+				delivered(d)
+				removeLocked(d.Id)
+			case IsPermanent(err):
+				markDeadLocked(d, err.Error())
+			default:
+				d.LastError = err.Error()
+				d.NextAttempt = now().Add(backoff(d.Attempts))
+			}
+			save()
+		}
+		mu.Unlock()
+	}
+}
+
+func send(sender Sender, d Delivery) error {
+	if sender == nil {
+		return Permanent(fmt.Errorf("no sender for %s deliveries", d.Kind))
+	}
+	return sender(d)
+}
+
+func delivered(d *Delivery) {
+	logger.Info(fmt.Sprintf("Delivered %s on attempt %d", d.Target, d.Attempts))
+	ok := true
+	applog.Append(logEntry(d, models.AppLogLevelInfo, fmt.Sprintf("Delivered on attempt %d", d.Attempts), &ok))
+}
+
+// droppedLocked removes a delivery that is too late to send.
+func droppedLocked(d *Delivery, reason string) {
+	d.LastError = reason
+	logger.Warn(fmt.Sprintf("Dropped %s: %s", d.Target, reason))
+	ok := false
+	applog.Append(logEntry(d, models.AppLogLevelWarn, "Dropped: "+reason, &ok))
+	removeLocked(d.Id)
+}
+
+func markDeadLocked(d *Delivery, reason string) {
+	d.Status = StatusDead
+	d.LastError = reason
+	logger.Warn(fmt.Sprintf("Gave up on %s: %s", d.Target, reason))
+	ok := false
+	applog.Append(logEntry(d, models.AppLogLevelWarn, "Moved to the dead-letter list: "+reason, &ok))
+}
+
+// trimDeadLocked keeps the dead-letter list to deliveries.dead_letter_max,
+// dropping the oldest.
+func trimDeadLocked() {
+	limit := viper.GetInt("deliveries.dead_letter_max")
+	if limit <= 0 {
+		return
+	}
+	dead := 0
+	for _, d := range queue {
+		if d.Status == StatusDead {
+			dead++
+		}
+	}
+	kept := queue[:0]
+	for _, d := range queue {
+		if d.Status == StatusDead && dead > limit {
+			dead--
+			continue
+		}
+		kept = append(kept, d)
+	}
+	queue = kept
+}
+
+func logEntry(d *Delivery, level models.AppLogLevel, message string, ok *bool) models.AppLogEntry {
+	entry := models.AppLogEntry{
+		Type:    models.AppLogTypeLogLine,
+		Level:   level,
+		Message: message,
+		Source:  "deliveries",
+		Target:  d.Target,
+		Success: ok,
+		Error:   d.LastError,
+	}
+	if d.Event != nil {
+		entry.LeagueId = models.League(d.Event.LeagueId)
+		entry.LeagueName = d.Event.LeagueName
+		entry.TeamCode = d.Event.TeamCode
+		entry.Opponent = d.Event.OpponentCode
+		entry.GameCode = d.Event.GameCode
+		entry.CorrelationId = d.Event.Id
+	}
+	if *ok {
+		entry.Error = ""
+	}
+	return entry
+}
+
+func findLocked(id string) *Delivery {
+	for _, d := range queue {
+		if d.Id == id {
+			return d
+		}
+	}
+	return nil
+}
+
+func removeLocked(id string) {
+	for i, d := range queue {
+		if d.Id == id {
+			queue = append(queue[:i], queue[i+1:]...)
+			return
+		}
+	}
+}
+
+// backoff is the wait before the next attempt, after attempts tries:
+// deliveries.retry_base_ms doubled per attempt, capped at
+// deliveries.retry_max_ms.
+func backoff(attempts int) time.Duration {
+	base := viper.GetInt("deliveries.retry_base_ms")
+	max := viper.GetInt("deliveries.retry_max_ms")
+	if base <= 0 {
+		base = 2000
+	}
+	if max < base {
+		max = base
+	}
+	wait := base
+	for i := 1; i < attempts && wait < max; i++ {
+		wait *= 2
+	}
+	if wait > max {
+		wait = max
+	}
+	return time.Duration(wait) * time.Millisecond
+}
+
+func maxAge() time.Duration {
+	d, err := time.ParseDuration(viper.GetString("deliveries.max_age"))
+	if err != nil {
+		return 0
+	}
+	return d
+}
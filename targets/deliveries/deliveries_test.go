@@ -0,0 +1,184 @@
+package deliveries
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"goalfeed/models"
+	"goalfeed/targets/applog"
+
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+)
+
+// withQueue starts each test on an empty queue file and a clock it
+// controls, with a "test" sender that returns whatever *result holds.
+func withQueue(t *testing.T) (string, *time.Time, *error, *int) {
+	dir := t.TempDir()
+	applog.SetLogFilePathForTest(filepath.Join(dir, "app.log.jsonl"))
+	viper.Reset()
+	viper.Set("deliveries.retry_base_ms", 1000)
+	viper.Set("deliveries.retry_max_ms", 4000)
+	viper.Set("deliveries.max_age", "1m")
+
+	clock := time.Date(2026, 3, 1, 19, 0, 0, 0, time.UTC)
+	oldNow := now
+	now = func() time.Time { return clock }
+
+	var result error
+	sent := 0
+	RegisterSender("test", func(Delivery) error {
+		sent++
+		return result
+	})
+
+	p := filepath.Join(dir, "deliveries.json")
+	SetPathForTest(p)
+	t.Cleanup(func() {
+		now = oldNow
+		mu.Lock()
+		queue, path = nil, ""
+		mu.Unlock()
+	})
+	return p, &clock, &result, &sent
+}
+
+func goal() Delivery {
+	return Delivery{
+		Kind:    "test",
+		Target:  "ha:event:goal",
+		Name:    "goal",
+		Payload: json.RawMessage(`{"teamCode":"WPG"}`),
+		Event:   &models.Event{Id: "e1", TeamCode: "WPG", LeagueName: "NHL"},
+	}
+}
+
+func TestRetriedWithBackoffUntilDelivered(t *testing.T) {
+	_, clock, result, sent := withQueue(t)
+	*result = errors.New("connection refused")
+	Enqueue(goal(), errors.New("connection refused"))
+
+	processDue()
+	assert.Equal(t, 0, *sent, "nothing is due before the first backoff")
+
+	*clock = clock.Add(time.Second)
+	processDue()
+	assert.Equal(t, 1, *sent)
+	assert.Equal(t, clock.Add(2*time.Second), List()[0].NextAttempt, "the wait doubles")
+
+	*clock = clock.Add(2 * time.Second)
+	*result = nil
+	processDue()
+	assert.Equal(t, 2, *sent)
+	assert.Empty(t, List())
+}
+
+func TestBackoffIsCapped(t *testing.T) {
+	withQueue(t)
+	assert.Equal(t, time.Second, backoff(1))
+	assert.Equal(t, 4*time.Second, backoff(3))
+	assert.Equal(t, 4*time.Second, backoff(10))
+}
+
+func TestStaleDeliveriesAreDropped(t *testing.T) {
+	_, clock, result, sent := withQueue(t)
+	*result = errors.New("502 Bad Gateway")
+	Enqueue(goal(), errors.New("502 Bad Gateway"))
+	fresh := goal()
+	fresh.Name = "touchdown"
+
+	*clock = clock.Add(2 * time.Minute)
+	Enqueue(fresh, errors.New("502 Bad Gateway"))
+	processDue()
+	assert.Equal(t, 0, *sent, "a stale goal isn't sent")
+	left := List()
+	assert.Len(t, left, 1, "nor kept for a manual retry")
+	assert.Equal(t, "touchdown", left[0].Name)
+	assert.Equal(t, StatusPending, left[0].Status)
+}
+
+func TestPermanentFailuresSkipTheQueue(t *testing.T) {
+	withQueue(t)
+	Enqueue(goal(), Permanent(errors.New("401 Unauthorized")))
+	assert.Equal(t, StatusDead, List()[0].Status)
+}
+
+func TestQueueSurvivesRestart(t *testing.T) {
+	p, _, _, _ := withQueue(t)
+	Enqueue(goal(), errors.New("connection refused"))
+
+	b, err := os.ReadFile(p)
+	assert.NoError(t, err)
+	assert.Contains(t, string(b), `"teamCode": "WPG"`)
+
+	SetPathForTest(p)
+	loaded := List()
+	assert.Len(t, loaded, 1)
+	assert.Equal(t, "WPG", loaded[0].Event.TeamCode)
+}
+
+func TestManualRetryAndPurge(t *testing.T) {
+	_, _, result, sent := withQueue(t)
+	Enqueue(goal(), Permanent(errors.New("401 Unauthorized")))
+	Enqueue(goal(), Permanent(errors.New("401 Unauthorized")))
+	ids := []string{List()[0].Id, List()[1].Id}
+	assert.NotEqual(t, ids[0], ids[1])
+
+	*result = errors.New("still down")
+	assert.Error(t, Retry(ids[0]))
+	assert.Equal(t, "still down", List()[0].LastError)
+
+	*result = nil
+	assert.NoError(t, Retry(ids[0]))
+	assert.Equal(t, 2, *sent)
+	assert.Len(t, List(), 1)
+
+	assert.ErrorIs(t, Retry("missing"), ErrNotFound)
+	assert.ErrorIs(t, Purge("missing"), ErrNotFound)
+	assert.Equal(t, 1, PurgeDead())
+	assert.Empty(t, List())
+}
+
+func TestRetryWhileSendingIsNotSentTwice(t *testing.T) {
+	_, clock, _, _ := withQueue(t)
+	entered := make(chan struct{})
+	release := make(chan struct{})
+	sent := 0
+	RegisterSender("slow", func(Delivery) error {
+		sent++
+		close(entered)
+		<-release
+		return nil
+	})
+	d := goal()
+	d.Kind = "slow"
+	Enqueue(d, errors.New("502 Bad Gateway"))
+	id := List()[0].Id
+
+	*clock = clock.Add(time.Second)
+	done := make(chan struct{})
+	go func() {
+		processDue()
+		close(done)
+	}()
+	<-entered
+	assert.ErrorIs(t, Retry(id), ErrInFlight)
+	close(release)
+	<-done
+
+	assert.Equal(t, 1, sent)
+	assert.Empty(t, List())
+}
+
+func TestDeadLetterListIsCapped(t *testing.T) {
+	withQueue(t)
+	viper.Set("deliveries.dead_letter_max", 2)
+	for i := 0; i < 3; i++ {
+		Enqueue(goal(), Permanent(errors.New("400 Bad Request")))
+	}
+	assert.Len(t, List(), 2)
+}
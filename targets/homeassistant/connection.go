@@ -3,7 +3,6 @@ package homeassistant
 import (
 	"fmt"
 	"goalfeed/config"
-	"net/http"
 	"os"
 	"strings"
 	"time"
@@ -40,19 +39,9 @@ func CheckConnection(timeout time.Duration) (bool, string, string) {
 	if err := validateOutboundHAURL(url); err != nil {
 		return false, source, fmt.Sprintf("refusing to check connection: %v", err)
 	}
-	client := &http.Client{Timeout: timeout}
-	req, err := http.NewRequest("GET", strings.TrimRight(url, "/")+"/api/", nil)
-	if err != nil {
-		return false, source, fmt.Sprintf("request error: %v", err)
+	if WebSocketConnected() {
+		return true, source, "OK (websocket)"
 	}
-	req.Header.Set("Authorization", "Bearer "+token)
-	resp, err := client.Do(req)
-	if err != nil {
-		return false, source, fmt.Sprintf("connection error: %v", err)
-	}
-	defer resp.Body.Close()
-	if resp.StatusCode >= 200 && resp.StatusCode < 300 {
-		return true, source, "OK"
-	}
-	return false, source, fmt.Sprintf("HTTP %s", resp.Status)
+	connected, message := checkREST(url, token, timeout)
+	return connected, source, message
 }
@@ -0,0 +1,145 @@
+package homeassistant
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"goalfeed/models"
+	"regexp"
+	"sort"
+	"strings"
+	"text/template"
+
+	"github.com/spf13/viper"
+)
+
+// EventConfig is one entry under home_assistant.events, keyed by Goalfeed
+// event type (goal, touchdown, period_start, ...), or default for every
+// type without an entry of its own.
+//
+//	home_assistant:
+//	  events:
+//	    default:
+//	      name: "goalfeed_{type}"
+//	    goal:
+//	      name: goal
+//	      template: '{"team": {{json .TeamCode}}, "scorer": {{json .PlayerName}}}'
+type EventConfig struct {
+	// Name is the Home Assistant event type fired; {type} is replaced
+	// with the Goalfeed event type
+	Name string `mapstructure:"name"`
+	// Template is a Go template, run against the event, that renders the
+	// JSON object sent in place of the full event payload
+	Template string `mapstructure:"template"`
+}
+
+const (
+	defaultEventName = "goal"
+	defaultEventKey  = "default"
+)
+
+var validEventName = regexp.MustCompile(`^[A-Za-z0-9_.]+$`)
+
+var eventTemplateFuncs = template.FuncMap{
+	"json": func(v interface{}) (string, error) {
+		b, err := json.Marshal(v)
+		return string(b), err
+	},
+	"lower": strings.ToLower,
+	"upper": strings.ToUpper,
+}
+
+func eventConfigs() (map[string]EventConfig, error) {
+	var configs map[string]EventConfig
+	if err := viper.UnmarshalKey("home_assistant.events", &configs); err != nil {
+		return nil, err
+	}
+	return configs, nil
+}
+
+// eventTypeKey is the home_assistant.events key for an event type. Test
+// goals carry no type and are sent as goals.
+func eventTypeKey(eventType models.EventType) string {
+	if eventType == "" {
+		return string(models.EventTypeGoal)
+	}
+	return string(eventType)
+}
+
+// eventConfigFor resolves the name and template for an event type: its own
+// entry, then default, then the goal event with the full payload. An
+// invalid name falls back to goal; EventWarnings reports it at startup.
+func eventConfigFor(eventType models.EventType) EventConfig {
+	configs, _ := eventConfigs()
+	key := eventTypeKey(eventType)
+	cfg := configs[key]
+	fallback := configs[defaultEventKey]
+	if cfg.Name == "" {
+		cfg.Name = fallback.Name
+	}
+	if cfg.Template == "" {
+		cfg.Template = fallback.Template
+	}
+	cfg.Name = strings.ReplaceAll(strings.TrimSpace(cfg.Name), "{type}", key)
+	if !validEventName.MatchString(cfg.Name) {
+		cfg.Name = defaultEventName
+	}
+	return cfg
+}
+
+// EventName returns the Home Assistant event type an event is fired as.
+func EventName(eventType models.EventType) string {
+	return eventConfigFor(eventType).Name
+}
+
+// eventPayload is the data fired with the event: the rich event, or the
+// configured template's JSON object. A template that fails still returns
+// the rich event, along with the error, so the event isn't lost.
+func eventPayload(event models.Event, cfg EventConfig) (interface{}, error) {
+	rich := createRichEvent(event)
+	if cfg.Template == "" {
+		return rich, nil
+	}
+	tmpl, err := template.New(eventTypeKey(event.Type)).Funcs(eventTemplateFuncs).Option("missingkey=error").Parse(cfg.Template)
+	if err != nil {
+		return rich, err
+	}
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, rich); err != nil {
+		return rich, err
+	}
+	var payload map[string]interface{}
+	if err := json.Unmarshal(buf.Bytes(), &payload); err != nil {
+		return rich, fmt.Errorf("template output is not a JSON object: %v", err)
+	}
+	return payload, nil
+}
+
+// EventWarnings explains each home_assistant.events entry that can't be
+// used as written.
+func EventWarnings() []string {
+	configs, err := eventConfigs()
+	if err != nil {
+		return []string{fmt.Sprintf("Ignoring home_assistant.events: %v", err)}
+	}
+	keys := make([]string, 0, len(configs))
+	for key := range configs {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	var warnings []string
+	for _, key := range keys {
+		cfg := configs[key]
+		name := strings.ReplaceAll(strings.TrimSpace(cfg.Name), "{type}", key)
+		if cfg.Name != "" && !validEventName.MatchString(name) {
+			warnings = append(warnings, fmt.Sprintf("home_assistant.events.%s: event name %q may only use letters, digits, _ and .; sending as %s", key, cfg.Name, defaultEventName))
+		}
+		if cfg.Template != "" {
+			if _, err := template.New(key).Funcs(eventTemplateFuncs).Parse(cfg.Template); err != nil {
+				warnings = append(warnings, fmt.Sprintf("home_assistant.events.%s: template doesn't parse, sending the full event instead: %v", key, err))
+			}
+		}
+	}
+	return warnings
+}
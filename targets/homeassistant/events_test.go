@@ -0,0 +1,85 @@
+package homeassistant
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+
+	"goalfeed/models"
+	"goalfeed/targets/applog"
+
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEventNameDefaultsToGoal(t *testing.T) {
+	viper.Reset()
+	assert.Equal(t, "goal", EventName(models.EventTypeTouchdown))
+	assert.Equal(t, "goal", EventName(""))
+}
+
+func TestEventNamePerType(t *testing.T) {
+	viper.Reset()
+	viper.Set("home_assistant.events", map[string]interface{}{
+		"default":      map[string]interface{}{"name": "goalfeed_{type}"},
+		"goal":         map[string]interface{}{"name": "goal"},
+		"period_start": map[string]interface{}{"name": "period started"},
+	})
+	assert.Equal(t, "goal", EventName(models.EventTypeGoal))
+	assert.Equal(t, "goal", EventName(""), "test goals are goals")
+	assert.Equal(t, "goalfeed_touchdown", EventName(models.EventTypeTouchdown))
+	assert.Equal(t, "goal", EventName(models.EventTypePeriodStart), "an invalid name falls back to goal")
+	assert.Len(t, EventWarnings(), 1)
+}
+
+func TestEventPayloadTemplate(t *testing.T) {
+	event := models.Event{Type: models.EventTypeGoal, TeamCode: "WPG", PlayerName: `Kyle "KC" Connor`, Period: 2}
+
+	payload, err := eventPayload(event, EventConfig{Template: `{"team": {{json .TeamCode}}, "scorer": {{json .PlayerName}}, "period": {{.Period}}}`})
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]interface{}{"team": "WPG", "scorer": `Kyle "KC" Connor`, "period": float64(2)}, payload)
+
+	payload, err = eventPayload(event, EventConfig{Template: `team {{.TeamCode}}`})
+	assert.Error(t, err)
+	assert.IsType(t, models.RichEvent{}, payload, "a broken template still sends the full event")
+
+	payload, err = eventPayload(event, EventConfig{})
+	assert.NoError(t, err)
+	assert.IsType(t, models.RichEvent{}, payload)
+}
+
+func TestEventWarningsForBadTemplate(t *testing.T) {
+	viper.Reset()
+	viper.Set("home_assistant.events", map[string]interface{}{
+		"goal": map[string]interface{}{"template": `{"team": {{.TeamCode}`},
+	})
+	assert.Len(t, EventWarnings(), 1)
+}
+
+func TestSendEventUsesConfiguredNameAndTemplate(t *testing.T) {
+	applog.SetLogFilePathForTest(filepath.Join(t.TempDir(), "app.log.jsonl"))
+	var path string
+	var body map[string]interface{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		path = r.URL.Path
+		raw, _ := io.ReadAll(r.Body)
+		_ = json.Unmarshal(raw, &body)
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+	withSupervisor(t, server.URL, "token")
+	viper.Reset()
+	viper.Set("home_assistant.events", map[string]interface{}{
+		"touchdown": map[string]interface{}{
+			"name":     "goalfeed_touchdown",
+			"template": `{"team": {{json .TeamCode}}, "league": {{json (lower .LeagueName)}}}`,
+		},
+	})
+
+	SendEvent(models.Event{Id: "td", Type: models.EventTypeTouchdown, TeamCode: "KC", LeagueId: int(models.LeagueIdNFL), LeagueName: "NFL"})
+	assert.Equal(t, "/core/api/events/goalfeed_touchdown", path)
+	assert.Equal(t, map[string]interface{}{"team": "KC", "league": "nfl"}, body)
+}
@@ -7,16 +7,35 @@ import (
 	"goalfeed/config"
 	"goalfeed/models"
 	"goalfeed/targets/applog"
+	"goalfeed/targets/deliveries"
+	"goalfeed/targets/mqtt"
 	"goalfeed/utils"
 	"net/http"
 	"os"
+	"strings"
 	"time"
 )
 
 var logger = utils.GetLogger()
 
-// SendEvent sends a detailed event to Home Assistant
+// SendEvent sends a detailed event to Home Assistant, as the event type and
+// payload home_assistant.events configures for it (goal by default)
 func SendEvent(event models.Event) {
+	if mqtt.Enabled() && event.Type == models.EventTypeGoal && event.TeamCode != "" {
+		device := mqttDevice(models.League(event.LeagueId), event.TeamCode)
+		rememberPublished("event." + device.ID + "_" + string(event.Type))
+		mqtt.PublishEvent(device, string(event.Type), map[string]interface{}{
+			"team_code":     event.TeamCode,
+			"opponent_code": event.OpponentCode,
+			"league":        event.LeagueName,
+			"game_code":     event.GameCode,
+			"player_name":   event.PlayerName,
+			"description":   event.Description,
+			"period":        event.Period,
+			"clock":         event.Clock,
+		})
+	}
+
 	// Detect if running inside Home Assistant add-on environment
 	homeAssistantURL := os.Getenv("SUPERVISOR_API")
 	accessToken := os.Getenv("SUPERVISOR_TOKEN")
@@ -31,60 +50,107 @@ func SendEvent(event models.Event) {
 		accessToken = config.GetString("home_assistant.access_token")
 	}
 
+	// The event type and payload follow home_assistant.events
+	eventConfig := eventConfigFor(event.Type)
+	target := "ha:event:" + eventConfig.Name
+
+	// Create a rich event with additional context, or the configured template's payload
+	payload, err := eventPayload(event, eventConfig)
+	if err != nil {
+		logger.Warn(fmt.Sprintf("Event template for %s failed, sending the full event: %v", eventTypeKey(event.Type), err))
+	}
+
+	// Other Home Assistants watching either team, once the primary has had
+	// it and whether or not it's set
+	defer sendEventToInstances(event, eventConfig.Name, payload)
+
 	if err := validateOutboundHAURL(homeAssistantURL); err != nil {
 		logger.Warn(err)
 		ok := false
-		applog.Append(models.AppLogEntry{Type: models.AppLogTypeEvent, LeagueId: models.League(event.LeagueId), LeagueName: event.LeagueName, TeamCode: event.TeamCode, Opponent: event.OpponentCode, GameCode: event.GameCode, Event: &event, Target: "ha:event:goal", Success: &ok, Error: err.Error(), CorrelationId: event.Id})
+		applog.Append(models.AppLogEntry{Type: models.AppLogTypeEvent, LeagueId: models.League(event.LeagueId), LeagueName: event.LeagueName, TeamCode: event.TeamCode, Opponent: event.OpponentCode, GameCode: event.GameCode, Event: &event, Target: target, Success: &ok, Error: err.Error(), CorrelationId: event.Id})
 		return
 	}
 
 	// Construct the URL for the Home Assistant event endpoint
-	url := homeAssistantURL + "/api/events/goal"
+	url := homeAssistantURL + "/api/events/" + eventConfig.Name
 
-	// Create a rich event with additional context
-	richEvent := createRichEvent(event)
+	if fireEventWS(eventConfig.Name, payload) {
+		logger.Info(fmt.Sprintf("Successfully sent %s event to Home Assistant", event.Type))
+		ok := true
+		applog.Append(models.AppLogEntry{Type: models.AppLogTypeEvent, LeagueId: models.League(event.LeagueId), LeagueName: event.LeagueName, TeamCode: event.TeamCode, Opponent: event.OpponentCode, GameCode: event.GameCode, Event: &event, Target: target, Success: &ok, CorrelationId: event.Id})
+		return
+	}
 
 	// Convert the event to JSON
-	jsonData, err := json.Marshal(richEvent)
+	jsonData, err := json.Marshal(payload)
 	if err != nil {
 		logger.Error("Failed to marshal event: " + err.Error())
 		return
 	}
 
-	// Create a new request
-	req, err := http.NewRequest("POST", url, bytes.NewBuffer(jsonData))
-	if err != nil {
-		logger.Error("Failed to create request: " + err.Error())
+	if err := postEvent(url, accessToken, jsonData); err != nil {
+		logger.Warn(err)
+		logger.Warn("Failed to send event to Home Assistant")
+		ok := false
+		applog.Append(models.AppLogEntry{Type: models.AppLogTypeEvent, LeagueId: models.League(event.LeagueId), LeagueName: event.LeagueName, TeamCode: event.TeamCode, Opponent: event.OpponentCode, GameCode: event.GameCode, Event: &event, Target: target, Success: &ok, Error: err.Error(), CorrelationId: event.Id})
+		// Retried from the delivery queue, e.g. while Home Assistant restarts
+		deliveries.Enqueue(deliveries.Delivery{Kind: eventDeliveryKind, Target: target, Name: eventConfig.Name, Payload: jsonData, Event: &event}, err)
 		return
 	}
+	logger.Info(fmt.Sprintf("Successfully sent %s event to Home Assistant", event.Type))
+	ok := true
+	applog.Append(models.AppLogEntry{Type: models.AppLogTypeEvent, LeagueId: models.League(event.LeagueId), LeagueName: event.LeagueName, TeamCode: event.TeamCode, Opponent: event.OpponentCode, GameCode: event.GameCode, Event: &event, Target: target, Success: &ok, CorrelationId: event.Id})
+}
 
-	// Set headers
+// eventDeliveryKind marks Home Assistant events in the delivery queue.
+const eventDeliveryKind = "ha_event"
+
+func init() {
+	deliveries.RegisterSender(eventDeliveryKind, resendEvent)
+}
+
+// postEvent posts an event's JSON to Home Assistant's REST API. A 4xx
+// other than 408 or 429 (a bad token, say) is marked permanent, since
+// sending the same request again won't change the answer.
+func postEvent(url, accessToken string, jsonData []byte) error {
+	req, err := http.NewRequest("POST", url, bytes.NewBuffer(jsonData))
+	if err != nil {
+		return deliveries.Permanent(err)
+	}
 	req.Header.Set("Authorization", "Bearer "+accessToken)
 	req.Header.Set("Content-Type", "application/json")
 
-	// Send the request
-	client := &http.Client{}
+	client := &http.Client{Timeout: 10 * time.Second}
 	resp, err := client.Do(req)
 	if err != nil {
-		logger.Warn(err)
-		logger.Warn("Failed to send event to Home Assistant")
-		ok := false
-		applog.Append(models.AppLogEntry{Type: models.AppLogTypeEvent, LeagueId: models.League(event.LeagueId), LeagueName: event.LeagueName, TeamCode: event.TeamCode, Opponent: event.OpponentCode, GameCode: event.GameCode, Event: &event, Target: "ha:event:goal", Success: &ok, Error: err.Error(), CorrelationId: event.Id})
-		return
+		return err
 	}
 	defer resp.Body.Close()
 
-	// Handle non-2xx status codes
 	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
-		logger.Warn(resp.Status)
-		logger.Warn("Failed to send event to Home Assistant")
-		ok := false
-		applog.Append(models.AppLogEntry{Type: models.AppLogTypeEvent, LeagueId: models.League(event.LeagueId), LeagueName: event.LeagueName, TeamCode: event.TeamCode, Opponent: event.OpponentCode, GameCode: event.GameCode, Event: &event, Target: "ha:event:goal", Success: &ok, Error: resp.Status, CorrelationId: event.Id})
-	} else {
-		logger.Info(fmt.Sprintf("Successfully sent %s event to Home Assistant", event.Type))
-		ok := true
-		applog.Append(models.AppLogEntry{Type: models.AppLogTypeEvent, LeagueId: models.League(event.LeagueId), LeagueName: event.LeagueName, TeamCode: event.TeamCode, Opponent: event.OpponentCode, GameCode: event.GameCode, Event: &event, Target: "ha:event:goal", Success: &ok, CorrelationId: event.Id})
+		err := fmt.Errorf("home assistant answered %s", resp.Status)
+		if resp.StatusCode >= 400 && resp.StatusCode < 500 && resp.StatusCode != http.StatusRequestTimeout && resp.StatusCode != http.StatusTooManyRequests {
+			return deliveries.Permanent(err)
+		}
+		return err
 	}
+	return nil
+}
+
+// resendEvent sends a queued event again, over the WebSocket connection
+// when it's up and REST otherwise.
+func resendEvent(d deliveries.Delivery) error {
+	haURL, token := getHAAuth()
+	if haURL == "" || token == "" {
+		return fmt.Errorf("home assistant not configured")
+	}
+	if err := validateOutboundHAURL(haURL); err != nil {
+		return err
+	}
+	if fireEventWS(d.Name, d.Payload) {
+		return nil
+	}
+	return postEvent(strings.TrimRight(haURL, "/")+"/api/events/"+d.Name, token, d.Payload)
 }
 
 // SendGameUpdate sends detailed game state updates to Home Assistant
@@ -138,6 +204,9 @@ func SendGameUpdate(game models.Game) {
 		"timestamp":  time.Now().Format(time.RFC3339),
 		"isFetching": game.IsFetching,
 	}
+	if fireEventWS("game_update", gameUpdate) {
+		return
+	}
 
 	jsonData, err := json.Marshal(gameUpdate)
 	if err != nil {
@@ -207,6 +276,9 @@ func SendPeriodUpdate(game models.Game, eventType models.EventType) {
 		"awayScore":  game.CurrentState.Away.Score,
 		"timestamp":  time.Now().Format(time.RFC3339),
 	}
+	if fireEventWS("period_update", periodUpdate) {
+		return
+	}
 
 	jsonData, err := json.Marshal(periodUpdate)
 	if err != nil {
@@ -271,11 +343,32 @@ func getLeagueName(leagueId models.League) string {
 		return "CFL"
 	case models.LeagueIdNFL:
 		return "NFL"
+	case models.LeagueIdNBA:
+		return "NBA"
+	case models.LeagueIdWNBA:
+		return "WNBA"
+	case models.LeagueIdMLS:
+		return "MLS"
+	case models.LeagueIdUCL:
+		return "UCL"
+	case models.LeagueIdPWHL:
+		return "PWHL"
+	case models.LeagueIdAHL:
+		return "AHL"
+	case models.LeagueIdNCAAF:
+		return "NCAAF"
+	case models.LeagueIdNCAAMHockey:
+		return "NCAAMH"
+	case models.LeagueIdNCAAWHockey:
+		return "NCAAWH"
 	case models.LeagueIdOlympicMensHockey:
 		return "Olympic Men's Hockey"
 	case models.LeagueIdOlympicWomensHockey:
 		return "Olympic Women's Hockey"
 	default:
+		if tournament, ok := config.TournamentForLeague(leagueId); ok {
+			return tournament.Name
+		}
 		return "Unknown"
 	}
 }
@@ -304,6 +397,9 @@ func SendCustomEvent(eventType string, data map[string]interface{}) {
 	// Add timestamp and source
 	data["timestamp"] = time.Now().Format(time.RFC3339)
 	data["source"] = "goalfeed"
+	if fireEventWS(eventType, data) {
+		return
+	}
 
 	jsonData, err := json.Marshal(data)
 	if err != nil {
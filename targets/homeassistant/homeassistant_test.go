@@ -2,6 +2,7 @@ package homeassistant
 
 import (
 	"encoding/json"
+	"io"
 	"net/http"
 	"net/http/httptest"
 	"os"
@@ -12,6 +13,7 @@ import (
 
 	"goalfeed/models"
 	"goalfeed/targets/applog"
+	"goalfeed/targets/deliveries"
 
 	"github.com/spf13/viper"
 	"github.com/stretchr/testify/assert"
@@ -47,6 +49,49 @@ func TestSendEvent_ErrorStatus(t *testing.T) {
 	SendEvent(models.Event{Id: "e", Type: models.EventTypeGoal, LeagueId: int(models.LeagueIdNHL), LeagueName: "NHL"})
 }
 
+func TestSendEvent_QueuesFailedDelivery(t *testing.T) {
+	applog.SetLogFilePathForTest(filepath.Join(t.TempDir(), "app.log.jsonl"))
+	deliveries.SetPathForTest(filepath.Join(t.TempDir(), "deliveries.json"))
+	status := http.StatusServiceUnavailable
+	var bodies []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		b, _ := io.ReadAll(r.Body)
+		bodies = append(bodies, string(b))
+		w.WriteHeader(status)
+	}))
+	defer server.Close()
+	withSupervisor(t, server.URL, "t")
+	viper.Reset()
+
+	SendEvent(models.Event{Id: "q", Type: models.EventTypeGoal, TeamCode: "WPG", LeagueId: int(models.LeagueIdNHL), LeagueName: "NHL"})
+	queued := deliveries.List()
+	assert.Len(t, queued, 1)
+	assert.Equal(t, deliveries.StatusPending, queued[0].Status)
+	assert.Equal(t, "ha:event:goal", queued[0].Target)
+
+	status = http.StatusOK
+	assert.NoError(t, deliveries.Retry(queued[0].Id))
+	assert.Empty(t, deliveries.List())
+	assert.Len(t, bodies, 2)
+	assert.JSONEq(t, bodies[0], bodies[1], "the retry sends the same payload")
+}
+
+func TestSendEvent_RejectedTokenIsDeadLettered(t *testing.T) {
+	applog.SetLogFilePathForTest(filepath.Join(t.TempDir(), "app.log.jsonl"))
+	deliveries.SetPathForTest(filepath.Join(t.TempDir(), "deliveries.json"))
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusUnauthorized)
+	}))
+	defer server.Close()
+	withSupervisor(t, server.URL, "t")
+	viper.Reset()
+
+	SendEvent(models.Event{Id: "q", Type: models.EventTypeGoal, TeamCode: "WPG", LeagueId: int(models.LeagueIdNHL), LeagueName: "NHL"})
+	queued := deliveries.List()
+	assert.Len(t, queued, 1)
+	assert.Equal(t, deliveries.StatusDead, queued[0].Status)
+}
+
 func TestSendGameUpdate_OK(t *testing.T) {
 	_, count := setupTestServer(t)
 	game := models.Game{GameCode: "g1", LeagueId: models.LeagueIdNHL}
@@ -109,6 +154,18 @@ func TestGetLeagueName(t *testing.T) {
 	if getLeagueName(models.LeagueIdNFL) != "NFL" {
 		t.Fatal("nfl")
 	}
+	if getLeagueName(models.LeagueIdNBA) != "NBA" || getLeagueName(models.LeagueIdWNBA) != "WNBA" {
+		t.Fatal("basketball")
+	}
+	if getLeagueName(models.LeagueIdEPL) != "EPL" || getLeagueName(models.LeagueIdMLS) != "MLS" || getLeagueName(models.LeagueIdUCL) != "UCL" {
+		t.Fatal("soccer")
+	}
+	if getLeagueName(models.LeagueIdPWHL) != "PWHL" || getLeagueName(models.LeagueIdAHL) != "AHL" {
+		t.Fatal("hockeytech")
+	}
+	if getLeagueName(models.LeagueIdNCAAF) != "NCAAF" || getLeagueName(models.LeagueIdNCAAMHockey) != "NCAAMH" || getLeagueName(models.LeagueIdNCAAWHockey) != "NCAAWH" {
+		t.Fatal("college")
+	}
 	if getLeagueName(models.LeagueIdOlympicMensHockey) != "Olympic Men's Hockey" {
 		t.Fatal("olympic men's hockey")
 	}
@@ -197,12 +254,13 @@ func TestSendEvent_EmptyEvent(t *testing.T) {
 }
 
 // TestSendEvent_AlwaysPostsToGoalEndpoint pins down the README's documented
-// guarantee: every event SendEvent transmits - real goal detections,
-// period-start notices, and synthetic test goals - is posted to the same HA
-// REST endpoint, /api/events/goal, regardless of the event's own Type field.
-// Home Assistant automations must therefore filter on teamCode/leagueId, not
-// on the HA event type, since there is only ever one HA event type: "goal".
+// default: with no home_assistant.events config, every event SendEvent
+// transmits - real goal detections, period-start notices, and synthetic test
+// goals - is posted to the same HA REST endpoint, /api/events/goal,
+// regardless of the event's own Type field, so existing automations that
+// filter on teamCode/leagueId keep working.
 func TestSendEvent_AlwaysPostsToGoalEndpoint(t *testing.T) {
+	viper.Reset()
 	var gotPaths []string
 	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		gotPaths = append(gotPaths, r.URL.Path)
@@ -241,11 +299,11 @@ func TestSendEvent_AlwaysPostsToGoalEndpoint(t *testing.T) {
 //
 // It uses 192.0.2.1 (TEST-NET-1, RFC 5737 - reserved for documentation and
 // guaranteed non-routable) as the "attacker" host. The outbound http.Client
-// used by SendEvent has no timeout set, so if the URL guard ever regresses,
-// a real connection attempt to that address would hang far longer than this
-// test's assertion window; bounding the call in a goroutine with a short
-// timeout turns "the guard silently stopped working" into a fast, clear
-// failure instead of a multi-minute hang.
+// used by SendEvent waits up to 10 seconds, so if the URL guard ever
+// regresses, a real connection attempt to that address would hang far longer
+// than this test's assertion window; bounding the call in a goroutine with a
+// short timeout turns "the guard silently stopped working" into a fast,
+// clear failure instead of a long hang.
 func TestHomeAssistantTarget_RefusesToSendTokenToUnvalidatedURL(t *testing.T) {
 	os.Unsetenv("SUPERVISOR_API")
 	os.Unsetenv("SUPERVISOR_TOKEN")
@@ -0,0 +1,464 @@
+package homeassistant
+
+import (
+	"encoding/json"
+	"fmt"
+	"goalfeed/config"
+	"goalfeed/models"
+	"goalfeed/targets/applog"
+	"goalfeed/targets/deliveries"
+	"goalfeed/utils"
+	"net/http"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/spf13/viper"
+)
+
+// Instance is an extra Home Assistant under home_assistant.instances. The
+// one set by home_assistant.url (or the Supervisor) stays the primary:
+// the WebSocket connection, MQTT, actions and pruning only ever talk to
+// it. Instances get events and team sensors over REST. Every team has to
+// be on the top-level watch list to be followed at all.
+//
+//	home_assistant:
+//	  instances:
+//	    - name: cabin
+//	      url: "http://192.168.2.10:8123"
+//	      access_token: "..."
+//	      prefix: cabin
+//	      watch:
+//	        nhl: [WPG]
+type Instance struct {
+	Name        string `mapstructure:"name"`
+	URL         string `mapstructure:"url"`
+	AccessToken string `mapstructure:"access_token"`
+	// Prefix namespaces its entity IDs, home_assistant.entities.prefix by
+	// default
+	Prefix string `mapstructure:"prefix"`
+	// Watch narrows what it's sent, keyed like the top-level watch. A
+	// league it leaves out isn't sent; without a watch it gets everything.
+	// conf: entries match a team once a game has given its conference.
+	Watch map[string][]string `mapstructure:"watch"`
+}
+
+// InstanceStatus is one Home Assistant in /api/homeassistant/status.
+type InstanceStatus struct {
+	Name      string `json:"name"`
+	URL       string `json:"url"`
+	TokenSet  bool   `json:"tokenSet"`
+	Connected bool   `json:"connected"`
+	Message   string `json:"message"`
+}
+
+// primaryInstanceName is what the primary Home Assistant is called in the
+// status and the app log.
+const primaryInstanceName = "default"
+
+var validInstanceName = regexp.MustCompile(`^[a-z0-9_-]+$`)
+
+func instanceConfigs() ([]Instance, error) {
+	var instances []Instance
+	if err := viper.UnmarshalKey("home_assistant.instances", &instances); err != nil {
+		return nil, err
+	}
+	return instances, nil
+}
+
+var (
+	instancesMu     sync.Mutex
+	instancesLoaded bool
+	loadedInstances []Instance
+)
+
+// Instances returns the usable home_assistant.instances entries; those
+// InstanceWarnings complains about are left out. They're read once, and
+// again after ReloadInstances, rather than on every publish.
+func Instances() []Instance {
+	instancesMu.Lock()
+	defer instancesMu.Unlock()
+	loadInstancesLocked()
+	return loadedInstances
+}
+
+// ReloadInstances rereads home_assistant.instances after the configuration changed.
+func ReloadInstances() {
+	instancesMu.Lock()
+	defer instancesMu.Unlock()
+	instancesLoaded = false
+	loadInstancesLocked()
+}
+
+func loadInstancesLocked() {
+	if instancesLoaded {
+		return
+	}
+	loadedInstances = readInstances()
+	instancesLoaded = true
+}
+
+func readInstances() []Instance {
+	configs, err := instanceConfigs()
+	if err != nil {
+		return nil
+	}
+	seen := map[string]bool{primaryInstanceName: true}
+	var instances []Instance
+	for _, inst := range configs {
+		inst.Name = strings.TrimSpace(inst.Name)
+		inst.URL = strings.TrimRight(strings.TrimSpace(inst.URL), "/")
+		if instanceProblem(inst, seen) != "" {
+			continue
+		}
+		seen[inst.Name] = true
+		instances = append(instances, inst)
+	}
+	return instances
+}
+
+// instanceProblem says why an instance can't be used, or "". URLs are
+// checked the same way validateOutboundHAURL checks them before a send.
+func instanceProblem(inst Instance, seen map[string]bool) string {
+	switch {
+	case !validInstanceName.MatchString(inst.Name):
+		return fmt.Sprintf("name %q may only use a-z, 0-9, _ and -", inst.Name)
+	case seen[inst.Name]:
+		return fmt.Sprintf("name %q is already taken", inst.Name)
+	case inst.AccessToken == "":
+		return "access_token is not set"
+	}
+	if err := utils.ValidateHomeAssistantURL(inst.URL, config.GetBool("home_assistant.allow_remote_url")); err != nil {
+		return err.Error()
+	}
+	return ""
+}
+
+// InstanceWarnings explains each home_assistant.instances entry that is
+// skipped, and each instance watch that names a league Goalfeed doesn't
+// know or a team the top-level watch list doesn't follow.
+func InstanceWarnings() []string {
+	configs, err := instanceConfigs()
+	if err != nil {
+		return []string{fmt.Sprintf("Ignoring home_assistant.instances: %v", err)}
+	}
+	var warnings []string
+	seen := map[string]bool{primaryInstanceName: true}
+	for i, inst := range configs {
+		inst.Name = strings.TrimSpace(inst.Name)
+		inst.URL = strings.TrimRight(strings.TrimSpace(inst.URL), "/")
+		if problem := instanceProblem(inst, seen); problem != "" {
+			warnings = append(warnings, fmt.Sprintf("home_assistant.instances[%d]: skipped: %s", i, problem))
+			continue
+		}
+		seen[inst.Name] = true
+		for _, problem := range watchProblems(inst.Watch) {
+			warnings = append(warnings, fmt.Sprintf("home_assistant.instances[%d] (%s): %s", i, inst.Name, problem))
+		}
+	}
+	return warnings
+}
+
+// watchProblems lists the leagues in a watch Goalfeed doesn't know, and its
+// teams the top-level watch list doesn't follow, which are never sent. A
+// top-level conf: entry might take any team in, so those aren't flagged.
+func watchProblems(watch map[string][]string) []string {
+	known := map[string]bool{}
+	for _, lc := range watchedLeagues() {
+		known[lc.key] = true
+	}
+	var problems []string
+	keys := make([]string, 0, len(watch))
+	for key := range watch {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		if !known[key] {
+			problems = append(problems, fmt.Sprintf("unknown league %q in watch", key))
+			continue
+		}
+		followed := config.GetStringSlice("watch." + key)
+		for _, entry := range watch[key] {
+			entry = strings.TrimSpace(entry)
+			if _, conference := models.WatchConference(entry); entry == "" || entry == "*" || conference {
+				continue
+			}
+			if !followsTeam(followed, entry) {
+				problems = append(problems, fmt.Sprintf("%s isn't on watch.%s, so it's never sent", entry, key))
+			}
+		}
+	}
+	return problems
+}
+
+func followsTeam(followed []string, teamCode string) bool {
+	for _, entry := range followed {
+		entry = strings.TrimSpace(entry)
+		if _, conference := models.WatchConference(entry); entry == "*" || conference || strings.EqualFold(entry, teamCode) {
+			return true
+		}
+	}
+	return false
+}
+
+// watches reports whether the instance wants a team's events and sensors.
+// A conf: entry matches the team once a game has given its conference; an
+// empty teamCode only matches a * entry.
+func (inst Instance) watches(league models.League, teamCode string) bool {
+	if len(inst.Watch) == 0 {
+		return true
+	}
+	team := models.Team{}
+	if teamCode != "" {
+		team = knownTeam(league, teamCode)
+	}
+	for _, lc := range watchedLeagues() {
+		if lc.id != league {
+			continue
+		}
+		for _, entry := range inst.Watch[lc.key] {
+			if team.MatchesWatchEntry(entry) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// watchesEvent reports whether the instance wants an event about either
+// team or, for one about no team in particular like a period start, about
+// either team in the game.
+func (inst Instance) watchesEvent(event models.Event) bool {
+	league := models.League(event.LeagueId)
+	codes := []string{event.TeamCode, event.OpponentCode}
+	if event.TeamCode == "" && event.OpponentCode == "" {
+		codes = []string{event.Score.HomeTeam, event.Score.AwayTeam}
+	}
+	for _, code := range codes {
+		if code != "" && inst.watches(league, code) {
+			return true
+		}
+	}
+	return inst.watches(league, "")
+}
+
+// naming is the entity naming with the instance's prefix.
+func (inst Instance) naming() EntityNaming {
+	naming := entityNaming()
+	if inst.Prefix != "" {
+		naming.Prefix = inst.Prefix
+		naming = naming.normalized()
+	}
+	return naming
+}
+
+// publishToInstances queues a team sensor for each instance watching the
+// team. Each has its own state cache, so one that was down catches up.
+func publishToInstances(domain string, league models.League, teamCode, metric string, value interface{}, attrs map[string]interface{}) {
+	for _, inst := range Instances() {
+		if !inst.watches(league, teamCode) {
+			continue
+		}
+		naming := inst.naming()
+		entity := naming.entityName(league, teamCode, metric)
+		queueFor(inst).push(domain+"."+entity, instanceState{
+			domain:   domain,
+			entity:   entity,
+			friendly: naming.friendlyName(league, teamCode, metric),
+			value:    value,
+			attrs:    metricAttributes(metric, attrs),
+		})
+	}
+}
+
+// instanceState is a sensor state waiting to go to an instance.
+type instanceState struct {
+	domain, entity, friendly string
+	value                    interface{}
+	attrs                    map[string]interface{}
+}
+
+// instanceQueue sends an instance its sensors in the background, so a slow
+// or unreachable one never holds up the primary or the poll loop. Only the
+// latest state of each entity waits, so a backlog can't outgrow the
+// entities.
+type instanceQueue struct {
+	mu      sync.Mutex
+	inst    Instance
+	pending map[string]instanceState
+	order   []string
+	sending bool
+	wake    chan struct{}
+}
+
+var (
+	queuesMu       sync.Mutex
+	instanceQueues = map[string]*instanceQueue{}
+)
+
+// queueFor returns the instance's queue, starting its sender the first
+// time.
+func queueFor(inst Instance) *instanceQueue {
+	queuesMu.Lock()
+	defer queuesMu.Unlock()
+	q := instanceQueues[inst.Name]
+	if q == nil {
+		q = &instanceQueue{pending: map[string]instanceState{}, wake: make(chan struct{}, 1)}
+		instanceQueues[inst.Name] = q
+		go q.run()
+	}
+	q.mu.Lock()
+	q.inst = inst
+	q.mu.Unlock()
+	return q
+}
+
+func (q *instanceQueue) push(key string, state instanceState) {
+	q.mu.Lock()
+	if _, ok := q.pending[key]; !ok {
+		q.order = append(q.order, key)
+	}
+	q.pending[key] = state
+	q.mu.Unlock()
+	select {
+	case q.wake <- struct{}{}:
+	default:
+	}
+}
+
+func (q *instanceQueue) run() {
+	for range q.wake {
+		for {
+			q.mu.Lock()
+			if len(q.order) == 0 {
+				q.sending = false
+				q.mu.Unlock()
+				break
+			}
+			key := q.order[0]
+			q.order = q.order[1:]
+			state := q.pending[key]
+			delete(q.pending, key)
+			inst := q.inst
+			q.sending = true
+			q.mu.Unlock()
+
+			cacheMu.Lock()
+			cache := instanceCaches[inst.Name]
+			if cache == nil {
+				cache = map[string]entityCacheEntry{}
+				instanceCaches[inst.Name] = cache
+			}
+			cacheMu.Unlock()
+			publishEntityTo(cache, inst.URL, inst.AccessToken, state.domain, state.entity, state.friendly, state.value, state.attrs)
+		}
+	}
+}
+
+// idle reports whether nothing is waiting or being sent.
+func (q *instanceQueue) idle() bool {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	return len(q.order) == 0 && !q.sending
+}
+
+// instanceEventDeliveryKind marks instance events in the delivery queue;
+// the delivery's Name is <instance>/<event name>.
+const instanceEventDeliveryKind = "ha_instance_event"
+
+func init() {
+	deliveries.RegisterSender(instanceEventDeliveryKind, resendInstanceEvent)
+}
+
+// sendEventToInstances fires an event on each instance watching either
+// team, each in its own goroutine so none waits on another, queuing the
+// sends that fail.
+func sendEventToInstances(event models.Event, name string, payload interface{}) {
+	var jsonData []byte
+	for _, inst := range Instances() {
+		if !inst.watchesEvent(event) {
+			continue
+		}
+		if jsonData == nil {
+			b, err := json.Marshal(payload)
+			if err != nil {
+				logger.Error("Failed to marshal event: " + err.Error())
+				return
+			}
+			jsonData = b
+		}
+		go sendEventToInstance(inst, event, name, jsonData)
+	}
+}
+
+func sendEventToInstance(inst Instance, event models.Event, name string, jsonData []byte) {
+	target := fmt.Sprintf("ha:%s:event:%s", inst.Name, name)
+	err := validateOutboundHAURL(inst.URL)
+	if err == nil {
+		err = postEvent(inst.URL+"/api/events/"+name, inst.AccessToken, jsonData)
+	}
+	ok := err == nil
+	entry := models.AppLogEntry{Type: models.AppLogTypeEvent, LeagueId: models.League(event.LeagueId), LeagueName: event.LeagueName, TeamCode: event.TeamCode, Opponent: event.OpponentCode, GameCode: event.GameCode, Event: &event, Target: target, Success: &ok, CorrelationId: event.Id}
+	if err != nil {
+		logger.Warn(fmt.Sprintf("Failed to send event to Home Assistant %s: %v", inst.Name, err))
+		entry.Error = err.Error()
+		applog.Append(entry)
+		deliveries.Enqueue(deliveries.Delivery{Kind: instanceEventDeliveryKind, Target: target, Name: inst.Name + "/" + name, Payload: jsonData, Event: &event}, err)
+		return
+	}
+	logger.Info(fmt.Sprintf("Successfully sent %s event to Home Assistant %s", event.Type, inst.Name))
+	applog.Append(entry)
+}
+
+// resendInstanceEvent sends a queued instance event again, to the instance
+// as it's configured now.
+func resendInstanceEvent(d deliveries.Delivery) error {
+	instName, name, _ := strings.Cut(d.Name, "/")
+	for _, inst := range Instances() {
+		if inst.Name != instName {
+			continue
+		}
+		if err := validateOutboundHAURL(inst.URL); err != nil {
+			return deliveries.Permanent(err)
+		}
+		return postEvent(inst.URL+"/api/events/"+name, inst.AccessToken, d.Payload)
+	}
+	return deliveries.Permanent(fmt.Errorf("home assistant instance %q is no longer configured", instName))
+}
+
+// CheckInstances checks each instance's REST API.
+func CheckInstances(timeout time.Duration) []InstanceStatus {
+	instances := Instances()
+	statuses := make([]InstanceStatus, 0, len(instances))
+	for _, inst := range instances {
+		status := InstanceStatus{Name: inst.Name, URL: inst.URL, TokenSet: inst.AccessToken != ""}
+		status.Connected, status.Message = checkREST(inst.URL, inst.AccessToken, timeout)
+		statuses = append(statuses, status)
+	}
+	return statuses
+}
+
+// checkREST makes an authenticated GET /api/ request.
+func checkREST(url, token string, timeout time.Duration) (bool, string) {
+	if err := validateOutboundHAURL(url); err != nil {
+		return false, fmt.Sprintf("refusing to check connection: %v", err)
+	}
+	client := &http.Client{Timeout: timeout}
+	req, err := http.NewRequest("GET", strings.TrimRight(url, "/")+"/api/", nil)
+	if err != nil {
+		return false, fmt.Sprintf("request error: %v", err)
+	}
+	req.Header.Set("Authorization", "Bearer "+token)
+	resp, err := client.Do(req)
+	if err != nil {
+		return false, fmt.Sprintf("connection error: %v", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode >= 200 && resp.StatusCode < 300 {
+		return true, "OK"
+	}
+	return false, fmt.Sprintf("HTTP %s", resp.Status)
+}
@@ -0,0 +1,183 @@
+package homeassistant
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"goalfeed/models"
+	"goalfeed/targets/applog"
+	"goalfeed/targets/deliveries"
+
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+)
+
+// fakeHA records the paths posted to a fake Home Assistant, which answers
+// with status. Instances are sent to in the background, so it's locked.
+type fakeHA struct {
+	mu     sync.Mutex
+	posted []string
+	status int
+}
+
+func (f *fakeHA) paths() []string {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return append([]string(nil), f.posted...)
+}
+
+func (f *fakeHA) setStatus(status int) {
+	f.mu.Lock()
+	f.status = status
+	f.mu.Unlock()
+}
+
+// fakeInstance is a Home Assistant that records the paths posted to it.
+func fakeInstance(t *testing.T) (*httptest.Server, *fakeHA) {
+	ha := &fakeHA{status: http.StatusOK}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ha.mu.Lock()
+		defer ha.mu.Unlock()
+		ha.posted = append(ha.posted, r.URL.Path)
+		w.WriteHeader(ha.status)
+	}))
+	t.Cleanup(server.Close)
+	return server, ha
+}
+
+// setInstances configures home_assistant.instances for a test, clearing
+// them again once it's done and the instances have been sent everything.
+func setInstances(t *testing.T, instances []map[string]interface{}) {
+	viper.Set("home_assistant.instances", instances)
+	ReloadInstances()
+	t.Cleanup(func() {
+		waitForInstances(t)
+		viper.Set("home_assistant.instances", nil)
+		ReloadInstances()
+	})
+}
+
+// waitForInstances waits until every instance has been sent what was
+// queued for it.
+func waitForInstances(t *testing.T) {
+	assert.Eventually(t, func() bool {
+		queuesMu.Lock()
+		defer queuesMu.Unlock()
+		for _, q := range instanceQueues {
+			if !q.idle() {
+				return false
+			}
+		}
+		return true
+	}, time.Second, 5*time.Millisecond)
+}
+
+func TestInstanceWarnings(t *testing.T) {
+	viper.Reset()
+	viper.Set("watch.nhl", []string{"WPG"})
+	setInstances(t, []map[string]interface{}{
+		{"name": "cabin", "url": "http://192.168.2.10:8123", "access_token": "t", "watch": map[string]interface{}{"nhl": []string{"WPG", "EDM"}, "ncaaf": []string{"Conf:SEC"}, "xfl": []string{"*"}}},
+		{"name": "cabin", "url": "http://192.168.2.11:8123", "access_token": "t"},
+		{"name": "Main House", "url": "http://192.168.2.12:8123", "access_token": "t"},
+		{"name": "default", "url": "http://192.168.2.13:8123", "access_token": "t"},
+		{"name": "shed", "url": "http://192.168.2.14:8123"},
+		{"name": "remote", "url": "https://ha.example.com", "access_token": "t"},
+	})
+	warnings := InstanceWarnings()
+	assert.Len(t, warnings, 7)
+	assert.Contains(t, warnings, "home_assistant.instances[0] (cabin): EDM isn't on watch.nhl, so it's never sent")
+	assert.NotContains(t, warnings, "home_assistant.instances[0] (cabin): Conf:SEC isn't on watch.ncaaf, so it's never sent", "a conference entry isn't a team")
+	instances := Instances()
+	assert.Len(t, instances, 1)
+	assert.Equal(t, "cabin", instances[0].Name)
+
+	viper.Set("home_assistant.allow_remote_url", true)
+	assert.Len(t, Instances(), 1, "read once, not on every call")
+	ReloadInstances()
+	assert.Len(t, Instances(), 2, "allow_remote_url lets a public URL through")
+}
+
+func TestInstanceWatches(t *testing.T) {
+	everything := Instance{Name: "all"}
+	assert.True(t, everything.watches(models.LeagueIdNFL, "KC"))
+
+	cabin := Instance{Name: "cabin", Watch: map[string][]string{"nhl": {"WPG"}, "ncaaf": {"conf:Big Ten"}}}
+	assert.True(t, cabin.watches(models.LeagueIdNHL, "wpg"))
+	assert.False(t, cabin.watches(models.LeagueIdNHL, "TOR"))
+	assert.False(t, cabin.watches(models.LeagueIdNFL, "KC"), "a league left out isn't sent")
+
+	rememberTeam(models.LeagueIdNCAAF, models.Team{TeamCode: "MICH", TeamName: "Michigan Wolverines", Conference: "Big Ten"})
+	rememberTeam(models.LeagueIdNCAAF, models.Team{TeamCode: "UGA", TeamName: "Georgia Bulldogs", Conference: "SEC"})
+	assert.True(t, cabin.watches(models.LeagueIdNCAAF, "MICH"))
+	assert.True(t, Instance{Name: "lake", Watch: map[string][]string{"ncaaf": {"CONF:big ten"}}}.watches(models.LeagueIdNCAAF, "MICH"), "the prefix ignores case")
+	assert.False(t, cabin.watches(models.LeagueIdNCAAF, "UGA"), "conf: only takes its own conference")
+	assert.False(t, cabin.watches(models.LeagueIdNCAAF, "OSU"), "nor a team whose conference isn't known yet")
+
+	periodStart := models.Event{Type: models.EventTypePeriodStart, LeagueId: int(models.LeagueIdNHL), Score: models.ScoreUpdate{HomeTeam: "WPG", AwayTeam: "TOR"}}
+	assert.True(t, cabin.watchesEvent(periodStart), "an event of no team goes by the game's teams")
+	periodStart.Score = models.ScoreUpdate{HomeTeam: "MTL", AwayTeam: "TOR"}
+	assert.False(t, cabin.watchesEvent(periodStart))
+}
+
+func TestSensorsFanOutToInstances(t *testing.T) {
+	applog.SetLogFilePathForTest(filepath.Join(t.TempDir(), "app.log.jsonl"))
+	primary, primaryHA := fakeInstance(t)
+	cabin, cabinHA := fakeInstance(t)
+	withSupervisor(t, primary.URL, "token")
+	viper.Reset()
+	setInstances(t, []map[string]interface{}{
+		{"name": "cabin", "url": cabin.URL, "access_token": "t", "prefix": "cabin", "watch": map[string]interface{}{"nhl": []string{"WPG"}}},
+	})
+	debounceAfter = 0
+	entityCache = map[string]entityCacheEntry{}
+	instanceCaches = map[string]map[string]entityCacheEntry{}
+
+	publishSensor(models.LeagueIdNHL, "WPG", "team.shots", 12, nil)
+	publishSensor(models.LeagueIdNHL, "TOR", "team.shots", 9, nil)
+	publishSensor(models.LeagueIdNHL, "WPG", "team.shots", 12, nil)
+	waitForInstances(t)
+
+	assert.Equal(t, []string{
+		"/core/api/states/sensor.goalfeed_nhl_wpg_team_shots",
+		"/core/api/states/sensor.goalfeed_nhl_tor_team_shots",
+	}, primaryHA.paths())
+	assert.Equal(t, []string{"/api/states/sensor.cabin_nhl_wpg_team_shots"}, cabinHA.paths(), "deduped per instance")
+}
+
+func TestEventsFanOutAndQueuePerInstance(t *testing.T) {
+	applog.SetLogFilePathForTest(filepath.Join(t.TempDir(), "app.log.jsonl"))
+	deliveries.SetPathForTest(filepath.Join(t.TempDir(), "deliveries.json"))
+	primary, primaryHA := fakeInstance(t)
+	cabin, cabinHA := fakeInstance(t)
+	lake, lakeHA := fakeInstance(t)
+	withSupervisor(t, primary.URL, "token")
+	viper.Reset()
+	viper.Set("home_assistant.websocket.enabled", false)
+	setInstances(t, []map[string]interface{}{
+		{"name": "cabin", "url": cabin.URL, "access_token": "t", "watch": map[string]interface{}{"nhl": []string{"WPG"}}},
+		{"name": "lake", "url": lake.URL, "access_token": "t", "watch": map[string]interface{}{"nfl": []string{"*"}}},
+	})
+	cabinHA.setStatus(http.StatusBadGateway)
+
+	// TOR scoring on WPG still matters to the cabin
+	SendEvent(models.Event{Id: "g", Type: models.EventTypeGoal, TeamCode: "TOR", OpponentCode: "WPG", LeagueId: int(models.LeagueIdNHL), LeagueName: "NHL"})
+	assert.Equal(t, []string{"/core/api/events/goal"}, primaryHA.paths())
+	assert.Eventually(t, func() bool { return len(deliveries.List()) == 1 }, time.Second, 5*time.Millisecond, "the cabin's send fails in the background")
+	assert.Equal(t, []string{"/api/events/goal"}, cabinHA.paths())
+	assert.Empty(t, lakeHA.paths())
+
+	queued := deliveries.List()
+	assert.Len(t, queued, 1)
+	assert.Equal(t, "ha:cabin:event:goal", queued[0].Target)
+	assert.True(t, strings.HasPrefix(queued[0].Name, "cabin/"))
+
+	cabinHA.setStatus(http.StatusOK)
+	assert.NoError(t, deliveries.Retry(queued[0].Id))
+	assert.Len(t, cabinHA.paths(), 2)
+	assert.Empty(t, deliveries.List())
+}
@@ -0,0 +1,115 @@
+package homeassistant
+
+import "sort"
+
+// metricInfo is what Home Assistant is told about a metric so it can graph
+// it and keep long-term statistics. Metrics without a state class are text
+// or on/off and get no statistics.
+type metricInfo struct {
+	// Component is sensor or binary_sensor
+	Component   string
+	StateClass  string
+	Unit        string
+	DeviceClass string
+}
+
+const (
+	stateClassMeasurement = "measurement"
+	deviceClassTimestamp  = "timestamp"
+)
+
+func measurement(unit string) metricInfo {
+	return metricInfo{Component: "sensor", StateClass: stateClassMeasurement, Unit: unit}
+}
+
+var (
+	textSensor   = metricInfo{Component: "sensor"}
+	binarySensor = metricInfo{Component: "binary_sensor"}
+)
+
+// metricRegistry holds every team.* metric Goalfeed publishes. Scores have
+// no unit since a goal, a run and a point aren't the same thing.
+var metricRegistry = map[string]metricInfo{
+	"team.status":          textSensor,
+	"team.has_game_today":  binarySensor,
+	"team.has_active_game": {Component: "binary_sensor", DeviceClass: "running"},
+	"team.current_score":   measurement(""),
+	"team.opponent":        textSensor,
+	"team.home_away":       textSensor,
+	"team.next_game_date":  {Component: "sensor", DeviceClass: deviceClassTimestamp},
+	"team.clock":           textSensor,
+	"team.period":          measurement(""),
+
+	// Baseball
+	"team.is_batting":      binarySensor,
+	"team.balls":           measurement(""),
+	"team.strikes":         measurement(""),
+	"team.outs":            measurement(""),
+	"team.runners_on_base": textSensor,
+	"team.current_pitcher": textSensor,
+	"team.current_batter":  textSensor,
+
+	// Football
+	"team.has_possession": binarySensor,
+	"team.down":           measurement(""),
+	"team.distance":       measurement("yd"),
+	"team.yard_line":      measurement("yd"),
+	"team.red_zone":       binarySensor,
+
+	// Basketball
+	"team.lead":    measurement("pts"),
+	"team.leading": binarySensor,
+	"team.run":     measurement("pts"),
+
+	// Soccer
+	"team.added_time": measurement("min"),
+
+	// Hockey
+	"team.shots":         measurement(""),
+	"team.penalties":     measurement(""),
+	"team.goalie_pulled": binarySensor,
+
+	// College
+	"team.rank": measurement(""),
+}
+
+// teamMetrics lists the registered metrics, sorted.
+func teamMetrics() []string {
+	metrics := make([]string, 0, len(metricRegistry))
+	for metric := range metricRegistry {
+		metrics = append(metrics, metric)
+	}
+	sort.Strings(metrics)
+	return metrics
+}
+
+// metricAttributes returns attrs with the metric's state_class,
+// unit_of_measurement and device_class added, for a state sent over REST.
+func metricAttributes(metric string, attrs map[string]interface{}) map[string]interface{} {
+	out := map[string]interface{}{}
+	info := metricRegistry[metric]
+	if info.StateClass != "" {
+		out["state_class"] = info.StateClass
+	}
+	if info.Unit != "" {
+		out["unit_of_measurement"] = info.Unit
+	}
+	if info.DeviceClass != "" {
+		out["device_class"] = info.DeviceClass
+	}
+	for k, v := range attrs {
+		out[k] = v
+	}
+	return out
+}
+
+// mqttState is the state sent over MQTT. A numeric or timestamp sensor
+// rejects placeholder text like unknown, so it is sent as None, which Home
+// Assistant shows as unknown.
+func mqttState(metric, state string) string {
+	info := metricRegistry[metric]
+	if (info.StateClass != "" || info.DeviceClass == deviceClassTimestamp) && (state == "" || state == "unknown") {
+		return "None"
+	}
+	return state
+}
@@ -0,0 +1,86 @@
+package homeassistant
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"goalfeed/models"
+	"goalfeed/targets/applog"
+
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBaselineMetricsAreRegistered(t *testing.T) {
+	applog.SetLogFilePathForTest(filepath.Join(t.TempDir(), "app.log.jsonl"))
+	viper.Reset()
+	for _, lc := range watchedLeagues() {
+		viper.Set("watch."+lc.key, []string{"ABC"})
+	}
+	cacheMu.Lock()
+	entityCache = map[string]entityCacheEntry{}
+	cacheMu.Unlock()
+
+	PublishBaselineForMonitoredTeams()
+	assert.NotEmpty(t, entityCache)
+	naming := entityNaming()
+	for key := range entityCache {
+		domain, name, _ := strings.Cut(key, ".")
+		_, _, metric, ok := naming.parseEntityName(domain, name)
+		assert.True(t, ok, key)
+		assert.Equal(t, domain, metricRegistry[metric].Component, key)
+	}
+}
+
+func TestSensorsCarryMetricMetadata(t *testing.T) {
+	applog.SetLogFilePathForTest(filepath.Join(t.TempDir(), "app.log.jsonl"))
+	var mu sync.Mutex
+	bodies := map[string]map[string]interface{}{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		raw, _ := io.ReadAll(r.Body)
+		var body map[string]interface{}
+		_ = json.Unmarshal(raw, &body)
+		mu.Lock()
+		bodies[strings.TrimPrefix(r.URL.Path, "/core/api/states/")] = body
+		mu.Unlock()
+	}))
+	defer server.Close()
+	withSupervisor(t, server.URL, "token")
+	viper.Reset()
+	debounceAfter = 0
+	entityCache = map[string]entityCacheEntry{}
+
+	publishSensor(models.LeagueIdNHL, "WPG", "team.shots", 12, nil)
+	publishSensor(models.LeagueIdNFL, "KC", "team.distance", 7, nil)
+	publishSensor(models.LeagueIdNHL, "WPG", "team.next_game_date", time.Date(2026, 10, 20, 23, 0, 0, 0, time.UTC), nil)
+	publishSensor(models.LeagueIdNHL, "WPG", "team.opponent", "TOR", nil)
+
+	attrs := func(entityID string) map[string]interface{} {
+		return bodies[entityID]["attributes"].(map[string]interface{})
+	}
+	assert.Equal(t, "measurement", attrs("sensor.goalfeed_nhl_wpg_team_shots")["state_class"])
+	assert.NotContains(t, attrs("sensor.goalfeed_nhl_wpg_team_shots"), "unit_of_measurement")
+	assert.Equal(t, "yd", attrs("sensor.goalfeed_nfl_kc_team_distance")["unit_of_measurement"])
+	assert.Equal(t, "timestamp", attrs("sensor.goalfeed_nhl_wpg_team_next_game_date")["device_class"])
+	assert.Equal(t, "2026-10-20T23:00:00Z", bodies["sensor.goalfeed_nhl_wpg_team_next_game_date"]["state"])
+	assert.NotContains(t, attrs("sensor.goalfeed_nhl_wpg_team_opponent"), "state_class")
+}
+
+func TestMQTTEntitiesCarryMetricMetadata(t *testing.T) {
+	entity := mqttEntity("sensor", models.LeagueIdNFL, "KC", "team.yard_line")
+	assert.Equal(t, "measurement", entity.StateClass)
+	assert.Equal(t, "yd", entity.Unit)
+	assert.Equal(t, "running", mqttEntity("binary_sensor", models.LeagueIdNFL, "KC", "team.has_active_game").DeviceClass)
+
+	assert.Equal(t, "None", mqttState("team.next_game_date", "unknown"), "a timestamp sensor won't take unknown")
+	assert.Equal(t, "None", mqttState("team.shots", ""))
+	assert.Equal(t, "12", mqttState("team.shots", "12"))
+	assert.Equal(t, "unknown", mqttState("team.opponent", "unknown"))
+}
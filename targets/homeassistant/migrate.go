@@ -0,0 +1,116 @@
+package homeassistant
+
+import (
+	"fmt"
+	"goalfeed/models"
+	"goalfeed/targets/applog"
+	"goalfeed/targets/mqtt"
+	"sort"
+	"strings"
+	"time"
+)
+
+// MigrationResult is one entity MigrateEntityNames renamed.
+type MigrationResult struct {
+	From  string `json:"from"`
+	To    string `json:"to"`
+	Error string `json:"error,omitempty"`
+}
+
+// MigrateEntityNames renames the entities Home Assistant holds under the
+// naming from to the one in home_assistant.entities. A state written over
+// REST is copied to its new ID, with the new friendly name, and the old one
+// deleted. An MQTT entity has its old discovery config cleared; Goalfeed
+// announces it under the new name the next time it publishes the metric.
+// A dry run only reports what would be renamed.
+func MigrateEntityNames(from EntityNaming, dryRun bool) ([]MigrationResult, error) {
+	from = from.normalized()
+	to := entityNaming()
+	states, err := listStates()
+	if err != nil {
+		return nil, err
+	}
+	sort.Slice(states, func(i, j int) bool { return states[i].EntityID < states[j].EntityID })
+
+	useMQTT := mqtt.Enabled()
+	if useMQTT && !dryRun {
+		mqtt.Start()
+		mqtt.WaitConnected(5 * time.Second)
+	}
+
+	var results []MigrationResult
+	for _, state := range states {
+		domain, name, ok := strings.Cut(state.EntityID, ".")
+		if !ok || !isPrunedDomain(domain) {
+			continue
+		}
+		// Event entities only exist through MQTT discovery
+		if domain == "event" && !useMQTT {
+			continue
+		}
+		league, team, metric, ok := from.parseEntityName(domain, name)
+		if !ok {
+			continue
+		}
+		newName := to.entityName(league, team, metric)
+		if domain == "event" {
+			newName = to.deviceID(league, team) + "_" + metric
+		}
+		if newName == name {
+			continue
+		}
+		result := MigrationResult{From: state.EntityID, To: domain + "." + newName}
+		if dryRun {
+			results = append(results, result)
+			continue
+		}
+
+		if useMQTT {
+			err = mqtt.Remove(domain, name)
+		} else {
+			err = copyState(state, domain, newName, to.friendlyName(league, strings.ToUpper(team), metric))
+		}
+		cacheMu.Lock()
+		delete(entityCache, state.EntityID)
+		cacheMu.Unlock()
+
+		ok = err == nil
+		if ok {
+			forgetPublished(state.EntityID)
+		}
+		entry := models.AppLogEntry{
+			Type:     models.AppLogTypeLogLine,
+			Level:    models.AppLogLevelInfo,
+			Message:  fmt.Sprintf("Renamed %s to %s", result.From, result.To),
+			Source:   "homeassistant",
+			Target:   "ha:state:" + result.To,
+			LeagueId: league,
+			TeamCode: strings.ToUpper(team),
+			Success:  &ok,
+		}
+		if err != nil {
+			result.Error = err.Error()
+			entry.Level = models.AppLogLevelWarn
+			entry.Error = err.Error()
+			logger.Warn(fmt.Sprintf("Renaming %s failed: %v", result.From, err))
+		}
+		applog.Append(entry)
+		results = append(results, result)
+	}
+	return results, nil
+}
+
+// copyState writes a state under its new entity ID and deletes the old one,
+// which is kept when the copy fails.
+func copyState(state HAState, domain, name, friendly string) error {
+	attrs := map[string]interface{}{}
+	for k, v := range state.Attributes {
+		if k != "friendly_name" {
+			attrs[k] = v
+		}
+	}
+	if sent, _ := publishEntity(domain, name, friendly, state.State, attrs); !sent {
+		return fmt.Errorf("state not sent to %s.%s", domain, name)
+	}
+	return deleteState(state.EntityID)
+}
@@ -0,0 +1,261 @@
+package homeassistant
+
+import (
+	"fmt"
+	"goalfeed/models"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/spf13/viper"
+)
+
+// EntityNaming is home_assistant.entities, how the team entities are named.
+//
+//	home_assistant:
+//	  entities:
+//	    prefix: goalfeed
+//	    slug_style: short
+//	    friendly_name: "{team_name} {metric_name}"
+//	    disabled_metrics: [team.clock, team.period]
+type EntityNaming struct {
+	// Prefix starts every entity ID, goalfeed by default
+	Prefix string `mapstructure:"prefix"`
+	// SlugStyle is full for goalfeed_nhl_wpg_team_current_score, or short
+	// to drop the team_ from the metric: goalfeed_nhl_wpg_current_score
+	SlugStyle string `mapstructure:"slug_style"`
+	// FriendlyName is the friendly_name template; see friendlyPlaceholders
+	FriendlyName string `mapstructure:"friendly_name"`
+	// DisabledMetrics are never sent to Home Assistant
+	DisabledMetrics []string `mapstructure:"disabled_metrics"`
+}
+
+const (
+	defaultEntityPrefix = "goalfeed"
+	slugStyleFull       = "full"
+	slugStyleShort      = "short"
+	// The friendly name entities had before the template, "WPG team.current score"
+	defaultFriendlyName = "{team} {metric}"
+)
+
+// friendlyPlaceholders are what a friendly_name template can use.
+var friendlyPlaceholders = []string{
+	"{team}",        // team code, WPG
+	"{team_name}",   // full team name once a game has been seen, else the code
+	"{league}",      // NHL
+	"{league_name}", // National Hockey League
+	"{metric}",      // team.current score
+	"{metric_name}", // Current score
+}
+
+var placeholderPattern = regexp.MustCompile(`\{[a-z_]+\}`)
+
+var (
+	namingMu     sync.Mutex
+	namingLoaded bool
+	loadedNaming EntityNaming
+)
+
+// entityNaming is home_assistant.entities, with the defaults filled in for
+// anything unset or unusable. It's read once, and again after
+// ReloadEntityNaming, rather than for every entity published.
+func entityNaming() EntityNaming {
+	namingMu.Lock()
+	defer namingMu.Unlock()
+	if !namingLoaded {
+		var naming EntityNaming
+		_ = viper.UnmarshalKey("home_assistant.entities", &naming)
+		loadedNaming = naming.normalized()
+		namingLoaded = true
+	}
+	return loadedNaming
+}
+
+// ReloadEntityNaming rereads home_assistant.entities after the
+// configuration changed.
+func ReloadEntityNaming() {
+	namingMu.Lock()
+	namingLoaded = false
+	namingMu.Unlock()
+}
+
+func (n EntityNaming) normalized() EntityNaming {
+	n.Prefix = strings.Trim(sanitizeId(strings.TrimSpace(n.Prefix)), "_")
+	if n.Prefix == "" {
+		n.Prefix = defaultEntityPrefix
+	}
+	n.SlugStyle = strings.ToLower(strings.TrimSpace(n.SlugStyle))
+	if n.SlugStyle != slugStyleShort {
+		n.SlugStyle = slugStyleFull
+	}
+	if strings.TrimSpace(n.FriendlyName) == "" {
+		n.FriendlyName = defaultFriendlyName
+	}
+	return n
+}
+
+// metricSlug is the metric's part of the entity ID.
+func (n EntityNaming) metricSlug(metric string) string {
+	if n.SlugStyle == slugStyleShort {
+		metric = strings.TrimPrefix(metric, "team.")
+	}
+	return sanitizeId(metric)
+}
+
+// entityName is the entity ID, without its domain, of a team's metric.
+func (n EntityNaming) entityName(league models.League, teamCode, metric string) string {
+	return sanitizeId(fmt.Sprintf("%s_%s_%s_%s", n.Prefix, leagueSlug(league), strings.ToLower(teamCode), n.metricSlug(metric)))
+}
+
+// deviceID is the MQTT device of a team; its event entity is <id>_goal.
+func (n EntityNaming) deviceID(league models.League, teamCode string) string {
+	return sanitizeId(fmt.Sprintf("%s_%s_%s", n.Prefix, leagueSlug(league), teamCode))
+}
+
+func (n EntityNaming) friendlyName(league models.League, teamCode, metric string) string {
+	metricName := strings.ReplaceAll(strings.TrimPrefix(metric, "team."), "_", " ")
+	if metricName != "" {
+		metricName = strings.ToUpper(metricName[:1]) + metricName[1:]
+	}
+	replacer := strings.NewReplacer(
+		"{team}", teamCode,
+		"{team_name}", teamName(league, teamCode),
+		"{league}", strings.ToUpper(leagueSlug(league)),
+		"{league_name}", getLeagueName(league),
+		"{metric}", strings.ReplaceAll(metric, "_", " "),
+		"{metric_name}", metricName,
+	)
+	return strings.TrimSpace(replacer.Replace(n.FriendlyName))
+}
+
+// metricDisabled reports whether a metric is in disabled_metrics, written
+// as team.clock, clock or team_clock.
+func (n EntityNaming) metricDisabled(metric string) bool {
+	want := strings.TrimPrefix(sanitizeId(metric), "team_")
+	for _, disabled := range n.DisabledMetrics {
+		if strings.TrimPrefix(sanitizeId(strings.TrimSpace(disabled)), "team_") == want {
+			return true
+		}
+	}
+	return false
+}
+
+// parseEntityName splits an entity ID built with this naming back into its
+// league, team and metric. Event entities have the metric goal.
+func (n EntityNaming) parseEntityName(domain, name string) (models.League, string, string, bool) {
+	rest, ok := strings.CutPrefix(name, n.Prefix+"_")
+	if !ok {
+		return 0, "", "", false
+	}
+	leagues := watchedLeagues()
+	// Longest slug first, so olympic_men isn't taken for a team of olympic
+	sort.Slice(leagues, func(i, j int) bool {
+		return len(leagueSlug(leagues[i].id)) > len(leagueSlug(leagues[j].id))
+	})
+	var league models.League
+	found := false
+	for _, lc := range leagues {
+		if after, ok := strings.CutPrefix(rest, leagueSlug(lc.id)+"_"); ok {
+			league, rest, found = lc.id, after, true
+			break
+		}
+	}
+	if !found {
+		return 0, "", "", false
+	}
+
+	if domain == "event" {
+		team, ok := strings.CutSuffix(rest, "_goal")
+		return league, team, "goal", ok && team != ""
+	}
+	metrics := teamMetrics()
+	sort.Slice(metrics, func(i, j int) bool { return len(metrics[i]) > len(metrics[j]) })
+	for _, metric := range metrics {
+		if team, ok := strings.CutSuffix(rest, "_"+n.metricSlug(metric)); ok && team != "" {
+			return league, team, metric, true
+		}
+	}
+	return 0, "", "", false
+}
+
+// NamingWarnings explains each home_assistant.entities setting that can't
+// be used as written.
+func NamingWarnings() []string {
+	var naming EntityNaming
+	if err := viper.UnmarshalKey("home_assistant.entities", &naming); err != nil {
+		return []string{fmt.Sprintf("Ignoring home_assistant.entities: %v", err)}
+	}
+	var warnings []string
+	if prefix := strings.TrimSpace(naming.Prefix); prefix != "" && prefix != naming.normalized().Prefix {
+		warnings = append(warnings, fmt.Sprintf("home_assistant.entities.prefix: %q isn't a valid entity ID prefix; using %s", prefix, naming.normalized().Prefix))
+	}
+	if style := strings.ToLower(strings.TrimSpace(naming.SlugStyle)); style != "" && style != slugStyleFull && style != slugStyleShort {
+		warnings = append(warnings, fmt.Sprintf("home_assistant.entities.slug_style: unknown style %q; using %s", naming.SlugStyle, slugStyleFull))
+	}
+	for _, placeholder := range placeholderPattern.FindAllString(naming.FriendlyName, -1) {
+		known := false
+		for _, p := range friendlyPlaceholders {
+			known = known || p == placeholder
+		}
+		if !known {
+			warnings = append(warnings, fmt.Sprintf("home_assistant.entities.friendly_name: unknown placeholder %s", placeholder))
+		}
+	}
+	for _, disabled := range naming.DisabledMetrics {
+		known := false
+		for _, metric := range teamMetrics() {
+			known = known || EntityNaming{DisabledMetrics: []string{disabled}}.metricDisabled(metric)
+		}
+		if !known {
+			warnings = append(warnings, fmt.Sprintf("home_assistant.entities.disabled_metrics: unknown metric %q", disabled))
+		}
+	}
+	// Disabling a metric stops it being sent, but what was sent before
+	// stays in Home Assistant until it's pruned
+	left := 0
+	for _, id := range publishedEntities() {
+		if isDisabledEntity(id, naming.normalized()) {
+			left++
+		}
+	}
+	if left > 0 {
+		warnings = append(warnings, fmt.Sprintf("home_assistant.entities.disabled_metrics: %d entities published before their metric was disabled are still in Home Assistant; run goalfeed ha prune to remove them", left))
+	}
+	return warnings
+}
+
+var (
+	teamsMu    sync.Mutex
+	knownTeams = map[string]models.Team{}
+)
+
+// rememberTeam keeps a team as a game describes it, for {team_name} and
+// for matching conf: entries against its conference.
+func rememberTeam(league models.League, team models.Team) {
+	if team.TeamCode == "" || (team.TeamName == "" && team.Conference == "") {
+		return
+	}
+	teamsMu.Lock()
+	knownTeams[fmt.Sprintf("%d:%s", league, strings.ToUpper(team.TeamCode))] = team
+	teamsMu.Unlock()
+}
+
+// knownTeam is a team as its last game described it, or only its code
+// until a game has.
+func knownTeam(league models.League, teamCode string) models.Team {
+	teamsMu.Lock()
+	defer teamsMu.Unlock()
+	if team, ok := knownTeams[fmt.Sprintf("%d:%s", league, strings.ToUpper(teamCode))]; ok {
+		return team
+	}
+	return models.Team{TeamCode: teamCode}
+}
+
+// teamName is a team's full name, or its code until a game has named it.
+func teamName(league models.League, teamCode string) string {
+	if name := knownTeam(league, teamCode).TeamName; name != "" {
+		return name
+	}
+	return teamCode
+}
@@ -0,0 +1,147 @@
+package homeassistant
+
+import (
+	"testing"
+
+	"goalfeed/models"
+
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+)
+
+// setNaming sets home_assistant.entities keys for a test, putting the
+// default naming back once it's done.
+func setNaming(t *testing.T, settings map[string]interface{}) {
+	for key, value := range settings {
+		viper.Set("home_assistant.entities."+key, value)
+	}
+	ReloadEntityNaming()
+	t.Cleanup(func() {
+		for key := range settings {
+			viper.Set("home_assistant.entities."+key, nil)
+		}
+		ReloadEntityNaming()
+	})
+}
+
+func TestDefaultNamingKeepsEntityIDs(t *testing.T) {
+	viper.Reset()
+	naming := entityNaming()
+	assert.Equal(t, "goalfeed_nhl_wpg_team_current_score", naming.entityName(models.LeagueIdNHL, "WPG", "team.current_score"))
+	assert.Equal(t, "WPG team.current score", naming.friendlyName(models.LeagueIdNHL, "WPG", "team.current_score"))
+	assert.Equal(t, "goalfeed_nhl_wpg", naming.deviceID(models.LeagueIdNHL, "WPG"))
+}
+
+func TestConfiguredNaming(t *testing.T) {
+	viper.Reset()
+	setNaming(t, map[string]interface{}{
+		"prefix":        "Sports",
+		"slug_style":    "short",
+		"friendly_name": "{team_name} {metric_name} ({league})",
+	})
+	naming := entityNaming()
+
+	assert.Equal(t, "sports_nhl_wpg_current_score", naming.entityName(models.LeagueIdNHL, "WPG", "team.current_score"))
+	assert.Equal(t, "sports_nhl_wpg", naming.deviceID(models.LeagueIdNHL, "WPG"))
+	assert.Equal(t, "WPG Current score (NHL)", naming.friendlyName(models.LeagueIdNHL, "WPG", "team.current_score"), "the code until a game names the team")
+	rememberTeam(models.LeagueIdNHL, models.Team{TeamCode: "WPG", TeamName: "Winnipeg Jets"})
+	assert.Equal(t, "Winnipeg Jets Current score (NHL)", naming.friendlyName(models.LeagueIdNHL, "WPG", "team.current_score"))
+
+	viper.Set("home_assistant.entities.prefix", "hockey")
+	assert.Equal(t, "sports", entityNaming().Prefix, "read once, not for every entity")
+	ReloadEntityNaming()
+	assert.Equal(t, "hockey", entityNaming().Prefix)
+}
+
+func TestParseEntityName(t *testing.T) {
+	viper.Reset()
+	full := EntityNaming{}.normalized()
+	league, team, metric, ok := full.parseEntityName("sensor", "goalfeed_nhl_wpg_team_current_score")
+	assert.True(t, ok)
+	assert.Equal(t, models.League(models.LeagueIdNHL), league)
+	assert.Equal(t, "wpg", team)
+	assert.Equal(t, "team.current_score", metric)
+
+	short := EntityNaming{Prefix: "sports", SlugStyle: "short"}.normalized()
+	_, team, metric, ok = short.parseEntityName("binary_sensor", "sports_mlb_tor_is_batting")
+	assert.True(t, ok)
+	assert.Equal(t, "tor", team)
+	assert.Equal(t, "team.is_batting", metric)
+
+	_, team, metric, ok = full.parseEntityName("event", "goalfeed_nhl_wpg_goal")
+	assert.True(t, ok)
+	assert.Equal(t, "wpg", team)
+	assert.Equal(t, "goal", metric)
+
+	_, _, _, ok = full.parseEntityName("sensor", "goalfeed_nhl_wpg_lamp")
+	assert.False(t, ok)
+	_, _, _, ok = full.parseEntityName("sensor", "sports_nhl_wpg_team_shots")
+	assert.False(t, ok)
+}
+
+func TestDisabledMetricsAreNotPublished(t *testing.T) {
+	_, posted := fakeHAStates(t, nil)
+	debounceAfter = 0
+	viper.Reset()
+	setNaming(t, map[string]interface{}{"disabled_metrics": []string{"team.clock", "period"}})
+
+	publishSensor(models.LeagueIdNHL, "WPG", "team.clock", "12:00", nil)
+	publishSensor(models.LeagueIdNHL, "WPG", "team.period", 2, nil)
+	publishSensor(models.LeagueIdNHL, "WPG", "team.shots", 12, nil)
+	assert.Equal(t, []string{"sensor.goalfeed_nhl_wpg_team_shots"}, *posted)
+}
+
+func TestPruneRemovesDisabledMetrics(t *testing.T) {
+	states := []HAState{
+		{EntityID: "sensor.goalfeed_nhl_wpg_team_clock", State: "12:00"},
+		{EntityID: "sensor.goalfeed_nhl_wpg_team_shots", State: "12"},
+	}
+	deleted, _ := fakeHAStates(t, states)
+	markPublished(states)
+	viper.Reset()
+	viper.Set("watch.nhl", []string{"WPG"})
+	setNaming(t, map[string]interface{}{"disabled_metrics": []string{"clock"}})
+	assert.Contains(t, NamingWarnings(), "home_assistant.entities.disabled_metrics: 1 entities published before their metric was disabled are still in Home Assistant; run goalfeed ha prune to remove them")
+
+	_, err := PruneStaleEntities(false)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"sensor.goalfeed_nhl_wpg_team_clock"}, *deleted)
+	assert.NotContains(t, publishedEntities(), "sensor.goalfeed_nhl_wpg_team_clock")
+	assert.Empty(t, NamingWarnings(), "pruned, so nothing is left behind")
+}
+
+func TestMigrateEntityNames(t *testing.T) {
+	deleted, posted := fakeHAStates(t, []HAState{
+		{EntityID: "sensor.goalfeed_nhl_wpg_team_current_score", State: "2", Attributes: map[string]interface{}{"friendly_name": "WPG team.current score"}},
+		{EntityID: "binary_sensor.goalfeed_nhl_wpg_team_goalie_pulled", State: "off"},
+		{EntityID: "sensor.goalfeed_nhl_wpg_lamp", State: "on"},
+		{EntityID: "sensor.living_room_temperature", State: "21"},
+	})
+	debounceAfter = 0
+	viper.Reset()
+	setNaming(t, map[string]interface{}{"prefix": "hockey", "slug_style": "short"})
+
+	results, err := MigrateEntityNames(EntityNaming{}, true)
+	assert.NoError(t, err)
+	assert.Equal(t, []MigrationResult{
+		{From: "binary_sensor.goalfeed_nhl_wpg_team_goalie_pulled", To: "binary_sensor.hockey_nhl_wpg_goalie_pulled"},
+		{From: "sensor.goalfeed_nhl_wpg_team_current_score", To: "sensor.hockey_nhl_wpg_current_score"},
+	}, results)
+	assert.Empty(t, *posted, "a dry run changes nothing")
+
+	_, err = MigrateEntityNames(EntityNaming{}, false)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"binary_sensor.hockey_nhl_wpg_goalie_pulled", "sensor.hockey_nhl_wpg_current_score"}, *posted)
+	assert.Equal(t, []string{"binary_sensor.goalfeed_nhl_wpg_team_goalie_pulled", "sensor.goalfeed_nhl_wpg_team_current_score"}, *deleted)
+	assert.Contains(t, entityCache["sensor.hockey_nhl_wpg_current_score"].Serialized, `"friendly_name":"WPG team.current score"`)
+}
+
+func TestNamingWarnings(t *testing.T) {
+	viper.Reset()
+	assert.Empty(t, NamingWarnings())
+
+	viper.Set("home_assistant.entities.slug_style", "camel")
+	viper.Set("home_assistant.entities.friendly_name", "{team} {score}")
+	viper.Set("home_assistant.entities.disabled_metrics", []string{"clock", "team.temperature"})
+	assert.Len(t, NamingWarnings(), 3)
+}
@@ -0,0 +1,286 @@
+package homeassistant
+
+import (
+	"encoding/json"
+	"fmt"
+	"goalfeed/config"
+	"goalfeed/models"
+	"goalfeed/targets/applog"
+	"goalfeed/targets/mqtt"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+)
+
+// PruneResult is one entity PruneStaleEntities dealt with.
+type PruneResult struct {
+	EntityID string `json:"entityId"`
+	// Action is removed, unavailable, or, on a dry run, what would be done
+	Action string `json:"action"`
+	Error  string `json:"error,omitempty"`
+}
+
+const (
+	pruneModeRemove      = "remove"
+	pruneModeUnavailable = "unavailable"
+)
+
+// Actions a PruneResult reports.
+const (
+	pruneActionRemoved     = "removed"
+	pruneActionUnavailable = "unavailable"
+)
+
+// prunedDomains are the domains Goalfeed creates entities in.
+var prunedDomains = []string{"sensor", "binary_sensor", "event"}
+
+func isPrunedDomain(domain string) bool {
+	for _, d := range prunedDomains {
+		if domain == d {
+			return true
+		}
+	}
+	return false
+}
+
+// managedPrefixes returns the entity name prefixes of the teams on the watch
+// lists, <prefix>_<league>_<team>_, or <prefix>_<league>_ for a league
+// watched with * or a conf: entry, where any team may be one of ours.
+func managedPrefixes() []string {
+	prefix := entityNaming().Prefix
+	var prefixes []string
+	for _, lc := range watchedLeagues() {
+		slug := leagueSlug(lc.id)
+		for _, entry := range config.GetStringSlice("watch." + lc.key) {
+			entry = strings.TrimSpace(entry)
+			_, conference := models.WatchConference(entry)
+			switch {
+			case entry == "":
+			case entry == "*", conference:
+				prefixes = append(prefixes, sanitizeId(fmt.Sprintf("%s_%s_", prefix, slug)))
+			default:
+				prefixes = append(prefixes, sanitizeId(fmt.Sprintf("%s_%s_%s_", prefix, slug, entry)))
+			}
+		}
+	}
+	return prefixes
+}
+
+// isStaleEntity reports whether an entity ID is one of Goalfeed's and
+// belongs to no watched team.
+func isStaleEntity(entityID string, prefixes []string) bool {
+	domain, name, ok := strings.Cut(entityID, ".")
+	if !ok || !strings.HasPrefix(name, entityNaming().Prefix+"_") {
+		return false
+	}
+	if !isPrunedDomain(domain) {
+		return false
+	}
+	for _, prefix := range prefixes {
+		if strings.HasPrefix(name, prefix) {
+			return false
+		}
+	}
+	return true
+}
+
+// goalfeedEntities lists the entities Goalfeed has published, in this run or
+// an earlier one, with Home Assistant's state where it has one. Entities it
+// didn't create are never listed, whatever they're called.
+func goalfeedEntities() (map[string]HAState, error) {
+	entities := map[string]HAState{}
+	for _, id := range publishedEntities() {
+		entities[id] = HAState{EntityID: id}
+	}
+
+	states, err := listStates()
+	for _, state := range states {
+		if _, ok := entities[state.EntityID]; ok {
+			entities[state.EntityID] = state
+		}
+	}
+	return entities, err
+}
+
+// listStates fetches every entity state from GET /api/states.
+func listStates() ([]HAState, error) {
+	haURL, token := getHAAuth()
+	if haURL == "" || token == "" {
+		return nil, fmt.Errorf("home assistant not configured")
+	}
+	if err := validateOutboundHAURL(haURL); err != nil {
+		return nil, err
+	}
+	req, err := http.NewRequest("GET", strings.TrimRight(haURL, "/")+"/api/states", nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Authorization", "Bearer "+token)
+	client := &http.Client{Timeout: 10 * time.Second}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return nil, fmt.Errorf("listing states: %s", resp.Status)
+	}
+	var states []HAState
+	if err := json.NewDecoder(resp.Body).Decode(&states); err != nil {
+		return nil, fmt.Errorf("listing states: %v", err)
+	}
+	return states, nil
+}
+
+// deleteState removes a state written through POST /api/states. One that
+// is already gone counts as removed.
+func deleteState(entityID string) error {
+	haURL, token := getHAAuth()
+	if haURL == "" || token == "" {
+		return fmt.Errorf("home assistant not configured")
+	}
+	if err := validateOutboundHAURL(haURL); err != nil {
+		return err
+	}
+	req, err := http.NewRequest("DELETE", strings.TrimRight(haURL, "/")+"/api/states/"+entityID, nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Authorization", "Bearer "+token)
+	client := &http.Client{Timeout: 5 * time.Second}
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode == http.StatusNotFound || (resp.StatusCode >= 200 && resp.StatusCode < 300) {
+		return nil
+	}
+	return fmt.Errorf("%s", resp.Status)
+}
+
+// isDisabledEntity reports whether an entity is a metric in
+// home_assistant.entities.disabled_metrics.
+func isDisabledEntity(entityID string, naming EntityNaming) bool {
+	domain, name, ok := strings.Cut(entityID, ".")
+	if !ok || domain == "event" || len(naming.DisabledMetrics) == 0 {
+		return false
+	}
+	_, _, metric, ok := naming.parseEntityName(domain, name)
+	return ok && naming.metricDisabled(metric)
+}
+
+// PruneStaleEntities removes the entities Goalfeed published for teams that
+// are no longer on a watch list, and for metrics in disabled_metrics, or
+// with home_assistant.prune.mode: unavailable, sets them to unavailable so
+// dashboards and history keep them. MQTT entities are always removed, by
+// clearing their discovery config. A dry run only reports what would be
+// done. The error is set when Home Assistant couldn't be asked for its
+// entities; what Goalfeed knows it published is still pruned.
+func PruneStaleEntities(dryRun bool) ([]PruneResult, error) {
+	entities, listErr := goalfeedEntities()
+	prefixes := managedPrefixes()
+	mode := strings.ToLower(strings.TrimSpace(config.GetString("home_assistant.prune.mode")))
+	if mode != pruneModeUnavailable {
+		mode = pruneModeRemove
+	}
+	useMQTT := mqtt.Enabled()
+	if useMQTT && !dryRun {
+		// Already running in the service; a one-off prune connects here
+		mqtt.Start()
+		mqtt.WaitConnected(5 * time.Second)
+	}
+
+	markUnavailable := mode == pruneModeUnavailable && !useMQTT
+	ids := make([]string, 0, len(entities))
+	naming := entityNaming()
+	reasons := map[string]string{}
+	for id, state := range entities {
+		// The cache keeps the states of disabled metrics for the log, so only
+		// those Home Assistant holds are pruned
+		disabled := state.State != "" && isDisabledEntity(id, naming)
+		if !isStaleEntity(id, prefixes) && !disabled {
+			continue
+		}
+		// Already marked by an earlier prune
+		if markUnavailable && state.State == "unavailable" {
+			continue
+		}
+		reasons[id] = "no longer watched"
+		if disabled {
+			reasons[id] = "metric disabled"
+		}
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+
+	results := make([]PruneResult, 0, len(ids))
+	for _, id := range ids {
+		result := PruneResult{EntityID: id, Action: pruneActionRemoved}
+		if markUnavailable {
+			result.Action = pruneActionUnavailable
+		}
+		if dryRun {
+			results = append(results, result)
+			continue
+		}
+
+		domain, name, _ := strings.Cut(id, ".")
+		cacheMu.Lock()
+		delete(entityCache, id)
+		cacheMu.Unlock()
+		var err error
+		switch {
+		case useMQTT:
+			err = mqtt.Remove(domain, name)
+		case markUnavailable:
+			if sent, _ := publishEntity(domain, name, "", "unavailable", entities[id].Attributes); !sent {
+				err = fmt.Errorf("state not sent")
+			}
+			// Forget it again, so a team added back is published in full
+			cacheMu.Lock()
+			delete(entityCache, id)
+			cacheMu.Unlock()
+		default:
+			err = deleteState(id)
+		}
+
+		ok := err == nil
+		if ok && !markUnavailable {
+			forgetPublished(id)
+		}
+		entry := models.AppLogEntry{
+			Type:    models.AppLogTypeLogLine,
+			Level:   models.AppLogLevelInfo,
+			Message: fmt.Sprintf("Pruned %s (%s): %s", id, result.Action, reasons[id]),
+			Source:  "homeassistant",
+			Target:  "ha:state:" + id,
+			Success: &ok,
+		}
+		if err != nil {
+			result.Error = err.Error()
+			entry.Level = models.AppLogLevelWarn
+			entry.Error = err.Error()
+			logger.Warn(fmt.Sprintf("Pruning %s failed: %v", id, err))
+		}
+		applog.Append(entry)
+		results = append(results, result)
+	}
+	return results, listErr
+}
+
+// PruneAfterWatchChange prunes stale entities after a watch list changed,
+// when home_assistant.prune.on_watch_change is on.
+func PruneAfterWatchChange() {
+	if !config.GetBool("home_assistant.prune.on_watch_change") {
+		return
+	}
+	results, err := PruneStaleEntities(false)
+	if err != nil {
+		logger.Warn(fmt.Sprintf("Couldn't list Home Assistant entities to prune: %v", err))
+	}
+	if len(results) > 0 {
+		logger.Info(fmt.Sprintf("Pruned %d Home Assistant entities of teams no longer watched", len(results)))
+	}
+}
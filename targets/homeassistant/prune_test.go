@@ -0,0 +1,145 @@
+package homeassistant
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+
+	"goalfeed/targets/applog"
+
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+)
+
+// fakeHAStates serves GET /api/states from states and records DELETEs and
+// state POSTs. Goalfeed starts out having published none of them.
+func fakeHAStates(t *testing.T, states []HAState) (*[]string, *[]string) {
+	var mu sync.Mutex
+	var deleted, posted []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		switch {
+		case r.Method == "GET" && r.URL.Path == "/core/api/states":
+			_ = json.NewEncoder(w).Encode(states)
+		case r.Method == "DELETE":
+			deleted = append(deleted, strings.TrimPrefix(r.URL.Path, "/core/api/states/"))
+		case r.Method == "POST":
+			posted = append(posted, strings.TrimPrefix(r.URL.Path, "/core/api/states/"))
+		}
+	}))
+	t.Cleanup(server.Close)
+	withSupervisor(t, server.URL, "token")
+	applog.SetLogFilePathForTest(filepath.Join(t.TempDir(), "app.log.jsonl"))
+	SetPublishedPathForTest(filepath.Join(t.TempDir(), "ha_entities.json"))
+	cacheMu.Lock()
+	entityCache = map[string]entityCacheEntry{}
+	cacheMu.Unlock()
+	return &deleted, &posted
+}
+
+// markPublished records entities as created by Goalfeed.
+func markPublished(states []HAState) {
+	for _, state := range states {
+		if strings.Contains(state.EntityID, ".goalfeed_") {
+			rememberPublished(state.EntityID)
+		}
+	}
+}
+
+var haStates = []HAState{
+	{EntityID: "sensor.goalfeed_nhl_wpg_team_current_score", State: "2"},
+	{EntityID: "sensor.goalfeed_nhl_tor_team_current_score", State: "1"},
+	{EntityID: "binary_sensor.goalfeed_nhl_tor_team_has_active_game", State: "off"},
+	{EntityID: "sensor.goalfeed_ncaaf_mich_team_rank", State: "3"},
+	{EntityID: "sensor.living_room_temperature", State: "21"},
+}
+
+func TestIsStaleEntity(t *testing.T) {
+	prefixes := []string{"goalfeed_nhl_wpg_", "goalfeed_ncaaf_"}
+	assert.False(t, isStaleEntity("sensor.goalfeed_nhl_wpg_team_shots", prefixes))
+	assert.True(t, isStaleEntity("sensor.goalfeed_nhl_tor_team_shots", prefixes))
+	assert.True(t, isStaleEntity("event.goalfeed_nhl_tor_goal", prefixes))
+	assert.False(t, isStaleEntity("sensor.goalfeed_ncaaf_mich_team_rank", prefixes), "a conference or * entry keeps the whole league")
+	assert.False(t, isStaleEntity("sensor.living_room_temperature", prefixes))
+	assert.False(t, isStaleEntity("light.goalfeed_nhl_tor_lamp", prefixes), "only the domains Goalfeed writes")
+}
+
+func TestPruneRemovesUnwatchedTeams(t *testing.T) {
+	// Made by hand, or by another install; not Goalfeed's to prune
+	foreign := HAState{EntityID: "sensor.goalfeed_nhl_bos_team_shots", State: "30"}
+	deleted, _ := fakeHAStates(t, append([]HAState{foreign}, haStates...))
+	markPublished(haStates)
+	viper.Reset()
+	viper.Set("watch.nhl", []string{"WPG"})
+	viper.Set("watch.ncaaf", []string{"conf:B1G"})
+	rememberState("sensor.goalfeed_mlb_tor_team_outs", 1)
+	rememberPublished("sensor.goalfeed_mlb_tor_team_outs")
+
+	results, err := PruneStaleEntities(true)
+	assert.NoError(t, err)
+	assert.Len(t, results, 3)
+	assert.Empty(t, *deleted, "a dry run changes nothing")
+
+	results, err = PruneStaleEntities(false)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{
+		"binary_sensor.goalfeed_nhl_tor_team_has_active_game",
+		"sensor.goalfeed_mlb_tor_team_outs",
+		"sensor.goalfeed_nhl_tor_team_current_score",
+	}, *deleted)
+	for _, r := range results {
+		assert.Equal(t, "removed", r.Action)
+	}
+	_, cached := entityCache["sensor.goalfeed_mlb_tor_team_outs"]
+	assert.False(t, cached)
+	assert.NotContains(t, publishedEntities(), "sensor.goalfeed_mlb_tor_team_outs", "removed entities are forgotten")
+}
+
+func TestPruneMarksUnavailable(t *testing.T) {
+	states := append([]HAState{{EntityID: "sensor.goalfeed_nhl_mtl_team_shots", State: "unavailable"}}, haStates...)
+	deleted, posted := fakeHAStates(t, states)
+	markPublished(states)
+	viper.Reset()
+	viper.Set("watch.nhl", []string{"WPG"})
+	viper.Set("watch.ncaaf", []string{"*"})
+	viper.Set("home_assistant.prune.mode", "unavailable")
+
+	results, err := PruneStaleEntities(false)
+	assert.NoError(t, err)
+	assert.Len(t, results, 2, "entities already unavailable are left alone")
+	assert.Empty(t, *deleted)
+	assert.ElementsMatch(t, []string{
+		"sensor.goalfeed_nhl_tor_team_current_score",
+		"binary_sensor.goalfeed_nhl_tor_team_has_active_game",
+	}, *posted)
+}
+
+func TestPruneAfterWatchChangeIsOptIn(t *testing.T) {
+	deleted, _ := fakeHAStates(t, haStates)
+	markPublished(haStates)
+	viper.Reset()
+	viper.Set("watch.nhl", []string{"WPG"})
+	viper.Set("watch.ncaaf", []string{"*"})
+	PruneAfterWatchChange()
+	assert.Empty(t, *deleted)
+
+	viper.Set("home_assistant.prune.on_watch_change", true)
+	PruneAfterWatchChange()
+	assert.Len(t, *deleted, 2)
+}
+
+func TestPublishedEntitiesAreKept(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "ha_entities.json")
+	SetPublishedPathForTest(path)
+	rememberPublished("sensor.goalfeed_nhl_wpg_team_shots")
+	rememberPublished("event.goalfeed_nhl_wpg_goal")
+	forgetPublished("event.goalfeed_nhl_wpg_goal")
+
+	SetPublishedPathForTest(path)
+	assert.Equal(t, []string{"sensor.goalfeed_nhl_wpg_team_shots"}, publishedEntities())
+}
@@ -0,0 +1,129 @@
+package homeassistant
+
+import (
+	"encoding/json"
+	"fmt"
+	"goalfeed/config"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+)
+
+// published is every entity ID Goalfeed has created in the primary Home
+// Assistant. Pruning only ever considers these, so entities made by hand or
+// by another install are left alone. It's kept in
+// home_assistant.prune.entities_path once LoadPublishedEntities is called,
+// and only in memory until then.
+var (
+	publishedMu   sync.Mutex
+	published     = map[string]bool{}
+	publishedPath string
+)
+
+// LoadPublishedEntities reads the entity IDs created by earlier runs from
+// home_assistant.prune.entities_path.
+func LoadPublishedEntities() {
+	p := config.GetString("home_assistant.prune.entities_path")
+	if p == "" {
+		p = "ha_entities.json"
+	}
+	if err := loadPublished(p); err != nil {
+		logger.Warn(fmt.Sprintf("Couldn't load the published Home Assistant entities from %s: %v", p, err))
+	}
+}
+
+// SetPublishedPathForTest points the published entities at a file for tests
+// only, starting from whatever the file holds.
+func SetPublishedPathForTest(p string) {
+	publishedMu.Lock()
+	published = map[string]bool{}
+	publishedMu.Unlock()
+	_ = loadPublished(p)
+}
+
+func loadPublished(p string) error {
+	if dir := filepath.Dir(p); dir != "." && dir != "" {
+		_ = os.MkdirAll(dir, 0o755)
+	}
+	publishedMu.Lock()
+	defer publishedMu.Unlock()
+	publishedPath = p
+	b, err := os.ReadFile(p)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	var loaded []string
+	if err := json.Unmarshal(b, &loaded); err != nil {
+		return err
+	}
+	// Entities published before the load are kept alongside
+	for _, id := range loaded {
+		published[id] = true
+	}
+	return savePublishedLocked()
+}
+
+// savePublishedLocked rewrites the file through a temporary one, as the
+// delivery queue does.
+func savePublishedLocked() error {
+	if publishedPath == "" {
+		return nil
+	}
+	ids := make([]string, 0, len(published))
+	for id := range published {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	b, err := json.MarshalIndent(ids, "", "  ")
+	if err != nil {
+		return err
+	}
+	tmp := publishedPath + ".tmp"
+	if err := os.WriteFile(tmp, b, 0o644); err != nil {
+		return err
+	}
+	return os.Rename(tmp, publishedPath)
+}
+
+// rememberPublished records an entity Goalfeed created. The file is only
+// rewritten for one it hadn't seen.
+func rememberPublished(entityID string) {
+	publishedMu.Lock()
+	defer publishedMu.Unlock()
+	if published[entityID] {
+		return
+	}
+	published[entityID] = true
+	if err := savePublishedLocked(); err != nil {
+		logger.Warn(fmt.Sprintf("Couldn't save the published Home Assistant entities: %v", err))
+	}
+}
+
+// forgetPublished drops an entity that was removed from Home Assistant.
+func forgetPublished(entityID string) {
+	publishedMu.Lock()
+	defer publishedMu.Unlock()
+	if !published[entityID] {
+		return
+	}
+	delete(published, entityID)
+	if err := savePublishedLocked(); err != nil {
+		logger.Warn(fmt.Sprintf("Couldn't save the published Home Assistant entities: %v", err))
+	}
+}
+
+// publishedEntities lists the entities Goalfeed created.
+func publishedEntities() []string {
+	publishedMu.Lock()
+	defer publishedMu.Unlock()
+	ids := make([]string, 0, len(published))
+	for id := range published {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	return ids
+}
@@ -0,0 +1,66 @@
+package homeassistant
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// CallService calls a Home Assistant service such as light.turn_on. target
+// holds entity_id, area_id or device_id. It goes over the WebSocket
+// connection when that is up and POST /api/services otherwise, where the
+// target is merged into the service data.
+func CallService(service string, data, target map[string]interface{}) error {
+	domain, name, ok := strings.Cut(service, ".")
+	if !ok || domain == "" || name == "" {
+		return fmt.Errorf("service %q is not domain.service", service)
+	}
+
+	if client := sharedWSClient(); client != nil && client.Connected() {
+		err := client.CallService(domain, name, data, target)
+		if err == nil || err != errWSNotConnected {
+			return err
+		}
+	}
+
+	haURL, token := getHAAuth()
+	if haURL == "" || token == "" {
+		return fmt.Errorf("home assistant not configured")
+	}
+	if err := validateOutboundHAURL(haURL); err != nil {
+		return err
+	}
+	body := map[string]interface{}{}
+	for k, v := range data {
+		body[k] = v
+	}
+	for k, v := range target {
+		body[k] = v
+	}
+	payload, err := json.Marshal(body)
+	if err != nil {
+		return err
+	}
+	url := fmt.Sprintf("%s/api/services/%s/%s", strings.TrimRight(haURL, "/"), domain, name)
+	req, err := http.NewRequest("POST", url, bytes.NewBuffer(payload))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Authorization", "Bearer "+token)
+	req.Header.Set("Content-Type", "application/json")
+	client := &http.Client{Timeout: 10 * time.Second}
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		b, _ := io.ReadAll(resp.Body)
+		return fmt.Errorf("%s %s", resp.Status, strings.TrimSpace(string(b)))
+	}
+	return nil
+}
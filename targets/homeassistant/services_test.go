@@ -0,0 +1,51 @@
+package homeassistant
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCallServiceOverREST(t *testing.T) {
+	var path string
+	var body map[string]interface{}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		path = r.URL.Path
+		_ = json.NewDecoder(r.Body).Decode(&body)
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer srv.Close()
+	os.Setenv("SUPERVISOR_API", srv.URL)
+	os.Setenv("SUPERVISOR_TOKEN", "t")
+	defer os.Unsetenv("SUPERVISOR_API")
+	defer os.Unsetenv("SUPERVISOR_TOKEN")
+
+	err := CallService("light.turn_on", map[string]interface{}{"brightness": 255}, map[string]interface{}{"entity_id": "light.den"})
+	assert.NoError(t, err)
+	assert.Equal(t, "/core/api/services/light/turn_on", path)
+	assert.Equal(t, "light.den", body["entity_id"], "the target is merged into the service data")
+	assert.Equal(t, float64(255), body["brightness"])
+}
+
+func TestCallServiceRejectsBadName(t *testing.T) {
+	assert.Error(t, CallService("turn_on", nil, nil))
+}
+
+func TestCallServiceOverWebSocket(t *testing.T) {
+	fired := make(chan map[string]interface{}, 1)
+	srv := fakeHAWebSocket(t, "t", fired)
+	defer srv.Close()
+	withSupervisor(t, srv.URL, "t")
+
+	client := NewWSClient(WSConfig{ReconnectBaseMs: 10, ReconnectMaxMs: 20, PingIntervalSec: 30, PongWaitSec: 60, ResultTimeout: time.Second})
+	go client.Run()
+	defer client.Stop()
+	assert.Eventually(t, client.Connected, 2*time.Second, 10*time.Millisecond)
+	assert.NoError(t, client.CallService("scene", "turn_on", nil, map[string]interface{}{"entity_id": "scene.goal"}))
+	assert.Error(t, client.CallService("light", "turn_on", nil, nil), "a failed result is an error")
+}
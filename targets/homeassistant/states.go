@@ -7,6 +7,7 @@ import (
 	"goalfeed/config"
 	"goalfeed/models"
 	"goalfeed/targets/applog"
+	"goalfeed/targets/mqtt"
 	"goalfeed/utils"
 	"io"
 	"net/http"
@@ -24,9 +25,11 @@ type entityCacheEntry struct {
 }
 
 var (
-	cacheMu       sync.Mutex
-	entityCache   = map[string]entityCacheEntry{}
-	debounceAfter = 500 * time.Millisecond
+	cacheMu     sync.Mutex
+	entityCache = map[string]entityCacheEntry{}
+	// instanceCaches is the state cache of each home_assistant.instances entry
+	instanceCaches = map[string]map[string]entityCacheEntry{}
+	debounceAfter  = 500 * time.Millisecond
 )
 
 func getHAAuth() (string, string) {
@@ -73,6 +76,17 @@ func toStateString(value interface{}) string {
 }
 
 func publishEntity(domain, entity, friendly string, state interface{}, attrs map[string]interface{}) (bool, string) {
+	haURL, token := getHAAuth()
+	sent, prev := publishEntityTo(entityCache, haURL, token, domain, entity, friendly, state, attrs)
+	if sent {
+		rememberPublished(domain + "." + entity)
+	}
+	return sent, prev
+}
+
+// publishEntityTo posts a state to one Home Assistant, deduped and
+// debounced against cache, which callers only touch under cacheMu.
+func publishEntityTo(cache map[string]entityCacheEntry, haURL, token, domain, entity, friendly string, state interface{}, attrs map[string]interface{}) (bool, string) {
 	// Always include friendly_name
 	if attrs == nil {
 		attrs = map[string]interface{}{}
@@ -90,7 +104,7 @@ func publishEntity(domain, entity, friendly string, state interface{}, attrs map
 	key := domain + "." + entity
 	// Dedupe + debounce
 	cacheMu.Lock()
-	last, ok := entityCache[key]
+	last, ok := cache[key]
 	if ok {
 		if string(serialized) == last.Serialized {
 			cacheMu.Unlock()
@@ -99,17 +113,15 @@ func publishEntity(domain, entity, friendly string, state interface{}, attrs map
 		if time.Since(last.UpdatedAt) < debounceAfter {
 			// IMPORTANT: Persist the new state in cache even if we debounce the outbound HA call.
 			// This prevents repeated unknown->X transitions in our logs on subsequent publishes.
-			entityCache[key] = entityCacheEntry{Serialized: string(serialized), UpdatedAt: time.Now()}
+			cache[key] = entityCacheEntry{Serialized: string(serialized), UpdatedAt: time.Now()}
 			cacheMu.Unlock()
 			return false, ""
 		}
 	}
 	// Update local cache so we persist state even if HA is unavailable
-	entityCache[key] = entityCacheEntry{Serialized: string(serialized), UpdatedAt: time.Now()}
+	cache[key] = entityCacheEntry{Serialized: string(serialized), UpdatedAt: time.Now()}
 	cacheMu.Unlock()
 
-	// Resolve HA endpoint after caching
-	haURL, token := getHAAuth()
 	if haURL == "" || token == "" {
 		stateLogger.Warn("Home Assistant not configured; cached state only")
 		return false, ""
@@ -152,6 +164,43 @@ func publishEntity(domain, entity, friendly string, state interface{}, attrs map
 	return true, prev
 }
 
+// rememberState caches a state that went over MQTT the way publishEntity
+// would, so the next publish still finds the previous value for the log.
+func rememberState(key string, value interface{}) {
+	serialized, _ := json.Marshal(map[string]interface{}{"state": toStateString(value)})
+	cacheMu.Lock()
+	entityCache[key] = entityCacheEntry{Serialized: string(serialized), UpdatedAt: time.Now()}
+	cacheMu.Unlock()
+}
+
+// mqttDevice is the MQTT discovery device for a team.
+func mqttDevice(league models.League, teamCode string) mqtt.Device {
+	return mqtt.Device{
+		ID:    entityNaming().deviceID(league, teamCode),
+		Name:  fmt.Sprintf("%s %s", strings.ToUpper(leagueSlug(league)), strings.ToUpper(teamCode)),
+		Model: getLeagueName(league),
+	}
+}
+
+// mqttEntity is the MQTT discovery entity for a team metric. Its object ID
+// is the REST entity's name, so switching to MQTT keeps the entity IDs.
+func mqttEntity(component string, league models.League, teamCode, metric string) mqtt.Entity {
+	name := strings.ReplaceAll(strings.TrimPrefix(metric, "team."), "_", " ")
+	if name != "" {
+		name = strings.ToUpper(name[:1]) + name[1:]
+	}
+	info := metricRegistry[metric]
+	return mqtt.Entity{
+		Component:   component,
+		ObjectID:    buildEntityName(league, teamCode, metric),
+		Name:        name,
+		Device:      mqttDevice(league, teamCode),
+		StateClass:  info.StateClass,
+		Unit:        info.Unit,
+		DeviceClass: info.DeviceClass,
+	}
+}
+
 func leagueSlug(league models.League) string {
 	switch league {
 	case models.LeagueIdNHL:
@@ -162,18 +211,70 @@ func leagueSlug(league models.League) string {
 		return "cfl"
 	case models.LeagueIdNFL:
 		return "nfl"
+	case models.LeagueIdNBA:
+		return "nba"
+	case models.LeagueIdWNBA:
+		return "wnba"
+	case models.LeagueIdEPL:
+		return "epl"
+	case models.LeagueIdMLS:
+		return "mls"
+	case models.LeagueIdUCL:
+		return "ucl"
+	case models.LeagueIdPWHL:
+		return "pwhl"
+	case models.LeagueIdAHL:
+		return "ahl"
+	case models.LeagueIdNCAAF:
+		return "ncaaf"
+	case models.LeagueIdNCAAMHockey:
+		return "ncaamh"
+	case models.LeagueIdNCAAWHockey:
+		return "ncaawh"
 	case models.LeagueIdOlympicMensHockey:
 		return "olympic_men"
 	case models.LeagueIdOlympicWomensHockey:
 		return "olympic_women"
 	default:
+		if tournament, ok := config.TournamentForLeague(league); ok {
+			return tournament.Key
+		}
 		return "misc"
 	}
 }
 
+// isSoccerLeague reports whether the league's teams get soccer sensors.
+func isSoccerLeague(league models.League) bool {
+	switch league {
+	case models.LeagueIdEPL, models.LeagueIdMLS, models.LeagueIdUCL:
+		return true
+	}
+	return false
+}
+
+// isHockeyLeague reports whether the league's teams get hockey sensors: the
+// NHL, PWHL, AHL, college hockey and every IIHF league and tournament.
+func isHockeyLeague(league models.League) bool {
+	switch league {
+	case models.LeagueIdNHL, models.LeagueIdPWHL, models.LeagueIdAHL, models.LeagueIdNCAAMHockey, models.LeagueIdNCAAWHockey, models.LeagueIdIIHF, models.LeagueIdOlympicMensHockey, models.LeagueIdOlympicWomensHockey:
+		return true
+	}
+	_, ok := config.TournamentForLeague(league)
+	return ok
+}
+
+// isCollegeLeague reports whether the league's teams get a poll ranking
+// sensor.
+func isCollegeLeague(league models.League) bool {
+	switch league {
+	case models.LeagueIdNCAAF, models.LeagueIdNCAAMHockey, models.LeagueIdNCAAWHockey:
+		return true
+	}
+	return false
+}
+
 func buildEntityName(league models.League, teamCode, metric string) string {
-	base := fmt.Sprintf("goalfeed_%s_%s_%s", leagueSlug(league), strings.ToLower(teamCode), metric)
-	return sanitizeId(base)
+	return entityNaming().entityName(league, teamCode, metric)
 }
 
 func PublishTeamSensors(game models.Game) {
@@ -182,17 +283,27 @@ func PublishTeamSensors(game models.Game) {
 	publishTeamCommon(game, game.CurrentState.Away, game.CurrentState.Home, game)
 
 	// League-specific metrics
-	switch game.LeagueId {
-	case models.LeagueIdMLB:
+	switch {
+	case game.LeagueId == models.LeagueIdMLB:
 		publishMLBTeam(game, game.CurrentState.Home, game.CurrentState.Away)
 		publishMLBTeam(game, game.CurrentState.Away, game.CurrentState.Home)
-	case models.LeagueIdNFL, models.LeagueIdCFL:
+	case game.LeagueId == models.LeagueIdNFL, game.LeagueId == models.LeagueIdCFL, game.LeagueId == models.LeagueIdNCAAF:
 		publishFootballTeam(game, game.CurrentState.Home, game.CurrentState.Away)
 		publishFootballTeam(game, game.CurrentState.Away, game.CurrentState.Home)
-	case models.LeagueIdNHL, models.LeagueIdOlympicMensHockey, models.LeagueIdOlympicWomensHockey:
+	case game.LeagueId == models.LeagueIdNBA, game.LeagueId == models.LeagueIdWNBA:
+		publishBasketballTeam(game, game.CurrentState.Home, game.CurrentState.Away)
+		publishBasketballTeam(game, game.CurrentState.Away, game.CurrentState.Home)
+	case isSoccerLeague(game.LeagueId):
+		publishSoccerTeam(game, game.CurrentState.Home)
+		publishSoccerTeam(game, game.CurrentState.Away)
+	case isHockeyLeague(game.LeagueId):
 		publishNHLTeam(game, game.CurrentState.Home, game.CurrentState.Away)
 		publishNHLTeam(game, game.CurrentState.Away, game.CurrentState.Home)
 	}
+	if isCollegeLeague(game.LeagueId) {
+		publishCollegeTeam(game, game.CurrentState.Home)
+		publishCollegeTeam(game, game.CurrentState.Away)
+	}
 }
 
 func statusString(s models.GameStatus) string {
@@ -214,6 +325,7 @@ func publishTeamCommon(game models.Game, team models.TeamState, opponent models.
 	league := game.LeagueId
 	teamCode := team.Team.TeamCode
 	oppCode := opponent.Team.TeamCode
+	rememberTeam(league, team.Team)
 
 	// team.status
 	publishSensor(league, teamCode, "team.status", statusString(game.CurrentState.Status), map[string]interface{}{
@@ -312,11 +424,41 @@ func publishFootballTeam(game models.Game, team models.TeamState, opponent model
 		publishSensor(league, teamCode, "team.yard_line", d.YardLine, nil)
 	}
 
-	// Heuristic red zone: offense and at or inside 20
+	// Prefer the tracked drive: on from the red zone entry until the drive
+	// ends. Without one, fall back to the yard line heuristic.
 	redZone := hasPossession && d.YardLine >= 80 // assuming 0-100 scale; if 1-50, this might be wrong
+	if drive := game.CurrentState.Drive; drive != nil {
+		redZone = strings.EqualFold(drive.TeamCode, teamCode) && drive.Result == "" && drive.RedZone
+	}
 	publishBinarySensor(league, teamCode, "team.red_zone", redZone, nil)
 }
 
+func publishBasketballTeam(game models.Game, team models.TeamState, opponent models.TeamState) {
+	league := game.LeagueId
+	teamCode := team.Team.TeamCode
+
+	// Lead is negative while trailing
+	publishSensor(league, teamCode, "team.lead", team.Score-opponent.Score, nil)
+	publishBinarySensor(league, teamCode, "team.leading", team.Score > opponent.Score, nil)
+
+	// Points on the current unanswered run, 0 when the other team is on it
+	run := 0
+	if r := game.CurrentState.Run; r != nil && strings.EqualFold(r.TeamCode, teamCode) {
+		run = r.Points
+	}
+	publishSensor(league, teamCode, "team.run", run, nil)
+}
+
+func publishSoccerTeam(game models.Game, team models.TeamState) {
+	// Minutes of stoppage time played so far in the half, 0 outside it
+	publishSensor(game.LeagueId, team.Team.TeamCode, "team.added_time", game.CurrentState.AddedTime, nil)
+}
+
+func publishCollegeTeam(game models.Game, team models.TeamState) {
+	// Poll ranking going into the game, 0 when unranked
+	publishSensor(game.LeagueId, team.Team.TeamCode, "team.rank", team.Rank, map[string]interface{}{"conference": team.Team.Conference})
+}
+
 func publishNHLTeam(game models.Game, team models.TeamState, opponent models.TeamState) {
 	league := game.LeagueId
 	teamCode := team.Team.TeamCode
@@ -326,12 +468,14 @@ func publishNHLTeam(game models.Game, team models.TeamState, opponent models.Tea
 	if team.Statistics.Penalties >= 0 {
 		publishSensor(league, teamCode, "team.penalties", team.Statistics.Penalties, nil)
 	}
+	publishBinarySensor(league, teamCode, "team.goalie_pulled", team.GoaliePulled, nil)
 	// power_play unknown without explicit flag; skip to avoid flapping
 }
 
 func publishSensor(league models.League, teamCode, metric string, value interface{}, attrs map[string]interface{}) {
-	entity := buildEntityName(league, teamCode, metric)
-	friendly := fmt.Sprintf("%s %s", teamCode, strings.ReplaceAll(metric, "_", " "))
+	naming := entityNaming()
+	entity := naming.entityName(league, teamCode, metric)
+	friendly := naming.friendlyName(league, teamCode, metric)
 	// log before HA publish
 	key := "sensor." + entity
 	cacheMu.Lock()
@@ -349,12 +493,25 @@ func publishSensor(league models.League, teamCode, metric string, value interfac
 	if prev != toStateString(value) {
 		applog.AppendStateChange(league, strings.ToUpper(leagueSlug(league)), teamCode, "", "", metric, prev, value)
 	}
-	_, _ = publishEntity("sensor", entity, friendly, value, attrs)
+	if naming.metricDisabled(metric) {
+		rememberState(key, value)
+		return
+	}
+	switch {
+	case mqtt.Enabled():
+		rememberState(key, value)
+		rememberPublished(key)
+		mqtt.PublishState(mqttEntity("sensor", league, teamCode, metric), mqttState(metric, toStateString(value)), attrs)
+	default:
+		_, _ = publishEntity("sensor", entity, friendly, value, metricAttributes(metric, attrs))
+	}
+	publishToInstances("sensor", league, teamCode, metric, value, attrs)
 }
 
 func publishBinarySensor(league models.League, teamCode, metric string, value bool, attrs map[string]interface{}) {
-	entity := buildEntityName(league, teamCode, metric)
-	friendly := fmt.Sprintf("%s %s", teamCode, strings.ReplaceAll(metric, "_", " "))
+	naming := entityNaming()
+	entity := naming.entityName(league, teamCode, metric)
+	friendly := naming.friendlyName(league, teamCode, metric)
 	key := "binary_sensor." + entity
 	cacheMu.Lock()
 	last, ok := entityCache[key]
@@ -371,7 +528,19 @@ func publishBinarySensor(league models.League, teamCode, metric string, value bo
 	if prev != toStateString(value) {
 		applog.AppendStateChange(league, strings.ToUpper(leagueSlug(league)), teamCode, "", "", metric, prev, value)
 	}
-	_, _ = publishEntity("binary_sensor", entity, friendly, value, attrs)
+	if naming.metricDisabled(metric) {
+		rememberState(key, value)
+		return
+	}
+	switch {
+	case mqtt.Enabled():
+		rememberState(key, value)
+		rememberPublished(key)
+		mqtt.PublishState(mqttEntity("binary_sensor", league, teamCode, metric), mqttState(metric, toStateString(value)), attrs)
+	default:
+		_, _ = publishEntity("binary_sensor", entity, friendly, value, metricAttributes(metric, attrs))
+	}
+	publishToInstances("binary_sensor", league, teamCode, metric, value, attrs)
 }
 
 func PublishGameSummary(game models.Game) {
@@ -390,6 +559,7 @@ func PublishScheduleSensorsForGame(game models.Game) {
 func publishTeamSchedule(league models.League, team models.TeamState, opponent models.TeamState, game models.Game) {
 	teamCode := team.Team.TeamCode
 	oppCode := opponent.Team.TeamCode
+	rememberTeam(league, team.Team)
 	// status -> scheduled
 	publishSensor(league, teamCode, "team.status", "scheduled", map[string]interface{}{
 		"opponent": oppCode,
@@ -417,6 +587,36 @@ func publishTeamSchedule(league models.League, team models.TeamState, opponent m
 	publishSensor(league, teamCode, "team.home_away", homeAway, nil)
 }
 
+type watchedLeague struct {
+	id  models.League
+	key string
+}
+
+// watchedLeagues pairs each league with its watch.<key> list, including
+// the configured IIHF tournaments.
+func watchedLeagues() []watchedLeague {
+	leagues := []watchedLeague{
+		{models.LeagueIdNHL, "nhl"},
+		{models.LeagueIdMLB, "mlb"},
+		{models.LeagueIdCFL, "cfl"},
+		{models.LeagueIdNFL, "nfl"},
+		{models.LeagueIdNBA, "nba"},
+		{models.LeagueIdWNBA, "wnba"},
+		{models.LeagueIdEPL, "epl"},
+		{models.LeagueIdMLS, "mls"},
+		{models.LeagueIdUCL, "ucl"},
+		{models.LeagueIdPWHL, "pwhl"},
+		{models.LeagueIdAHL, "ahl"},
+		{models.LeagueIdNCAAF, "ncaaf"},
+		{models.LeagueIdNCAAMHockey, "ncaamh"},
+		{models.LeagueIdNCAAWHockey, "ncaawh"},
+	}
+	for _, tournament := range config.Tournaments() {
+		leagues = append(leagues, watchedLeague{tournament.LeagueID, tournament.Key})
+	}
+	return leagues
+}
+
 func sanitizeId(s string) string {
 	s = strings.ToLower(s)
 	var b []rune
@@ -431,24 +631,14 @@ func sanitizeId(s string) string {
 }
 
 func PublishBaselineForMonitoredTeams() {
-	leagues := []struct {
-		id  models.League
-		key string
-	}{
-		{models.LeagueIdNHL, "nhl"},
-		{models.LeagueIdMLB, "mlb"},
-		{models.LeagueIdCFL, "cfl"},
-		{models.LeagueIdNFL, "nfl"},
-		{models.LeagueIdOlympicMensHockey, "olympic_men"},
-		{models.LeagueIdOlympicWomensHockey, "olympic_women"},
-	}
-	for _, lc := range leagues {
+	for _, lc := range watchedLeagues() {
 		teams := config.GetStringSlice("watch." + lc.key)
 		if len(teams) == 0 {
 			continue
 		}
 		for _, t := range teams {
-			if t == "*" || strings.TrimSpace(t) == "" {
+			// * and conf: entries aren't teams
+			if _, conference := models.WatchConference(t); t == "*" || strings.TrimSpace(t) == "" || conference {
 				continue
 			}
 			// Common baseline entities
@@ -463,8 +653,8 @@ func PublishBaselineForMonitoredTeams() {
 			publishSensor(lc.id, t, "team.period", 0, map[string]interface{}{"period_type": "unknown"})
 
 			// League-specific baseline entities
-			switch lc.id {
-			case models.LeagueIdMLB:
+			switch {
+			case lc.id == models.LeagueIdMLB:
 				publishBinarySensor(lc.id, t, "team.is_batting", false, nil)
 				publishSensor(lc.id, t, "team.balls", 0, nil)
 				publishSensor(lc.id, t, "team.strikes", 0, nil)
@@ -472,15 +662,25 @@ func PublishBaselineForMonitoredTeams() {
 				publishSensor(lc.id, t, "team.runners_on_base", "", nil)
 				publishSensor(lc.id, t, "team.current_pitcher", "unknown", nil)
 				publishSensor(lc.id, t, "team.current_batter", "unknown", nil)
-			case models.LeagueIdNFL, models.LeagueIdCFL:
+			case lc.id == models.LeagueIdNFL, lc.id == models.LeagueIdCFL, lc.id == models.LeagueIdNCAAF:
 				publishBinarySensor(lc.id, t, "team.has_possession", false, nil)
 				publishSensor(lc.id, t, "team.down", 0, nil)
 				publishSensor(lc.id, t, "team.distance", 0, nil)
 				publishSensor(lc.id, t, "team.yard_line", 0, nil)
 				publishBinarySensor(lc.id, t, "team.red_zone", false, nil)
-			case models.LeagueIdNHL, models.LeagueIdOlympicMensHockey, models.LeagueIdOlympicWomensHockey:
+			case lc.id == models.LeagueIdNBA, lc.id == models.LeagueIdWNBA:
+				publishSensor(lc.id, t, "team.lead", 0, nil)
+				publishBinarySensor(lc.id, t, "team.leading", false, nil)
+				publishSensor(lc.id, t, "team.run", 0, nil)
+			case isSoccerLeague(lc.id):
+				publishSensor(lc.id, t, "team.added_time", 0, nil)
+			case isHockeyLeague(lc.id):
 				publishSensor(lc.id, t, "team.shots", 0, nil)
 				publishSensor(lc.id, t, "team.penalties", 0, nil)
+				publishBinarySensor(lc.id, t, "team.goalie_pulled", false, nil)
+			}
+			if isCollegeLeague(lc.id) {
+				publishSensor(lc.id, t, "team.rank", 0, nil)
 			}
 		}
 	}
@@ -504,8 +704,8 @@ func PublishEndOfGameReset(game models.Game) {
 		publishSensor(league, teamCode, "team.clock", "", nil)
 		publishSensor(league, teamCode, "team.period", 0, map[string]interface{}{"period_type": ""})
 		// League-specific resets
-		switch league {
-		case models.LeagueIdMLB:
+		switch {
+		case league == models.LeagueIdMLB:
 			publishBinarySensor(league, teamCode, "team.is_batting", false, nil)
 			publishSensor(league, teamCode, "team.balls", 0, nil)
 			publishSensor(league, teamCode, "team.strikes", 0, nil)
@@ -513,14 +713,20 @@ func PublishEndOfGameReset(game models.Game) {
 			publishSensor(league, teamCode, "team.runners_on_base", "", nil)
 			publishSensor(league, teamCode, "team.current_pitcher", "unknown", nil)
 			publishSensor(league, teamCode, "team.current_batter", "unknown", nil)
-		case models.LeagueIdNFL, models.LeagueIdCFL:
+		case league == models.LeagueIdNFL, league == models.LeagueIdCFL, league == models.LeagueIdNCAAF:
 			publishBinarySensor(league, teamCode, "team.has_possession", false, nil)
 			publishSensor(league, teamCode, "team.down", 0, nil)
 			publishSensor(league, teamCode, "team.distance", 0, nil)
 			publishSensor(league, teamCode, "team.yard_line", 0, nil)
 			publishBinarySensor(league, teamCode, "team.red_zone", false, nil)
-		case models.LeagueIdNHL, models.LeagueIdOlympicMensHockey, models.LeagueIdOlympicWomensHockey:
-			// Keep shots/penalties as final numbers; nothing to reset
+		case league == models.LeagueIdNBA, league == models.LeagueIdWNBA:
+			// Keep the final margin; the run is over
+			publishSensor(league, teamCode, "team.run", 0, nil)
+		case isSoccerLeague(league):
+			publishSensor(league, teamCode, "team.added_time", 0, nil)
+		case isHockeyLeague(league):
+			// Keep shots/penalties as final numbers
+			publishBinarySensor(league, teamCode, "team.goalie_pulled", false, nil)
 		}
 	}
 
@@ -1,6 +1,7 @@
 package homeassistant
 
 import (
+	"encoding/json"
 	"net/http"
 	"net/http/httptest"
 	"os"
@@ -9,6 +10,7 @@ import (
 
 	"goalfeed/models"
 
+	"github.com/spf13/viper"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -79,6 +81,33 @@ func TestBuildEntityNameAndSlug(t *testing.T) {
 	}
 }
 
+func TestSensorsGoOverMQTTWhenEnabled(t *testing.T) {
+	var requests int
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer srv.Close()
+	os.Setenv("SUPERVISOR_API", srv.URL)
+	os.Setenv("SUPERVISOR_TOKEN", "t")
+	defer os.Unsetenv("SUPERVISOR_API")
+	defer os.Unsetenv("SUPERVISOR_TOKEN")
+	viper.Set("mqtt.enabled", true)
+	viper.Set("mqtt.broker", "tcp://localhost:1883")
+	defer viper.Set("mqtt.enabled", false)
+	entityCache = map[string]entityCacheEntry{}
+
+	publishSensor(models.LeagueIdNHL, "WPG", "team.shots", 12, nil)
+	assert.Equal(t, 0, requests, "no REST state is written")
+	assert.Contains(t, entityCache["sensor.goalfeed_nhl_wpg_team_shots"].Serialized, `"12"`)
+
+	entity := mqttEntity("sensor", models.LeagueIdNHL, "WPG", "team.current_score")
+	assert.Equal(t, "goalfeed_nhl_wpg_team_current_score", entity.ObjectID)
+	assert.Equal(t, "Current score", entity.Name)
+	assert.Equal(t, "goalfeed_nhl_wpg", entity.Device.ID)
+	assert.Equal(t, "NHL WPG", entity.Device.Name)
+}
+
 func TestPublishScheduleAndEndOfGame(t *testing.T) {
 	withHAServer(t)
 	game := models.Game{
@@ -131,6 +160,58 @@ func TestPublishTeamSensorsNFL(t *testing.T) {
 	PublishTeamSensors(game)
 }
 
+func TestPublishTeamSensorsNBA(t *testing.T) {
+	withHAServer(t)
+	debounceAfter = 0
+	entityCache = map[string]entityCacheEntry{}
+	game := models.Game{
+		LeagueId: models.LeagueIdNBA,
+		CurrentState: models.GameState{
+			Status: models.StatusActive,
+			Period: 3,
+			Clock:  "5:32",
+			Run:    &models.RunState{TeamCode: "LAL", Points: 10},
+			Home:   models.TeamState{Team: models.Team{TeamCode: "LAL"}, Score: 68},
+			Away:   models.TeamState{Team: models.Team{TeamCode: "BOS"}, Score: 60},
+		},
+	}
+	PublishTeamSensors(game)
+
+	state := func(key string) string {
+		var m map[string]interface{}
+		_ = json.Unmarshal([]byte(entityCache[key].Serialized), &m)
+		s, _ := m["state"].(string)
+		return s
+	}
+	assert.Equal(t, "8", state("sensor.goalfeed_nba_lal_team_lead"))
+	assert.Equal(t, "-8", state("sensor.goalfeed_nba_bos_team_lead"))
+	assert.Equal(t, "on", state("binary_sensor.goalfeed_nba_lal_team_leading"))
+	assert.Equal(t, "10", state("sensor.goalfeed_nba_lal_team_run"))
+	assert.Equal(t, "0", state("sensor.goalfeed_nba_bos_team_run"))
+}
+
+func TestPublishTeamSensorsSoccer(t *testing.T) {
+	withHAServer(t)
+	debounceAfter = 0
+	entityCache = map[string]entityCacheEntry{}
+	game := models.Game{
+		LeagueId: models.LeagueIdMLS,
+		CurrentState: models.GameState{
+			Status:    models.StatusActive,
+			Period:    2,
+			Clock:     "90+3'",
+			AddedTime: 3,
+			Home:      models.TeamState{Team: models.Team{TeamCode: "TOR"}, Score: 2},
+			Away:      models.TeamState{Team: models.Team{TeamCode: "MTL"}, Score: 2},
+		},
+	}
+	PublishTeamSensors(game)
+
+	var m map[string]interface{}
+	_ = json.Unmarshal([]byte(entityCache["sensor.goalfeed_mls_tor_team_added_time"].Serialized), &m)
+	assert.Equal(t, "3", m["state"])
+}
+
 func TestPublishTeamSensorsMLB(t *testing.T) {
 	withHAServer(t)
 	debounceAfter = 0
@@ -0,0 +1,516 @@
+package homeassistant
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"goalfeed/config"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"github.com/spf13/viper"
+)
+
+// Home Assistant's WebSocket API is the primary transport for events: one
+// authenticated connection is kept open and reused, and the REST calls are
+// the fallback whenever it's down. Home Assistant has no WebSocket command
+// for writing an entity's state, so sensors are still published over REST.
+
+var errWSNotConnected = errors.New("home assistant websocket not connected")
+
+// errNoResult means a command was written but no result came back in time.
+// Home Assistant may still have run it, so it mustn't be sent again.
+var errNoResult = errors.New("no result from home assistant websocket")
+
+// WSConfig holds the keepalive and reconnect settings for the connection.
+type WSConfig struct {
+	ReconnectBaseMs int
+	ReconnectMaxMs  int
+	PingIntervalSec int
+	PongWaitSec     int
+	ResultTimeout   time.Duration
+}
+
+// NewWSConfig reads home_assistant.websocket.* with the same defaults as the
+// NFL Fastcast connection.
+func NewWSConfig() WSConfig {
+	cfg := WSConfig{
+		ReconnectBaseMs: viper.GetInt("home_assistant.websocket.reconnect_base_ms"),
+		ReconnectMaxMs:  viper.GetInt("home_assistant.websocket.reconnect_max_ms"),
+		PingIntervalSec: viper.GetInt("home_assistant.websocket.ping_interval_sec"),
+		PongWaitSec:     viper.GetInt("home_assistant.websocket.pong_wait_sec"),
+		ResultTimeout:   5 * time.Second,
+	}
+	if cfg.ReconnectBaseMs <= 0 {
+		cfg.ReconnectBaseMs = 2000
+	}
+	if cfg.ReconnectMaxMs <= 0 {
+		cfg.ReconnectMaxMs = 60000
+	}
+	if cfg.PingIntervalSec <= 0 {
+		cfg.PingIntervalSec = 30
+	}
+	if cfg.PongWaitSec <= 0 {
+		cfg.PongWaitSec = 75
+	}
+	return cfg
+}
+
+// wsMessage covers every message Goalfeed sends or reads; unused fields are
+// left out of the JSON.
+type wsMessage struct {
+	ID          int             `json:"id,omitempty"`
+	Type        string          `json:"type"`
+	AccessToken string          `json:"access_token,omitempty"`
+	EventType   string          `json:"event_type,omitempty"`
+	EventData   interface{}     `json:"event_data,omitempty"`
+	Domain      string          `json:"domain,omitempty"`
+	Service     string          `json:"service,omitempty"`
+	ServiceData interface{}     `json:"service_data,omitempty"`
+	Target      interface{}     `json:"target,omitempty"`
+	Success     *bool           `json:"success,omitempty"`
+	Error       *wsError        `json:"error,omitempty"`
+	Event       json.RawMessage `json:"event,omitempty"`
+	Message     string          `json:"message,omitempty"`
+}
+
+type wsError struct {
+	Code    string `json:"code"`
+	Message string `json:"message"`
+}
+
+// WSEvent is an event delivered to a subscription.
+type WSEvent struct {
+	EventType string          `json:"event_type"`
+	Data      json.RawMessage `json:"data"`
+	TimeFired string          `json:"time_fired"`
+}
+
+// HAState is an entity's state as Home Assistant reports it.
+type HAState struct {
+	EntityID   string                 `json:"entity_id"`
+	State      string                 `json:"state"`
+	Attributes map[string]interface{} `json:"attributes"`
+}
+
+type wsSubscription struct {
+	eventType string
+	handler   func(WSEvent)
+}
+
+// WSClient keeps one authenticated connection to Home Assistant's WebSocket
+// API, reconnecting with backoff. Subscriptions survive reconnects.
+type WSClient struct {
+	config WSConfig
+
+	mu      sync.Mutex
+	writeMu sync.Mutex
+	conn    *websocket.Conn
+	nextID  int
+	pending map[int]chan wsMessage
+	// active maps the subscription ids of the current connection to their
+	// subscription; subs is the list re-sent after every reconnect
+	active map[int]wsSubscription
+	subs   []wsSubscription
+	stop   chan struct{}
+}
+
+var (
+	wsMu     sync.Mutex
+	wsClient *WSClient
+)
+
+// NewWSClient creates a client that isn't connected yet; Run connects it.
+func NewWSClient(config WSConfig) *WSClient {
+	return &WSClient{
+		config:  config,
+		pending: map[int]chan wsMessage{},
+		active:  map[int]wsSubscription{},
+		stop:    make(chan struct{}),
+	}
+}
+
+// StartWebSocket starts the shared connection in the background unless
+// home_assistant.websocket.enabled is false. Events go over it while it is
+// connected and over REST otherwise.
+func StartWebSocket() {
+	if !config.GetBool("home_assistant.websocket.enabled") {
+		return
+	}
+	wsMu.Lock()
+	defer wsMu.Unlock()
+	if wsClient != nil {
+		return
+	}
+	wsClient = NewWSClient(NewWSConfig())
+	go wsClient.Run()
+}
+
+// WebSocketConnected reports whether the shared connection is up and
+// authenticated.
+func WebSocketConnected() bool {
+	client := sharedWSClient()
+	return client != nil && client.Connected()
+}
+
+func sharedWSClient() *WSClient {
+	wsMu.Lock()
+	defer wsMu.Unlock()
+	return wsClient
+}
+
+// fireEventWS fires the event over the shared connection and reports whether
+// it was sent, so the caller can fall back to REST. An event whose result
+// never came back counts as sent: posting it again could fire it twice.
+func fireEventWS(eventType string, data interface{}) bool {
+	client := sharedWSClient()
+	if client == nil || !client.Connected() {
+		return false
+	}
+	if err := client.FireEvent(eventType, data); err != nil {
+		if errors.Is(err, errNoResult) {
+			logger.Warn(fmt.Sprintf("HA websocket fire_event %s sent but not confirmed, not resending over REST: %v", eventType, err))
+			return true
+		}
+		logger.Warn(fmt.Sprintf("HA websocket fire_event %s failed, falling back to REST: %v", eventType, err))
+		return false
+	}
+	return true
+}
+
+// Connected reports whether the client is connected and authenticated.
+func (c *WSClient) Connected() bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.conn != nil
+}
+
+// Stop closes the connection and ends Run.
+func (c *WSClient) Stop() {
+	c.mu.Lock()
+	select {
+	case <-c.stop:
+	default:
+		close(c.stop)
+	}
+	conn := c.conn
+	c.mu.Unlock()
+	if conn != nil {
+		_ = conn.Close()
+	}
+}
+
+// FireEvent fires eventType on Home Assistant's event bus and waits for the
+// result.
+func (c *WSClient) FireEvent(eventType string, data interface{}) error {
+	_, err := c.call(wsMessage{Type: "fire_event", EventType: eventType, EventData: data})
+	return err
+}
+
+// CallService calls domain.service with its data and target (entity_id,
+// area_id or device_id) and waits for the result.
+func (c *WSClient) CallService(domain, service string, data, target map[string]interface{}) error {
+	msg := wsMessage{Type: "call_service", Domain: domain, Service: service}
+	if len(data) > 0 {
+		msg.ServiceData = data
+	}
+	if len(target) > 0 {
+		msg.Target = target
+	}
+	_, err := c.call(msg)
+	return err
+}
+
+// Subscribe calls handler for every eventType event. The subscription is
+// sent now if the client is connected and again after each reconnect.
+func (c *WSClient) Subscribe(eventType string, handler func(WSEvent)) {
+	sub := wsSubscription{eventType: eventType, handler: handler}
+	c.mu.Lock()
+	c.subs = append(c.subs, sub)
+	connected := c.conn != nil
+	c.mu.Unlock()
+	if connected {
+		if err := c.subscribe(sub); err != nil {
+			logger.Warn(fmt.Sprintf("HA websocket subscribe %s failed: %v", eventType, err))
+		}
+	}
+}
+
+func (c *WSClient) subscribe(sub wsSubscription) error {
+	id, result, err := c.send(wsMessage{Type: "subscribe_events", EventType: sub.eventType}, func(id int) {
+		c.active[id] = sub
+	})
+	if err != nil {
+		return err
+	}
+	_, err = c.await(id, result)
+	return err
+}
+
+// call sends a command and waits for its result.
+func (c *WSClient) call(msg wsMessage) (wsMessage, error) {
+	id, result, err := c.send(msg, nil)
+	if err != nil {
+		return wsMessage{}, err
+	}
+	return c.await(id, result)
+}
+
+// send assigns the message an id, registers it as pending and writes it,
+// returning the channel its result arrives on. register runs under the
+// client's lock with the new id.
+func (c *WSClient) send(msg wsMessage, register func(id int)) (int, chan wsMessage, error) {
+	c.mu.Lock()
+	conn := c.conn
+	if conn == nil {
+		c.mu.Unlock()
+		return 0, nil, errWSNotConnected
+	}
+	c.nextID++
+	msg.ID = c.nextID
+	result := make(chan wsMessage, 1)
+	c.pending[msg.ID] = result
+	if register != nil {
+		register(msg.ID)
+	}
+	c.mu.Unlock()
+
+	if err := c.write(conn, msg); err != nil {
+		c.mu.Lock()
+		delete(c.pending, msg.ID)
+		delete(c.active, msg.ID)
+		c.mu.Unlock()
+		return 0, nil, err
+	}
+	return msg.ID, result, nil
+}
+
+func (c *WSClient) await(id int, ch chan wsMessage) (wsMessage, error) {
+	select {
+	case result, ok := <-ch:
+		if !ok {
+			return wsMessage{}, errWSNotConnected
+		}
+		if result.Success != nil && !*result.Success {
+			if result.Error != nil {
+				return result, fmt.Errorf("%s: %s", result.Error.Code, result.Error.Message)
+			}
+			return result, errors.New("command failed")
+		}
+		return result, nil
+	case <-time.After(c.config.ResultTimeout):
+		c.mu.Lock()
+		delete(c.pending, id)
+		c.mu.Unlock()
+		return wsMessage{}, fmt.Errorf("%w for command %d after %s", errNoResult, id, c.config.ResultTimeout)
+	}
+}
+
+func (c *WSClient) write(conn *websocket.Conn, msg wsMessage) error {
+	c.writeMu.Lock()
+	defer c.writeMu.Unlock()
+	_ = conn.SetWriteDeadline(time.Now().Add(5 * time.Second))
+	return conn.WriteJSON(msg)
+}
+
+// Run connects and keeps reconnecting with exponential backoff until Stop
+// is called. The backoff resets after each successful authentication.
+func (c *WSClient) Run() {
+	backoffMs := c.config.ReconnectBaseMs
+	for {
+		authenticated, err := c.connectAndServe()
+		select {
+		case <-c.stop:
+			return
+		default:
+		}
+		if authenticated {
+			backoffMs = c.config.ReconnectBaseMs
+		}
+		if err != nil {
+			logger.Debug(fmt.Sprintf("HA websocket: %v; retrying in %dms", err, backoffMs))
+		}
+		select {
+		case <-c.stop:
+			return
+		case <-time.After(wsJitter(backoffMs)):
+		}
+		backoffMs = wsBackoff(backoffMs, c.config.ReconnectMaxMs)
+	}
+}
+
+// connectAndServe dials, authenticates and reads messages until the
+// connection drops. It reports whether authentication succeeded.
+func (c *WSClient) connectAndServe() (bool, error) {
+	haURL, token, _ := ResolveHA()
+	if haURL == "" || token == "" {
+		return false, errors.New("home assistant URL or access token not set")
+	}
+	if err := validateOutboundHAURL(haURL); err != nil {
+		return false, err
+	}
+	dialer := websocket.Dialer{
+		HandshakeTimeout: 10 * time.Second,
+		Proxy:            http.ProxyFromEnvironment,
+	}
+	conn, _, err := dialer.Dial(websocketURL(haURL), nil)
+	if err != nil {
+		return false, err
+	}
+	defer conn.Close()
+
+	if err := authenticate(conn, token); err != nil {
+		return false, err
+	}
+	logger.Info("Connected to Home Assistant websocket")
+
+	c.mu.Lock()
+	c.conn = conn
+	c.active = map[int]wsSubscription{}
+	subs := append([]wsSubscription(nil), c.subs...)
+	c.mu.Unlock()
+	defer c.disconnect()
+
+	pongWait := time.Duration(c.config.PongWaitSec) * time.Second
+	_ = conn.SetReadDeadline(time.Now().Add(pongWait))
+	done := make(chan struct{})
+	defer close(done)
+	go c.keepalive(conn, done)
+	go func() {
+		for _, sub := range subs {
+			if err := c.subscribe(sub); err != nil {
+				logger.Warn(fmt.Sprintf("HA websocket subscribe %s failed: %v", sub.eventType, err))
+			}
+		}
+	}()
+
+	for {
+		var msg wsMessage
+		if err := conn.ReadJSON(&msg); err != nil {
+			return true, err
+		}
+		_ = conn.SetReadDeadline(time.Now().Add(pongWait))
+		c.dispatch(msg)
+	}
+}
+
+// authenticate answers Home Assistant's auth_required with the token.
+func authenticate(conn *websocket.Conn, token string) error {
+	_ = conn.SetReadDeadline(time.Now().Add(10 * time.Second))
+	var msg wsMessage
+	if err := conn.ReadJSON(&msg); err != nil {
+		return err
+	}
+	if msg.Type != "auth_required" {
+		return fmt.Errorf("unexpected message %q before auth", msg.Type)
+	}
+	_ = conn.SetWriteDeadline(time.Now().Add(5 * time.Second))
+	if err := conn.WriteJSON(wsMessage{Type: "auth", AccessToken: token}); err != nil {
+		return err
+	}
+	if err := conn.ReadJSON(&msg); err != nil {
+		return err
+	}
+	if msg.Type != "auth_ok" {
+		return fmt.Errorf("authentication failed: %s", msg.Message)
+	}
+	return nil
+}
+
+// keepalive sends Home Assistant's ping command; the pong, like any other
+// message, pushes the read deadline back.
+func (c *WSClient) keepalive(conn *websocket.Conn, done chan struct{}) {
+	ticker := time.NewTicker(time.Duration(c.config.PingIntervalSec) * time.Second)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			c.mu.Lock()
+			c.nextID++
+			id := c.nextID
+			c.mu.Unlock()
+			if err := c.write(conn, wsMessage{ID: id, Type: "ping"}); err != nil {
+				return
+			}
+		case <-done:
+			return
+		}
+	}
+}
+
+func (c *WSClient) dispatch(msg wsMessage) {
+	switch msg.Type {
+	case "result":
+		c.mu.Lock()
+		ch := c.pending[msg.ID]
+		delete(c.pending, msg.ID)
+		if msg.Success != nil && !*msg.Success {
+			delete(c.active, msg.ID)
+		}
+		c.mu.Unlock()
+		if ch != nil {
+			ch <- msg
+		}
+	case "event":
+		c.mu.Lock()
+		sub, ok := c.active[msg.ID]
+		c.mu.Unlock()
+		if !ok {
+			return
+		}
+		var event WSEvent
+		if err := json.Unmarshal(msg.Event, &event); err == nil {
+			sub.handler(event)
+		}
+	}
+}
+
+// disconnect fails every command still waiting for a result.
+func (c *WSClient) disconnect() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.conn = nil
+	for id, ch := range c.pending {
+		close(ch)
+		delete(c.pending, id)
+	}
+	c.active = map[int]wsSubscription{}
+}
+
+// websocketURL turns the REST base URL into the WebSocket endpoint. Through
+// the Supervisor proxy (".../core") it is /websocket; directly it is
+// /api/websocket.
+func websocketURL(haURL string) string {
+	u := strings.TrimRight(haURL, "/")
+	switch {
+	case strings.HasPrefix(u, "https://"):
+		u = "wss://" + strings.TrimPrefix(u, "https://")
+	case strings.HasPrefix(u, "http://"):
+		u = "ws://" + strings.TrimPrefix(u, "http://")
+	}
+	if strings.HasSuffix(u, "/core") {
+		return u + "/websocket"
+	}
+	return u + "/api/websocket"
+}
+
+func wsBackoff(currentMs, maxMs int) int {
+	currentMs *= 2
+	if currentMs > maxMs {
+		currentMs = maxMs
+	}
+	return currentMs
+}
+
+// wsJitter spreads reconnects by ±10% so several Goalfeeds don't retry in
+// step after Home Assistant restarts.
+func wsJitter(ms int) time.Duration {
+	j := int64(ms / 10)
+	if j == 0 {
+		return time.Duration(ms) * time.Millisecond
+	}
+	return time.Duration(int64(ms)+j-time.Now().UnixNano()%(2*j+1)) * time.Millisecond
+}
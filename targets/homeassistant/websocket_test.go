@@ -0,0 +1,169 @@
+package homeassistant
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"github.com/stretchr/testify/assert"
+)
+
+// fakeHAWebSocket speaks enough of Home Assistant's WebSocket API for the
+// client: auth, fire_event (never answered for "unanswered" events),
+// call_service (which only succeeds for scenes) and subscribe_events. Fired
+// events are sent on fired, and a state_changed event follows each
+// subscription.
+func fakeHAWebSocket(t *testing.T, token string, fired chan map[string]interface{}) *httptest.Server {
+	upgrader := websocket.Upgrader{}
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/core/websocket" {
+			http.NotFound(w, r)
+			return
+		}
+		conn, err := upgrader.Upgrade(w, r, nil)
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		_ = conn.WriteJSON(map[string]interface{}{"type": "auth_required"})
+		var auth map[string]interface{}
+		if conn.ReadJSON(&auth) != nil {
+			return
+		}
+		if auth["access_token"] != token {
+			_ = conn.WriteJSON(map[string]interface{}{"type": "auth_invalid", "message": "Invalid access token"})
+			return
+		}
+		_ = conn.WriteJSON(map[string]interface{}{"type": "auth_ok"})
+		for {
+			var msg map[string]interface{}
+			if conn.ReadJSON(&msg) != nil {
+				return
+			}
+			id := msg["id"]
+			switch msg["type"] {
+			case "fire_event":
+				fired <- msg
+				if msg["event_type"] == "unanswered" {
+					continue
+				}
+				_ = conn.WriteJSON(map[string]interface{}{"id": id, "type": "result", "success": true})
+			case "call_service":
+				_ = conn.WriteJSON(map[string]interface{}{"id": id, "type": "result", "success": msg["domain"] == "scene"})
+			case "subscribe_events":
+				_ = conn.WriteJSON(map[string]interface{}{"id": id, "type": "result", "success": true})
+				_ = conn.WriteJSON(map[string]interface{}{"id": id, "type": "event", "event": map[string]interface{}{
+					"event_type": "state_changed",
+					"data": map[string]interface{}{
+						"entity_id": "input_boolean.goalfeed_mute",
+						"new_state": map[string]interface{}{"entity_id": "input_boolean.goalfeed_mute", "state": "on"},
+					},
+				}})
+			}
+		}
+	}))
+}
+
+func withSupervisor(t *testing.T, url, token string) {
+	os.Setenv("SUPERVISOR_API", url)
+	os.Setenv("SUPERVISOR_TOKEN", token)
+	t.Cleanup(func() {
+		os.Unsetenv("SUPERVISOR_API")
+		os.Unsetenv("SUPERVISOR_TOKEN")
+	})
+}
+
+func TestWebsocketURL(t *testing.T) {
+	assert.Equal(t, "ws://supervisor/core/websocket", websocketURL("http://supervisor/core"))
+	assert.Equal(t, "wss://ha.local:8123/api/websocket", websocketURL("https://ha.local:8123/"))
+	assert.Equal(t, "ws://192.168.1.10:8123/api/websocket", websocketURL("http://192.168.1.10:8123"))
+}
+
+func TestWSClientFiresEventsAndSubscribes(t *testing.T) {
+	fired := make(chan map[string]interface{}, 1)
+	srv := fakeHAWebSocket(t, "t", fired)
+	defer srv.Close()
+	withSupervisor(t, srv.URL, "t")
+
+	client := NewWSClient(WSConfig{ReconnectBaseMs: 10, ReconnectMaxMs: 20, PingIntervalSec: 30, PongWaitSec: 60, ResultTimeout: time.Second})
+	type stateChange struct {
+		EntityID string   `json:"entity_id"`
+		NewState *HAState `json:"new_state"`
+	}
+	changes := make(chan stateChange, 1)
+	client.Subscribe("state_changed", func(event WSEvent) {
+		var change stateChange
+		_ = json.Unmarshal(event.Data, &change)
+		changes <- change
+	})
+	go client.Run()
+	defer client.Stop()
+
+	assert.Eventually(t, client.Connected, 2*time.Second, 10*time.Millisecond)
+	assert.NoError(t, client.FireEvent("goal", map[string]interface{}{"team_code": "WPG"}))
+	select {
+	case msg := <-fired:
+		assert.Equal(t, "goal", msg["event_type"])
+		assert.Equal(t, "WPG", msg["event_data"].(map[string]interface{})["team_code"])
+	case <-time.After(time.Second):
+		t.Fatal("event was not fired")
+	}
+
+	select {
+	case change := <-changes:
+		assert.Equal(t, "input_boolean.goalfeed_mute", change.EntityID)
+		assert.Equal(t, "on", change.NewState.State)
+	case <-time.After(time.Second):
+		t.Fatal("subscription received no state change")
+	}
+}
+
+func TestWSClientRejectedToken(t *testing.T) {
+	srv := fakeHAWebSocket(t, "right", make(chan map[string]interface{}, 1))
+	defer srv.Close()
+	withSupervisor(t, srv.URL, "wrong")
+
+	client := NewWSClient(NewWSConfig())
+	authenticated, err := client.connectAndServe()
+	assert.False(t, authenticated)
+	assert.Error(t, err)
+	assert.False(t, client.Connected())
+	assert.ErrorIs(t, client.FireEvent("goal", nil), errWSNotConnected)
+}
+
+func TestFireEventWSFallsBackWithoutConnection(t *testing.T) {
+	assert.False(t, fireEventWS("goal", map[string]interface{}{}))
+}
+
+func TestFireEventWSDoesNotFallBackWithoutResult(t *testing.T) {
+	fired := make(chan map[string]interface{}, 2)
+	srv := fakeHAWebSocket(t, "t", fired)
+	defer srv.Close()
+	withSupervisor(t, srv.URL, "t")
+
+	client := NewWSClient(WSConfig{ReconnectBaseMs: 10, ReconnectMaxMs: 20, PingIntervalSec: 30, PongWaitSec: 60, ResultTimeout: 50 * time.Millisecond})
+	go client.Run()
+	defer client.Stop()
+	wsMu.Lock()
+	wsClient = client
+	wsMu.Unlock()
+	t.Cleanup(func() {
+		wsMu.Lock()
+		wsClient = nil
+		wsMu.Unlock()
+	})
+
+	assert.Eventually(t, client.Connected, 2*time.Second, 10*time.Millisecond)
+	assert.ErrorIs(t, client.FireEvent("unanswered", nil), errNoResult)
+	assert.True(t, fireEventWS("unanswered", map[string]interface{}{}), "an unconfirmed event must not be posted again")
+	assert.True(t, fireEventWS("goal", map[string]interface{}{}))
+}
+
+func TestWSBackoff(t *testing.T) {
+	assert.Equal(t, 4000, wsBackoff(2000, 60000))
+	assert.Equal(t, 60000, wsBackoff(40000, 60000))
+}
@@ -0,0 +1,328 @@
+// Package mqtt publishes Goalfeed's sensors to Home Assistant through MQTT
+// discovery. Each watched team is a device; its metrics are sensors and
+// binary sensors with a unique_id, and goals are an event entity. Configs and
+// states are retained, so Home Assistant has them again straight after a
+// restart, and an availability topic with a last will marks everything
+// unavailable while Goalfeed is down.
+//
+// The package only knows about devices and entities; the homeassistant
+// target decides their names, as it does for the REST sensors.
+package mqtt
+
+import (
+	"encoding/json"
+	"fmt"
+	"goalfeed/config"
+	"goalfeed/utils"
+	"strings"
+	"sync"
+	"time"
+
+	paho "github.com/eclipse/paho.mqtt.golang"
+)
+
+var logger = utils.GetLogger()
+
+// Device is one watched team.
+type Device struct {
+	ID    string
+	Name  string
+	Model string
+}
+
+// Entity is one metric of a device. Component is "sensor" or
+// "binary_sensor"; ObjectID is also the entity ID Home Assistant gives it.
+type Entity struct {
+	Component string
+	ObjectID  string
+	Name      string
+	Device    Device
+	// StateClass, Unit and DeviceClass are left out of the config when empty
+	StateClass  string
+	Unit        string
+	DeviceClass string
+}
+
+// broker is the part of the MQTT client the target uses, so tests can
+// record what is published.
+type broker interface {
+	Publish(topic string, retained bool, payload []byte) error
+	Connected() bool
+}
+
+var (
+	mu     sync.Mutex
+	client broker
+	// retained holds the last payload of every retained topic. It is what
+	// gets republished when the connection comes back or Home Assistant
+	// restarts, and it keeps unchanged states from being sent again.
+	retained = map[string][]byte{}
+)
+
+// Enabled reports whether mqtt.enabled is set with a broker to connect to.
+func Enabled() bool {
+	return config.GetBool("mqtt.enabled") && config.GetString("mqtt.broker") != ""
+}
+
+func discoveryPrefix() string {
+	if p := strings.Trim(config.GetString("mqtt.discovery_prefix"), "/"); p != "" {
+		return p
+	}
+	return "homeassistant"
+}
+
+func topicPrefix() string {
+	if p := strings.Trim(config.GetString("mqtt.topic_prefix"), "/"); p != "" {
+		return p
+	}
+	return "goalfeed"
+}
+
+func availabilityTopic() string {
+	return topicPrefix() + "/status"
+}
+
+// Start connects to mqtt.broker in the background when MQTT is enabled. The
+// client reconnects on its own, and everything retained is republished on
+// each connect.
+func Start() {
+	if !Enabled() {
+		return
+	}
+	mu.Lock()
+	defer mu.Unlock()
+	if client != nil {
+		return
+	}
+	client = newPahoBroker()
+}
+
+// PublishState announces the entity the first time it is seen and publishes
+// its state and attributes when they change.
+func PublishState(entity Entity, state string, attrs map[string]interface{}) {
+	mu.Lock()
+	defer mu.Unlock()
+
+	base := topicPrefix() + "/" + entity.ObjectID
+	configTopic := fmt.Sprintf("%s/%s/%s/config", discoveryPrefix(), entity.Component, entity.ObjectID)
+	if _, ok := retained[configTopic]; !ok {
+		publishRetained(configTopic, entityConfig(entity, base))
+		announceEvents(entity.Device)
+	}
+	publishRetained(base+"/state", []byte(state))
+	if attrs == nil {
+		attrs = map[string]interface{}{}
+	}
+	b, _ := json.Marshal(attrs)
+	publishRetained(base+"/attributes", b)
+}
+
+// PublishEvent fires eventType on the device's event entity. Events aren't
+// retained; a goal from before a restart isn't a goal now.
+func PublishEvent(device Device, eventType string, data map[string]interface{}) {
+	mu.Lock()
+	defer mu.Unlock()
+
+	announceEvents(device)
+	payload := map[string]interface{}{}
+	for k, v := range data {
+		payload[k] = v
+	}
+	payload["event_type"] = eventType
+	b, _ := json.Marshal(payload)
+	if client == nil || !client.Connected() {
+		return
+	}
+	if err := client.Publish(eventTopic(device, eventType), false, b); err != nil {
+		logger.Warn(fmt.Sprintf("MQTT event publish failed: %v", err))
+	}
+}
+
+// eventTypes are the events a team's event entity fires.
+var eventTypes = []string{"goal"}
+
+func eventTopic(device Device, eventType string) string {
+	return fmt.Sprintf("%s/%s/%s", topicPrefix(), device.ID, eventType)
+}
+
+// announceEvents publishes the device's event entity config once. Callers
+// hold mu.
+func announceEvents(device Device) {
+	for _, eventType := range eventTypes {
+		objectID := device.ID + "_" + eventType
+		configTopic := fmt.Sprintf("%s/event/%s/config", discoveryPrefix(), objectID)
+		if _, ok := retained[configTopic]; ok {
+			continue
+		}
+		b, _ := json.Marshal(map[string]interface{}{
+			"name":               strings.ToUpper(eventType[:1]) + eventType[1:],
+			"unique_id":          objectID,
+			"object_id":          objectID,
+			"state_topic":        eventTopic(device, eventType),
+			"event_types":        []string{eventType},
+			"availability_topic": availabilityTopic(),
+			"device":             deviceConfig(device),
+		})
+		publishRetained(configTopic, b)
+	}
+}
+
+func entityConfig(entity Entity, base string) []byte {
+	cfg := map[string]interface{}{
+		"name":                  entity.Name,
+		"unique_id":             entity.ObjectID,
+		"object_id":             entity.ObjectID,
+		"state_topic":           base + "/state",
+		"json_attributes_topic": base + "/attributes",
+		"availability_topic":    availabilityTopic(),
+		"device":                deviceConfig(entity.Device),
+	}
+	if entity.StateClass != "" {
+		cfg["state_class"] = entity.StateClass
+	}
+	if entity.Unit != "" {
+		cfg["unit_of_measurement"] = entity.Unit
+	}
+	if entity.DeviceClass != "" {
+		cfg["device_class"] = entity.DeviceClass
+	}
+	if entity.Component == "binary_sensor" {
+		cfg["payload_on"] = "on"
+		cfg["payload_off"] = "off"
+	}
+	b, _ := json.Marshal(cfg)
+	return b
+}
+
+func deviceConfig(device Device) map[string]interface{} {
+	return map[string]interface{}{
+		"identifiers":  []string{device.ID},
+		"name":         device.Name,
+		"model":        device.Model,
+		"manufacturer": "Goalfeed",
+	}
+}
+
+// publishRetained records the payload and sends it when it differs from the
+// last one. Callers hold mu.
+func publishRetained(topic string, payload []byte) {
+	if last, ok := retained[topic]; ok && string(last) == string(payload) {
+		return
+	}
+	retained[topic] = payload
+	if client == nil || !client.Connected() {
+		return
+	}
+	if err := client.Publish(topic, true, payload); err != nil {
+		logger.Warn(fmt.Sprintf("MQTT publish to %s failed: %v", topic, err))
+	}
+}
+
+// Remove deletes an entity from Home Assistant by clearing its retained
+// discovery config, state and attributes. Removing a device's last entity,
+// its event entity included, removes the device. The empty payloads are
+// sent even for topics this process never published, since an earlier run
+// may have.
+func Remove(component, objectID string) error {
+	mu.Lock()
+	defer mu.Unlock()
+	if client == nil || !client.Connected() {
+		return fmt.Errorf("mqtt broker not connected")
+	}
+	base := topicPrefix() + "/" + objectID
+	for _, topic := range []string{
+		fmt.Sprintf("%s/%s/%s/config", discoveryPrefix(), component, objectID),
+		base + "/state",
+		base + "/attributes",
+	} {
+		delete(retained, topic)
+		// An empty retained payload is how the broker drops a retained message
+		if err := client.Publish(topic, true, []byte{}); err != nil {
+			return fmt.Errorf("clearing %s: %v", topic, err)
+		}
+	}
+	return nil
+}
+
+// WaitConnected waits up to timeout for the broker connection Start opens,
+// for one-off commands that publish and exit.
+func WaitConnected(timeout time.Duration) bool {
+	deadline := time.Now().Add(timeout)
+	for {
+		mu.Lock()
+		connected := client != nil && client.Connected()
+		mu.Unlock()
+		if connected || time.Now().After(deadline) {
+			return connected
+		}
+		time.Sleep(50 * time.Millisecond)
+	}
+}
+
+// republishAll sends every retained payload again, for a new connection or
+// a Home Assistant that just restarted.
+func republishAll() {
+	mu.Lock()
+	defer mu.Unlock()
+	if client == nil {
+		return
+	}
+	for topic, payload := range retained {
+		if err := client.Publish(topic, true, payload); err != nil {
+			logger.Warn(fmt.Sprintf("MQTT republish to %s failed: %v", topic, err))
+			return
+		}
+	}
+}
+
+// pahoBroker is the broker backed by the Paho client.
+type pahoBroker struct {
+	client paho.Client
+}
+
+func newPahoBroker() *pahoBroker {
+	clientID := config.GetString("mqtt.client_id")
+	if clientID == "" {
+		clientID = "goalfeed"
+	}
+	opts := paho.NewClientOptions().
+		AddBroker(config.GetString("mqtt.broker")).
+		SetClientID(clientID).
+		SetUsername(config.GetString("mqtt.username")).
+		SetPassword(config.GetString("mqtt.password")).
+		SetWill(availabilityTopic(), "offline", 1, true).
+		SetAutoReconnect(true).
+		SetConnectRetry(true).
+		SetConnectRetryInterval(10 * time.Second).
+		SetOrderMatters(false)
+	opts.SetOnConnectHandler(func(c paho.Client) {
+		logger.Info("Connected to MQTT broker")
+		c.Publish(availabilityTopic(), 1, true, "online")
+		// Home Assistant announces "online" on its status topic when it
+		// starts; the discovery configs are sent again for it
+		c.Subscribe(discoveryPrefix()+"/status", 1, func(_ paho.Client, msg paho.Message) {
+			if string(msg.Payload()) == "online" {
+				go republishAll()
+			}
+		})
+		go republishAll()
+	})
+	opts.SetConnectionLostHandler(func(_ paho.Client, err error) {
+		logger.Warn(fmt.Sprintf("MQTT connection lost: %v", err))
+	})
+	b := &pahoBroker{client: paho.NewClient(opts)}
+	b.client.Connect()
+	return b
+}
+
+func (b *pahoBroker) Publish(topic string, retained bool, payload []byte) error {
+	token := b.client.Publish(topic, 1, retained, payload)
+	if !token.WaitTimeout(5 * time.Second) {
+		return fmt.Errorf("timed out")
+	}
+	return token.Error()
+}
+
+func (b *pahoBroker) Connected() bool {
+	return b.client.IsConnectionOpen()
+}
@@ -0,0 +1,205 @@
+package mqtt
+
+import (
+	"encoding/json"
+	"os"
+	"sync"
+	"testing"
+	"time"
+
+	paho "github.com/eclipse/paho.mqtt.golang"
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+)
+
+type published struct {
+	topic    string
+	retained bool
+	payload  string
+}
+
+type recordingBroker struct {
+	mu       sync.Mutex
+	messages []published
+}
+
+func (b *recordingBroker) Publish(topic string, retained bool, payload []byte) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.messages = append(b.messages, published{topic, retained, string(payload)})
+	return nil
+}
+
+func (b *recordingBroker) Connected() bool { return true }
+
+func (b *recordingBroker) topics() []string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	var topics []string
+	for _, m := range b.messages {
+		topics = append(topics, m.topic)
+	}
+	return topics
+}
+
+func withRecordingBroker(t *testing.T) *recordingBroker {
+	viper.Reset()
+	b := &recordingBroker{}
+	mu.Lock()
+	client = b
+	retained = map[string][]byte{}
+	mu.Unlock()
+	t.Cleanup(func() {
+		mu.Lock()
+		client = nil
+		retained = map[string][]byte{}
+		mu.Unlock()
+	})
+	return b
+}
+
+var wpg = Device{ID: "goalfeed_nhl_wpg", Name: "NHL WPG", Model: "NHL"}
+
+func TestEnabled(t *testing.T) {
+	viper.Reset()
+	assert.False(t, Enabled())
+	viper.Set("mqtt.enabled", true)
+	assert.False(t, Enabled(), "a broker is required")
+	viper.Set("mqtt.broker", "tcp://localhost:1883")
+	assert.True(t, Enabled())
+}
+
+func TestPublishStateAnnouncesOnceAndDedupes(t *testing.T) {
+	b := withRecordingBroker(t)
+	entity := Entity{Component: "sensor", ObjectID: "goalfeed_nhl_wpg_team_current_score", Name: "Current score", Device: wpg}
+
+	PublishState(entity, "2", nil)
+	assert.Equal(t, []string{
+		"homeassistant/sensor/goalfeed_nhl_wpg_team_current_score/config",
+		"homeassistant/event/goalfeed_nhl_wpg_goal/config",
+		"goalfeed/goalfeed_nhl_wpg_team_current_score/state",
+		"goalfeed/goalfeed_nhl_wpg_team_current_score/attributes",
+	}, b.topics())
+	for _, m := range b.messages {
+		assert.True(t, m.retained, m.topic)
+	}
+
+	var cfg map[string]interface{}
+	assert.NoError(t, json.Unmarshal([]byte(b.messages[0].payload), &cfg))
+	assert.Equal(t, "goalfeed_nhl_wpg_team_current_score", cfg["unique_id"])
+	assert.Equal(t, "goalfeed/goalfeed_nhl_wpg_team_current_score/state", cfg["state_topic"])
+	assert.Equal(t, "goalfeed/status", cfg["availability_topic"])
+	assert.Equal(t, []interface{}{"goalfeed_nhl_wpg"}, cfg["device"].(map[string]interface{})["identifiers"])
+
+	PublishState(entity, "2", nil)
+	assert.Len(t, b.topics(), 4, "an unchanged state isn't sent again")
+	PublishState(entity, "3", nil)
+	assert.Equal(t, "goalfeed/goalfeed_nhl_wpg_team_current_score/state", b.topics()[4])
+	assert.Equal(t, "3", b.messages[4].payload)
+}
+
+func TestEntityConfigCarriesMetadata(t *testing.T) {
+	var cfg map[string]interface{}
+	entity := Entity{Component: "sensor", ObjectID: "goalfeed_nfl_kc_team_distance", Name: "Distance", StateClass: "measurement", Unit: "yd"}
+	assert.NoError(t, json.Unmarshal(entityConfig(entity, "goalfeed/goalfeed_nfl_kc_team_distance"), &cfg))
+	assert.Equal(t, "measurement", cfg["state_class"])
+	assert.Equal(t, "yd", cfg["unit_of_measurement"])
+	assert.NotContains(t, cfg, "device_class", "empty metadata is left out")
+}
+
+func TestBinarySensorPayloads(t *testing.T) {
+	b := withRecordingBroker(t)
+	PublishState(Entity{Component: "binary_sensor", ObjectID: "goalfeed_nhl_wpg_team_goalie_pulled", Name: "Goalie pulled", Device: wpg}, "off", nil)
+
+	var cfg map[string]interface{}
+	assert.NoError(t, json.Unmarshal([]byte(b.messages[0].payload), &cfg))
+	assert.Equal(t, "on", cfg["payload_on"])
+	assert.Equal(t, "off", cfg["payload_off"])
+}
+
+func TestPublishEventIsNotRetained(t *testing.T) {
+	b := withRecordingBroker(t)
+	PublishEvent(wpg, "goal", map[string]interface{}{"team_code": "WPG"})
+
+	last := b.messages[len(b.messages)-1]
+	assert.Equal(t, "goalfeed/goalfeed_nhl_wpg/goal", last.topic)
+	assert.False(t, last.retained)
+	var payload map[string]interface{}
+	assert.NoError(t, json.Unmarshal([]byte(last.payload), &payload))
+	assert.Equal(t, "goal", payload["event_type"])
+	assert.Equal(t, "WPG", payload["team_code"])
+}
+
+func TestRepublishAll(t *testing.T) {
+	b := withRecordingBroker(t)
+	PublishState(Entity{Component: "sensor", ObjectID: "goalfeed_nhl_wpg_team_shots", Name: "Shots", Device: wpg}, "12", nil)
+	sent := len(b.topics())
+	republishAll()
+	assert.ElementsMatch(t, b.topics()[:sent], b.topics()[sent:])
+}
+
+func TestRemoveClearsRetainedTopics(t *testing.T) {
+	b := withRecordingBroker(t)
+	PublishState(Entity{Component: "sensor", ObjectID: "goalfeed_nhl_wpg_team_shots", Name: "Shots", Device: wpg}, "12", nil)
+	sent := len(b.topics())
+
+	assert.NoError(t, Remove("sensor", "goalfeed_nhl_wpg_team_shots"))
+	assert.Equal(t, []string{
+		"homeassistant/sensor/goalfeed_nhl_wpg_team_shots/config",
+		"goalfeed/goalfeed_nhl_wpg_team_shots/state",
+		"goalfeed/goalfeed_nhl_wpg_team_shots/attributes",
+	}, b.topics()[sent:])
+	for _, m := range b.messages[sent:] {
+		assert.True(t, m.retained)
+		assert.Empty(t, m.payload)
+	}
+	republishAll()
+	assert.NotContains(t, b.topics()[sent+3:], "goalfeed/goalfeed_nhl_wpg_team_shots/state", "a removed entity isn't republished")
+}
+
+// TestMosquitto runs against a real broker, e.g.
+// GOALFEED_TEST_MQTT_BROKER=tcp://localhost:1883 with a local Mosquitto.
+func TestMosquitto(t *testing.T) {
+	broker := os.Getenv("GOALFEED_TEST_MQTT_BROKER")
+	if broker == "" {
+		t.Skip("GOALFEED_TEST_MQTT_BROKER not set")
+	}
+	viper.Reset()
+	viper.Set("mqtt.enabled", true)
+	viper.Set("mqtt.broker", broker)
+	viper.Set("mqtt.client_id", "goalfeed-test")
+	mu.Lock()
+	client = nil
+	retained = map[string][]byte{}
+	mu.Unlock()
+	Start()
+	t.Cleanup(func() {
+		mu.Lock()
+		if b, ok := client.(*pahoBroker); ok {
+			b.client.Disconnect(100)
+		}
+		client = nil
+		mu.Unlock()
+	})
+	assert.Eventually(t, func() bool {
+		mu.Lock()
+		defer mu.Unlock()
+		return client.Connected()
+	}, 5*time.Second, 50*time.Millisecond)
+
+	PublishState(Entity{Component: "sensor", ObjectID: "goalfeed_test_wpg_team_shots", Name: "Shots", Device: Device{ID: "goalfeed_test_wpg", Name: "TEST WPG"}}, "7", nil)
+
+	received := make(chan string, 1)
+	reader := paho.NewClient(paho.NewClientOptions().AddBroker(broker).SetClientID("goalfeed-test-reader"))
+	assert.True(t, reader.Connect().WaitTimeout(5*time.Second))
+	defer reader.Disconnect(100)
+	reader.Subscribe("goalfeed/goalfeed_test_wpg_team_shots/state", 1, func(_ paho.Client, msg paho.Message) {
+		received <- string(msg.Payload())
+	})
+	select {
+	case state := <-received:
+		assert.Equal(t, "7", state, "the retained state is delivered to a new subscriber")
+	case <-time.After(5 * time.Second):
+		t.Fatal("no retained state from the broker")
+	}
+}
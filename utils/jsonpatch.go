@@ -0,0 +1,280 @@
+package utils
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// JSONPatchOp is a single RFC 6902 JSON Patch operation.
+type JSONPatchOp struct {
+	Op    string          `json:"op"`
+	Path  string          `json:"path"`
+	From  string          `json:"from,omitempty"`
+	Value json.RawMessage `json:"value,omitempty"`
+}
+
+var errJSONPatchPathNotFound = errors.New("path not found")
+
+// DecodeJSONDocument decodes a JSON document into the generic tree that
+// ApplyJSONPatch works on. Numbers are kept as json.Number so they encode
+// back exactly as the feed sent them.
+func DecodeJSONDocument(data []byte) (interface{}, error) {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	var doc interface{}
+	if err := decoder.Decode(&doc); err != nil {
+		return nil, err
+	}
+	return doc, nil
+}
+
+// ApplyJSONPatch applies ops to doc in order and returns the patched document.
+// Maps and slices in doc are modified in place, and a failed operation leaves
+// the operations before it applied, so on error callers should discard doc and
+// reload it from its source.
+func ApplyJSONPatch(doc interface{}, ops []JSONPatchOp) (interface{}, error) {
+	for i, op := range ops {
+		patched, err := applyJSONPatchOp(doc, op)
+		if err != nil {
+			return doc, fmt.Errorf("json patch op %d (%s %s): %w", i, op.Op, op.Path, err)
+		}
+		doc = patched
+	}
+	return doc, nil
+}
+
+func applyJSONPatchOp(doc interface{}, op JSONPatchOp) (interface{}, error) {
+	path, err := parseJSONPointer(op.Path)
+	if err != nil {
+		return doc, err
+	}
+	switch op.Op {
+	case "add":
+		value, err := patchValue(op)
+		if err != nil {
+			return doc, err
+		}
+		return jsonPatchAdd(doc, path, value)
+	case "remove":
+		patched, _, err := jsonPatchRemove(doc, path)
+		return patched, err
+	case "replace":
+		value, err := patchValue(op)
+		if err != nil {
+			return doc, err
+		}
+		patched, _, err := jsonPatchRemove(doc, path)
+		if err != nil {
+			return doc, err
+		}
+		return jsonPatchAdd(patched, path, value)
+	case "move":
+		from, err := parseJSONPointer(op.From)
+		if err != nil {
+			return doc, err
+		}
+		if op.Path != op.From && strings.HasPrefix(op.Path, op.From+"/") {
+			return doc, errors.New("cannot move a value into one of its children")
+		}
+		patched, value, err := jsonPatchRemove(doc, from)
+		if err != nil {
+			return doc, err
+		}
+		return jsonPatchAdd(patched, path, value)
+	case "copy":
+		from, err := parseJSONPointer(op.From)
+		if err != nil {
+			return doc, err
+		}
+		value, err := jsonPatchGet(doc, from)
+		if err != nil {
+			return doc, err
+		}
+		value, err = deepCopyJSON(value)
+		if err != nil {
+			return doc, err
+		}
+		return jsonPatchAdd(doc, path, value)
+	case "test":
+		value, err := patchValue(op)
+		if err != nil {
+			return doc, err
+		}
+		current, err := jsonPatchGet(doc, path)
+		if err != nil {
+			return doc, err
+		}
+		if !reflect.DeepEqual(current, value) {
+			return doc, errors.New("test failed")
+		}
+		return doc, nil
+	default:
+		return doc, fmt.Errorf("unsupported op %q", op.Op)
+	}
+}
+
+func patchValue(op JSONPatchOp) (interface{}, error) {
+	if len(op.Value) == 0 {
+		return nil, errors.New("missing value")
+	}
+	return DecodeJSONDocument(op.Value)
+}
+
+// parseJSONPointer splits an RFC 6901 pointer into unescaped reference
+// tokens. The empty pointer refers to the whole document.
+func parseJSONPointer(pointer string) ([]string, error) {
+	if pointer == "" {
+		return nil, nil
+	}
+	if !strings.HasPrefix(pointer, "/") {
+		return nil, fmt.Errorf("invalid pointer %q", pointer)
+	}
+	tokens := strings.Split(pointer[1:], "/")
+	for i, token := range tokens {
+		tokens[i] = strings.ReplaceAll(strings.ReplaceAll(token, "~1", "/"), "~0", "~")
+	}
+	return tokens, nil
+}
+
+// arrayIndex parses an array reference token. max is the largest index
+// allowed, which is len(array) when inserting and len(array)-1 otherwise.
+func arrayIndex(token string, max int) (int, error) {
+	index, err := strconv.Atoi(token)
+	if err != nil || index < 0 || (len(token) > 1 && token[0] == '0') {
+		return 0, fmt.Errorf("invalid array index %q", token)
+	}
+	if index > max {
+		return 0, errJSONPatchPathNotFound
+	}
+	return index, nil
+}
+
+func jsonPatchGet(node interface{}, path []string) (interface{}, error) {
+	for _, token := range path {
+		switch n := node.(type) {
+		case map[string]interface{}:
+			child, ok := n[token]
+			if !ok {
+				return nil, errJSONPatchPathNotFound
+			}
+			node = child
+		case []interface{}:
+			index, err := arrayIndex(token, len(n)-1)
+			if err != nil {
+				return nil, err
+			}
+			node = n[index]
+		default:
+			return nil, errJSONPatchPathNotFound
+		}
+	}
+	return node, nil
+}
+
+// jsonPatchAdd adds value at path and returns the updated node. Slices are
+// returned rather than modified in place since inserting can reallocate them.
+func jsonPatchAdd(node interface{}, path []string, value interface{}) (interface{}, error) {
+	if len(path) == 0 {
+		return value, nil
+	}
+	token, rest := path[0], path[1:]
+	switch n := node.(type) {
+	case map[string]interface{}:
+		if len(rest) == 0 {
+			n[token] = value
+			return n, nil
+		}
+		child, ok := n[token]
+		if !ok {
+			return node, errJSONPatchPathNotFound
+		}
+		updated, err := jsonPatchAdd(child, rest, value)
+		if err != nil {
+			return node, err
+		}
+		n[token] = updated
+		return n, nil
+	case []interface{}:
+		if len(rest) == 0 {
+			index := len(n)
+			if token != "-" {
+				var err error
+				if index, err = arrayIndex(token, len(n)); err != nil {
+					return node, err
+				}
+			}
+			n = append(n, nil)
+			copy(n[index+1:], n[index:])
+			n[index] = value
+			return n, nil
+		}
+		index, err := arrayIndex(token, len(n)-1)
+		if err != nil {
+			return node, err
+		}
+		updated, err := jsonPatchAdd(n[index], rest, value)
+		if err != nil {
+			return node, err
+		}
+		n[index] = updated
+		return n, nil
+	default:
+		return node, errJSONPatchPathNotFound
+	}
+}
+
+// jsonPatchRemove removes the value at path, returning the updated node and
+// the value that was removed.
+func jsonPatchRemove(node interface{}, path []string) (interface{}, interface{}, error) {
+	if len(path) == 0 {
+		return nil, node, nil
+	}
+	token, rest := path[0], path[1:]
+	switch n := node.(type) {
+	case map[string]interface{}:
+		child, ok := n[token]
+		if !ok {
+			return node, nil, errJSONPatchPathNotFound
+		}
+		if len(rest) == 0 {
+			delete(n, token)
+			return n, child, nil
+		}
+		updated, removed, err := jsonPatchRemove(child, rest)
+		if err != nil {
+			return node, nil, err
+		}
+		n[token] = updated
+		return n, removed, nil
+	case []interface{}:
+		index, err := arrayIndex(token, len(n)-1)
+		if err != nil {
+			return node, nil, err
+		}
+		if len(rest) == 0 {
+			removed := n[index]
+			return append(n[:index], n[index+1:]...), removed, nil
+		}
+		updated, removed, err := jsonPatchRemove(n[index], rest)
+		if err != nil {
+			return node, nil, err
+		}
+		n[index] = updated
+		return n, removed, nil
+	default:
+		return node, nil, errJSONPatchPathNotFound
+	}
+}
+
+func deepCopyJSON(value interface{}) (interface{}, error) {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return nil, err
+	}
+	return DecodeJSONDocument(data)
+}
@@ -19,6 +19,7 @@ package webApi
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
 	"log"
 	"net/http"
@@ -31,19 +32,28 @@ import (
 	"sync"
 	"time"
 
+	basketballClients "goalfeed/clients/leagues/basketball"
 	cflClients "goalfeed/clients/leagues/cfl"
+	hockeytechClients "goalfeed/clients/leagues/hockeytech"
+	iihfClients "goalfeed/clients/leagues/iihf"
 	mlbClients "goalfeed/clients/leagues/mlb"
 	nflClients "goalfeed/clients/leagues/nfl"
 	nhlClients "goalfeed/clients/leagues/nhl"
+	soccerClients "goalfeed/clients/leagues/soccer"
 	"goalfeed/config"
 	"goalfeed/models"
 	"goalfeed/services/leagues"
+	basketballServices "goalfeed/services/leagues/basketball"
 	cflServices "goalfeed/services/leagues/cfl"
+	collegeHockeyServices "goalfeed/services/leagues/collegehockey"
+	hockeytechServices "goalfeed/services/leagues/hockeytech"
 	iihfServices "goalfeed/services/leagues/iihf"
 	mlbServices "goalfeed/services/leagues/mlb"
 	nflServices "goalfeed/services/leagues/nfl"
 	nhlServices "goalfeed/services/leagues/nhl"
+	soccerServices "goalfeed/services/leagues/soccer"
 	"goalfeed/targets/applog"
+	"goalfeed/targets/deliveries"
 	"goalfeed/targets/homeassistant"
 	"goalfeed/targets/memoryStore"
 	"goalfeed/targets/notify"
@@ -223,6 +233,10 @@ func (wsm *WebServerManager) RegisterAPIRoutes(r *gin.Engine) {
 		api.POST("/debug/nfl/add", addNFLGame)
 		api.GET("/events", getEvents)
 		api.GET("/logs", getLogs)
+		api.GET("/deliveries", getDeliveries)
+		api.POST("/deliveries/:id/retry", retryDelivery)
+		api.DELETE("/deliveries/:id", purgeDelivery)
+		api.DELETE("/deliveries", purgeDeadDeliveries)
 		api.GET("/teams", getAllTeams)
 		// Home Assistant integration endpoints
 		api.GET("/homeassistant/status", getHomeAssistantStatus)
@@ -483,15 +497,57 @@ func leagueNameForID(leagueId models.League) string {
 		return "IIHF"
 	case models.LeagueIdNFL:
 		return "NFL"
+	case models.LeagueIdNBA:
+		return "NBA"
+	case models.LeagueIdWNBA:
+		return "WNBA"
+	case models.LeagueIdEPL:
+		return "EPL"
+	case models.LeagueIdMLS:
+		return "MLS"
+	case models.LeagueIdUCL:
+		return "UCL"
+	case models.LeagueIdPWHL:
+		return "PWHL"
+	case models.LeagueIdAHL:
+		return "AHL"
+	case models.LeagueIdNCAAF:
+		return "NCAA Football"
+	case models.LeagueIdNCAAMHockey:
+		return "NCAA Men's Hockey"
+	case models.LeagueIdNCAAWHockey:
+		return "NCAA Women's Hockey"
 	case models.LeagueIdOlympicMensHockey:
 		return "Olympic Men's Hockey"
 	case models.LeagueIdOlympicWomensHockey:
 		return "Olympic Women's Hockey"
 	default:
+		if tournament, ok := config.TournamentForLeague(leagueId); ok {
+			return tournament.Name
+		}
 		return "Game"
 	}
 }
 
+// tournamentLeagues lists the configured IIHF tournaments by league id and
+// watch list key.
+func tournamentLeagues() []struct {
+	leagueId   models.League
+	leagueName string
+} {
+	var configs []struct {
+		leagueId   models.League
+		leagueName string
+	}
+	for _, tournament := range config.Tournaments() {
+		configs = append(configs, struct {
+			leagueId   models.League
+			leagueName string
+		}{tournament.LeagueID, tournament.Key})
+	}
+	return configs
+}
+
 func enrichGamesWithLeagueName(games []models.Game) {
 	for i := range games {
 		if games[i].LeagueName == "" {
@@ -593,9 +649,18 @@ func getGamesByDate(c *gin.Context) {
 		{models.LeagueIdCFL, "cfl"},
 		{models.LeagueIdIIHF, "iihf"},
 		{models.LeagueIdNFL, "nfl"},
-		{models.LeagueIdOlympicMensHockey, "olympic_men"},
-		{models.LeagueIdOlympicWomensHockey, "olympic_women"},
+		{models.LeagueIdNBA, "nba"},
+		{models.LeagueIdWNBA, "wnba"},
+		{models.LeagueIdEPL, "epl"},
+		{models.LeagueIdMLS, "mls"},
+		{models.LeagueIdUCL, "ucl"},
+		{models.LeagueIdPWHL, "pwhl"},
+		{models.LeagueIdAHL, "ahl"},
+		{models.LeagueIdNCAAF, "ncaaf"},
+		{models.LeagueIdNCAAMHockey, "ncaamh"},
+		{models.LeagueIdNCAAWHockey, "ncaawh"},
 	}
+	leagueConfigs = append(leagueConfigs, tournamentLeagues()...)
 
 	for _, leagueConfig := range leagueConfigs {
 		// Get monitored teams for this league
@@ -617,8 +682,32 @@ func getGamesByDate(c *gin.Context) {
 			leagueService = iihfServices.IIHFService{}
 		case models.LeagueIdNFL:
 			leagueService = nflServices.NFLService{Client: nflClients.NFLAPIClient{}}
+		case models.LeagueIdNBA:
+			leagueService = basketballServices.BasketballService{Client: basketballClients.BasketballApiClient{League: basketballClients.LeagueNBA}, League: models.LeagueIdNBA}
+		case models.LeagueIdWNBA:
+			leagueService = basketballServices.BasketballService{Client: basketballClients.BasketballApiClient{League: basketballClients.LeagueWNBA}, League: models.LeagueIdWNBA}
+		case models.LeagueIdEPL:
+			leagueService = soccerServices.SoccerService{Client: soccerClients.SoccerApiClient{League: soccerClients.LeagueEPL}, League: models.LeagueIdEPL}
+		case models.LeagueIdMLS:
+			leagueService = soccerServices.SoccerService{Client: soccerClients.SoccerApiClient{League: soccerClients.LeagueMLS}, League: models.LeagueIdMLS}
+		case models.LeagueIdUCL:
+			leagueService = soccerServices.SoccerService{Client: soccerClients.SoccerApiClient{League: soccerClients.LeagueUCL}, League: models.LeagueIdUCL}
+		case models.LeagueIdPWHL:
+			leagueService = hockeytechServices.HockeyTechService{Client: hockeytechClients.HockeyTechApiClient{ClientCode: hockeytechClients.ClientCodePWHL}, League: models.LeagueIdPWHL}
+		case models.LeagueIdAHL:
+			leagueService = hockeytechServices.HockeyTechService{Client: hockeytechClients.HockeyTechApiClient{ClientCode: hockeytechClients.ClientCodeAHL}, League: models.LeagueIdAHL}
+		case models.LeagueIdNCAAF:
+			leagueService = nflServices.NFLService{Client: nflClients.NFLAPIClient{League: nflClients.LeagueNCAAF}, League: models.LeagueIdNCAAF}
+		case models.LeagueIdNCAAMHockey:
+			leagueService = collegeHockeyServices.CollegeHockeyService{Client: nflClients.NFLAPIClient{League: nflClients.LeagueNCAAMHockey}, League: models.LeagueIdNCAAMHockey}
+		case models.LeagueIdNCAAWHockey:
+			leagueService = collegeHockeyServices.CollegeHockeyService{Client: nflClients.NFLAPIClient{League: nflClients.LeagueNCAAWHockey}, League: models.LeagueIdNCAAWHockey}
 		default:
-			continue
+			tournament, ok := config.TournamentForLeague(leagueConfig.leagueId)
+			if !ok {
+				continue
+			}
+			leagueService = iihfServices.IIHFService{Client: iihfClients.IIHFApiClient{}, Tournament: tournament}
 		}
 
 		// Get games for this date
@@ -635,7 +724,7 @@ func getGamesByDate(c *gin.Context) {
 					isMonitored = true
 					break
 				}
-				if game.CurrentState.Away.Team.TeamCode == teamCode || game.CurrentState.Home.Team.TeamCode == teamCode {
+				if game.CurrentState.Away.Team.MatchesWatchEntry(teamCode) || game.CurrentState.Home.Team.MatchesWatchEntry(teamCode) {
 					isMonitored = true
 					break
 				}
@@ -684,9 +773,18 @@ func getUpcomingGames(c *gin.Context) {
 		{models.LeagueIdCFL, "cfl"},
 		{models.LeagueIdIIHF, "iihf"},
 		{models.LeagueIdNFL, "nfl"},
-		{models.LeagueIdOlympicMensHockey, "olympic_men"},
-		{models.LeagueIdOlympicWomensHockey, "olympic_women"},
+		{models.LeagueIdNBA, "nba"},
+		{models.LeagueIdWNBA, "wnba"},
+		{models.LeagueIdEPL, "epl"},
+		{models.LeagueIdMLS, "mls"},
+		{models.LeagueIdUCL, "ucl"},
+		{models.LeagueIdPWHL, "pwhl"},
+		{models.LeagueIdAHL, "ahl"},
+		{models.LeagueIdNCAAF, "ncaaf"},
+		{models.LeagueIdNCAAMHockey, "ncaamh"},
+		{models.LeagueIdNCAAWHockey, "ncaawh"},
 	}
+	leagueConfigs = append(leagueConfigs, tournamentLeagues()...)
 
 	// Get timeframe filter (default to next 7 days)
 	now := time.Now()
@@ -712,8 +810,32 @@ func getUpcomingGames(c *gin.Context) {
 			leagueService = iihfServices.IIHFService{}
 		case models.LeagueIdNFL:
 			leagueService = nflServices.NFLService{Client: nflClients.NFLAPIClient{}}
+		case models.LeagueIdNBA:
+			leagueService = basketballServices.BasketballService{Client: basketballClients.BasketballApiClient{League: basketballClients.LeagueNBA}, League: models.LeagueIdNBA}
+		case models.LeagueIdWNBA:
+			leagueService = basketballServices.BasketballService{Client: basketballClients.BasketballApiClient{League: basketballClients.LeagueWNBA}, League: models.LeagueIdWNBA}
+		case models.LeagueIdEPL:
+			leagueService = soccerServices.SoccerService{Client: soccerClients.SoccerApiClient{League: soccerClients.LeagueEPL}, League: models.LeagueIdEPL}
+		case models.LeagueIdMLS:
+			leagueService = soccerServices.SoccerService{Client: soccerClients.SoccerApiClient{League: soccerClients.LeagueMLS}, League: models.LeagueIdMLS}
+		case models.LeagueIdUCL:
+			leagueService = soccerServices.SoccerService{Client: soccerClients.SoccerApiClient{League: soccerClients.LeagueUCL}, League: models.LeagueIdUCL}
+		case models.LeagueIdPWHL:
+			leagueService = hockeytechServices.HockeyTechService{Client: hockeytechClients.HockeyTechApiClient{ClientCode: hockeytechClients.ClientCodePWHL}, League: models.LeagueIdPWHL}
+		case models.LeagueIdAHL:
+			leagueService = hockeytechServices.HockeyTechService{Client: hockeytechClients.HockeyTechApiClient{ClientCode: hockeytechClients.ClientCodeAHL}, League: models.LeagueIdAHL}
+		case models.LeagueIdNCAAF:
+			leagueService = nflServices.NFLService{Client: nflClients.NFLAPIClient{League: nflClients.LeagueNCAAF}, League: models.LeagueIdNCAAF}
+		case models.LeagueIdNCAAMHockey:
+			leagueService = collegeHockeyServices.CollegeHockeyService{Client: nflClients.NFLAPIClient{League: nflClients.LeagueNCAAMHockey}, League: models.LeagueIdNCAAMHockey}
+		case models.LeagueIdNCAAWHockey:
+			leagueService = collegeHockeyServices.CollegeHockeyService{Client: nflClients.NFLAPIClient{League: nflClients.LeagueNCAAWHockey}, League: models.LeagueIdNCAAWHockey}
 		default:
-			continue
+			tournament, ok := config.TournamentForLeague(leagueConfig.leagueId)
+			if !ok {
+				continue
+			}
+			leagueService = iihfServices.IIHFService{Client: iihfClients.IIHFApiClient{}, Tournament: tournament}
 		}
 
 		// Get upcoming games for this league
@@ -731,7 +853,7 @@ func getUpcomingGames(c *gin.Context) {
 					isMonitored = true
 					break
 				}
-				if game.CurrentState.Away.Team.TeamCode == teamCode || game.CurrentState.Home.Team.TeamCode == teamCode {
+				if game.CurrentState.Away.Team.MatchesWatchEntry(teamCode) || game.CurrentState.Home.Team.MatchesWatchEntry(teamCode) {
 					isMonitored = true
 					break
 				}
@@ -776,10 +898,25 @@ func getLeagues(c *gin.Context) {
 	leagues := []map[string]interface{}{
 		{"leagueId": 1, "leagueName": "NHL", "teams": config.GetStringSlice("watch.nhl")},
 		{"leagueId": 2, "leagueName": "MLB", "teams": config.GetStringSlice("watch.mlb")},
+		{"leagueId": 3, "leagueName": "EPL", "teams": config.GetStringSlice("watch.epl")},
 		{"leagueId": 5, "leagueName": "CFL", "teams": config.GetStringSlice("watch.cfl")},
 		{"leagueId": 6, "leagueName": "NFL", "teams": config.GetStringSlice("watch.nfl")},
-		{"leagueId": 7, "leagueName": "Olympic Men's Hockey", "teams": config.GetStringSlice("watch.olympic_men")},
-		{"leagueId": 8, "leagueName": "Olympic Women's Hockey", "teams": config.GetStringSlice("watch.olympic_women")},
+		{"leagueId": 9, "leagueName": "NBA", "teams": config.GetStringSlice("watch.nba")},
+		{"leagueId": 10, "leagueName": "WNBA", "teams": config.GetStringSlice("watch.wnba")},
+		{"leagueId": 11, "leagueName": "MLS", "teams": config.GetStringSlice("watch.mls")},
+		{"leagueId": 12, "leagueName": "UCL", "teams": config.GetStringSlice("watch.ucl")},
+		{"leagueId": 13, "leagueName": "PWHL", "teams": config.GetStringSlice("watch.pwhl")},
+		{"leagueId": 14, "leagueName": "AHL", "teams": config.GetStringSlice("watch.ahl")},
+		{"leagueId": 15, "leagueName": "NCAAF", "teams": config.GetStringSlice("watch.ncaaf")},
+		{"leagueId": 16, "leagueName": "NCAAMH", "teams": config.GetStringSlice("watch.ncaamh")},
+		{"leagueId": 17, "leagueName": "NCAAWH", "teams": config.GetStringSlice("watch.ncaawh")},
+	}
+	for _, tournament := range config.Tournaments() {
+		leagues = append(leagues, map[string]interface{}{
+			"leagueId":   tournament.LeagueID,
+			"leagueName": tournament.Name,
+			"teams":      config.GetStringSlice("watch." + tournament.Key),
+		})
 	}
 	c.JSON(http.StatusOK, ApiResponse{
 		Success: true,
@@ -799,11 +936,11 @@ func getLeagues(c *gin.Context) {
 // @Failure      500   {object}  ApiResponse
 // @Router       /leagues [post]
 func updateLeagueConfig(c *gin.Context) {
-	var config struct {
-		LeagueId int      `json:"leagueId" example:"1"` // 1=NHL, 2=MLB, 5=CFL, 6=NFL, 7=Olympic Men's Hockey, 8=Olympic Women's Hockey
+	var body struct {
+		LeagueId int      `json:"leagueId" example:"1"` // 1=NHL, 2=MLB, 3=EPL, 5=CFL, 6=NFL, 9=NBA, 10=WNBA, 11=MLS, 12=UCL, 13=PWHL, 14=AHL, 15=NCAAF, 16=NCAAMH, 17=NCAAWH, or an IIHF tournament's league id
 		Teams    []string `json:"teams" example:"TOR,MTL"`
 	}
-	if err := c.ShouldBindJSON(&config); err != nil {
+	if err := c.ShouldBindJSON(&body); err != nil {
 		c.JSON(http.StatusBadRequest, ApiResponse{
 			Success: false,
 			Message: "Invalid JSON",
@@ -813,20 +950,40 @@ func updateLeagueConfig(c *gin.Context) {
 
 	// Update the configuration based on league ID
 	var leagueKey string
-	switch config.LeagueId {
+	switch body.LeagueId {
 	case 1:
 		leagueKey = "watch.nhl"
 	case 2:
 		leagueKey = "watch.mlb"
+	case 3:
+		leagueKey = "watch.epl"
 	case 5:
 		leagueKey = "watch.cfl"
 	case 6:
 		leagueKey = "watch.nfl"
-	case 7:
-		leagueKey = "watch.olympic_men"
-	case 8:
-		leagueKey = "watch.olympic_women"
+	case 9:
+		leagueKey = "watch.nba"
+	case 10:
+		leagueKey = "watch.wnba"
+	case 11:
+		leagueKey = "watch.mls"
+	case 12:
+		leagueKey = "watch.ucl"
+	case 13:
+		leagueKey = "watch.pwhl"
+	case 14:
+		leagueKey = "watch.ahl"
+	case 15:
+		leagueKey = "watch.ncaaf"
+	case 16:
+		leagueKey = "watch.ncaamh"
+	case 17:
+		leagueKey = "watch.ncaawh"
 	default:
+		if tournament, ok := config.TournamentForLeague(models.League(body.LeagueId)); ok {
+			leagueKey = "watch." + tournament.Key
+			break
+		}
 		c.JSON(http.StatusBadRequest, ApiResponse{
 			Success: false,
 			Message: "Invalid league ID",
@@ -835,7 +992,7 @@ func updateLeagueConfig(c *gin.Context) {
 	}
 
 	// Update the viper configuration
-	viper.Set(leagueKey, config.Teams)
+	viper.Set(leagueKey, body.Teams)
 
 	// Write the configuration to file
 	if err := viper.WriteConfig(); err != nil {
@@ -849,8 +1006,10 @@ func updateLeagueConfig(c *gin.Context) {
 
 	// Trigger an immediate refresh in the background so active games are populated
 	go refreshActiveGamesInternal()
-	// Also publish baseline sensors for updated monitored teams
+	// Also publish baseline sensors for updated monitored teams, and clean
+	// up after teams taken off the list
 	go homeassistant.PublishBaselineForMonitoredTeams()
+	go homeassistant.PruneAfterWatchChange()
 
 	c.JSON(http.StatusOK, ApiResponse{
 		Success: true,
@@ -864,7 +1023,7 @@ func updateLeagueConfig(c *gin.Context) {
 // @Tags         events
 // @Accept       json
 // @Produce      json
-// @Param        leagueId  query     int     false  "Filter by league ID (1=NHL, 2=MLB, 5=CFL, 6=NFL)"
+// @Param        leagueId  query     int     false  "Filter by league ID (1=NHL, 2=MLB, 3=EPL, 5=CFL, 6=NFL, 9=NBA, 10=WNBA, 11=MLS, 12=UCL, 13=PWHL, 14=AHL, 15=NCAAF, 16=NCAAMH, 17=NCAAWH)"
 // @Param        team      query     string  false  "Filter by team code"
 // @Param        since     query     string  false  "Filter events since timestamp (RFC3339)"
 // @Param        limit     query     int     false  "Maximum number of events to return (default: 50)"
@@ -992,6 +1151,83 @@ func getLogs(c *gin.Context) {
 	})
 }
 
+// getDeliveries godoc
+// @Summary      Get queued deliveries
+// @Description  Returns deliveries that failed and are waiting to be retried (status pending) and the dead-letter list (status dead), oldest first
+// @Tags         deliveries
+// @Accept       json
+// @Produce      json
+// @Param        status  query     string  false  "Filter by status (pending or dead)"
+// @Success      200     {object}  ApiResponse{data=[]deliveries.Delivery}
+// @Router       /deliveries [get]
+func getDeliveries(c *gin.Context) {
+	status := deliveries.Status(c.Query("status"))
+	list := []deliveries.Delivery{}
+	for _, d := range deliveries.List() {
+		if status == "" || d.Status == status {
+			list = append(list, d)
+		}
+	}
+	c.JSON(http.StatusOK, ApiResponse{Success: true, Data: list})
+}
+
+// retryDelivery godoc
+// @Summary      Retry a delivery
+// @Description  Sends a pending or dead delivery right away. It leaves the queue if the send works and stays where it was otherwise.
+// @Tags         deliveries
+// @Accept       json
+// @Produce      json
+// @Param        id   path      string  true  "Delivery ID"
+// @Success      200  {object}  ApiResponse
+// @Failure      404  {object}  ApiResponse
+// @Failure      409  {object}  ApiResponse
+// @Failure      502  {object}  ApiResponse
+// @Router       /deliveries/{id}/retry [post]
+func retryDelivery(c *gin.Context) {
+	err := deliveries.Retry(c.Param("id"))
+	switch {
+	case errors.Is(err, deliveries.ErrNotFound):
+		c.JSON(http.StatusNotFound, ApiResponse{Success: false, Message: err.Error()})
+	case errors.Is(err, deliveries.ErrInFlight):
+		c.JSON(http.StatusConflict, ApiResponse{Success: false, Message: err.Error()})
+	case err != nil:
+		c.JSON(http.StatusBadGateway, ApiResponse{Success: false, Message: fmt.Sprintf("Retry failed: %v", err)})
+	default:
+		c.JSON(http.StatusOK, ApiResponse{Success: true, Message: "Delivered"})
+	}
+}
+
+// purgeDelivery godoc
+// @Summary      Purge a delivery
+// @Description  Removes a pending or dead delivery without sending it
+// @Tags         deliveries
+// @Accept       json
+// @Produce      json
+// @Param        id   path      string  true  "Delivery ID"
+// @Success      200  {object}  ApiResponse
+// @Failure      404  {object}  ApiResponse
+// @Router       /deliveries/{id} [delete]
+func purgeDelivery(c *gin.Context) {
+	if err := deliveries.Purge(c.Param("id")); err != nil {
+		c.JSON(http.StatusNotFound, ApiResponse{Success: false, Message: err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, ApiResponse{Success: true, Message: "Delivery purged"})
+}
+
+// purgeDeadDeliveries godoc
+// @Summary      Purge the dead-letter list
+// @Description  Removes every dead delivery. Pending deliveries are kept.
+// @Tags         deliveries
+// @Accept       json
+// @Produce      json
+// @Success      200  {object}  ApiResponse{data=object}
+// @Router       /deliveries [delete]
+func purgeDeadDeliveries(c *gin.Context) {
+	purged := deliveries.PurgeDead()
+	c.JSON(http.StatusOK, ApiResponse{Success: true, Message: fmt.Sprintf("Purged %d dead deliveries", purged), Data: map[string]interface{}{"purged": purged}})
+}
+
 // clearGames godoc
 // @Summary      Clear all games
 // @Description  Clears all games from the memory store. Useful for testing and resetting state.
@@ -1015,7 +1251,7 @@ func clearGames(c *gin.Context) {
 // @Tags         teams
 // @Accept       json
 // @Produce      json
-// @Param        leagueId  query     int     true  "League ID (1=NHL, 2=MLB, 5=CFL, 6=NFL)"
+// @Param        leagueId  query     int     true  "League ID (1=NHL, 2=MLB, 3=EPL, 5=CFL, 6=NFL, 9=NBA, 10=WNBA, 11=MLS, 12=UCL, 13=PWHL, 14=AHL, 15=NCAAF, 16=NCAAMH, 17=NCAAWH, or an IIHF tournament's league id)"
 // @Success      200       {object}  ApiResponse{data=[]object}
 // @Failure      400       {object}  ApiResponse
 // @Failure      500       {object}  ApiResponse
@@ -1172,6 +1408,222 @@ func getAllTeams(c *gin.Context) {
 				"logo":     team["logo"],
 			})
 		}
+	case models.LeagueIdNBA:
+		// NBA teams with logos
+		nbaTeams := []map[string]string{
+			{"code": "ATL", "name": "Atlanta Hawks", "location": "Atlanta", "logo": "https://a.espncdn.com/i/teamlogos/nba/500/atl.png"},
+			{"code": "BOS", "name": "Boston Celtics", "location": "Boston", "logo": "https://a.espncdn.com/i/teamlogos/nba/500/bos.png"},
+			{"code": "BKN", "name": "Brooklyn Nets", "location": "Brooklyn", "logo": "https://a.espncdn.com/i/teamlogos/nba/500/bkn.png"},
+			{"code": "CHA", "name": "Charlotte Hornets", "location": "Charlotte", "logo": "https://a.espncdn.com/i/teamlogos/nba/500/cha.png"},
+			{"code": "CHI", "name": "Chicago Bulls", "location": "Chicago", "logo": "https://a.espncdn.com/i/teamlogos/nba/500/chi.png"},
+			{"code": "CLE", "name": "Cleveland Cavaliers", "location": "Cleveland", "logo": "https://a.espncdn.com/i/teamlogos/nba/500/cle.png"},
+			{"code": "DAL", "name": "Dallas Mavericks", "location": "Dallas", "logo": "https://a.espncdn.com/i/teamlogos/nba/500/dal.png"},
+			{"code": "DEN", "name": "Denver Nuggets", "location": "Denver", "logo": "https://a.espncdn.com/i/teamlogos/nba/500/den.png"},
+			{"code": "DET", "name": "Detroit Pistons", "location": "Detroit", "logo": "https://a.espncdn.com/i/teamlogos/nba/500/det.png"},
+			{"code": "GS", "name": "Golden State Warriors", "location": "Golden State", "logo": "https://a.espncdn.com/i/teamlogos/nba/500/gs.png"},
+			{"code": "HOU", "name": "Houston Rockets", "location": "Houston", "logo": "https://a.espncdn.com/i/teamlogos/nba/500/hou.png"},
+			{"code": "IND", "name": "Indiana Pacers", "location": "Indiana", "logo": "https://a.espncdn.com/i/teamlogos/nba/500/ind.png"},
+			{"code": "LAC", "name": "LA Clippers", "location": "Los Angeles", "logo": "https://a.espncdn.com/i/teamlogos/nba/500/lac.png"},
+			{"code": "LAL", "name": "Los Angeles Lakers", "location": "Los Angeles", "logo": "https://a.espncdn.com/i/teamlogos/nba/500/lal.png"},
+			{"code": "MEM", "name": "Memphis Grizzlies", "location": "Memphis", "logo": "https://a.espncdn.com/i/teamlogos/nba/500/mem.png"},
+			{"code": "MIA", "name": "Miami Heat", "location": "Miami", "logo": "https://a.espncdn.com/i/teamlogos/nba/500/mia.png"},
+			{"code": "MIL", "name": "Milwaukee Bucks", "location": "Milwaukee", "logo": "https://a.espncdn.com/i/teamlogos/nba/500/mil.png"},
+			{"code": "MIN", "name": "Minnesota Timberwolves", "location": "Minnesota", "logo": "https://a.espncdn.com/i/teamlogos/nba/500/min.png"},
+			{"code": "NO", "name": "New Orleans Pelicans", "location": "New Orleans", "logo": "https://a.espncdn.com/i/teamlogos/nba/500/no.png"},
+			{"code": "NY", "name": "New York Knicks", "location": "New York", "logo": "https://a.espncdn.com/i/teamlogos/nba/500/ny.png"},
+			{"code": "OKC", "name": "Oklahoma City Thunder", "location": "Oklahoma City", "logo": "https://a.espncdn.com/i/teamlogos/nba/500/okc.png"},
+			{"code": "ORL", "name": "Orlando Magic", "location": "Orlando", "logo": "https://a.espncdn.com/i/teamlogos/nba/500/orl.png"},
+			{"code": "PHI", "name": "Philadelphia 76ers", "location": "Philadelphia", "logo": "https://a.espncdn.com/i/teamlogos/nba/500/phi.png"},
+			{"code": "PHX", "name": "Phoenix Suns", "location": "Phoenix", "logo": "https://a.espncdn.com/i/teamlogos/nba/500/phx.png"},
+			{"code": "POR", "name": "Portland Trail Blazers", "location": "Portland", "logo": "https://a.espncdn.com/i/teamlogos/nba/500/por.png"},
+			{"code": "SAC", "name": "Sacramento Kings", "location": "Sacramento", "logo": "https://a.espncdn.com/i/teamlogos/nba/500/sac.png"},
+			{"code": "SA", "name": "San Antonio Spurs", "location": "San Antonio", "logo": "https://a.espncdn.com/i/teamlogos/nba/500/sa.png"},
+			{"code": "TOR", "name": "Toronto Raptors", "location": "Toronto", "logo": "https://a.espncdn.com/i/teamlogos/nba/500/tor.png"},
+			{"code": "UTAH", "name": "Utah Jazz", "location": "Utah", "logo": "https://a.espncdn.com/i/teamlogos/nba/500/utah.png"},
+			{"code": "WSH", "name": "Washington Wizards", "location": "Washington", "logo": "https://a.espncdn.com/i/teamlogos/nba/500/wsh.png"},
+		}
+		for _, team := range nbaTeams {
+			teams = append(teams, map[string]interface{}{
+				"code":     team["code"],
+				"name":     team["name"],
+				"location": team["location"],
+				"logo":     team["logo"],
+			})
+		}
+	case models.LeagueIdWNBA:
+		// WNBA teams with logos
+		wnbaTeams := []map[string]string{
+			{"code": "ATL", "name": "Atlanta Dream", "location": "Atlanta", "logo": "https://a.espncdn.com/i/teamlogos/wnba/500/atl.png"},
+			{"code": "CHI", "name": "Chicago Sky", "location": "Chicago", "logo": "https://a.espncdn.com/i/teamlogos/wnba/500/chi.png"},
+			{"code": "CONN", "name": "Connecticut Sun", "location": "Connecticut", "logo": "https://a.espncdn.com/i/teamlogos/wnba/500/conn.png"},
+			{"code": "DAL", "name": "Dallas Wings", "location": "Dallas", "logo": "https://a.espncdn.com/i/teamlogos/wnba/500/dal.png"},
+			{"code": "GS", "name": "Golden State Valkyries", "location": "Golden State", "logo": "https://a.espncdn.com/i/teamlogos/wnba/500/gs.png"},
+			{"code": "IND", "name": "Indiana Fever", "location": "Indiana", "logo": "https://a.espncdn.com/i/teamlogos/wnba/500/ind.png"},
+			{"code": "LV", "name": "Las Vegas Aces", "location": "Las Vegas", "logo": "https://a.espncdn.com/i/teamlogos/wnba/500/lv.png"},
+			{"code": "LA", "name": "Los Angeles Sparks", "location": "Los Angeles", "logo": "https://a.espncdn.com/i/teamlogos/wnba/500/la.png"},
+			{"code": "MIN", "name": "Minnesota Lynx", "location": "Minnesota", "logo": "https://a.espncdn.com/i/teamlogos/wnba/500/min.png"},
+			{"code": "NY", "name": "New York Liberty", "location": "New York", "logo": "https://a.espncdn.com/i/teamlogos/wnba/500/ny.png"},
+			{"code": "PHX", "name": "Phoenix Mercury", "location": "Phoenix", "logo": "https://a.espncdn.com/i/teamlogos/wnba/500/phx.png"},
+			{"code": "POR", "name": "Portland Fire", "location": "Portland", "logo": "https://a.espncdn.com/i/teamlogos/wnba/500/por.png"},
+			{"code": "SEA", "name": "Seattle Storm", "location": "Seattle", "logo": "https://a.espncdn.com/i/teamlogos/wnba/500/sea.png"},
+			{"code": "TOR", "name": "Toronto Tempo", "location": "Toronto", "logo": "https://a.espncdn.com/i/teamlogos/wnba/500/tor.png"},
+			{"code": "WSH", "name": "Washington Mystics", "location": "Washington", "logo": "https://a.espncdn.com/i/teamlogos/wnba/500/wsh.png"},
+		}
+		for _, team := range wnbaTeams {
+			teams = append(teams, map[string]interface{}{
+				"code":     team["code"],
+				"name":     team["name"],
+				"location": team["location"],
+				"logo":     team["logo"],
+			})
+		}
+	case models.LeagueIdEPL, models.LeagueIdMLS:
+		// Soccer teams by ESPN abbreviation - logos will be handled by frontend fallback
+		soccerTeams := []map[string]string{
+			{"code": "ARS", "name": "Arsenal", "location": "London"},
+			{"code": "AVL", "name": "Aston Villa", "location": "Birmingham"},
+			{"code": "BOU", "name": "AFC Bournemouth", "location": "Bournemouth"},
+			{"code": "BRE", "name": "Brentford", "location": "London"},
+			{"code": "BHA", "name": "Brighton & Hove Albion", "location": "Brighton"},
+			{"code": "BUR", "name": "Burnley", "location": "Burnley"},
+			{"code": "CHE", "name": "Chelsea", "location": "London"},
+			{"code": "CRY", "name": "Crystal Palace", "location": "London"},
+			{"code": "EVE", "name": "Everton", "location": "Liverpool"},
+			{"code": "FUL", "name": "Fulham", "location": "London"},
+			{"code": "LEE", "name": "Leeds United", "location": "Leeds"},
+			{"code": "LIV", "name": "Liverpool", "location": "Liverpool"},
+			{"code": "MNC", "name": "Manchester City", "location": "Manchester"},
+			{"code": "MAN", "name": "Manchester United", "location": "Manchester"},
+			{"code": "NEW", "name": "Newcastle United", "location": "Newcastle"},
+			{"code": "NFO", "name": "Nottingham Forest", "location": "Nottingham"},
+			{"code": "SUN", "name": "Sunderland", "location": "Sunderland"},
+			{"code": "TOT", "name": "Tottenham Hotspur", "location": "London"},
+			{"code": "WHU", "name": "West Ham United", "location": "London"},
+			{"code": "WOL", "name": "Wolverhampton Wanderers", "location": "Wolverhampton"},
+		}
+		if leagueId == models.LeagueIdMLS {
+			soccerTeams = []map[string]string{
+				{"code": "ATL", "name": "Atlanta United FC", "location": "Atlanta"},
+				{"code": "ATX", "name": "Austin FC", "location": "Austin"},
+				{"code": "CLT", "name": "Charlotte FC", "location": "Charlotte"},
+				{"code": "CHI", "name": "Chicago Fire FC", "location": "Chicago"},
+				{"code": "CIN", "name": "FC Cincinnati", "location": "Cincinnati"},
+				{"code": "COL", "name": "Colorado Rapids", "location": "Colorado"},
+				{"code": "CLB", "name": "Columbus Crew", "location": "Columbus"},
+				{"code": "DAL", "name": "FC Dallas", "location": "Dallas"},
+				{"code": "DC", "name": "D.C. United", "location": "Washington"},
+				{"code": "HOU", "name": "Houston Dynamo FC", "location": "Houston"},
+				{"code": "MIA", "name": "Inter Miami CF", "location": "Miami"},
+				{"code": "LA", "name": "LA Galaxy", "location": "Los Angeles"},
+				{"code": "LAFC", "name": "LAFC", "location": "Los Angeles"},
+				{"code": "MIN", "name": "Minnesota United FC", "location": "Minnesota"},
+				{"code": "MTL", "name": "CF Montréal", "location": "Montreal"},
+				{"code": "NSH", "name": "Nashville SC", "location": "Nashville"},
+				{"code": "NE", "name": "New England Revolution", "location": "New England"},
+				{"code": "NY", "name": "New York Red Bulls", "location": "New York"},
+				{"code": "NYC", "name": "New York City FC", "location": "New York"},
+				{"code": "ORL", "name": "Orlando City SC", "location": "Orlando"},
+				{"code": "PHI", "name": "Philadelphia Union", "location": "Philadelphia"},
+				{"code": "POR", "name": "Portland Timbers", "location": "Portland"},
+				{"code": "RSL", "name": "Real Salt Lake", "location": "Salt Lake"},
+				{"code": "SD", "name": "San Diego FC", "location": "San Diego"},
+				{"code": "SJ", "name": "San Jose Earthquakes", "location": "San Jose"},
+				{"code": "SEA", "name": "Seattle Sounders FC", "location": "Seattle"},
+				{"code": "SKC", "name": "Sporting Kansas City", "location": "Kansas City"},
+				{"code": "STL", "name": "St. Louis CITY SC", "location": "St. Louis"},
+				{"code": "TOR", "name": "Toronto FC", "location": "Toronto"},
+				{"code": "VAN", "name": "Vancouver Whitecaps", "location": "Vancouver"},
+			}
+		}
+		for _, team := range soccerTeams {
+			teams = append(teams, map[string]interface{}{
+				"code":     team["code"],
+				"name":     team["name"],
+				"location": team["location"],
+				"logo":     "", // Empty logo - frontend will show team code as fallback
+			})
+		}
+	case models.LeagueIdUCL, models.LeagueIdNCAAF, models.LeagueIdNCAAMHockey, models.LeagueIdNCAAWHockey:
+		// The Champions League field changes every season and college
+		// leagues have hundreds of teams, so only the monitored teams (and
+		// conferences) are listed
+		watchKey := map[models.League]string{
+			models.LeagueIdUCL:         "watch.ucl",
+			models.LeagueIdNCAAF:       "watch.ncaaf",
+			models.LeagueIdNCAAMHockey: "watch.ncaamh",
+			models.LeagueIdNCAAWHockey: "watch.ncaawh",
+		}[models.League(leagueId)]
+		for _, code := range config.GetStringSlice(watchKey) {
+			code = strings.TrimSpace(code)
+			if code == "*" || code == "" {
+				continue
+			}
+			if _, conference := models.WatchConference(code); !conference {
+				code = strings.ToUpper(code)
+			}
+			teams = append(teams, map[string]interface{}{
+				"code":     code,
+				"name":     code,
+				"location": "",
+				"logo":     "",
+			})
+		}
+	case models.LeagueIdPWHL, models.LeagueIdAHL:
+		// HockeyTech teams by HockeyTech code - logos will be handled by frontend fallback
+		hockeytechTeams := []map[string]string{
+			{"code": "BOS", "name": "Boston Fleet", "location": "Boston"},
+			{"code": "MIN", "name": "Minnesota Frost", "location": "Minnesota"},
+			{"code": "MTL", "name": "Montréal Victoire", "location": "Montreal"},
+			{"code": "NY", "name": "New York Sirens", "location": "New York"},
+			{"code": "OTT", "name": "Ottawa Charge", "location": "Ottawa"},
+			{"code": "SEA", "name": "Seattle Torrent", "location": "Seattle"},
+			{"code": "TOR", "name": "Toronto Sceptres", "location": "Toronto"},
+			{"code": "VAN", "name": "Vancouver Goldeneyes", "location": "Vancouver"},
+		}
+		if leagueId == models.LeagueIdAHL {
+			hockeytechTeams = []map[string]string{
+				{"code": "ABB", "name": "Abbotsford Canucks", "location": "Abbotsford"},
+				{"code": "BAK", "name": "Bakersfield Condors", "location": "Bakersfield"},
+				{"code": "BEL", "name": "Belleville Senators", "location": "Belleville"},
+				{"code": "BRI", "name": "Bridgeport Islanders", "location": "Bridgeport"},
+				{"code": "CGY", "name": "Calgary Wranglers", "location": "Calgary"},
+				{"code": "CLT", "name": "Charlotte Checkers", "location": "Charlotte"},
+				{"code": "CHI", "name": "Chicago Wolves", "location": "Chicago"},
+				{"code": "CLE", "name": "Cleveland Monsters", "location": "Cleveland"},
+				{"code": "CV", "name": "Coachella Valley Firebirds", "location": "Coachella Valley"},
+				{"code": "COL", "name": "Colorado Eagles", "location": "Colorado"},
+				{"code": "GR", "name": "Grand Rapids Griffins", "location": "Grand Rapids"},
+				{"code": "HFD", "name": "Hartford Wolf Pack", "location": "Hartford"},
+				{"code": "HSK", "name": "Henderson Silver Knights", "location": "Henderson"},
+				{"code": "HER", "name": "Hershey Bears", "location": "Hershey"},
+				{"code": "IA", "name": "Iowa Wild", "location": "Iowa"},
+				{"code": "LAV", "name": "Laval Rocket", "location": "Laval"},
+				{"code": "LV", "name": "Lehigh Valley Phantoms", "location": "Lehigh Valley"},
+				{"code": "MB", "name": "Manitoba Moose", "location": "Manitoba"},
+				{"code": "MIL", "name": "Milwaukee Admirals", "location": "Milwaukee"},
+				{"code": "ONT", "name": "Ontario Reign", "location": "Ontario"},
+				{"code": "PRO", "name": "Providence Bruins", "location": "Providence"},
+				{"code": "ROC", "name": "Rochester Americans", "location": "Rochester"},
+				{"code": "RFD", "name": "Rockford IceHogs", "location": "Rockford"},
+				{"code": "SD", "name": "San Diego Gulls", "location": "San Diego"},
+				{"code": "SJ", "name": "San Jose Barracuda", "location": "San Jose"},
+				{"code": "SPR", "name": "Springfield Thunderbirds", "location": "Springfield"},
+				{"code": "SYR", "name": "Syracuse Crunch", "location": "Syracuse"},
+				{"code": "TEX", "name": "Texas Stars", "location": "Texas"},
+				{"code": "TOR", "name": "Toronto Marlies", "location": "Toronto"},
+				{"code": "TUC", "name": "Tucson Roadrunners", "location": "Tucson"},
+				{"code": "UTC", "name": "Utica Comets", "location": "Utica"},
+				{"code": "WBS", "name": "Wilkes-Barre/Scranton Penguins", "location": "Wilkes-Barre/Scranton"},
+			}
+		}
+		for _, team := range hockeytechTeams {
+			teams = append(teams, map[string]interface{}{
+				"code":     team["code"],
+				"name":     team["name"],
+				"location": team["location"],
+				"logo":     "", // Empty logo - frontend will show team code as fallback
+			})
+		}
 	case models.LeagueIdCFL:
 		// CFL teams - logos will be handled by frontend fallback
 		cflTeams := []map[string]string{
@@ -1209,9 +1661,12 @@ func getAllTeams(c *gin.Context) {
 				})
 			}
 		}
-	case models.LeagueIdOlympicMensHockey, models.LeagueIdOlympicWomensHockey:
-		// Olympic ice hockey: country codes (same list for men's and women's)
-		olympicTeams := []map[string]string{
+	default:
+		// IIHF tournaments: country codes (same list for every tournament)
+		if _, ok := config.TournamentForLeague(models.League(leagueId)); !ok {
+			break
+		}
+		iihfTeams := []map[string]string{
 			{"code": "CAN", "name": "Canada", "location": ""},
 			{"code": "USA", "name": "United States", "location": ""},
 			{"code": "FIN", "name": "Finland", "location": ""},
@@ -1231,7 +1686,7 @@ func getAllTeams(c *gin.Context) {
 			{"code": "FRA", "name": "France", "location": ""},
 			{"code": "GBR", "name": "Great Britain", "location": ""},
 		}
-		for _, team := range olympicTeams {
+		for _, team := range iihfTeams {
 			teams = append(teams, map[string]interface{}{
 				"code":     team["code"],
 				"name":     team["name"],
@@ -1251,7 +1706,7 @@ func getAllTeams(c *gin.Context) {
 
 // getHomeAssistantStatus godoc
 // @Summary      Get Home Assistant connection status
-// @Description  Checks the connection status to Home Assistant and returns configuration details
+// @Description  Checks the connection status to Home Assistant and returns configuration details. The top-level fields describe the primary Home Assistant; instances lists each home_assistant.instances entry with its own status.
 // @Tags         homeassistant
 // @Accept       json
 // @Produce      json
@@ -1269,6 +1724,7 @@ func getHomeAssistantStatus(c *gin.Context) {
 			"message":   message,
 			"url":       url,
 			"tokenSet":  token != "",
+			"instances": homeassistant.CheckInstances(3 * time.Second),
 		},
 	})
 }
@@ -2,6 +2,7 @@ package webApi
 
 import (
 	"encoding/json"
+	"errors"
 	"net/http"
 	"net/http/httptest"
 	"os"
@@ -16,6 +17,8 @@ import (
 
 	"goalfeed/models"
 	"goalfeed/targets/applog"
+	"goalfeed/targets/deliveries"
+	"goalfeed/targets/homeassistant"
 	"goalfeed/targets/memoryStore"
 )
 
@@ -56,6 +59,10 @@ func setupRouter() *gin.Engine {
 	api.POST("/refresh", refreshActiveGames)
 	api.GET("/events", getEvents)
 	api.GET("/logs", getLogs)
+	api.GET("/deliveries", getDeliveries)
+	api.POST("/deliveries/:id/retry", retryDelivery)
+	api.DELETE("/deliveries/:id", purgeDelivery)
+	api.DELETE("/deliveries", purgeDeadDeliveries)
 	api.GET("/homeassistant/status", getHomeAssistantStatus)
 	api.GET("/homeassistant/config", getHomeAssistantConfig)
 	api.POST("/homeassistant/config", setHomeAssistantConfig)
@@ -364,6 +371,51 @@ func TestHomeAssistantStatus_EnvSource(t *testing.T) {
 	}
 }
 
+func TestHomeAssistantStatus_Instances(t *testing.T) {
+	ha := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer cabin-token" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer ha.Close()
+	viper.Set("home_assistant.instances", []map[string]interface{}{
+		{"name": "cabin", "url": ha.URL, "access_token": "cabin-token"},
+		{"name": "garage", "url": ha.URL, "access_token": "wrong"},
+		{"name": "remote", "url": "https://example.com", "access_token": "tok"},
+	})
+	homeassistant.ReloadInstances()
+	defer func() {
+		viper.Set("home_assistant.instances", nil)
+		homeassistant.ReloadInstances()
+	}()
+
+	r := setupRouter()
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", "/api/homeassistant/status", nil)
+	r.ServeHTTP(w, req)
+	var resp struct {
+		Data struct {
+			Instances []map[string]any
+		}
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("unmarshal: %v", err)
+	}
+	// A public URL is refused without allow_remote_url
+	if len(resp.Data.Instances) != 2 {
+		t.Fatalf("expected 2 instances, got %+v", resp.Data.Instances)
+	}
+	cabin, garage := resp.Data.Instances[0], resp.Data.Instances[1]
+	if cabin["name"] != "cabin" || cabin["connected"] != true {
+		t.Fatalf("expected cabin connected, got %+v", cabin)
+	}
+	if garage["connected"] != false || !strings.Contains(garage["message"].(string), "401") {
+		t.Fatalf("expected garage refused with 401, got %+v", garage)
+	}
+}
+
 // TestHomeAssistantConfig_GET_SET originally asserted that POSTing a new HA
 // URL/token always persisted to config.yaml. That was the vulnerable default:
 // this unauthenticated endpoint could rewrite the on-disk config for any
@@ -1131,3 +1183,61 @@ func TestBuildFrontend_ReportsMissingSourceAndBuild(t *testing.T) {
 		t.Fatalf("error should name both what it looked for; got: %v", err)
 	}
 }
+
+func TestDeliveriesEndpoints(t *testing.T) {
+	applog.SetLogFilePathForTest(filepath.Join(t.TempDir(), "app.log.jsonl"))
+	deliveries.SetPathForTest(filepath.Join(t.TempDir(), "deliveries.json"))
+	var sendErr error
+	deliveries.RegisterSender("api_test", func(deliveries.Delivery) error { return sendErr })
+	deliveries.Enqueue(deliveries.Delivery{Kind: "api_test", Target: "ha:event:goal", Name: "goal"}, deliveries.Permanent(errors.New("401 Unauthorized")))
+	deliveries.Enqueue(deliveries.Delivery{Kind: "api_test", Target: "ha:event:goal", Name: "goal"}, errors.New("connection refused"))
+	r := setupRouter()
+
+	list := func(query string) []deliveries.Delivery {
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("GET", "/api/deliveries"+query, nil)
+		r.ServeHTTP(w, req)
+		var resp struct {
+			Data []deliveries.Delivery `json:"data"`
+		}
+		if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+			t.Fatalf("unmarshal deliveries: %v", err)
+		}
+		return resp.Data
+	}
+	if got := list(""); len(got) != 2 {
+		t.Fatalf("expected 2 deliveries, got %d", len(got))
+	}
+	dead := list("?status=dead")
+	if len(dead) != 1 {
+		t.Fatalf("expected 1 dead delivery, got %d", len(dead))
+	}
+
+	do := func(method, path string) int {
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest(method, path, nil)
+		r.ServeHTTP(w, req)
+		return w.Code
+	}
+	sendErr = errors.New("still down")
+	if code := do("POST", "/api/deliveries/"+dead[0].Id+"/retry"); code != http.StatusBadGateway {
+		t.Fatalf("failed retry expected 502, got %d", code)
+	}
+	sendErr = nil
+	if code := do("POST", "/api/deliveries/"+dead[0].Id+"/retry"); code != http.StatusOK {
+		t.Fatalf("retry expected 200, got %d", code)
+	}
+	if code := do("POST", "/api/deliveries/missing/retry"); code != http.StatusNotFound {
+		t.Fatalf("unknown delivery expected 404, got %d", code)
+	}
+	pending := list("?status=pending")
+	if code := do("DELETE", "/api/deliveries/"+pending[0].Id); code != http.StatusOK {
+		t.Fatalf("purge expected 200, got %d", code)
+	}
+	if code := do("DELETE", "/api/deliveries"); code != http.StatusOK {
+		t.Fatalf("purge dead expected 200, got %d", code)
+	}
+	if got := list(""); len(got) != 0 {
+		t.Fatalf("expected an empty queue, got %d", len(got))
+	}
+}
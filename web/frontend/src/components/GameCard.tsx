@@ -52,22 +52,46 @@ const GameCard: React.FC<GameCardProps> = ({ game }) => {
     switch (leagueId) {
       case 1: return '🏒'; // NHL
       case 2: return '⚾'; // MLB
+      case 3: return '⚽'; // EPL
       case 5: return '🏈'; // CFL
       case 6: return '🏈'; // NFL
       case 7: return '🏒'; // Olympic Men's Hockey
       case 8: return '🏒'; // Olympic Women's Hockey
-      default: return '🏆';
+      case 9: return '🏀'; // NBA
+      case 10: return '🏀'; // WNBA
+      case 11: return '⚽'; // MLS
+      case 12: return '⚽'; // UCL
+      case 13: return '🏒'; // PWHL
+      case 14: return '🏒'; // AHL
+      case 15: return '🏈'; // NCAAF
+      case 16: return '🏒'; // NCAA men's hockey
+      case 17: return '🏒'; // NCAA women's hockey
+      default: return isHockeyLeague(leagueId) ? '🏒' : '🏆';
     }
   };
 
+  // IIHF tournaments from config are numbered from 100
+  const isHockeyLeague = (leagueId: number) =>
+    leagueId === 1 || leagueId === 4 || leagueId === 7 || leagueId === 8 || leagueId === 13 || leagueId === 14 || leagueId === 16 || leagueId === 17 || leagueId >= 100;
+
   const getLeagueName = (leagueId: number) => {
     switch (leagueId) {
       case 1: return 'NHL';
       case 2: return 'MLB';
+      case 3: return 'EPL';
       case 5: return 'CFL';
       case 6: return 'NFL';
       case 7: return "Olympic Men's Hockey";
       case 8: return "Olympic Women's Hockey";
+      case 9: return 'NBA';
+      case 10: return 'WNBA';
+      case 11: return 'MLS';
+      case 12: return 'UCL';
+      case 13: return 'PWHL';
+      case 14: return 'AHL';
+      case 15: return 'NCAAF';
+      case 16: return 'NCAA Men';
+      case 17: return 'NCAA Women';
       default: return 'Game';
     }
   };
@@ -177,9 +201,9 @@ const GameCard: React.FC<GameCardProps> = ({ game }) => {
           isTopInning={game.currentState.clock?.includes('Top')}
         />
       );
-    } else if ((game.leagueId === 5 || game.leagueId === 6) && (game.currentState.status === 'active' || game.currentState.status === 'delayed')) {
+    } else if ((game.leagueId === 5 || game.leagueId === 6 || game.leagueId === 15) && (game.currentState.status === 'active' || game.currentState.status === 'delayed')) {
       return <FootballGameDetails game={game} />;
-    } else if ((game.leagueId === 1 || game.leagueId === 7 || game.leagueId === 8) && (game.currentState.status === 'active' || game.currentState.status === 'delayed')) {
+    } else if (isHockeyLeague(game.leagueId) && (game.currentState.status === 'active' || game.currentState.status === 'delayed')) {
       return <HockeyGameDetails game={game} />;
     } else {
       return <div className="text-gray-400 text-lg font-medium">VS</div>;
@@ -220,11 +244,12 @@ const GameCard: React.FC<GameCardProps> = ({ game }) => {
             {renderTeamLogo(game.currentState.away.team)}
             <div>
               <div className="text-lg font-semibold text-white">
+                {game.currentState.away.rank ? <span className="text-sm text-gray-400 mr-1">#{game.currentState.away.rank}</span> : null}
                 {game.currentState.away.team.teamCode}
               </div>
               <div className="text-sm text-gray-300 flex items-center justify-end">
                 <span>{game.currentState.away.team.teamName}</span>
-                {(game.leagueId === 5 || game.leagueId === 6) && hasPossession(game.currentState.away.team.teamCode) && (
+                {(game.leagueId === 5 || game.leagueId === 6 || game.leagueId === 15) && hasPossession(game.currentState.away.team.teamCode) && (
                   <span className="ml-1" title="Possession">🏈</span>
                 )}
               </div>
@@ -247,11 +272,12 @@ const GameCard: React.FC<GameCardProps> = ({ game }) => {
           <div className="flex items-center space-x-3 mb-2">
             <div>
               <div className="text-lg font-semibold text-white">
+                {game.currentState.home.rank ? <span className="text-sm text-gray-400 mr-1">#{game.currentState.home.rank}</span> : null}
                 {game.currentState.home.team.teamCode}
               </div>
               <div className="text-sm text-gray-300 flex items-center">
                 <span>{game.currentState.home.team.teamName}</span>
-                {(game.leagueId === 5 || game.leagueId === 6) && hasPossession(game.currentState.home.team.teamCode) && (
+                {(game.leagueId === 5 || game.leagueId === 6 || game.leagueId === 15) && hasPossession(game.currentState.home.team.teamCode) && (
                   <span className="ml-1" title="Possession">🏈</span>
                 )}
               </div>
@@ -322,7 +348,7 @@ const GameCard: React.FC<GameCardProps> = ({ game }) => {
             )}
           </div>
           {/* Shots on Goal for NHL games */}
-          {(game.leagueId === 1 || game.leagueId === 7 || game.leagueId === 8) && (game.currentState.home.statistics?.shots !== undefined || game.currentState.away.statistics?.shots !== undefined) && (
+          {isHockeyLeague(game.leagueId) && (game.currentState.home.statistics?.shots !== undefined || game.currentState.away.statistics?.shots !== undefined) && (
             <div className="mt-2 text-sm text-gray-400">
               <span className="font-medium">Shots:</span> {game.currentState.home.statistics?.shots ?? 0} - {game.currentState.away.statistics?.shots ?? 0}
             </div>
@@ -102,10 +102,20 @@ const Scoreboard: React.FC<ScoreboardProps> = ({ games }) => {
     switch (leagueId) {
       case 1: return '🏒'; // NHL
       case 2: return '⚾'; // MLB
+      case 3: return '⚽'; // EPL
       case 5: return '🏈'; // CFL
       case 6: return '🏈'; // NFL
       case 7: return '🏒'; // Olympic Men's Hockey
       case 8: return '🏒'; // Olympic Women's Hockey
+      case 9: return '🏀'; // NBA
+      case 10: return '🏀'; // WNBA
+      case 11: return '⚽'; // MLS
+      case 12: return '⚽'; // UCL
+      case 13: return '🏒'; // PWHL
+      case 14: return '🏒'; // AHL
+      case 15: return '🏈'; // NCAAF
+      case 16: return '🏒'; // NCAA men's hockey
+      case 17: return '🏒'; // NCAA women's hockey
       default: return '🏆';
     }
   };
@@ -114,10 +124,20 @@ const Scoreboard: React.FC<ScoreboardProps> = ({ games }) => {
     switch (leagueId) {
       case 1: return 'NHL';
       case 2: return 'MLB';
+      case 3: return 'EPL';
       case 5: return 'CFL';
       case 6: return 'NFL';
       case 7: return "Olympic Men's Hockey";
       case 8: return "Olympic Women's Hockey";
+      case 9: return 'NBA';
+      case 10: return 'WNBA';
+      case 11: return 'MLS';
+      case 12: return 'UCL';
+      case 13: return 'PWHL';
+      case 14: return 'AHL';
+      case 15: return 'NCAAF';
+      case 16: return 'NCAA Men';
+      case 17: return 'NCAA Women';
       default: return 'Game';
     }
   };
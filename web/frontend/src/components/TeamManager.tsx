@@ -32,7 +32,16 @@ const TeamManager: React.FC<TeamManagerProps> = ({ leagueConfigs, onUpdateConfig
       case 6: return '🏈'; // NFL
       case 7: return '🏒'; // Olympic Men's Hockey
       case 8: return '🏒'; // Olympic Women's Hockey
-      default: return '🏆';
+      case 9: return '🏀'; // NBA
+      case 10: return '🏀'; // WNBA
+      case 11: return '⚽'; // MLS
+      case 12: return '⚽'; // UCL
+      case 13: return '🏒'; // PWHL
+      case 14: return '🏒'; // AHL
+      case 15: return '🏈'; // NCAAF
+      case 16: return '🏒'; // NCAA men's hockey
+      case 17: return '🏒'; // NCAA women's hockey
+      default: return leagueId >= 100 ? '🏒' : '🏆'; // IIHF tournaments from config
     }
   };
 
@@ -46,7 +55,16 @@ const TeamManager: React.FC<TeamManagerProps> = ({ leagueConfigs, onUpdateConfig
       case 6: return 'from-indigo-500 to-indigo-600'; // NFL
       case 7: return 'from-amber-500 to-amber-600'; // Olympic Men's Hockey
       case 8: return 'from-rose-500 to-rose-600'; // Olympic Women's Hockey
-      default: return 'from-gray-500 to-gray-600';
+      case 9: return 'from-orange-600 to-red-600'; // NBA
+      case 10: return 'from-orange-400 to-orange-500'; // WNBA
+      case 11: return 'from-emerald-500 to-emerald-600'; // MLS
+      case 12: return 'from-sky-600 to-blue-700'; // UCL
+      case 13: return 'from-violet-600 to-purple-700'; // PWHL
+      case 14: return 'from-red-600 to-red-700'; // AHL
+      case 15: return 'from-blue-800 to-blue-900'; // NCAAF
+      case 16: return 'from-sky-600 to-sky-700'; // NCAA men's hockey
+      case 17: return 'from-teal-600 to-teal-700'; // NCAA women's hockey
+      default: return leagueId >= 100 ? 'from-purple-500 to-purple-600' : 'from-gray-500 to-gray-600'; // IIHF tournaments from config
     }
   };
 
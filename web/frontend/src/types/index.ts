@@ -24,17 +24,45 @@ export interface GameState {
   clock?: string;
   venue?: Venue;
   weather?: Weather;
+  // Football: the drive in progress, or the one that just ended
+  drive?: DriveState;
+  // Basketball: the current unanswered run, and the team that last led
+  run?: RunState;
+  leader?: string;
+  // Soccer: minutes of stoppage time played so far in the half
+  addedTime?: number;
   // Baseball-specific details
   details?: EventDetails;
   statistics?: TeamStats;
 }
 
+export interface DriveState {
+  id: string;
+  teamCode: string;
+  period: number;
+  startYardLine: number; // Yards from the offence's own goal line
+  plays: number;
+  yards: number;
+  timeConsumed?: string;
+  redZone?: boolean; // The drive has reached the opponent's 20
+  result?: string; // "touchdown", "field_goal", "punt", "turnover", "downs", ...; empty while in progress
+}
+
+export interface RunState {
+  teamCode: string;
+  points: number;
+}
+
 export interface TeamState {
   team: Team;
   score: number;
   // Enhanced team state
   periodScores?: number[];
   statistics?: TeamStats;
+  // Hockey: goalie pulled for an extra attacker
+  goaliePulled?: boolean;
+  // College: poll ranking going into the game
+  rank?: number;
 }
 
 export interface Team {
@@ -44,6 +72,7 @@ export interface Team {
   leagueId: number;
   extId: string;
   logoUrl?: string;
+  conference?: string;
 }
 
 export interface GameDetails {
@@ -148,6 +177,8 @@ export type EventType =
   | "touchdown"
   | "field_goal"
   | "safety"
+  | "single"
+  | "convert"
   | "home_run"
   | "strikeout"
   | "walk"
@@ -155,7 +186,17 @@ export type EventType =
   | "period_start"
   | "period_end"
   | "game_start"
-  | "game_end";
+  | "game_end"
+  | "goalie_pulled"
+  | "goalie_returned"
+  | "red_zone_entry"
+  | "big_play"
+  | "drive_result"
+  | "lead_change"
+  | "scoring_run"
+  | "red_card"
+  | "var_overturn"
+  | "play";
 
 export interface Player {
   id: string;
@@ -173,10 +214,15 @@ export interface BaseRunners {
 
 export interface EventDetails {
   // Goal details
-  goalType?: string;
+  goalType?: string; // Hockey: "even_strength", "power_play", ...; soccer: "penalty", "own_goal"
   assist1?: Player;
   assist2?: Player;
   
+  // Football scoring details
+  scoringType?: string; // "touchdown", "defensive_touchdown", "field_goal", "safety", "single", "convert"
+  conversion?: string; // "extra_point", "extra_point_missed", "two_point", "two_point_failed"
+  points?: number;
+  
   // Penalty details
   penaltyType?: string;
   penaltyMinutes?: number;
@@ -187,6 +233,11 @@ export interface EventDetails {
   distance?: number;
   yardsGained?: number;
   possession?: string; // Team code with possession
+  drive?: DriveState; // The drive a red zone entry, turnover, big play or drive result happened on
+
+  // Basketball details
+  lead?: number; // The team's margin after a lead change
+  run?: RunState; // The run a scoring_run event is about
   
   // Baseball details
   inning?: number;
@@ -330,7 +381,11 @@ export type EventPriority = 'low' | 'normal' | 'high' | 'critical';
 export const getEventIcon = (eventType: EventType): string => {
   switch (eventType) {
     case 'goal': return '🏒';
-    case 'touchdown': return '🏈';
+    case 'touchdown':
+    case 'field_goal':
+    case 'safety':
+    case 'single':
+    case 'convert': return '🏈';
     case 'home_run': return '⚾';
     case 'penalty': return '⚠️';
     case 'power_play': return '⚡';
@@ -343,6 +398,17 @@ export const getEventIcon = (eventType: EventType): string => {
     case 'game_end': return '🏁';
     case 'period_start': return '⏰';
     case 'period_end': return '⏰';
+    case 'goalie_pulled':
+    case 'goalie_returned': return '🥅';
+    case 'red_zone_entry': return '🚩';
+    case 'big_play': return '💥';
+    case 'lead_change': return '🔀';
+    case 'scoring_run': return '🏀';
+    case 'red_card': return '🟥';
+    case 'var_overturn': return '📺';
+    case 'turnover':
+    case 'fumble':
+    case 'interception': return '🔄';
     default: return '📰';
   }
 };
@@ -351,15 +417,27 @@ export const getEventColor = (eventType: EventType): string => {
   switch (eventType) {
     case 'goal':
     case 'touchdown':
+    case 'field_goal':
+    case 'safety':
+    case 'single':
+    case 'convert':
     case 'home_run':
       return 'green';
     case 'penalty':
     case 'turnover':
     case 'fumble':
+    case 'interception':
     case 'error':
+    case 'red_card':
       return 'red';
     case 'power_play':
     case 'strikeout':
+    case 'goalie_pulled':
+    case 'red_zone_entry':
+    case 'big_play':
+    case 'lead_change':
+    case 'scoring_run':
+    case 'var_overturn':
       return 'yellow';
     case 'game_start':
     case 'game_end':
@@ -376,6 +454,10 @@ export const getEventPriority = (eventType: EventType): EventPriority => {
   switch (eventType) {
     case 'goal':
     case 'touchdown':
+    case 'field_goal':
+    case 'safety':
+    case 'single':
+    case 'convert':
     case 'home_run':
       return 'high';
     case 'game_start':
@@ -386,6 +468,11 @@ export const getEventPriority = (eventType: EventType): EventPriority => {
     case 'penalty':
     case 'turnover':
     case 'fumble':
+    case 'interception':
+    case 'red_zone_entry':
+    case 'lead_change':
+    case 'red_card':
+    case 'var_overturn':
       return 'high';
     default:
       return 'normal';